@@ -25,10 +25,12 @@
 package main
 
 import (
+	"encoding/hex"
 	"flag"
 	"fmt"
-	"golang.org/x/exp/slices"
 	"os"
+	"path/filepath"
+	"text/tabwriter"
 	"time"
 
 	"github.com/vhive-serverless/loader/pkg/common"
@@ -46,10 +48,43 @@ const (
 )
 
 var (
-	configPath    = flag.String("config", "config.json", "Path to loader configuration file")
-	verbosity     = flag.String("verbosity", "info", "Logging verbosity - choose from [info, debug, trace]")
-	iatGeneration = flag.Bool("iatGeneration", false, "Generate iats only or run invocations as well")
-	generated     = flag.Bool("generated", false, "True if iats were already generated")
+	configPath       = flag.String("config", "config.json", "Path to loader configuration file")
+	verbosity        = flag.String("verbosity", "info", "Logging verbosity - choose from [info, debug, trace]")
+	iatGeneration    = flag.Bool("iatGeneration", false, "Generate iats only or run invocations as well")
+	generated        = flag.Bool("generated", false, "True if iats were already generated")
+	migrateConfigTo  = flag.String("migrateConfigTo", "", "Migrate the serverless.yml files to the given Serverless Framework version (e.g. v4) and exit")
+	serverlessConfig = flag.String("serverlessConfig", "./serverless-*.yml", "Glob pattern of the serverless.yml files to migrate")
+	ioWorkload       = flag.String("ioWorkload", "", "I/O operation performed by AWS Lambda functions on each invocation, as <s3-read|s3-write>:<sizeKB>:<bucket>")
+	liveHistogram    = flag.Bool("liveHistogram", false, "Print the histogram of function execution times every minute of the experiment")
+	skipPreflight    = flag.Bool("skipPreflight", false, "Skip checking the reachability of the function endpoints before the experiment")
+	anonymize        = flag.Bool("anonymize", false, "Write a copy of the trace with anonymized function, application, and owner hashes to <TracePath>_anonymized and exit")
+	anonymizeSalt    = flag.String("salt", "", "Hex-encoded HMAC key used by -anonymize")
+	traceChecksum    = flag.String("traceChecksum", "", "Expected SHA-256 checksum (hex) of the invocation trace file; the loader aborts on mismatch")
+	configDiff       = flag.String("configDiff", "", "Print the differences between this configuration file and the one given as argument (-configDiff a.json b.json) and exit")
+	abortErrorRate   = flag.Float64("abortErrorRate", 0, "Abort the experiment once the fraction of failed invocations exceeds this threshold (0 disables)")
+	abortWindow      = flag.Int("abortWindowMinutes", 1, "Number of most recent minutes over which the error rate is computed for -abortErrorRate")
+	abortCooldown    = flag.Int("abortCooldownMinutes", 5, "Pause after which an aborted experiment resumes when -autoResume is set")
+	autoResume       = flag.Bool("autoResume", false, "Resume the experiment after the cooldown instead of aborting it")
+	spikeMode        = flag.Bool("spikeMode", false, "Issue all invocations of a minute within the first second of the minute")
+	jaegerEndpoint   = flag.String("jaegerEndpoint", "", "host:port of a Jaeger agent to which one span per invocation is exported over UDP after the experiment")
+	adaptiveTimeout  = flag.Int("adaptiveTimeoutMinMs", 0, "Lower bound of the function timeout adapted each minute to 1.5x the p99 latency of the previous minute (0 disables)")
+	maxGoroutines    = flag.Int("maxGoroutines", 0, "Shed invocations once the loader runs this many goroutines (0 disables)")
+	shedStrategy     = flag.String("shedStrategy", driver.ShedDropNewest, "Invocations shed under overload with -maxGoroutines: drop-newest or drop-oldest (queued the longest)")
+	keepaliveGap     = flag.Int("warmKeepaliveGapSeconds", 0, "Invoke each function at least this often between its invocations in the trace to keep its containers warm (0 disables)")
+	keepaliveRuntime = flag.Uint("warmKeepaliveRuntimeMs", 0, "Runtime requested by the keepalive invocations (defaults to the minimal runtime)")
+	minVariance      = flag.Bool("minVarianceScheduling", false, "Dispatch the invocations with sub-millisecond precision by spinning shortly before each of them, at the cost of CPU time")
+	maxJitterMs      = flag.Int("maxJitterMs", 0, "Delay each invocation by a random offset of up to this many milliseconds to avoid synchronized bursts (0 disables)")
+	serverLogs       = flag.Bool("collectServerLogs", false, "Collect the execution trace logs of the function servers started with -server-trace-log after the experiment (Knative only)")
+	useCache         = flag.Bool("useCache", false, "Reuse the results of matching invocations cached by previous experiments writing to the same output directory instead of invoking the functions")
+	predictLatency   = flag.Bool("predictLatency", false, "Fit a regression of the execution time on the runtime, memory, and first invocation during the experiment and write its prediction error per minute")
+	aggregateResults = flag.Bool("aggregateResults", false, "Aggregate the results per function and minute while the experiment runs and write them to a CSV file")
+	detectDrift      = flag.Bool("detectDrift", false, "Warn about functions whose per-minute mean latency trends up or down (Mann-Kendall test, requires at least 10 minutes)")
+	rpsBudget        = flag.Float64("rpsBudget", 0, "Cap the invocations of all the functions at this many requests per second, delaying the invocations beyond it (0 disables)")
+	anomalyZScore    = flag.Float64("anomalyZScore", 0, "Warn about invocations with an execution time more than this many standard deviations away from the mean of the function (0 disables)")
+	preWarmDeployed  = flag.Int("preWarmDeployed", 0, "Invoke each function this many times right after deploying it to AWS Lambda to avoid starting the experiment with cold starts, overriding PreWarmDeployed of the configuration")
+	statusPort       = flag.Int("statusPort", 0, "Port on which the progress of the experiment is reported as JSON on GET /status, and the Prometheus metrics of the loader on GET /metrics (0 disables)")
+	watchConfig      = flag.Bool("watchConfig", false, "Apply changes of the timeouts, compute mode, and I/O workload in the configuration file to the running experiment")
+	multiCloudConfig = flag.String("multiCloudConfig", "", "Path to a YAML file with a serverless.yml definition per cloud provider (aws, gcp, azure) to deploy in parallel and exit")
 )
 
 func init() {
@@ -72,8 +107,29 @@ func init() {
 }
 
 func main() {
+	if *migrateConfigTo != "" {
+		migrateServerlessConfigs(*serverlessConfig, *migrateConfigTo)
+		return
+	}
+	if *configDiff != "" {
+		if flag.NArg() != 1 {
+			log.Fatal("Usage: -configDiff <config-a.json> <config-b.json>")
+		}
+		printConfigDiff(*configDiff, flag.Arg(0))
+		return
+	}
+	if *multiCloudConfig != "" {
+		deployMultiCloud(*multiCloudConfig)
+		return
+	}
+
 	cfg := config.ReadConfigurationFile(*configPath)
 
+	if *anonymize {
+		anonymizeTrace(&cfg, *anonymizeSalt)
+		return
+	}
+
 	if cfg.EnableZipkinTracing {
 		// TODO: how not to exclude Zipkin spans here? - file a feature request
 		log.Warnf("Zipkin tracing has been enabled. This will exclude Istio spans from the Zipkin traces.")
@@ -86,24 +142,64 @@ func main() {
 		defer shutdown()
 	}
 
-	if cfg.ExperimentDuration < 1 {
-		log.Fatal("Runtime duration should be longer, at least a minute.")
+	if *ioWorkload != "" {
+		workload, err := common.ParseIOWorkload(*ioWorkload)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg.IOWorkload = workload
+	}
+	if cfg.IOWorkload != nil {
+		if err := cfg.IOWorkload.Validate(); err != nil {
+			log.Fatal(err)
+		}
+		if cfg.Platform != "AWSLambda" {
+			log.Fatal("I/O workloads are only supported on AWSLambda.")
+		}
+		if cfg.AWSLambdaHandler == common.AwsLambdaHandlerMemory {
+			log.Fatal("I/O workloads are not supported by the memory handler.")
+		}
 	}
 
-	supportedPlatforms := []string{
-		"Knative",
-		"OpenWhisk",
-		"AWSLambda",
-		"Dirigent",
+	if *preWarmDeployed > 0 {
+		cfg.PreWarmDeployed = *preWarmDeployed
 	}
 
-	if !slices.Contains(supportedPlatforms, cfg.Platform) {
-		log.Fatal("Unsupported platform! Supported platforms are [Knative, OpenWhisk, AWSLambda, Dirigent]")
+	if err := config.ValidateConfiguration(cfg); err != nil {
+		log.Fatalf("Invalid configuration - %v", err)
 	}
 
 	runTraceMode(&cfg, *iatGeneration, *generated)
 }
 
+func migrateServerlessConfigs(pattern string, targetVersion string) {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(paths) == 0 {
+		log.Fatalf("No serverless.yml files match %s", pattern)
+	}
+
+	for _, path := range paths {
+		serverless, err := driver.ReadServerlessConfigFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %s", path, err)
+		}
+
+		migrated, err := driver.MigrateServerlessConfig(serverless, targetVersion)
+		if err != nil {
+			log.Fatalf("Failed to migrate %s: %s", path, err)
+		}
+
+		if err = migrated.WriteServerlessConfigFile(path); err != nil {
+			log.Fatalf("Failed to write %s: %s", path, err)
+		}
+
+		log.Infof("Migrated %s from framework version %s to %s", path, serverless.FrameworkVersion, migrated.FrameworkVersion)
+	}
+}
+
 func determineDurationToParse(runtimeDuration int, warmupDuration int) int {
 	result := 0
 
@@ -117,9 +213,41 @@ func determineDurationToParse(runtimeDuration int, warmupDuration int) int {
 	return result
 }
 
+func anonymizeTrace(cfg *config.LoaderConfiguration, saltHex string) {
+	salt, err := hex.DecodeString(saltHex)
+	if err != nil || len(salt) == 0 {
+		log.Fatal("Anonymizing the trace requires a hex-encoded -salt.")
+	}
+
+	durationToParse := determineDurationToParse(cfg.ExperimentDuration, cfg.WarmupDuration)
+	functions := trace.NewAzureParser(cfg.TracePath, durationToParse).Parse(cfg.Platform)
+
+	outputPath := filepath.Clean(cfg.TracePath) + "_anonymized"
+	if err = os.MkdirAll(outputPath, 0755); err != nil {
+		log.Fatal(err)
+	}
+	if err = trace.ExportAzureTrace(trace.AnonymizeTrace(functions, string(salt)), outputPath); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Infof("Anonymized trace of %d functions written to %s", len(functions), outputPath)
+}
+
 func runTraceMode(cfg *config.LoaderConfiguration, iatOnly bool, generated bool) {
 	durationToParse := determineDurationToParse(cfg.ExperimentDuration, cfg.WarmupDuration)
 
+	invocationTracePath := cfg.TracePath + "/invocations.csv"
+	if *traceChecksum != "" {
+		if err := trace.VerifyTraceChecksum(invocationTracePath, *traceChecksum); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	checksum, err := trace.GenerateTraceChecksum(invocationTracePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	traceParser := trace.NewAzureParser(cfg.TracePath, durationToParse)
 	functions := traceParser.Parse(cfg.Platform)
 
@@ -143,6 +271,11 @@ func runTraceMode(cfg *config.LoaderConfiguration, iatOnly bool, generated bool)
 		shiftIAT = true
 	case "equidistant":
 		iatType = common.Equidistant
+	case "compound_poisson":
+		iatType = common.CompoundPoisson
+	case "compound_poisson_shift":
+		iatType = common.CompoundPoisson
+		shiftIAT = true
 	default:
 		log.Fatal("Unsupported IAT distribution.")
 	}
@@ -180,11 +313,126 @@ func runTraceMode(cfg *config.LoaderConfiguration, iatOnly bool, generated bool)
 		TraceGranularity:    traceGranularity,
 		TraceDuration:       durationToParse,
 
-		YAMLPath: yamlSpecificationPath,
-		TestMode: false,
+		YAMLPath:       yamlSpecificationPath,
+		TestMode:       false,
+		SkipPreflight:  *skipPreflight,
+		LiveHistogram:  *liveHistogram,
+		JaegerEndpoint: *jaegerEndpoint,
+		SpikeMode:      *spikeMode,
+
+		CollectServerLogs: *serverLogs,
+		StatusPort:        *statusPort,
+		UseResultCache:    *useCache,
+		PredictLatency:    *predictLatency,
+		AnomalyThreshold:  *anomalyZScore,
+		DetectDrift:       *detectDrift,
+		AggregateResults:  *aggregateResults,
+
+		RPSBudget: *rpsBudget,
+
+		MinAdaptiveTimeout: time.Duration(*adaptiveTimeout) * time.Millisecond,
+
+		AbortPolicy: abortPolicy(),
+		ShedPolicy:  shedPolicy(),
+		AutoResume:  *autoResume,
+
+		ScheduleJitter: scheduleJitter(),
+		WarmKeepalive:  warmKeepalive(),
+
+		MinVarianceScheduling: *minVariance,
 
 		Functions: functions,
 	})
+	experimentDriver.Metadata.TraceChecksum = checksum
+
+	if *watchConfig {
+		watcher, err := experimentDriver.WatchConfiguration(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to watch the configuration file: %s", err)
+		}
+		defer watcher.Close()
+	}
 
 	experimentDriver.RunExperiment(iatOnly, generated)
 }
+
+func deployMultiCloud(path string) {
+	deployment, err := driver.ReadMultiCloudConfig(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	endpoints, err := driver.DeployAll(*deployment)
+	if err != nil {
+		log.Fatalf("Multi-cloud deployment failed: %s", err)
+	}
+
+	for provider, functionToURL := range endpoints {
+		for name, url := range functionToURL {
+			log.Infof("[%s] %s: %s", provider, name, url)
+		}
+	}
+}
+
+func abortPolicy() *driver.AbortPolicy {
+	if *abortErrorRate == 0 {
+		return nil
+	}
+	if *abortErrorRate < 0 || *abortErrorRate > 1 {
+		log.Fatal("The abort error rate must be in (0, 1].")
+	}
+
+	return &driver.AbortPolicy{
+		ErrorRateThreshold: *abortErrorRate,
+		WindowMinutes:      *abortWindow,
+		CooldownMinutes:    *abortCooldown,
+	}
+}
+
+func shedPolicy() *driver.ShedPolicy {
+	if *maxGoroutines <= 0 {
+		return nil
+	}
+	if *shedStrategy != driver.ShedDropNewest && *shedStrategy != driver.ShedDropOldest {
+		log.Fatalf("Unsupported shed strategy %q.", *shedStrategy)
+	}
+
+	return &driver.ShedPolicy{
+		MaxGoroutines: *maxGoroutines,
+		Strategy:      *shedStrategy,
+	}
+}
+
+func scheduleJitter() *driver.ScheduleJitter {
+	if *maxJitterMs <= 0 {
+		return nil
+	}
+
+	return &driver.ScheduleJitter{MaxJitterMs: *maxJitterMs}
+}
+
+func warmKeepalive() *driver.WarmKeepaliveConfig {
+	if *keepaliveGap <= 0 {
+		return nil
+	}
+
+	return &driver.WarmKeepaliveConfig{
+		MaxGapSeconds:      *keepaliveGap,
+		KeepaliveRuntimeMs: uint32(*keepaliveRuntime),
+	}
+}
+
+func printConfigDiff(pathA string, pathB string) {
+	diffs := config.DiffConfigurations(config.ReadConfigurationFile(pathA), config.ReadConfigurationFile(pathB))
+	if len(diffs) == 0 {
+		fmt.Println("The configurations are identical.")
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "Field\t%s\t%s\n", pathA, pathB)
+	for _, diff := range diffs {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", diff.Field, diff.OldValue, diff.NewValue)
+	}
+	writer.Flush()
+}
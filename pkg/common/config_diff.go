@@ -0,0 +1,94 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package common
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConfigDiff is a field whose value differs between two configurations
+type ConfigDiff struct {
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+// DiffStructs walks all exported fields of two values of the same struct type, descending into nested structs and
+// pointers to structs, and reports the fields whose values differ. Nested fields are named by their dotted path.
+func DiffStructs(a, b interface{}) []ConfigDiff {
+	valueA, valueB := reflect.ValueOf(a), reflect.ValueOf(b)
+	if valueA.Type() != valueB.Type() {
+		panic(fmt.Sprintf("cannot compare %s with %s", valueA.Type(), valueB.Type()))
+	}
+
+	var diffs []ConfigDiff
+	diffValues("", valueA, valueB, &diffs)
+
+	return diffs
+}
+
+func diffValues(path string, a, b reflect.Value, diffs *[]ConfigDiff) {
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				*diffs = append(*diffs, ConfigDiff{Field: path, OldValue: formatConfigValue(a), NewValue: formatConfigValue(b)})
+			}
+			return
+		}
+
+		diffValues(path, a.Elem(), b.Elem(), diffs)
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+
+			diffValues(fieldPath, a.Field(i), b.Field(i), diffs)
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*diffs = append(*diffs, ConfigDiff{Field: path, OldValue: formatConfigValue(a), NewValue: formatConfigValue(b)})
+		}
+	}
+}
+
+func formatConfigValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		v = v.Elem()
+	}
+
+	return fmt.Sprintf("%+v", v.Interface())
+}
@@ -0,0 +1,100 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package common
+
+import (
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+)
+
+// ConfigWatcher calls the reload function whenever the watched configuration file is written
+type ConfigWatcher struct {
+	path    string
+	reload  func() error
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// NewConfigWatcher starts watching the file. Errors returned by the reload function are logged and the file keeps
+// being watched, so that a rejected configuration can be fixed by writing the file again.
+func NewConfigWatcher(path string, reload func() error) (*ConfigWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	path = filepath.Clean(path)
+	// Editors often replace the file instead of writing it in place, which would end a watch on the file itself
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	w := &ConfigWatcher{
+		path:    path,
+		reload:  reload,
+		watcher: watcher,
+		done:    make(chan struct{}),
+	}
+	go w.watch()
+
+	return w, nil
+}
+
+func (w *ConfigWatcher) watch() {
+	defer close(w.done)
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != w.path || !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+
+			log.Infof("Configuration file %s changed, reloading.", w.path)
+			if err := w.reload(); err != nil {
+				log.Warnf("Configuration reload rejected: %v", err)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Warnf("Error watching the configuration file %s: %v", w.path, err)
+		}
+	}
+}
+
+// Close stops watching the file and waits for the reload in progress, if any
+func (w *ConfigWatcher) Close() error {
+	err := w.watcher.Close()
+	<-w.done
+
+	return err
+}
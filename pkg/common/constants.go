@@ -54,6 +54,7 @@ const (
 	Exponential IatDistribution = iota
 	Uniform
 	Equidistant
+	CompoundPoisson // Poisson-distributed bursts of simultaneous invocations
 )
 
 type TraceGranularity int
@@ -97,3 +98,31 @@ const (
 	AwsRegion                  = "us-east-1"
 	AwsTraceFuncRepositoryName = "invitro_trace_function_aws"
 )
+
+const GcpRegion = "us-central1"
+
+const AlibabaRegion = "cn-hangzhou"
+
+// Handler variants of the AWS Lambda trace function, selected through an environment variable of the function
+const (
+	AwsLambdaHandlerEnvironmentVariable = "TRACE_FUNC_HANDLER"
+	AwsLambdaHandlerTrace               = "trace"
+	AwsLambdaHandlerMemory              = "memory"
+)
+
+// Modes in which the memory handler of the AWS Lambda trace function allocates the requested memory
+const (
+	MemoryAllocSingleGoroutine = "single-goroutine"
+	MemoryAllocStriped         = "striped" // split over up to 8 goroutines allocating simultaneously
+)
+
+// AwsIdempotencyTableEnvironmentVariable names the DynamoDB table in which the AWS Lambda trace function records the
+// idempotency keys of the invocations it has served
+const AwsIdempotencyTableEnvironmentVariable = "IDEMPOTENCY_TABLE"
+
+// Headers added by the loader to each HTTP invocation of the trace, identifying the invocation to the function
+const (
+	InvitroHeaderPrefix          = "X-Invitro-"
+	InvitroMinuteHeader          = InvitroHeaderPrefix + "Minute"
+	InvitroInvocationIndexHeader = InvitroHeaderPrefix + "Invocation-Index"
+)
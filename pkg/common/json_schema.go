@@ -0,0 +1,202 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const jsonSchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+// jsonSchema is a node of a JSON Schema (draft-07) document
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+}
+
+// GenerateJSONSchema reflects over the struct v and returns its JSON Schema (draft-07) document, so that editors can
+// validate and autocomplete the files it is read from. The properties are named after the json tags of the exported
+// fields. The jsonschema tag of a field lists comma-separated constraints:
+//
+//	required             the property must be set
+//	minimum=<number>     lower bound of a numeric property
+//	maximum=<number>     upper bound of a numeric property
+//	enum=<a>|<b>|...     allowed values of the property
+//	description=<text>   description of the property, which must come last as the text may contain commas
+func GenerateJSONSchema(v interface{}) ([]byte, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot generate the JSON Schema of %T, which is not a struct", v)
+	}
+
+	schema, err := schemaOfType(t)
+	if err != nil {
+		return nil, err
+	}
+	schema.Schema = jsonSchemaDraft07
+	schema.Title = t.Name()
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func schemaOfType(t reflect.Type) (*jsonSchema, error) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOfType(t.Elem())
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}, nil
+	case reflect.String:
+		return &jsonSchema{Type: "string"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := schemaOfType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		values, err := schemaOfType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return schemaOfStruct(t)
+	default:
+		return nil, fmt.Errorf("type %s has no JSON Schema", t)
+	}
+}
+
+func schemaOfStruct(t reflect.Type) (*jsonSchema, error) {
+	schema := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		} else if name == "" {
+			name = field.Name
+		}
+
+		property, err := schemaOfType(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		required, err := applyJSONSchemaTag(property, field.Tag.Get("jsonschema"))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = property
+	}
+
+	return schema, nil
+}
+
+// applyJSONSchemaTag sets the constraints of the jsonschema tag on the property and returns whether it is required
+func applyJSONSchemaTag(property *jsonSchema, tag string) (bool, error) {
+	required := false
+
+	for tag != "" {
+		var constraint string
+		if strings.HasPrefix(tag, "description=") {
+			constraint, tag = tag, ""
+		} else {
+			constraint, tag, _ = strings.Cut(tag, ",")
+		}
+
+		key, value, _ := strings.Cut(constraint, "=")
+		switch key {
+		case "required":
+			required = true
+		case "description":
+			property.Description = value
+		case "minimum", "maximum":
+			bound, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return false, fmt.Errorf("invalid %s %q", key, value)
+			}
+			if key == "minimum" {
+				property.Minimum = &bound
+			} else {
+				property.Maximum = &bound
+			}
+		case "enum":
+			for _, option := range strings.Split(value, "|") {
+				enumValue, err := parseEnumValue(property.Type, option)
+				if err != nil {
+					return false, err
+				}
+				property.Enum = append(property.Enum, enumValue)
+			}
+		default:
+			return false, fmt.Errorf("unknown jsonschema constraint %q", key)
+		}
+	}
+
+	return required, nil
+}
+
+func parseEnumValue(schemaType string, value string) (interface{}, error) {
+	switch schemaType {
+	case "integer":
+		return strconv.ParseInt(value, 10, 64)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
@@ -24,6 +24,12 @@
 
 package common
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // IATMatrix - columns are minutes, rows are IATs
 type IATMatrix [][]float64
 
@@ -42,3 +48,50 @@ type FunctionSpecification struct {
 	RawDuration          ProbabilisticDuration      `json:"RawDuration"`
 	RuntimeSpecification RuntimeSpecificationMatrix `json:"RuntimeSpecification"`
 }
+
+const (
+	IOWorkloadS3Read  = "s3-read"
+	IOWorkloadS3Write = "s3-write"
+)
+
+// IOWorkload instructs the function to perform an I/O operation on top of the CPU-bound execution
+type IOWorkload struct {
+	Type   string `json:"Type"`
+	SizeKB uint32 `json:"SizeKB"`
+	Bucket string `json:"Bucket"`
+}
+
+func (w *IOWorkload) Validate() error {
+	if w.Type != IOWorkloadS3Read && w.Type != IOWorkloadS3Write {
+		return fmt.Errorf("unsupported I/O workload type %q", w.Type)
+	}
+	if w.SizeKB == 0 {
+		return fmt.Errorf("I/O workload size must be positive")
+	}
+	if w.Bucket == "" {
+		return fmt.Errorf("I/O workload requires a bucket")
+	}
+
+	return nil
+}
+
+// ParseIOWorkload parses an I/O workload given in the <type>:<sizeKB>:<bucket> format, e.g. s3-read:64:loader-io
+func ParseIOWorkload(spec string) (*IOWorkload, error) {
+	fields := strings.SplitN(spec, ":", 3)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("invalid I/O workload %q, expected <type>:<sizeKB>:<bucket>", spec)
+	}
+
+	size, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid I/O workload size %q: %w", fields[1], err)
+	}
+
+	workload := &IOWorkload{
+		Type:   fields[0],
+		SizeKB: uint32(size),
+		Bucket: fields[2],
+	}
+
+	return workload, workload.Validate()
+}
@@ -80,10 +80,26 @@ type DirigentMetadata struct {
 	IterationMultiplier int    `csv:"IterationMultiplier"`
 }
 
+// FunctionBudget caps the estimated AWS Lambda charges of the invocations of a function. The invocations of a minute
+// that would exceed the budget are dropped.
+type FunctionBudget struct {
+	MaxUSDCentsPerMinute float64
+}
+
 type Function struct {
 	Name     string
 	Endpoint string
 
+	// CustomHeaders are set on each HTTP invocation of the function
+	CustomHeaders map[string]string
+
+	// Budget caps the estimated spend on the function, if set
+	Budget *FunctionBudget
+
+	// Tenant (owner) and application the function belongs to in the trace
+	HashOwner string
+	HashApp   string
+
 	// From the static trace profiler
 	InitialScale int
 	// From the trace
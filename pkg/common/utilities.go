@@ -25,6 +25,9 @@
 package common
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"hash/fnv"
 	"log"
 	"math/rand"
@@ -118,6 +121,13 @@ func Hash(s string) uint64 {
 	return h.Sum64()
 }
 
+// IdempotencyKey identifies an invocation of the trace in an experiment, such that retries of the same invocation
+// share the key while the invocations of another run of the same trace do not
+func IdempotencyKey(experimentID string, functionName string, minute int, index int, seed int64) string {
+	digest := sha256.Sum256([]byte(fmt.Sprintf("%s-%s-%d-%d-%d", experimentID, functionName, minute, index, seed)))
+	return hex.EncodeToString(digest[:])
+}
+
 func SumNumberOfInvocations(withWarmup bool, totalDuration int, functions []*Function) int {
 	result := 0
 
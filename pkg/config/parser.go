@@ -26,49 +26,154 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
 )
 
 type LoaderConfiguration struct {
-	Seed int64 `json:"Seed"`
+	Seed int64 `json:"Seed" jsonschema:"description=Seed of the generation of the IATs and the runtime specifications"`
 
-	Platform string `json:"Platform"`
+	Platform string `json:"Platform" jsonschema:"required,enum=Knative|OpenWhisk|AWSLambda|CloudRun|Dirigent,description=Platform the functions are deployed on"`
 
-	YAMLSelector string `json:"YAMLSelector"`
-	EndpointPort int    `json:"EndpointPort"`
+	YAMLSelector string `json:"YAMLSelector" jsonschema:"description=Service YAML of the functions (wimpy, container, or firecracker), not used by Dirigent"`
+	EndpointPort int    `json:"EndpointPort" jsonschema:"minimum=0,maximum=65535,description=Port of the function endpoints"`
 
-	TracePath          string `json:"TracePath"`
-	Granularity        string `json:"Granularity"`
-	OutputPathPrefix   string `json:"OutputPathPrefix"`
-	IATDistribution    string `json:"IATDistribution"`
-	CPULimit           string `json:"CPULimit"`
-	ExperimentDuration int    `json:"ExperimentDuration"`
-	WarmupDuration     int    `json:"WarmupDuration"`
+	TracePath          string `json:"TracePath" jsonschema:"required,description=Directory of the invocations, durations, and memory of the trace"`
+	Granularity        string `json:"Granularity" jsonschema:"required,enum=minute|second,description=Duration each column of the trace stands for"`
+	OutputPathPrefix   string `json:"OutputPathPrefix" jsonschema:"required,description=Path prefix of the output files"`
+	IATDistribution    string `json:"IATDistribution" jsonschema:"required,enum=exponential|exponential_shift|uniform|uniform_shift|equidistant|compound_poisson|compound_poisson_shift,description=Distribution of the inter-arrival times within each minute"`
+	CPULimit           string `json:"CPULimit" jsonschema:"description=CPU limit of the functions, e.g., 1vCPU"`
+	ExperimentDuration int    `json:"ExperimentDuration" jsonschema:"required,minimum=1,description=Duration of the experiment in minutes"`
+	WarmupDuration     int    `json:"WarmupDuration" jsonschema:"minimum=0,description=Warm-up minutes before the experiment, preceded by a profiling minute"`
 
 	IsPartiallyPanic            bool   `json:"IsPartiallyPanic"`
 	EnableZipkinTracing         bool   `json:"EnableZipkinTracing"`
 	EnableMetricsScrapping      bool   `json:"EnableMetricsScrapping"`
-	MetricScrapingPeriodSeconds int    `json:"MetricScrapingPeriodSeconds"`
-	AutoscalingMetric           string `json:"AutoscalingMetric"`
+	MetricScrapingPeriodSeconds int    `json:"MetricScrapingPeriodSeconds" jsonschema:"minimum=0"`
+	AutoscalingMetric           string `json:"AutoscalingMetric" jsonschema:"description=Knative autoscaling metric, e.g., concurrency or rps"`
 
-	GRPCConnectionTimeoutSeconds int  `json:"GRPCConnectionTimeoutSeconds"`
-	GRPCFunctionTimeoutSeconds   int  `json:"GRPCFunctionTimeoutSeconds"`
+	GRPCConnectionTimeoutSeconds int  `json:"GRPCConnectionTimeoutSeconds" jsonschema:"minimum=0"`
+	GRPCFunctionTimeoutSeconds   int  `json:"GRPCFunctionTimeoutSeconds" jsonschema:"minimum=0"`
+	GRPCKeepaliveSeconds         int  `json:"GRPCKeepaliveSeconds,omitempty" jsonschema:"minimum=0"`
 	DAGMode                      bool `json:"DAGMode"`
+	DropOldestOnScheduleDrift    bool `json:"DropOldestOnScheduleDrift"`
+
+	BurstSizeProbability       float64 `json:"BurstSizeProbability,omitempty" jsonschema:"minimum=0,maximum=1"` // compound Poisson IAT only
+	WithinBurstIATMicroseconds float64 `json:"WithinBurstIATMicroseconds,omitempty" jsonschema:"minimum=0"`     // compound Poisson IAT only
+	RuntimeMemoryCorrelation   float64 `json:"RuntimeMemoryCorrelation,omitempty" jsonschema:"minimum=-1,maximum=1"`
+
+	IOWorkload       *common.IOWorkload `json:"IOWorkload,omitempty"`                             // AWS Lambda only
+	AWSLambdaHandler string             `json:"AWSLambdaHandler,omitempty"`                       // AWS Lambda only
+	MemoryAllocMode  string             `json:"MemoryAllocMode,omitempty"`                        // AWS Lambda memory handler only
+	ComputeMode      string             `json:"ComputeMode,omitempty"`                            // AWS Lambda only
+	TLSPinnedCertHex string             `json:"TLSPinnedCertHex,omitempty"`                       // AWS Lambda only
+	IdempotencyTable string             `json:"IdempotencyTable,omitempty"`                       // AWS Lambda only
+	PreWarmDeployed  int                `json:"PreWarmDeployed,omitempty" jsonschema:"minimum=0"` // AWS Lambda only
+
+	VPCSecurityGroupIDs []string `json:"VPCSecurityGroupIDs,omitempty"` // AWS Lambda only
+	VPCSubnetIDs        []string `json:"VPCSubnetIDs,omitempty"`        // AWS Lambda only
+}
+
+var (
+	SupportedPlatforms        = []string{"Knative", "OpenWhisk", "AWSLambda", "CloudRun", "Dirigent"}
+	SupportedGranularities    = []string{"minute", "second"}
+	SupportedIATDistributions = []string{"exponential", "exponential_shift", "uniform", "uniform_shift", "equidistant",
+		"compound_poisson", "compound_poisson_shift"}
+)
+
+// HotReloadableFields are the fields of the loader configuration read anew by every invocation, which can thus be
+// changed while the experiment runs
+var HotReloadableFields = []string{
+	"GRPCConnectionTimeoutSeconds",
+	"GRPCFunctionTimeoutSeconds",
+	"GRPCKeepaliveSeconds",
+	"IOWorkload",
+	"ComputeMode",
+	"TLSPinnedCertHex",
+	"IdempotencyTable",
 }
 
 func ReadConfigurationFile(path string) LoaderConfiguration {
-	byteValue, err := os.ReadFile(path)
+	config, err := LoadConfigurationFile(path)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	return config
+}
+
+// LoadConfigurationFile is ReadConfigurationFile returning the errors instead of terminating the loader
+func LoadConfigurationFile(path string) (LoaderConfiguration, error) {
 	var config LoaderConfiguration
-	err = json.Unmarshal(byteValue, &config)
+
+	byteValue, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatal(err)
+		return config, err
 	}
 
-	return config
+	err = json.Unmarshal(byteValue, &config)
+
+	return config, err
+}
+
+// ValidateConfiguration checks the fields of the loader configuration that every experiment needs
+func ValidateConfiguration(cfg LoaderConfiguration) error {
+	if !slices.Contains(SupportedPlatforms, cfg.Platform) {
+		return fmt.Errorf("unsupported platform %q, supported platforms are %v", cfg.Platform, SupportedPlatforms)
+	}
+	if cfg.TracePath == "" {
+		return errors.New("the trace path must be set")
+	}
+	if cfg.OutputPathPrefix == "" {
+		return errors.New("the output path prefix must be set")
+	}
+	if !slices.Contains(SupportedGranularities, cfg.Granularity) {
+		return fmt.Errorf("unsupported trace granularity %q, supported granularities are %v", cfg.Granularity, SupportedGranularities)
+	}
+	if !slices.Contains(SupportedIATDistributions, cfg.IATDistribution) {
+		return fmt.Errorf("unsupported IAT distribution %q, supported distributions are %v", cfg.IATDistribution, SupportedIATDistributions)
+	}
+	if cfg.ExperimentDuration < 1 {
+		return errors.New("runtime duration should be longer, at least a minute")
+	}
+	if cfg.WarmupDuration < 0 {
+		return errors.New("the warm-up duration cannot be negative")
+	}
+
+	return nil
+}
+
+// ValidateReload returns the changes of the reloaded configuration, or an error if it changes fields other than
+// HotReloadableFields, such as the trace or the IAT distribution, which are fixed once the experiment has started
+func ValidateReload(current, reloaded LoaderConfiguration) ([]common.ConfigDiff, error) {
+	diffs := DiffConfigurations(current, reloaded)
+
+	var rejected []string
+	for _, diff := range diffs {
+		field, _, _ := strings.Cut(diff.Field, ".")
+		if !slices.Contains(HotReloadableFields, field) {
+			rejected = append(rejected, diff.Field)
+		}
+	}
+	if len(rejected) > 0 {
+		return nil, fmt.Errorf("%s cannot be changed while the experiment runs", strings.Join(rejected, ", "))
+	}
+	if reloaded.IOWorkload != nil {
+		if err := reloaded.IOWorkload.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return diffs, nil
+}
+
+// DiffConfigurations reports the fields whose values differ between two loader configurations
+func DiffConfigurations(a, b LoaderConfiguration) []common.ConfigDiff {
+	return common.DiffStructs(a, b)
 }
@@ -29,6 +29,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
 )
 
 func TestConfigParser(t *testing.T) {
@@ -66,3 +68,71 @@ func TestConfigParser(t *testing.T) {
 		t.Error("Unexpected configuration read.")
 	}
 }
+
+func TestDiffConfigurations(t *testing.T) {
+	a := ReadConfigurationFile("test_config.json")
+	b := ReadConfigurationFile("test_config.json")
+
+	if diffs := DiffConfigurations(a, b); len(diffs) != 0 {
+		t.Errorf("Expected identical configurations, got %+v", diffs)
+	}
+
+	b.Seed = 7
+	b.Platform = "AWSLambda"
+	b.IOWorkload = &common.IOWorkload{Type: common.IOWorkloadS3Read, SizeKB: 4, Bucket: "loader"}
+
+	expected := []common.ConfigDiff{
+		{Field: "Seed", OldValue: "42", NewValue: "7"},
+		{Field: "Platform", OldValue: "Knative", NewValue: "AWSLambda"},
+		{Field: "IOWorkload", OldValue: "<nil>", NewValue: "{Type:s3-read SizeKB:4 Bucket:loader}"},
+	}
+
+	diffs := DiffConfigurations(a, b)
+	if len(diffs) != len(expected) {
+		t.Fatalf("Expected %d differences, got %+v", len(expected), diffs)
+	}
+	for i, diff := range diffs {
+		if diff != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], diff)
+		}
+	}
+
+	// Nested fields are compared individually
+	a.IOWorkload = &common.IOWorkload{Type: common.IOWorkloadS3Read, SizeKB: 8, Bucket: "loader"}
+	diffs = DiffConfigurations(a, b)
+	if diffs[len(diffs)-1] != (common.ConfigDiff{Field: "IOWorkload.SizeKB", OldValue: "8", NewValue: "4"}) {
+		t.Errorf("Unexpected difference of nested fields: %+v", diffs)
+	}
+}
+
+func TestValidateReload(t *testing.T) {
+	current := ReadConfigurationFile("test_config.json")
+
+	reloaded := current
+	reloaded.GRPCFunctionTimeoutSeconds = 2 * current.GRPCFunctionTimeoutSeconds
+	reloaded.ComputeMode = "fib"
+	diffs, err := ValidateReload(current, reloaded)
+	if err != nil || len(diffs) != 2 {
+		t.Errorf("Expected the timeout and compute mode to be reloaded, got %+v: %v", diffs, err)
+	}
+
+	for _, field := range []string{"TracePath", "IATDistribution"} {
+		reloaded = current
+		reloaded.ComputeMode = "fib"
+		if field == "TracePath" {
+			reloaded.TracePath = "data/traces/other"
+		} else {
+			reloaded.IATDistribution = "exponential"
+		}
+
+		if _, err = ValidateReload(current, reloaded); err == nil || !strings.Contains(err.Error(), field) {
+			t.Errorf("Expected the change of %s to be rejected, got %v", field, err)
+		}
+	}
+
+	reloaded = current
+	reloaded.IOWorkload = &common.IOWorkload{Type: "s3-delete", SizeKB: 4, Bucket: "loader"}
+	if _, err = ValidateReload(current, reloaded); err == nil {
+		t.Error("Expected an invalid I/O workload to be rejected.")
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type testSchema struct {
+	Schema     string `json:"$schema"`
+	Title      string `json:"title"`
+	Properties map[string]struct {
+		Type    string        `json:"type"`
+		Enum    []interface{} `json:"enum"`
+		Minimum *float64      `json:"minimum"`
+		Maximum *float64      `json:"maximum"`
+	} `json:"properties"`
+	Required []string `json:"required"`
+}
+
+func readTestSchema(t *testing.T) testSchema {
+	raw, err := GenerateJSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema testSchema
+	if err = json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("The generated schema is not valid JSON - %v", err)
+	}
+
+	return schema
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	schema := readTestSchema(t)
+
+	if schema.Schema != "http://json-schema.org/draft-07/schema#" || schema.Title != "LoaderConfiguration" {
+		t.Errorf("Unexpected schema header %q, %q", schema.Schema, schema.Title)
+	}
+
+	configType := reflect.TypeOf(LoaderConfiguration{})
+	if len(schema.Properties) != configType.NumField() {
+		t.Errorf("Expected %d properties, got %d", configType.NumField(), len(schema.Properties))
+	}
+	for i := 0; i < configType.NumField(); i++ {
+		name, _, _ := strings.Cut(configType.Field(i).Tag.Get("json"), ",")
+		if _, ok := schema.Properties[name]; !ok {
+			t.Errorf("Property %s missing from the schema", name)
+		}
+	}
+
+	duration := schema.Properties["ExperimentDuration"]
+	if duration.Type != "integer" || duration.Minimum == nil || *duration.Minimum != 1 {
+		t.Errorf("Unexpected schema of ExperimentDuration %+v", duration)
+	}
+	if schema.Properties["IOWorkload"].Type != "object" {
+		t.Errorf("Expected IOWorkload to be an object, got %s", schema.Properties["IOWorkload"].Type)
+	}
+}
+
+func TestJSONSchemaRequiredFieldsMatchValidation(t *testing.T) {
+	valid := ReadConfigurationFile("test_config.json")
+	if err := ValidateConfiguration(valid); err != nil {
+		t.Fatalf("Expected the test configuration to be valid, got %v", err)
+	}
+
+	var rejected []string
+	configType := reflect.TypeOf(valid)
+	for i := 0; i < configType.NumField(); i++ {
+		cfg := valid
+		reflect.ValueOf(&cfg).Elem().Field(i).SetZero()
+
+		if ValidateConfiguration(cfg) != nil {
+			name, _, _ := strings.Cut(configType.Field(i).Tag.Get("json"), ",")
+			rejected = append(rejected, name)
+		}
+	}
+
+	required := readTestSchema(t).Required
+	slices.Sort(required)
+	slices.Sort(rejected)
+	if !slices.Equal(required, rejected) {
+		t.Errorf("The schema requires %v, while the validation rejects %v when unset", required, rejected)
+	}
+}
+
+func TestJSONSchemaEnumsMatchValidation(t *testing.T) {
+	schema := readTestSchema(t)
+	valid := ReadConfigurationFile("test_config.json")
+
+	setters := map[string]func(cfg *LoaderConfiguration, value string){
+		"Platform":        func(cfg *LoaderConfiguration, value string) { cfg.Platform = value },
+		"Granularity":     func(cfg *LoaderConfiguration, value string) { cfg.Granularity = value },
+		"IATDistribution": func(cfg *LoaderConfiguration, value string) { cfg.IATDistribution = value },
+	}
+
+	for name, property := range schema.Properties {
+		if len(property.Enum) == 0 {
+			continue
+		}
+
+		set, ok := setters[name]
+		if !ok {
+			t.Errorf("Enum of %s not covered by the test", name)
+			continue
+		}
+
+		for _, value := range property.Enum {
+			cfg := valid
+			set(&cfg, value.(string))
+			if err := ValidateConfiguration(cfg); err != nil {
+				t.Errorf("Expected %s %v allowed by the schema to be valid, got %v", name, value, err)
+			}
+		}
+
+		cfg := valid
+		set(&cfg, "unsupported")
+		if ValidateConfiguration(cfg) == nil {
+			t.Errorf("Expected %s outside of the enum to be invalid", name)
+		}
+	}
+}
+
+func TestCheckedInJSONSchemaUpToDate(t *testing.T) {
+	checkedIn, err := os.ReadFile("../../" + SchemaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated, err := GenerateJSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(bytes.TrimSpace(checkedIn), bytes.TrimSpace(generated)) {
+		t.Errorf("%s is out of date, run make schema", SchemaPath)
+	}
+}
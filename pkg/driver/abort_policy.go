@@ -0,0 +1,190 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// AbortPolicy stops issuing invocations once the error rate of the experiment becomes too high, so that an
+// overloaded platform does not distort the remaining measurements
+type AbortPolicy struct {
+	// ErrorRateThreshold is the fraction of failed invocations in (0, 1] above which the experiment is aborted
+	ErrorRateThreshold float64
+	// WindowMinutes is the number of most recent minutes over which the error rate is computed
+	WindowMinutes int
+	// CooldownMinutes is the pause before the experiment resumes, if auto-resume is enabled
+	CooldownMinutes int
+}
+
+type EventType string
+
+const (
+	ThresholdBreached EventType = "ThresholdBreached"
+	ExperimentResumed EventType = "ExperimentResumed"
+)
+
+// Event describes a change of the state of the experiment
+type Event struct {
+	Type      EventType
+	Minute    int     // minute of the experiment in which the event occurred
+	ErrorRate float64 // rolling error rate at the time of the event
+}
+
+// Notifier is informed about the events of the experiment
+type Notifier interface {
+	Notify(event Event)
+}
+
+// LogNotifier writes the events to the log
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(event Event) {
+	log.Warnf("%s in minute %d (error rate: %.2f%%)", event.Type, event.Minute, event.ErrorRate*100)
+}
+
+type abortAction int
+
+const (
+	continueExperiment abortAction = iota
+	pauseExperiment
+	resumeExperiment
+	abortExperiment
+)
+
+type minuteOutcome struct {
+	issued int64
+	failed int64
+}
+
+// abortMonitor evaluates the AbortPolicy at the end of every minute of the experiment. Outcomes can be recorded
+// concurrently with the evaluation.
+type abortMonitor struct {
+	policy     AbortPolicy
+	autoResume bool
+	notifier   Notifier
+
+	current struct {
+		issued atomic.Int64
+		failed atomic.Int64
+	}
+
+	mutex       sync.Mutex
+	minute      int
+	history     []minuteOutcome // outcomes of the minutes since the start or the last resumption
+	pausedUntil int
+	paused      atomic.Bool
+	aborted     atomic.Bool
+}
+
+func newAbortMonitor(policy AbortPolicy, autoResume bool, notifier Notifier) *abortMonitor {
+	if policy.WindowMinutes < 1 {
+		policy.WindowMinutes = 1
+	}
+	if notifier == nil {
+		notifier = LogNotifier{}
+	}
+
+	return &abortMonitor{
+		policy:     policy,
+		autoResume: autoResume,
+		notifier:   notifier,
+	}
+}
+
+// Record adds the outcome of an invocation to the current minute
+func (m *abortMonitor) Record(success bool) {
+	m.current.issued.Add(1)
+	if !success {
+		m.current.failed.Add(1)
+	}
+}
+
+// Paused reports whether invocations should currently be skipped
+func (m *abortMonitor) Paused() bool {
+	return m.paused.Load() || m.aborted.Load()
+}
+
+func (m *abortMonitor) errorRate() float64 {
+	var issued, failed int64
+	for i := max(0, len(m.history)-m.policy.WindowMinutes); i < len(m.history); i++ {
+		issued += m.history[i].issued
+		failed += m.history[i].failed
+	}
+
+	if issued == 0 {
+		return 0
+	}
+
+	return float64(failed) / float64(issued)
+}
+
+// EndMinute closes the current minute and decides how the experiment continues
+func (m *abortMonitor) EndMinute() abortAction {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	minute := m.minute
+	m.minute++
+
+	outcome := minuteOutcome{issued: m.current.issued.Swap(0), failed: m.current.failed.Swap(0)}
+
+	switch {
+	case m.aborted.Load():
+		return continueExperiment
+	case m.paused.Load():
+		if m.minute < m.pausedUntil {
+			return continueExperiment
+		}
+
+		// Failures before the pause do not count towards the error rate after the resumption
+		m.history = nil
+		m.paused.Store(false)
+		m.notifier.Notify(Event{Type: ExperimentResumed, Minute: minute})
+
+		return resumeExperiment
+	}
+
+	m.history = append(m.history, outcome)
+	errorRate := m.errorRate()
+	if errorRate <= m.policy.ErrorRateThreshold {
+		return continueExperiment
+	}
+
+	m.notifier.Notify(Event{Type: ThresholdBreached, Minute: minute, ErrorRate: errorRate})
+	if m.autoResume {
+		m.pausedUntil = m.minute + m.policy.CooldownMinutes
+		m.paused.Store(true)
+
+		return pauseExperiment
+	}
+
+	m.aborted.Store(true)
+
+	return abortExperiment
+}
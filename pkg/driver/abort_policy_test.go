@@ -0,0 +1,115 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"testing"
+)
+
+type recordingNotifier struct {
+	events []Event
+}
+
+func (n *recordingNotifier) Notify(event Event) {
+	n.events = append(n.events, event)
+}
+
+// simulateMinutes records the given number of failed out of 10 invocations for every minute and returns the action
+// taken at the end of each minute
+func simulateMinutes(monitor *abortMonitor, failuresPerMinute []int) []abortAction {
+	var actions []abortAction
+	for _, failures := range failuresPerMinute {
+		for i := 0; i < 10; i++ {
+			monitor.Record(i >= failures)
+		}
+		actions = append(actions, monitor.EndMinute())
+	}
+
+	return actions
+}
+
+func TestAbortPolicyFiresAtBreach(t *testing.T) {
+	notifier := &recordingNotifier{}
+	monitor := newAbortMonitor(AbortPolicy{ErrorRateThreshold: 0.5, WindowMinutes: 2}, false, notifier)
+
+	// Rolling error rates over two minutes: 0%, 20%, 40%, 50% (not above the threshold), 60%
+	actions := simulateMinutes(monitor, []int{0, 4, 4, 6, 6, 10})
+
+	expected := []abortAction{continueExperiment, continueExperiment, continueExperiment, continueExperiment, abortExperiment, continueExperiment}
+	for minute, action := range actions {
+		if action != expected[minute] {
+			t.Errorf("Minute %d: expected action %d, got %d.", minute, expected[minute], action)
+		}
+	}
+
+	if len(notifier.events) != 1 || notifier.events[0].Type != ThresholdBreached || notifier.events[0].Minute != 4 || notifier.events[0].ErrorRate != 0.6 {
+		t.Errorf("Expected a single breach in minute 4, got %+v", notifier.events)
+	}
+	if !monitor.Paused() {
+		t.Error("Invocations should not be issued after the abort.")
+	}
+}
+
+func TestAbortPolicyAutoResume(t *testing.T) {
+	notifier := &recordingNotifier{}
+	monitor := newAbortMonitor(AbortPolicy{ErrorRateThreshold: 0.3, WindowMinutes: 1, CooldownMinutes: 2}, true, notifier)
+
+	actions := simulateMinutes(monitor, []int{5, 0, 0, 0, 5})
+
+	expected := []abortAction{pauseExperiment, continueExperiment, resumeExperiment, continueExperiment, pauseExperiment}
+	for minute, action := range actions {
+		if action != expected[minute] {
+			t.Errorf("Minute %d: expected action %d, got %d.", minute, expected[minute], action)
+		}
+	}
+
+	expectedEvents := []Event{
+		{Type: ThresholdBreached, Minute: 0, ErrorRate: 0.5},
+		{Type: ExperimentResumed, Minute: 2},
+		{Type: ThresholdBreached, Minute: 4, ErrorRate: 0.5},
+	}
+	if len(notifier.events) != len(expectedEvents) {
+		t.Fatalf("Expected %d events, got %+v", len(expectedEvents), notifier.events)
+	}
+	for i, event := range notifier.events {
+		if event != expectedEvents[i] {
+			t.Errorf("Expected event %+v, got %+v", expectedEvents[i], event)
+		}
+	}
+}
+
+func TestGracefulShutdownStopsIssuingInvocations(t *testing.T) {
+	experiment := createScheduledExperiment(t, "shutdown", 4, 10)
+	invoker := NewSimulatedInvoker(0)
+
+	driver := NewDriver(experiment.Configuration)
+	driver.Invoker = invoker
+	driver.GracefulShutdown()
+	driver.RunExperiment(false, false)
+
+	if invoker.Invocations() != 0 || driver.invocationCounts.issued != 0 {
+		t.Errorf("Expected no invocations after the shutdown, got %d.", invoker.Invocations())
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// AdaptiveTimeoutFactor is the headroom of the adaptive timeout over the p99 latency of the previous minute
+const AdaptiveTimeoutFactor = 1.5
+
+// AdaptiveTimeout scales the function timeout with the observed latency. At the end of each minute, the timeout
+// becomes max(minTimeout, AdaptiveTimeoutFactor * p99) of the latencies recorded during that minute. The timeout
+// is left unchanged after a minute without any recorded latency.
+type AdaptiveTimeout struct {
+	mutex sync.Mutex
+
+	minTimeout time.Duration
+	current    time.Duration
+
+	latencies []float64       // in microseconds, of the current minute
+	series    []time.Duration // timeout in effect during each completed minute
+}
+
+func NewAdaptiveTimeout(defaultTimeout time.Duration, minTimeout time.Duration) *AdaptiveTimeout {
+	return &AdaptiveTimeout{
+		minTimeout: minTimeout,
+		current:    defaultTimeout,
+	}
+}
+
+// Record adds the latency of an invocation completed during the current minute
+func (a *AdaptiveTimeout) Record(latencyUs int64) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.latencies = append(a.latencies, float64(latencyUs))
+}
+
+// Current returns the timeout to apply to the invocations issued now
+func (a *AdaptiveTimeout) Current() time.Duration {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.current
+}
+
+// EndMinute closes the current minute and returns the timeout for the next one
+func (a *AdaptiveTimeout) EndMinute() time.Duration {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.series = append(a.series, a.current)
+
+	if len(a.latencies) > 0 {
+		sort.Float64s(a.latencies)
+		p99 := time.Duration(percentileOfSorted(a.latencies, 0.99)*AdaptiveTimeoutFactor) * time.Microsecond
+
+		a.current = a.minTimeout
+		if p99 > a.minTimeout {
+			a.current = p99
+		}
+		a.latencies = a.latencies[:0]
+	}
+
+	return a.current
+}
+
+// Series returns the timeout that was in effect during each completed minute
+func (a *AdaptiveTimeout) Series() []time.Duration {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return append([]time.Duration(nil), a.series...)
+}
@@ -0,0 +1,70 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveTimeoutTracksP99(t *testing.T) {
+	timeout := NewAdaptiveTimeout(10*time.Second, 100*time.Millisecond)
+
+	if timeout.Current() != 10*time.Second {
+		t.Fatalf("Expected the default timeout before the first minute, got %v.", timeout.Current())
+	}
+
+	// The p99 latency of each minute grows: 20ms (below the minimum), 200ms, 1s, 4s
+	p99s := []int64{20_000, 200_000, 1_000_000, 4_000_000}
+	expected := []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 1500 * time.Millisecond, 6 * time.Second}
+
+	for minute, p99 := range p99s {
+		for i := 0; i < 98; i++ {
+			timeout.Record(p99 / 10)
+		}
+		timeout.Record(p99)
+		timeout.Record(p99)
+
+		if next := timeout.EndMinute(); next != expected[minute] {
+			t.Errorf("Minute %d: expected a timeout of %v, got %v.", minute, expected[minute], next)
+		}
+	}
+
+	// A minute without any completed invocation keeps the timeout
+	if next := timeout.EndMinute(); next != 6*time.Second {
+		t.Errorf("Expected the timeout to be kept after an idle minute, got %v.", next)
+	}
+
+	series := timeout.Series()
+	expectedSeries := append([]time.Duration{10 * time.Second}, expected...)
+	if len(series) != len(expectedSeries) {
+		t.Fatalf("Expected %d minutes in the timeout series, got %d.", len(expectedSeries), len(series))
+	}
+	for i := range expectedSeries {
+		if series[i] != expectedSeries[i] {
+			t.Errorf("Minute %d of the series: expected %v, got %v.", i, expectedSeries[i], series[i])
+		}
+	}
+}
@@ -0,0 +1,309 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/gocarina/gocsv"
+	log "github.com/sirupsen/logrus"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// InvocationResult is the outcome of an invocation of a function issued in the given minute of the trace
+type InvocationResult struct {
+	Function string
+	Minute   int
+	Record   *mc.ExecutionRecord
+}
+
+// MinuteAggregate summarizes the invocations of a function issued in a minute. The latency is the response time of
+// the successful invocations.
+type MinuteAggregate struct {
+	Minute       int     `csv:"Minute"`
+	Function     string  `csv:"Function"`
+	Invocations  int64   `csv:"Invocations"`
+	Successful   int64   `csv:"Successful"`
+	Failed       int64   `csv:"Failed"`
+	P50LatencyUs float64 `csv:"P50LatencyUs"`
+	P99LatencyUs float64 `csv:"P99LatencyUs"`
+
+	latencies []float64 // µs, sorted
+
+	// Sums over the successful invocations
+	runtimeSum uint64 // µs
+	memorySum  uint64 // MiB
+}
+
+// FunctionSummary summarizes the invocations of a function over the whole experiment. The latency is the response
+// time and the runtime and memory are the measured ones, all of the successful invocations.
+type FunctionSummary struct {
+	FunctionName  string  `csv:"function_name"`
+	Invocations   int64   `csv:"total_invocations"`
+	Successful    int64   `csv:"success_count"`
+	Failed        int64   `csv:"error_count"`
+	MeanLatencyUs float64 `csv:"mean_latency_us"`
+	P50LatencyUs  float64 `csv:"p50_us"`
+	P99LatencyUs  float64 `csv:"p99_us"`
+	MeanRuntimeMs float64 `csv:"mean_runtime_ms"`
+	MeanMemoryMib float64 `csv:"mean_memory_mib"`
+}
+
+// ExperimentAggregate summarizes all the invocations of the experiment
+type ExperimentAggregate struct {
+	TotalDurationMinutes int
+	TotalInvocations     int64
+	OverallP50LatencyUs  float64
+	OverallP99LatencyUs  float64
+	FunctionBreakdown    map[string]FunctionSummary
+}
+
+// minuteClosed marks the end of a minute in the results of the AggregationPipeline
+type minuteClosed int
+
+// AggregationPipeline aggregates the results of the invocations while the experiment runs, in three levels connected
+// by channels: the raw results of the invocations, the MinuteAggregate of each function and minute, and the
+// ExperimentAggregate. The aggregate of a minute is emitted once the minute is closed; the results of the minute
+// arriving later are emitted in another aggregate of the same minute. Safe for concurrent use.
+type AggregationPipeline struct {
+	mutex      sync.Mutex
+	closed     bool
+	results    chan interface{} // InvocationResult or minuteClosed
+	minutes    chan MinuteAggregate
+	experiment chan ExperimentAggregate
+
+	minuteAggregates []MinuteAggregate // in the order they were emitted, complete once closed
+}
+
+func NewAggregationPipeline() *AggregationPipeline {
+	p := &AggregationPipeline{
+		results:    make(chan interface{}, 1024),
+		minutes:    make(chan MinuteAggregate, 64),
+		experiment: make(chan ExperimentAggregate, 1),
+	}
+
+	go p.aggregateMinutes()
+	go p.aggregateExperiment()
+
+	return p
+}
+
+// Submit passes the result of an invocation to the pipeline
+func (p *AggregationPipeline) Submit(result InvocationResult) {
+	p.send(result)
+}
+
+// CloseMinute emits the aggregates of the results of the given minute and of the earlier ones submitted so far
+func (p *AggregationPipeline) CloseMinute(minute int) {
+	p.send(minuteClosed(minute))
+}
+
+func (p *AggregationPipeline) send(event interface{}) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		log.Debugf("Ignoring %v submitted to the closed aggregation pipeline", event)
+		return
+	}
+	p.results <- event
+}
+
+// Close emits the aggregates of the minutes not closed yet and returns the aggregate of the experiment. The results
+// submitted afterward are ignored.
+func (p *AggregationPipeline) Close() ExperimentAggregate {
+	p.mutex.Lock()
+	p.closed = true
+	close(p.results)
+	p.mutex.Unlock()
+
+	return <-p.experiment
+}
+
+// MinuteAggregates returns the aggregates of all minutes, once the pipeline is closed
+func (p *AggregationPipeline) MinuteAggregates() []MinuteAggregate {
+	return p.minuteAggregates
+}
+
+func (p *AggregationPipeline) aggregateMinutes() {
+	type key struct {
+		minute   int
+		function string
+	}
+	pending := make(map[key]*MinuteAggregate)
+
+	emit := func(closed func(key) bool) {
+		var keys []key
+		for k := range pending {
+			if closed(k) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].minute != keys[j].minute {
+				return keys[i].minute < keys[j].minute
+			}
+			return keys[i].function < keys[j].function
+		})
+
+		for _, k := range keys {
+			aggregate := pending[k]
+			delete(pending, k)
+
+			sort.Float64s(aggregate.latencies)
+			if len(aggregate.latencies) > 0 {
+				aggregate.P50LatencyUs = percentileOfSorted(aggregate.latencies, 0.50)
+				aggregate.P99LatencyUs = percentileOfSorted(aggregate.latencies, 0.99)
+			}
+			p.minutes <- *aggregate
+		}
+	}
+
+	for event := range p.results {
+		switch event := event.(type) {
+		case minuteClosed:
+			emit(func(k key) bool { return k.minute <= int(event) })
+		case InvocationResult:
+			k := key{minute: event.Minute, function: event.Function}
+			aggregate, ok := pending[k]
+			if !ok {
+				aggregate = &MinuteAggregate{Minute: event.Minute, Function: event.Function}
+				pending[k] = aggregate
+			}
+
+			aggregate.Invocations++
+			if record := event.Record; record.ConnectionTimeout || record.FunctionTimeout || record.Shed {
+				aggregate.Failed++
+			} else {
+				aggregate.Successful++
+				aggregate.latencies = append(aggregate.latencies, float64(event.Record.ResponseTime))
+				aggregate.runtimeSum += uint64(record.ActualDuration)
+				aggregate.memorySum += uint64(record.ActualMemoryUsage)
+			}
+		}
+	}
+
+	emit(func(key) bool { return true })
+	close(p.minutes)
+}
+
+func (p *AggregationPipeline) aggregateExperiment() {
+	experiment := ExperimentAggregate{FunctionBreakdown: make(map[string]FunctionSummary)}
+	var latencies []float64
+	functionLatencies := make(map[string][]float64)
+	runtimeSums := make(map[string]uint64)
+	memorySums := make(map[string]uint64)
+
+	for aggregate := range p.minutes {
+		p.minuteAggregates = append(p.minuteAggregates, aggregate)
+
+		experiment.TotalDurationMinutes = max(experiment.TotalDurationMinutes, aggregate.Minute+1)
+		experiment.TotalInvocations += aggregate.Invocations
+		latencies = append(latencies, aggregate.latencies...)
+
+		summary := experiment.FunctionBreakdown[aggregate.Function]
+		summary.FunctionName = aggregate.Function
+		summary.Invocations += aggregate.Invocations
+		summary.Successful += aggregate.Successful
+		summary.Failed += aggregate.Failed
+		experiment.FunctionBreakdown[aggregate.Function] = summary
+		functionLatencies[aggregate.Function] = append(functionLatencies[aggregate.Function], aggregate.latencies...)
+		runtimeSums[aggregate.Function] += aggregate.runtimeSum
+		memorySums[aggregate.Function] += aggregate.memorySum
+	}
+
+	if len(latencies) > 0 {
+		sort.Float64s(latencies)
+		experiment.OverallP50LatencyUs = percentileOfSorted(latencies, 0.50)
+		experiment.OverallP99LatencyUs = percentileOfSorted(latencies, 0.99)
+	}
+	for function, summary := range experiment.FunctionBreakdown {
+		if functionLatency := functionLatencies[function]; len(functionLatency) > 0 {
+			sort.Float64s(functionLatency)
+			summary.P50LatencyUs = percentileOfSorted(functionLatency, 0.50)
+			summary.P99LatencyUs = percentileOfSorted(functionLatency, 0.99)
+
+			latencySum := 0.0
+			for _, latency := range functionLatency {
+				latencySum += latency
+			}
+			summary.MeanLatencyUs = latencySum / float64(len(functionLatency))
+		}
+		if summary.Successful > 0 {
+			summary.MeanRuntimeMs = float64(runtimeSums[function]) / float64(summary.Successful) / 1000
+			summary.MeanMemoryMib = float64(memorySums[function]) / float64(summary.Successful)
+		}
+		experiment.FunctionBreakdown[function] = summary
+	}
+
+	p.experiment <- experiment
+}
+
+// Summaries returns the summary of each function, sorted by name
+func (e *ExperimentAggregate) Summaries() []FunctionSummary {
+	var summaries []FunctionSummary
+	for _, summary := range e.FunctionBreakdown {
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].FunctionName < summaries[j].FunctionName })
+
+	return summaries
+}
+
+// ExportSummaryCSV writes one row per function with its invocation counts, latency, runtime, and memory, which is
+// quicker to analyze than the records of all the invocations
+func ExportSummaryCSV(summaries []FunctionSummary, w io.Writer) error {
+	return gocsv.Marshal(&summaries, w)
+}
+
+func (d *Driver) writeSummaryCSV() {
+	file, err := os.Create(d.outputFilename("summary"))
+	if err != nil {
+		log.Errorf("Failed to create the summary of the functions: %s", err)
+		return
+	}
+	defer file.Close()
+
+	if err = ExportSummaryCSV(d.experimentAggregate.Summaries(), file); err != nil {
+		log.Errorf("Failed to write the summary of the functions: %s", err)
+	}
+}
+
+func (d *Driver) writeMinuteAggregates() {
+	file, err := os.Create(d.outputFilename("minute_aggregates"))
+	if err != nil {
+		log.Errorf("Failed to create the per-minute aggregates: %s", err)
+		return
+	}
+	defer file.Close()
+
+	aggregates := d.aggregation.MinuteAggregates()
+	if err = gocsv.Marshal(&aggregates, file); err != nil {
+		log.Errorf("Failed to write the per-minute aggregates: %s", err)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func createAggregationResult(function string, minute int, responseTimeUs int64, failed bool) InvocationResult {
+	return InvocationResult{
+		Function: function,
+		Minute:   minute,
+		Record: &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{
+			ResponseTime:    responseTimeUs,
+			FunctionTimeout: failed,
+		}},
+	}
+}
+
+func TestAggregationPipeline(t *testing.T) {
+	pipeline := NewAggregationPipeline()
+
+	// Per function and minute, the response times of the successful invocations and the number of failed ones
+	latencies := map[string][][]int64{
+		"f1": {{100, 200, 300, 400}, {150, 250}, {1000, 2000, 3000}},
+		"f2": {{50}, {60, 70, 80}, {90}},
+	}
+	failures := map[string][]int{"f1": {1, 0, 2}, "f2": {0, 1, 0}}
+
+	for minute := 0; minute < 3; minute++ {
+		wg := sync.WaitGroup{}
+		for function := range latencies {
+			for _, latency := range latencies[function][minute] {
+				wg.Add(1)
+				go func(function string, latency int64) {
+					defer wg.Done()
+					pipeline.Submit(createAggregationResult(function, minute, latency, false))
+				}(function, latency)
+			}
+			for i := 0; i < failures[function][minute]; i++ {
+				pipeline.Submit(createAggregationResult(function, minute, 0, true))
+			}
+		}
+		wg.Wait()
+
+		pipeline.CloseMinute(minute)
+	}
+	// A late result of the first minute
+	pipeline.Submit(createAggregationResult("f2", 0, 500, false))
+	latencies["f2"][0] = append(latencies["f2"][0], 500)
+
+	experiment := pipeline.Close()
+	minutes := pipeline.MinuteAggregates()
+
+	if len(minutes) != 7 {
+		t.Fatalf("Expected an aggregate per function and minute plus the late one, got %d.", len(minutes))
+	}
+	if late := minutes[6]; late.Minute != 0 || late.Function != "f2" || late.Invocations != 1 || late.P50LatencyUs != 500 {
+		t.Errorf("Unexpected aggregate of the late result %+v", late)
+	}
+
+	// Level 2 against the raw results
+	for _, aggregate := range minutes[:6] {
+		expected := latencies[aggregate.Function][aggregate.Minute]
+		if aggregate.Function == "f2" && aggregate.Minute == 0 {
+			expected = expected[:1]
+		}
+
+		sorted := toSortedFloats(expected)
+		if aggregate.Successful != int64(len(expected)) || aggregate.Failed != int64(failures[aggregate.Function][aggregate.Minute]) ||
+			aggregate.Invocations != aggregate.Successful+aggregate.Failed ||
+			aggregate.P50LatencyUs != percentileOfSorted(sorted, 0.50) || aggregate.P99LatencyUs != percentileOfSorted(sorted, 0.99) {
+
+			t.Errorf("Unexpected aggregate %+v", aggregate)
+		}
+	}
+
+	// Level 3 against level 2 and the raw results
+	var all []int64
+	var totalInvocations int64
+	breakdown := make(map[string]FunctionSummary)
+	for _, aggregate := range minutes {
+		totalInvocations += aggregate.Invocations
+
+		summary := breakdown[aggregate.Function]
+		summary.Invocations += aggregate.Invocations
+		summary.Successful += aggregate.Successful
+		summary.Failed += aggregate.Failed
+		breakdown[aggregate.Function] = summary
+	}
+	for function, perMinute := range latencies {
+		var functionLatencies []int64
+		for _, minute := range perMinute {
+			functionLatencies = append(functionLatencies, minute...)
+		}
+		all = append(all, functionLatencies...)
+
+		latencySum := 0.0
+		for _, latency := range toSortedFloats(functionLatencies) {
+			latencySum += latency
+		}
+
+		summary := breakdown[function]
+		summary.FunctionName = function
+		summary.MeanLatencyUs = latencySum / float64(len(functionLatencies))
+		summary.P50LatencyUs = percentileOfSorted(toSortedFloats(functionLatencies), 0.50)
+		summary.P99LatencyUs = percentileOfSorted(toSortedFloats(functionLatencies), 0.99)
+		breakdown[function] = summary
+	}
+
+	if experiment.TotalDurationMinutes != 3 || experiment.TotalInvocations != totalInvocations || totalInvocations != 19 {
+		t.Errorf("Unexpected experiment aggregate %+v", experiment)
+	}
+	if experiment.OverallP50LatencyUs != percentileOfSorted(toSortedFloats(all), 0.50) ||
+		experiment.OverallP99LatencyUs != percentileOfSorted(toSortedFloats(all), 0.99) {
+
+		t.Errorf("Unexpected overall latency percentiles %+v", experiment)
+	}
+	if fmt.Sprint(experiment.FunctionBreakdown) != fmt.Sprint(breakdown) {
+		t.Errorf("Expected the function breakdown %v, got %v", breakdown, experiment.FunctionBreakdown)
+	}
+
+	// Results after the end of the experiment are ignored
+	pipeline.CloseMinute(3)
+	pipeline.Submit(createAggregationResult("f1", 3, 100, false))
+}
+
+func TestAggregationPipelineRuntimeAndMemory(t *testing.T) {
+	pipeline := NewAggregationPipeline()
+
+	for i, runtime := range []uint32{10000, 20000, 60000} {
+		result := createAggregationResult("f1", i, 1000, false)
+		result.Record.ActualDuration = runtime
+		result.Record.ActualMemoryUsage = 128 * uint32(i+1)
+		pipeline.Submit(result)
+	}
+	pipeline.Submit(createAggregationResult("f1", 2, 0, true))
+
+	summaries := pipeline.Close()
+	summary := summaries.Summaries()[0]
+
+	if summary.MeanRuntimeMs != 30 || summary.MeanMemoryMib != 256 || summary.MeanLatencyUs != 1000 {
+		t.Errorf("Expected the means of the successful invocations, got %+v", summary)
+	}
+}
+
+func TestExportSummaryCSV(t *testing.T) {
+	summaries := []FunctionSummary{
+		{FunctionName: "f1", Invocations: 10, Successful: 8, Failed: 2, MeanLatencyUs: 1234.5, P50LatencyUs: 1000, P99LatencyUs: 5000.25, MeanRuntimeMs: 12.125, MeanMemoryMib: 170.6666},
+		{FunctionName: "f2", Invocations: 3, Successful: 3, MeanLatencyUs: 80, P50LatencyUs: 80, P99LatencyUs: 90, MeanRuntimeMs: 0.5, MeanMemoryMib: 128},
+		{FunctionName: "f3"},
+	}
+
+	var buffer bytes.Buffer
+	if err := ExportSummaryCSV(summaries, &buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHeader := []string{"function_name", "total_invocations", "success_count", "error_count", "mean_latency_us",
+		"p50_us", "p99_us", "mean_runtime_ms", "mean_memory_mib"}
+	if !reflect.DeepEqual(rows[0], expectedHeader) {
+		t.Errorf("Expected the header %v, got %v", expectedHeader, rows[0])
+	}
+	if len(rows)-1 != len(summaries) {
+		t.Fatalf("Expected a row per function, got %d rows", len(rows)-1)
+	}
+
+	for i, row := range rows[1:] {
+		var parsed FunctionSummary
+		parsed.FunctionName = row[0]
+		for j, field := range []*int64{&parsed.Invocations, &parsed.Successful, &parsed.Failed} {
+			if *field, err = strconv.ParseInt(row[1+j], 10, 64); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for j, field := range []*float64{&parsed.MeanLatencyUs, &parsed.P50LatencyUs, &parsed.P99LatencyUs, &parsed.MeanRuntimeMs, &parsed.MeanMemoryMib} {
+			if *field, err = strconv.ParseFloat(row[4+j], 64); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if parsed != summaries[i] {
+			t.Errorf("Expected %+v to be read back, got %+v", summaries[i], parsed)
+		}
+	}
+}
+
+func toSortedFloats(values []int64) []float64 {
+	result := make([]float64, 0, len(values))
+	for _, value := range values {
+		result = append(result, float64(value))
+	}
+	sort.Float64s(result)
+
+	return result
+}
@@ -0,0 +1,146 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"math"
+	"sync"
+)
+
+// AnomalyReport is an invocation whose execution time deviates from the usual execution time of the function
+type AnomalyReport struct {
+	FunctionName string
+	Minute       int
+	Invocation   int
+	ZScore       float64
+	Duration     float64 // µs
+}
+
+// runningStatistics computes the mean and the variance of a sample online with Welford's algorithm
+type runningStatistics struct {
+	count int
+	mean  float64
+	m2    float64
+}
+
+func (s *runningStatistics) add(value float64) {
+	s.count++
+	delta := value - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (value - s.mean)
+}
+
+func (s *runningStatistics) stddev() float64 {
+	if s.count < 2 {
+		return 0
+	}
+
+	return math.Sqrt(s.m2 / float64(s.count-1))
+}
+
+type pendingExecution struct {
+	function   string
+	minute     int
+	invocation int
+	duration   float64
+}
+
+// AnomalyDetector flags the invocations whose execution time is more than Threshold standard deviations away from
+// the mean execution time of the function. The invocations of a minute are compared against the statistics of all
+// previous minutes at the end of the minute, so that an outlier does not mask itself. Safe for concurrent use.
+type AnomalyDetector struct {
+	Threshold float64
+
+	mutex      sync.Mutex
+	statistics map[string]*runningStatistics
+	pending    []pendingExecution
+	reports    []AnomalyReport
+}
+
+func NewAnomalyDetector(threshold float64) *AnomalyDetector {
+	return &AnomalyDetector{
+		Threshold:  threshold,
+		statistics: make(map[string]*runningStatistics),
+	}
+}
+
+// Record adds the execution time in microseconds of an invocation to the current minute
+func (a *AnomalyDetector) Record(function string, minute int, invocation int, duration float64) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.pending = append(a.pending, pendingExecution{function: function, minute: minute, invocation: invocation, duration: duration})
+}
+
+// EndMinute returns the anomalies among the invocations recorded during the minute and adds their execution times to
+// the statistics of the functions
+func (a *AnomalyDetector) EndMinute() []AnomalyReport {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	var anomalies []AnomalyReport
+	for _, execution := range a.pending {
+		statistics, ok := a.statistics[execution.function]
+		if !ok {
+			continue
+		}
+
+		stddev := statistics.stddev()
+		if stddev == 0 {
+			continue
+		}
+
+		if zScore := (execution.duration - statistics.mean) / stddev; math.Abs(zScore) > a.Threshold {
+			anomalies = append(anomalies, AnomalyReport{
+				FunctionName: execution.function,
+				Minute:       execution.minute,
+				Invocation:   execution.invocation,
+				ZScore:       zScore,
+				Duration:     execution.duration,
+			})
+		}
+	}
+
+	for _, execution := range a.pending {
+		statistics, ok := a.statistics[execution.function]
+		if !ok {
+			statistics = &runningStatistics{}
+			a.statistics[execution.function] = statistics
+		}
+		statistics.add(execution.duration)
+	}
+	a.pending = a.pending[:0]
+
+	a.reports = append(a.reports, anomalies...)
+	return anomalies
+}
+
+// Reports returns all the anomalies detected so far
+func (a *AnomalyDetector) Reports() []AnomalyReport {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return append([]AnomalyReport(nil), a.reports...)
+}
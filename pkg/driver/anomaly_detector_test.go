@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRunningStatistics(t *testing.T) {
+	statistics := runningStatistics{}
+	for _, value := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		statistics.add(value)
+	}
+
+	if statistics.mean != 5 {
+		t.Errorf("Expected a mean of 5, got %f", statistics.mean)
+	}
+	if math.Abs(statistics.stddev()-math.Sqrt(32.0/7)) > 1e-9 {
+		t.Errorf("Expected a sample standard deviation of %f, got %f", math.Sqrt(32.0/7), statistics.stddev())
+	}
+}
+
+func TestAnomalyDetector(t *testing.T) {
+	detector := NewAnomalyDetector(3)
+
+	var normal []float64
+	for i := 0; i < 100; i++ {
+		duration := 1000 + float64(i%5)*10
+		normal = append(normal, duration)
+		detector.Record("f", 0, i, duration)
+	}
+	if anomalies := detector.EndMinute(); len(anomalies) != 0 {
+		t.Fatalf("Expected no anomalies without history, got %v", anomalies)
+	}
+
+	mean, variance := 0.0, 0.0
+	for _, duration := range normal {
+		mean += duration / float64(len(normal))
+	}
+	for _, duration := range normal {
+		variance += (duration - mean) * (duration - mean) / float64(len(normal)-1)
+	}
+
+	outlier := 10 * mean
+	for i := 0; i < 100; i++ {
+		duration := normal[i]
+		if i == 42 {
+			duration = outlier
+		}
+		detector.Record("f", 1, i, duration)
+	}
+	// Another function without history
+	detector.Record("g", 1, 0, 1e6)
+
+	anomalies := detector.EndMinute()
+	if len(anomalies) != 1 {
+		t.Fatalf("Expected the outlier only, got %v", anomalies)
+	}
+
+	anomaly := anomalies[0]
+	expectedZScore := (outlier - mean) / math.Sqrt(variance)
+	if anomaly.FunctionName != "f" || anomaly.Minute != 1 || anomaly.Invocation != 42 || anomaly.Duration != outlier {
+		t.Errorf("Unexpected anomaly %+v", anomaly)
+	}
+	if math.Abs(anomaly.ZScore-expectedZScore) > 1e-6 {
+		t.Errorf("Expected a z-score of %f, got %f", expectedZScore, anomaly.ZScore)
+	}
+
+	if reports := detector.Reports(); len(reports) != 1 || reports[0] != anomaly {
+		t.Errorf("Expected the anomaly in the reports, got %v", reports)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrCertPinViolation is the cause of the invocations refused because the endpoint presented an unexpected certificate
+var ErrCertPinViolation = errors.New("certificate pin violation")
+
+// TLSPinConfig pins the certificate of the HTTPS endpoints to the given SHA-256 fingerprint of its DER encoding,
+// in hexadecimal, optionally separated by colons. The pinned certificate replaces the validation against the CAs.
+type TLSPinConfig struct {
+	PinnedCertHex string
+
+	clientsMutex sync.Mutex
+	clients      map[string]*http.Client // per scheme and host of the endpoints, reused across the invocations
+}
+
+// CertificateFingerprint returns the hexadecimal SHA-256 fingerprint of a DER-encoded certificate
+func CertificateFingerprint(der []byte) string {
+	fingerprint := sha256.Sum256(der)
+	return hex.EncodeToString(fingerprint[:])
+}
+
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+}
+
+// verifyCertificatePin compares the fingerprint of the certificate presented during a TLS handshake to the pin
+func verifyCertificatePin(state tls.ConnectionState, pinnedCertHex string) error {
+	if len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("%w: %s presented no certificate", ErrCertPinViolation, state.ServerName)
+	}
+
+	if fingerprint := CertificateFingerprint(state.PeerCertificates[0].Raw); fingerprint != normalizeFingerprint(pinnedCertHex) {
+		return fmt.Errorf("%w: %s presented the certificate %s", ErrCertPinViolation, state.ServerName, fingerprint)
+	}
+
+	return nil
+}
+
+// Client returns the client verifying the certificate of every connection it opens to the endpoint against the pin,
+// so no request is sent over a connection that violates it. The client is built once per endpoint host and keeps its
+// connections alive across the invocations.
+func (p *TLSPinConfig) Client(endpoint string) (*http.Client, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if endpointURL.Scheme != "https" {
+		return nil, fmt.Errorf("%w: %s is not an HTTPS endpoint", ErrCertPinViolation, endpoint)
+	}
+
+	p.clientsMutex.Lock()
+	defer p.clientsMutex.Unlock()
+
+	if client, ok := p.clients[endpointURL.Host]; ok {
+		return client, nil
+	}
+
+	pinnedCertHex := p.PinnedCertHex
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: true, // the pin replaces the validation against the CAs in VerifyConnection
+		VerifyConnection: func(state tls.ConnectionState) error {
+			return verifyCertificatePin(state, pinnedCertHex)
+		},
+	}
+
+	client := &http.Client{Transport: transport}
+	if p.clients == nil {
+		p.clients = make(map[string]*http.Client)
+	}
+	p.clients[endpointURL.Host] = client
+
+	return client, nil
+}
+
+// CloseIdleConnections closes the idle connections kept alive by the clients of the pin
+func (p *TLSPinConfig) CloseIdleConnections() {
+	p.clientsMutex.Lock()
+	defer p.clientsMutex.Unlock()
+
+	for _, client := range p.clients {
+		client.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestCertificatePinning(t *testing.T) {
+	var requests, connections atomic.Int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	// httptest serves a self-signed certificate, which only the pin lets through
+	fingerprint := CertificateFingerprint(server.Certificate().Raw)
+
+	invoke := func(endpoint string, pin *TLSPinConfig) *mc.ExecutionRecordBase {
+		announceDone := &sync.WaitGroup{}
+		announceDone.Add(1)
+		_, record, res := httpInvocation("", &common.Function{Name: "pinned", Endpoint: endpoint}, nil, announceDone, false, pin)
+		announceDone.Wait()
+		if res != nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		return record
+	}
+
+	pinned := &TLSPinConfig{PinnedCertHex: strings.ToUpper(fingerprint)}
+	defer pinned.CloseIdleConnections()
+	for i := 0; i < 2; i++ {
+		if record := invoke(server.URL, pinned); record.CertPinViolation || record.ConnectionTimeout {
+			t.Errorf("Expected the pinned certificate to be accepted, got %+v.", record)
+		}
+	}
+	if requests.Load() != 2 {
+		t.Fatalf("Expected the requests to reach the endpoint presenting the pinned certificate, got %d.", requests.Load())
+	}
+	if connections.Load() != 1 {
+		t.Errorf("Expected the invocations to reuse the connection of the pinned client, got %d connections.", connections.Load())
+	}
+
+	mismatched := &TLSPinConfig{PinnedCertHex: strings.Repeat("ab", 32)}
+	if record := invoke(server.URL, mismatched); !record.CertPinViolation || record.ConnectionTimeout {
+		t.Errorf("Expected the invocation to fail on a certificate pin violation, got %+v.", record)
+	}
+	if record := invoke(strings.Replace(server.URL, "https", "http", 1), mismatched); !record.CertPinViolation {
+		t.Errorf("Expected the invocation of a plain HTTP endpoint to be refused, got %+v.", record)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("No request should be sent to an endpoint violating the pin, got %d.", requests.Load()-2)
+	}
+}
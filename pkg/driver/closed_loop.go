@@ -0,0 +1,164 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// ThinkTimeConfig is the time a client of a closed-loop benchmark waits after the response to an invocation before
+// issuing the next one
+type ThinkTimeConfig struct {
+	Distribution common.IatDistribution // Exponential, Uniform or Equidistant
+	MeanMs       float64
+}
+
+func (c ThinkTimeConfig) Validate() error {
+	if c.MeanMs < 0 {
+		return fmt.Errorf("negative mean think time %f ms", c.MeanMs)
+	}
+
+	switch c.Distribution {
+	case common.Exponential, common.Uniform, common.Equidistant:
+		return nil
+	default:
+		return fmt.Errorf("unsupported think time distribution %d", c.Distribution)
+	}
+}
+
+// Draw returns a think time from the configured distribution. Uniform think times are drawn from [0, 2*MeanMs) and
+// Equidistant ones are always MeanMs.
+func (c ThinkTimeConfig) Draw(rng *rand.Rand) time.Duration {
+	var thinkTimeMs float64
+
+	switch c.Distribution {
+	case common.Exponential:
+		thinkTimeMs = rng.ExpFloat64() * c.MeanMs
+	case common.Uniform:
+		thinkTimeMs = rng.Float64() * 2 * c.MeanMs
+	default:
+		thinkTimeMs = c.MeanMs
+	}
+
+	return time.Duration(thinkTimeMs * float64(time.Millisecond))
+}
+
+// ClosedLoopDriver runs a closed-loop benchmark, as opposed to the open-loop Driver replaying the pre-generated IATs
+// of the trace. Each function is invoked by a single client, which issues the next invocation a think time after the
+// previous one completed, so that the load adapts to the response time of the platform.
+type ClosedLoopDriver struct {
+	Invoker   Invoker
+	ThinkTime ThinkTimeConfig
+	Seed      int64
+
+	clock func() time.Time
+	sleep func(time.Duration)
+}
+
+func NewClosedLoopDriver(invoker Invoker, thinkTime ThinkTimeConfig, seed int64) *ClosedLoopDriver {
+	return &ClosedLoopDriver{
+		Invoker:   invoker,
+		ThinkTime: thinkTime,
+		Seed:      seed,
+		clock:     time.Now,
+		sleep:     time.Sleep,
+	}
+}
+
+// Run invokes the functions in closed loops for the given duration and returns the records of all the invocations.
+// The invocations of each function cycle through the runtime specification generated for it, if any.
+func (d *ClosedLoopDriver) Run(functions []*common.Function, duration time.Duration) []*mc.ExecutionRecord {
+	if err := d.ThinkTime.Validate(); err != nil {
+		log.Fatalf("Invalid think time configuration: %v", err)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mutex   sync.Mutex
+		records []*mc.ExecutionRecord
+	)
+
+	for _, function := range functions {
+		wg.Add(1)
+
+		go func(function *common.Function) {
+			defer wg.Done()
+
+			functionRecords := d.runClient(function, duration)
+
+			mutex.Lock()
+			records = append(records, functionRecords...)
+			mutex.Unlock()
+		}(function)
+	}
+
+	wg.Wait()
+
+	return records
+}
+
+func (d *ClosedLoopDriver) runClient(function *common.Function, duration time.Duration) []*mc.ExecutionRecord {
+	var records []*mc.ExecutionRecord
+
+	specs := closedLoopRuntimeSpecs(function)
+	rng := newJitterRand(d.Seed, function.Name)
+
+	start := d.clock()
+	for i := 0; d.clock().Sub(start) < duration; i++ {
+		success, record := d.Invoker.Invoke(function, &specs[i%len(specs)])
+		if !success {
+			log.Debugf("Closed-loop invocation of function %s failed.", function.Name)
+		}
+		if record != nil {
+			records = append(records, record)
+		}
+
+		d.sleep(d.ThinkTime.Draw(rng))
+	}
+
+	return records
+}
+
+func closedLoopRuntimeSpecs(function *common.Function) []common.RuntimeSpecification {
+	var specs []common.RuntimeSpecification
+	if function.Specification != nil {
+		for _, minute := range function.Specification.RuntimeSpecification {
+			specs = append(specs, minute...)
+		}
+	}
+
+	if len(specs) == 0 {
+		specs = append(specs, common.RuntimeSpecification{Runtime: common.MinExecTimeMilli, Memory: common.MinMemQuotaMib})
+	}
+
+	return specs
+}
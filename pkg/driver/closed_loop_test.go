@@ -0,0 +1,140 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// newFakeClosedLoopDriver returns a driver whose clock only advances while the clients think and the think times
+// the clients waited for
+func newFakeClosedLoopDriver(thinkTime ThinkTimeConfig) (*ClosedLoopDriver, *[]time.Duration) {
+	var mutex sync.Mutex
+	now := time.Unix(0, 0)
+	var thinkTimes []time.Duration
+
+	clock := func() time.Time {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return now
+	}
+
+	d := NewClosedLoopDriver(newSimulatedInvokerWithClock(0, clock), thinkTime, 42)
+	d.clock = clock
+	d.sleep = func(duration time.Duration) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		now = now.Add(duration)
+		thinkTimes = append(thinkTimes, duration)
+	}
+
+	return d, &thinkTimes
+}
+
+func TestClosedLoopThinkTimeMean(t *testing.T) {
+	tests := []struct {
+		name         string
+		distribution common.IatDistribution
+	}{
+		{name: "exponential", distribution: common.Exponential},
+		{name: "uniform", distribution: common.Uniform},
+		{name: "equidistant", distribution: common.Equidistant},
+	}
+
+	const meanMs = 100.0
+	const duration = 20 * time.Minute
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, thinkTimes := newFakeClosedLoopDriver(ThinkTimeConfig{Distribution: test.distribution, MeanMs: meanMs})
+
+			records := d.Run([]*common.Function{{Name: "closed-loop"}}, duration)
+
+			if len(records) != len(*thinkTimes) {
+				t.Fatalf("Expected a think time after each of the %d invocations, got %d", len(records), len(*thinkTimes))
+			}
+
+			total := time.Duration(0)
+			for _, thinkTime := range *thinkTimes {
+				total += thinkTime
+			}
+			mean := float64(total.Milliseconds()) / float64(len(*thinkTimes))
+
+			if math.Abs(mean-meanMs)/meanMs > 0.03 {
+				t.Errorf("Wrong mean think time - got %.2f ms, expected %.2f ms", mean, meanMs)
+			}
+
+			expectedInvocations := float64(duration.Milliseconds()) / meanMs
+			if math.Abs(float64(len(records))-expectedInvocations)/expectedInvocations > 0.03 {
+				t.Errorf("Wrong number of invocations - got %d, expected %.0f", len(records), expectedInvocations)
+			}
+		})
+	}
+}
+
+func TestClosedLoopCyclesRuntimeSpecification(t *testing.T) {
+	d, _ := newFakeClosedLoopDriver(ThinkTimeConfig{Distribution: common.Equidistant, MeanMs: 1000})
+
+	function := &common.Function{
+		Name: "closed-loop",
+		Specification: &common.FunctionSpecification{
+			RuntimeSpecification: common.RuntimeSpecificationMatrix{
+				{{Runtime: 10, Memory: 128}, {Runtime: 20, Memory: 128}},
+				{{Runtime: 30, Memory: 256}},
+			},
+		},
+	}
+
+	records := d.Run([]*common.Function{function}, 5*time.Second)
+
+	expected := []uint32{10, 20, 30, 10, 20}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d invocations, got %d", len(expected), len(records))
+	}
+	for i, record := range records {
+		if record.RequestedDuration != expected[i]*1000 {
+			t.Errorf("Invocation %d requested %d us, expected %d us", i, record.RequestedDuration, expected[i]*1000)
+		}
+	}
+}
+
+func TestThinkTimeConfigValidate(t *testing.T) {
+	if err := (ThinkTimeConfig{Distribution: common.Exponential, MeanMs: 10}).Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := (ThinkTimeConfig{Distribution: common.CompoundPoisson, MeanMs: 10}).Validate(); err == nil {
+		t.Error("Expected an error for an unsupported distribution")
+	}
+	if err := (ThinkTimeConfig{Distribution: common.Uniform, MeanMs: -1}).Validate(); err == nil {
+		t.Error("Expected an error for a negative mean")
+	}
+}
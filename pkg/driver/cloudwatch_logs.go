@@ -0,0 +1,93 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// LambdaReport is the execution record AWS Lambda writes to CloudWatch Logs at the end of each invocation
+type LambdaReport struct {
+	RequestID        string
+	DurationMs       float64
+	BilledDurationMs float64
+	MemorySizeMB     float64
+	MaxMemoryUsedMB  float64
+	InitDurationMs   float64 // zero unless ColdStart
+	ColdStart        bool
+}
+
+var (
+	lambdaReportRegex = regexp.MustCompile(`REPORT RequestId: (\S+)\s+Duration: ([\d.]+) ms\s+Billed Duration: ([\d.]+) ms\s+Memory Size: ([\d.]+) MB\s+Max Memory Used: ([\d.]+) MB`)
+	initDurationRegex = regexp.MustCompile(`Init Duration: ([\d.]+) ms`)
+)
+
+// ParseCloudWatchLogs extracts the REPORT lines from a CloudWatch log stream of Lambda functions, ignoring any other
+// line. Invocations whose report includes an initialization duration are cold starts.
+func ParseCloudWatchLogs(logStream io.Reader) ([]LambdaReport, error) {
+	var reports []LambdaReport
+
+	scanner := bufio.NewScanner(logStream)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		match := lambdaReportRegex.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		report := LambdaReport{RequestID: match[1]}
+		for i, field := range []*float64{&report.DurationMs, &report.BilledDurationMs, &report.MemorySizeMB, &report.MaxMemoryUsedMB} {
+			value, err := strconv.ParseFloat(match[i+2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid REPORT line %d - %w", lineNumber, err)
+			}
+			*field = value
+		}
+
+		if initMatch := initDurationRegex.FindStringSubmatch(scanner.Text()); initMatch != nil {
+			initDuration, err := strconv.ParseFloat(initMatch[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid init duration on line %d - %w", lineNumber, err)
+			}
+
+			report.InitDurationMs = initDuration
+			report.ColdStart = true
+		}
+
+		reports = append(reports, report)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,81 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"strings"
+	"testing"
+)
+
+const cloudWatchLogFixture = `START RequestId: 6f1c2a80-0001 Version: $LATEST
+2024-03-01T10:00:00.123Z	6f1c2a80-0001	INFO	trace function started
+END RequestId: 6f1c2a80-0001
+REPORT RequestId: 6f1c2a80-0001	Duration: 102.35 ms	Billed Duration: 103 ms	Memory Size: 128 MB	Max Memory Used: 45 MB	Init Duration: 250.12 ms
+START RequestId: 6f1c2a80-0002 Version: $LATEST
+END RequestId: 6f1c2a80-0002
+REPORT RequestId: 6f1c2a80-0002	Duration: 98.01 ms	Billed Duration: 99 ms	Memory Size: 128 MB	Max Memory Used: 46 MB
+START RequestId: 6f1c2a80-0003 Version: $LATEST
+END RequestId: 6f1c2a80-0003
+REPORT RequestId: 6f1c2a80-0003	Duration: 1500.00 ms	Billed Duration: 1500 ms	Memory Size: 512 MB	Max Memory Used: 300 MB
+START RequestId: 6f1c2a80-0004 Version: $LATEST
+END RequestId: 6f1c2a80-0004
+REPORT RequestId: 6f1c2a80-0004	Duration: 1.50 ms	Billed Duration: 2 ms	Memory Size: 128 MB	Max Memory Used: 40 MB
+START RequestId: 6f1c2a80-0005 Version: $LATEST
+END RequestId: 6f1c2a80-0005
+REPORT RequestId: 6f1c2a80-0005	Duration: 20.00 ms	Billed Duration: 20 ms	Memory Size: 1024 MB	Max Memory Used: 512 MB
+`
+
+func TestParseCloudWatchLogs(t *testing.T) {
+	reports, err := ParseCloudWatchLogs(strings.NewReader(cloudWatchLogFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reports) != 5 {
+		t.Fatalf("Expected 5 reports, got %d.", len(reports))
+	}
+
+	first := reports[0]
+	if first.RequestID != "6f1c2a80-0001" ||
+		first.DurationMs != 102.35 ||
+		first.BilledDurationMs != 103 ||
+		first.MemorySizeMB != 128 ||
+		first.MaxMemoryUsedMB != 45 ||
+		!first.ColdStart ||
+		first.InitDurationMs != 250.12 {
+
+		t.Errorf("Unexpected cold start report: %+v", first)
+	}
+
+	for _, report := range reports[1:] {
+		if report.ColdStart || report.InitDurationMs != 0 {
+			t.Errorf("Report %s should be a warm start.", report.RequestID)
+		}
+	}
+
+	if reports[2].DurationMs != 1500 || reports[2].MemorySizeMB != 512 || reports[2].MaxMemoryUsedMB != 300 {
+		t.Errorf("Unexpected report: %+v", reports[2])
+	}
+}
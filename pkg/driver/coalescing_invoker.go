@@ -0,0 +1,103 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// CoalescingInvoker buffers the invocations of a function issued within CoalesceWindowMs of the first one and issues
+// them as a single invocation of the wrapped invoker, reducing the number of connections during bursts. Only the
+// invocations with the same runtime specification are coalesced, and each of them gets a copy of the record of the
+// shared invocation. Safe for concurrent use.
+type CoalescingInvoker struct {
+	CoalesceWindowMs int
+
+	invoker Invoker
+
+	mutex   sync.Mutex
+	pending map[coalescingKey]*coalescedInvocation
+}
+
+type coalescingKey struct {
+	function string
+	runtime  int
+	memory   int
+}
+
+// coalescedInvocation holds the invocations of a window, the result is set once done is closed
+type coalescedInvocation struct {
+	function    *common.Function
+	runtimeSpec *common.RuntimeSpecification
+	done        chan struct{}
+
+	success bool
+	record  *mc.ExecutionRecord
+}
+
+func NewCoalescingInvoker(invoker Invoker, coalesceWindowMs int) *CoalescingInvoker {
+	return &CoalescingInvoker{
+		CoalesceWindowMs: coalesceWindowMs,
+		invoker:          invoker,
+		pending:          make(map[coalescingKey]*coalescedInvocation),
+	}
+}
+
+// Invoke joins the invocation of the current window for the function and waits for it to complete
+func (c *CoalescingInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	key := coalescingKey{function: function.Name, runtime: runtimeSpec.Runtime, memory: runtimeSpec.Memory}
+
+	c.mutex.Lock()
+	invocation, ok := c.pending[key]
+	if !ok {
+		invocation = &coalescedInvocation{function: function, runtimeSpec: runtimeSpec, done: make(chan struct{})}
+		c.pending[key] = invocation
+		time.AfterFunc(time.Duration(c.CoalesceWindowMs)*time.Millisecond, func() { c.flush(key) })
+	}
+	c.mutex.Unlock()
+
+	<-invocation.done
+	if invocation.record == nil {
+		return invocation.success, nil
+	}
+
+	record := *invocation.record
+	return invocation.success, &record
+}
+
+// flush issues the invocation of the window that just ended
+func (c *CoalescingInvoker) flush(key coalescingKey) {
+	c.mutex.Lock()
+	invocation := c.pending[key]
+	delete(c.pending, key)
+	c.mutex.Unlock()
+
+	invocation.success, invocation.record = c.invoker.Invoke(invocation.function, invocation.runtimeSpec)
+	close(invocation.done)
+}
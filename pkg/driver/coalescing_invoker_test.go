@@ -0,0 +1,102 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// countingInvoker completes each invocation successfully and counts them per runtime specification
+type countingInvoker struct {
+	mutex       sync.Mutex
+	invocations map[common.RuntimeSpecification]int
+}
+
+func (c *countingInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.invocations[*runtimeSpec]++
+	return true, &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{Instance: function.Name}}
+}
+
+func (c *countingInvoker) count(runtimeSpec common.RuntimeSpecification) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.invocations[runtimeSpec]
+}
+
+func TestCoalescingInvoker(t *testing.T) {
+	inner := &countingInvoker{invocations: make(map[common.RuntimeSpecification]int)}
+	invoker := NewCoalescingInvoker(inner, 100)
+
+	other := common.RuntimeSpecification{Runtime: testRuntimeSpecs.Runtime + 1, Memory: testRuntimeSpecs.Memory}
+
+	const invocations = 20
+	var failed atomic.Int32
+	records := make([]*mc.ExecutionRecord, invocations)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < invocations; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			runtimeSpec := &testRuntimeSpecs
+			if i%2 == 1 {
+				runtimeSpec = &other
+			}
+
+			success, record := invoker.Invoke(&testFunction, runtimeSpec)
+			if !success || record.Instance != testFunction.Name {
+				failed.Add(1)
+			}
+			records[i] = record
+		}(i)
+	}
+	wg.Wait()
+
+	if failed.Load() != 0 {
+		t.Errorf("Expected all the invocations to succeed, %d failed.", failed.Load())
+	}
+	if inner.count(testRuntimeSpecs) != 1 || inner.count(other) != 1 {
+		t.Errorf("Expected 1 invocation per runtime specification, got %d and %d.",
+			inner.count(testRuntimeSpecs), inner.count(other))
+	}
+	if records[0] == records[2] {
+		t.Error("Expected each coalesced invocation to get its own record.")
+	}
+
+	// The next window is issued in another invocation
+	if success, _ := invoker.Invoke(&testFunction, &testRuntimeSpecs); !success || inner.count(testRuntimeSpecs) != 2 {
+		t.Errorf("Expected a second invocation, got %d.", inner.count(testRuntimeSpecs))
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// loaderConfiguration returns the configuration the invocations are issued with, which is the latest hot-reloaded
+// one if the configuration file is watched
+func (d *Driver) loaderConfiguration() *config.LoaderConfiguration {
+	if cfg := d.reloadedConfiguration.Load(); cfg != nil {
+		return cfg
+	}
+
+	return d.Configuration.LoaderConfiguration
+}
+
+// WatchConfiguration reloads the loader configuration whenever the file is written. Only changes of
+// config.HotReloadableFields are applied to the running experiment, while reloads changing any other field are
+// rejected as a whole.
+func (d *Driver) WatchConfiguration(path string) (*common.ConfigWatcher, error) {
+	return common.NewConfigWatcher(path, func() error {
+		return d.reloadConfiguration(path)
+	})
+}
+
+func (d *Driver) reloadConfiguration(path string) error {
+	reloaded, err := config.LoadConfigurationFile(path)
+	if err != nil {
+		return err
+	}
+
+	diffs, err := config.ValidateReload(*d.loaderConfiguration(), reloaded)
+	if err != nil {
+		return err
+	}
+
+	d.reloadedConfiguration.Store(&reloaded)
+	for _, diff := range diffs {
+		log.Infof("Reloaded %s: %s -> %s", diff.Field, diff.OldValue, diff.NewValue)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func writeConfigurationFile(t *testing.T, path string, cfg config.LoaderConfiguration) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitForConfiguration(d *Driver, condition func(*config.LoaderConfiguration) bool, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition(d.loaderConfiguration()) {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return false
+}
+
+func TestWatchConfiguration(t *testing.T) {
+	cfg := createFakeLoaderConfiguration()
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfigurationFile(t, path, *cfg)
+
+	d := NewDriver(&DriverConfiguration{LoaderConfiguration: cfg})
+	watcher, err := d.WatchConfiguration(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	reloaded := *cfg
+	reloaded.GRPCFunctionTimeoutSeconds = cfg.GRPCFunctionTimeoutSeconds + 5
+	writeConfigurationFile(t, path, reloaded)
+
+	if !waitForConfiguration(d, func(c *config.LoaderConfiguration) bool {
+		return c.GRPCFunctionTimeoutSeconds == reloaded.GRPCFunctionTimeoutSeconds
+	}, time.Second) {
+		t.Fatal("Expected the function timeout to be reloaded within a second.")
+	}
+
+	// The whole reload is rejected if it changes the trace, even along with hot-reloadable fields
+	rejected := reloaded
+	rejected.TracePath = "data/traces/other"
+	rejected.GRPCFunctionTimeoutSeconds = reloaded.GRPCFunctionTimeoutSeconds + 5
+	writeConfigurationFile(t, path, rejected)
+
+	if waitForConfiguration(d, func(c *config.LoaderConfiguration) bool {
+		return c.TracePath != cfg.TracePath || c.GRPCFunctionTimeoutSeconds != reloaded.GRPCFunctionTimeoutSeconds
+	}, time.Second) {
+		t.Error("Expected the change of the trace to be rejected mid-run.")
+	}
+	if d.Configuration.LoaderConfiguration.GRPCFunctionTimeoutSeconds != cfg.GRPCFunctionTimeoutSeconds {
+		t.Error("Expected the initial configuration to stay unchanged.")
+	}
+}
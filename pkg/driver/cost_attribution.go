@@ -0,0 +1,147 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	log "github.com/sirupsen/logrus"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// AWS Lambda on-demand pricing for x86 functions in us-east-1, in USD cents
+const (
+	awsCentsPerGBSecond = 0.00166667
+	awsCentsPerRequest  = 0.00002
+)
+
+// EstimateAWSCost returns the AWS Lambda charges in USD cents for the given compute time and number of requests
+func EstimateAWSCost(gbSeconds float64, invocations int64) float64 {
+	return gbSeconds*awsCentsPerGBSecond + float64(invocations)*awsCentsPerRequest
+}
+
+// billedGBSeconds returns the compute time AWS Lambda charges for an invocation, whose duration is rounded up to the
+// next millisecond
+func billedGBSeconds(record *mc.ExecutionRecord) float64 {
+	billedMilliseconds := math.Ceil(float64(record.ActualDuration) / 1e3)
+
+	return billedMilliseconds / 1e3 * float64(record.ActualMemoryUsage) / 1024
+}
+
+// CostDimension assigns an invocation to the group it is attributed to
+type CostDimension func(record *mc.ExecutionRecord) string
+
+// ByFunction groups the invocations by the function they invoked
+func ByFunction(record *mc.ExecutionRecord) string {
+	return record.Instance
+}
+
+// ByMinuteWindow groups the invocations by the minute (or second, depending on the trace granularity) of the
+// experiment they were issued in
+func ByMinuteWindow(record *mc.ExecutionRecord) string {
+	window, _, found := strings.Cut(record.InvocationID, ".")
+	if !found {
+		return "unknown"
+	}
+
+	return window
+}
+
+// ByLabel attributes all invocations to the same label, e.g. the IAT distribution of the experiment
+func ByLabel(label string) CostDimension {
+	return func(*mc.ExecutionRecord) string {
+		return label
+	}
+}
+
+type CostAttributionRow struct {
+	Dimension         string  `csv:"Dimension"`
+	TotalInvocations  int64   `csv:"TotalInvocations"`
+	TotalGBSeconds    float64 `csv:"TotalGBSeconds"`
+	EstimatedUSDCents float64 `csv:"EstimatedUSDCents"`
+}
+
+// CostAttributionReport breaks down the estimated AWS spend of an experiment by a dimension of the invocations
+type CostAttributionReport struct {
+	Rows []CostAttributionRow
+}
+
+// NewCostAttributionReport groups the invocations along the dimension, ordering the groups by name
+func NewCostAttributionReport(records []*mc.ExecutionRecord, dimension CostDimension) *CostAttributionReport {
+	groups := make(map[string]*CostAttributionRow)
+	for _, record := range records {
+		name := dimension(record)
+
+		row, ok := groups[name]
+		if !ok {
+			row = &CostAttributionRow{Dimension: name}
+			groups[name] = row
+		}
+
+		row.TotalInvocations++
+		row.TotalGBSeconds += billedGBSeconds(record)
+	}
+
+	report := &CostAttributionReport{}
+	for _, row := range groups {
+		row.EstimatedUSDCents = EstimateAWSCost(row.TotalGBSeconds, row.TotalInvocations)
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		return report.Rows[i].Dimension < report.Rows[j].Dimension
+	})
+
+	return report
+}
+
+// WriteCSV writes the report with one row per group
+func (r *CostAttributionReport) WriteCSV(w io.Writer) error {
+	return gocsv.Marshal(r.Rows, w)
+}
+
+// writeCostAttributionReport estimates the AWS spend of each function from the results of the experiment
+func (d *Driver) writeCostAttributionReport() {
+	records, err := d.readExecutionRecords()
+	if err != nil {
+		log.Errorf("Failed to read the results for the cost attribution report: %s", err)
+		return
+	}
+
+	file, err := os.Create(d.outputFilename("cost"))
+	if err != nil {
+		log.Errorf("Failed to create the cost attribution report: %s", err)
+		return
+	}
+	defer file.Close()
+
+	if err = NewCostAttributionReport(records, ByFunction).WriteCSV(file); err != nil {
+		log.Errorf("Failed to write the cost attribution report: %s", err)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func createCostRecord(function string, invocationID string, durationMicroSec uint32, memoryMiB uint32) *mc.ExecutionRecord {
+	return &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			Instance:       function,
+			InvocationID:   invocationID,
+			ActualDuration: durationMicroSec,
+		},
+		ActualMemoryUsage: memoryMiB,
+	}
+}
+
+func TestEstimateAWSCost(t *testing.T) {
+	// 1M invocations of 1 second with 1 GB cost $16.67 of compute and $0.20 of requests
+	if cents := EstimateAWSCost(1e6, 1e6); math.Abs(cents-1686.67) > 1e-6 {
+		t.Errorf("Unexpected cost: %f cents", cents)
+	}
+}
+
+func TestCostAttributionReport(t *testing.T) {
+	records := []*mc.ExecutionRecord{
+		createCostRecord("f1", "min0.inv0", 1000000, 1024), // 1 GB-s
+		createCostRecord("f1", "min1.inv0", 999001, 1024),  // rounded up to 1 GB-s
+		createCostRecord("f2", "min0.inv1", 500000, 512),   // 0.25 GB-s
+		createCostRecord("f2", "min1.inv1", 0, 512),        // failed invocation, billed for the request only
+	}
+
+	expected := map[string][]CostAttributionRow{
+		"function": {
+			{Dimension: "f1", TotalInvocations: 2, TotalGBSeconds: 2, EstimatedUSDCents: 2*0.00166667 + 2*0.00002},
+			{Dimension: "f2", TotalInvocations: 2, TotalGBSeconds: 0.25, EstimatedUSDCents: 0.25*0.00166667 + 2*0.00002},
+		},
+		"minute": {
+			{Dimension: "min0", TotalInvocations: 2, TotalGBSeconds: 1.25, EstimatedUSDCents: 1.25*0.00166667 + 2*0.00002},
+			{Dimension: "min1", TotalInvocations: 2, TotalGBSeconds: 1, EstimatedUSDCents: 0.00166667 + 2*0.00002},
+		},
+		"label": {
+			{Dimension: "exponential", TotalInvocations: 4, TotalGBSeconds: 2.25, EstimatedUSDCents: 2.25*0.00166667 + 4*0.00002},
+		},
+	}
+	dimensions := map[string]CostDimension{
+		"function": ByFunction,
+		"minute":   ByMinuteWindow,
+		"label":    ByLabel("exponential"),
+	}
+
+	for name, dimension := range dimensions {
+		report := NewCostAttributionReport(records, dimension)
+
+		if len(report.Rows) != len(expected[name]) {
+			t.Fatalf("Dimension %s: expected %d groups, got %d.", name, len(expected[name]), len(report.Rows))
+		}
+		for i, row := range report.Rows {
+			e := expected[name][i]
+			if row.Dimension != e.Dimension || row.TotalInvocations != e.TotalInvocations ||
+				math.Abs(row.TotalGBSeconds-e.TotalGBSeconds) > 1e-9 || math.Abs(row.EstimatedUSDCents-e.EstimatedUSDCents) > 1e-9 {
+
+				t.Errorf("Dimension %s: expected %+v, got %+v.", name, e, row)
+			}
+		}
+	}
+}
+
+func TestCostAttributionReportCSV(t *testing.T) {
+	report := NewCostAttributionReport([]*mc.ExecutionRecord{createCostRecord("f1", "min0.inv0", 1000000, 1024)}, ByFunction)
+
+	var output strings.Builder
+	if err := report.WriteCSV(&output); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 || lines[0] != "Dimension,TotalInvocations,TotalGBSeconds,EstimatedUSDCents" || !strings.HasPrefix(lines[1], "f1,1,1,") {
+		t.Errorf("Unexpected CSV:\n%s", output.String())
+	}
+}
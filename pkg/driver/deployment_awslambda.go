@@ -4,6 +4,7 @@ import (
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
 	"os/exec"
 	"strings"
 	"sync"
@@ -11,51 +12,139 @@ import (
 )
 
 // DeployFunctionsAWSLambda deploys functions to AWS Lambda using the Serverless.com framework, with additional dependencies on AWS CLI, Docker
-func DeployFunctionsAWSLambda(functions []*common.Function) {
+func DeployFunctionsAWSLambda(functions []*common.Function, handler string, idempotencyTable string, vpc *VPCConfig, metadata *ExperimentMetadata) {
 	const provider = "aws"
 
 	// Check if all required dependencies are installed, verify that AWS account is clean and ready for deployment
 	awsAccountId, functionGroups := initAWSLambda(functions, provider)
 
 	// Create all the serverless.yml files
-	createSlsConfigFiles(functionGroups, provider, awsAccountId)
+	createSlsConfigFiles(functionGroups, provider, awsAccountId, handler, idempotencyTable, vpc, metadata)
 
-	// Use goroutines to deploy functions in parallel, and ensure all finishes
-	// Due to CPU and memory constraints, by default, we will deploy 2 serverless.yml files in parallel and wait for them to finish before deploying the next 2
-	var wg sync.WaitGroup
-	var counter uint64 = 0
+	// Deploy the serverless.yml files in parallel, undeploying the successful ones if any of them fails
+	// Due to CPU and memory constraints, by default, we will deploy 2 serverless.yml files in parallel
 	parallelDeployment := 2
 
-	for i := 0; i < len(functionGroups); {
-		for parallelIndex := 0; parallelIndex < parallelDeployment; parallelIndex++ {
-			if i < len(functionGroups) {
-				wg.Add(1)
-				go func(functionGroup []*common.Function, index int) {
-					defer wg.Done()
-					log.Debugf("Deploying serverless-%d.yml", index)
-					// Deploy serverless functions and update the function endpoints
-					functionToURLMapping := DeployServerless(index)
-
-					if functionToURLMapping == nil {
-						CleanAWSLambda(functions)                               // Clean up all deployed functions before exiting
-						log.Fatalf("Failed to deploy serverless-%d.yml", index) // Immediately terminate deployment for fast feedback
-					} else {
-						atomic.AddUint64(&counter, 1)
-						for i := 0; i < len(functionGroup); i++ {
-							functionGroup[i].Endpoint = functionToURLMapping[i]
-							log.Debugf("Function %s set to %s", functionGroup[i].Name, functionGroup[i].Endpoint)
-						}
-					}
-				}(functionGroups[i], i)
-				i += 1
-			}
+	indices := make([]int, len(functionGroups))
+	for i := range indices {
+		indices[i] = i
+	}
+
+	endpoints, err := DeployWithRollback(indices, parallelDeployment)
+	if err != nil {
+		cleanAWSElasticContainerRegistry()
+		log.Fatal(err) // Immediately terminate deployment for fast feedback
+	}
+
+	for index, functionGroup := range functionGroups {
+		for i := 0; i < len(functionGroup); i++ {
+			functionGroup[i].Endpoint = endpoints[index][i]
+			log.Debugf("Function %s set to %s", functionGroup[i].Name, functionGroup[i].Endpoint)
 		}
-		wg.Wait()
 	}
 
 	log.Debugf("Deployed all %d serverless.yml files", len(functionGroups))
 }
 
+// vpcOfConfiguration returns the VPC the AWS Lambda functions are deployed in, or nil if none is configured
+func vpcOfConfiguration(cfg *config.LoaderConfiguration) *VPCConfig {
+	if len(cfg.VPCSecurityGroupIDs) == 0 && len(cfg.VPCSubnetIDs) == 0 {
+		return nil
+	}
+
+	return &VPCConfig{
+		SecurityGroupIDs: cfg.VPCSecurityGroupIDs,
+		SubnetIDs:        cfg.VPCSubnetIDs,
+	}
+}
+
+// deployServerlessIndex and cleanServerlessIndex deploy and remove serverless-<index>.yml, replaced in tests
+var deployServerlessIndex = func(index int) (map[int]string, error) {
+	return DeployServerlessWithRetry(index, deploymentAttempts, DefaultRetryBackoff)
+}
+var cleanServerlessIndex = CleanServerless
+
+// DeployWithRollback deploys the serverless-<index>.yml files of the given indices, with at most parallelism
+// deployments at a time, and returns the function URLs per index. If any deployment fails, no further deployment is
+// started and the successfully deployed indices are removed again in the reverse order of their deployment. The
+// failed indices, which may have left a partially created stack behind, and those never deployed are removed as
+// well, so that neither resources in the cloud nor serverless-<index>.yml files are left behind.
+func DeployWithRollback(indices []int, parallelism int) (map[int]map[int]string, error) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	var deployErr error
+	var deployed, failedIndices []int
+	started := make(map[int]bool)
+	endpoints := make(map[int]map[int]string)
+
+	semaphore := make(chan struct{}, parallelism)
+	for _, index := range indices {
+		semaphore <- struct{}{}
+
+		mutex.Lock()
+		failed := deployErr != nil
+		mutex.Unlock()
+		if failed {
+			<-semaphore
+			break
+		}
+
+		started[index] = true
+
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			log.Debugf("Deploying serverless-%d.yml", index)
+			functionToURL, err := deployServerlessIndex(index)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if err != nil {
+				if deployErr == nil {
+					deployErr = fmt.Errorf("failed to deploy serverless-%d.yml - %w", index, err)
+				}
+				failedIndices = append(failedIndices, index)
+				return
+			}
+
+			deployed = append(deployed, index)
+			endpoints[index] = functionToURL
+		}(index)
+	}
+	wg.Wait()
+
+	if deployErr == nil {
+		return endpoints, nil
+	}
+
+	log.Warnf("Rolling back %d successful deployments", len(deployed))
+	toClean := make([]int, 0, len(indices))
+	for i := len(deployed) - 1; i >= 0; i-- {
+		toClean = append(toClean, deployed[i])
+	}
+	toClean = append(toClean, failedIndices...)
+	for _, index := range indices {
+		if !started[index] {
+			toClean = append(toClean, index)
+		}
+	}
+
+	for _, index := range toClean {
+		if !cleanServerlessIndex(index) {
+			log.Errorf("Failed to roll back serverless-%d.yml", index)
+		}
+	}
+
+	return nil, deployErr
+}
+
 // CleanAWSLambda cleans up the AWS Lambda deployment environment by deleting all serverless.yml files and the ECR private repository
 func CleanAWSLambda(functions []*common.Function) {
 	cleanAWSElasticContainerRegistry()
@@ -145,7 +234,7 @@ func initAWSLambda(functions []*common.Function, provider string) (string, [][]*
 	// Clean up previous resources, if any
 	log.Debug("Checking and cleaning up previous AWS Lambda resources")
 	functionGroups := separateFunctions(functions)
-	createSlsConfigFiles(functionGroups, provider, "") // serverless.yml files created do not require AWS account ID
+	createSlsConfigFiles(functionGroups, provider, "", "", "", nil, nil) // serverless.yml files created do not require AWS account ID
 	CleanAWSLambda(functions)
 	cleanAWSCloudWatchLogGroups() // Clean up CloudWatch log groups (in rare occasions, log groups persist even after `sls remove`)
 
@@ -232,7 +321,7 @@ func obtainAWSAccountId() string {
 // separateFunctions splits functions into groups of 60 due to AWS CloudFormation template resource limit (500 resources per template) and IAM maximum policy size (10240 bytes)
 func separateFunctions(functions []*common.Function) [][]*common.Function {
 	var functionGroups [][]*common.Function
-	groupSize := 60
+	groupSize := maxFunctionsPerServerless
 
 	for i := 0; i < len(functions); i += groupSize {
 		end := i + groupSize
@@ -245,8 +334,8 @@ func separateFunctions(functions []*common.Function) [][]*common.Function {
 	return functionGroups
 }
 
-// createSlsConfigFiles creates serverless.yml files for each group of functions
-func createSlsConfigFiles(functionGroups [][]*common.Function, provider string, awsAccountId string) {
+// createSlsConfigFiles creates serverless.yml files for each group of functions, placing the functions in the VPC (if provided) and noting relevant deployment settings in the experiment metadata (if provided)
+func createSlsConfigFiles(functionGroups [][]*common.Function, provider string, awsAccountId string, handler string, idempotencyTable string, vpc *VPCConfig, metadata *ExperimentMetadata) {
 	for i := 0; i < len(functionGroups); i++ {
 		log.Debugf("Creating serverless-%d.yml", i)
 		serverless := Serverless{}
@@ -254,8 +343,21 @@ func createSlsConfigFiles(functionGroups [][]*common.Function, provider string,
 
 		for j := 0; j < len(functionGroups[i]); j++ {
 			serverless.AddFunctionConfig(functionGroups[i][j], provider, awsAccountId)
+
+			if vpc != nil {
+				if err := serverless.SetFunctionVPC(functionGroups[i][j].Name, *vpc); err != nil {
+					log.Fatal(err)
+				}
+			}
 		}
 
-		serverless.CreateServerlessConfigFile(i)
+		if err := serverless.SetAWSLambdaHandler(handler); err != nil {
+			log.Fatal(err)
+		}
+		serverless.SetIdempotencyTable(idempotencyTable)
+		serverless.annotateExperimentMetadata(metadata)
+		if err := serverless.CreateServerlessConfigFile(i); err != nil {
+			log.Fatal(err)
+		}
 	}
 }
@@ -0,0 +1,99 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func mockServerlessIndexDeployment(t *testing.T, deploy func(index int) (map[int]string, error), clean func(index int) bool) {
+	originalDeploy, originalClean := deployServerlessIndex, cleanServerlessIndex
+	deployServerlessIndex, cleanServerlessIndex = deploy, clean
+
+	t.Cleanup(func() {
+		deployServerlessIndex, cleanServerlessIndex = originalDeploy, originalClean
+	})
+}
+
+func TestDeployWithRollback(t *testing.T) {
+	var mutex sync.Mutex
+	var cleaned []int
+
+	mockServerlessIndexDeployment(t,
+		func(index int) (map[int]string, error) {
+			if index == 2 {
+				return nil, errors.New("stack creation failed")
+			}
+			return map[int]string{0: "https://example.com"}, nil
+		},
+		func(index int) bool {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			cleaned = append(cleaned, index)
+			return true
+		},
+	)
+
+	endpoints, err := DeployWithRollback([]int{0, 1, 2, 3, 4}, 1)
+	if err == nil {
+		t.Fatal("Expected the failed deployment to be reported.")
+	}
+	if endpoints != nil {
+		t.Errorf("Expected no endpoints after a rollback, got %v.", endpoints)
+	}
+
+	if len(cleaned) != 5 || cleaned[0] != 1 || cleaned[1] != 0 {
+		t.Fatalf("Expected the two successful deployments to be removed first in reverse order, got %v.", cleaned)
+	}
+	if cleaned[2] != 2 {
+		t.Errorf("Expected the failed deployment to be removed after the successful ones, got %v.", cleaned)
+	}
+	if (cleaned[3] != 3 || cleaned[4] != 4) && (cleaned[3] != 4 || cleaned[4] != 3) {
+		t.Errorf("Expected the deployments never started to be removed, got %v.", cleaned)
+	}
+}
+
+func TestDeployWithRollbackSucceeds(t *testing.T) {
+	mockServerlessIndexDeployment(t,
+		func(index int) (map[int]string, error) {
+			return map[int]string{0: "https://example.com"}, nil
+		},
+		func(index int) bool {
+			t.Errorf("serverless-%d.yml should not be removed after a successful deployment.", index)
+			return true
+		},
+	)
+
+	endpoints, err := DeployWithRollback([]int{0, 1, 2, 3, 4}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 5 {
+		t.Errorf("Expected the endpoints of 5 deployments, got %d.", len(endpoints))
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"gopkg.in/yaml.v3"
+)
+
+// cloudRunConfigIndex is the index of the serverless-<index>.yml file listing the functions deployed to Cloud Run
+const cloudRunConfigIndex = 0
+
+// DeployFunctionsCloudRun deploys the functions to Google Cloud Run with the Google Cloud CLI, one service per function
+// running the Node.js trace function
+func DeployFunctionsCloudRun(functions []*common.Function) {
+	const provider = "cloudrun"
+
+	serverless := Serverless{}
+	serverless.CreateHeader(cloudRunConfigIndex, provider)
+	for _, function := range functions {
+		serverless.AddFunctionConfig(function, provider, "")
+	}
+	if err := serverless.CreateServerlessConfigFile(cloudRunConfigIndex); err != nil {
+		log.Fatal(err)
+	}
+
+	functionToURL := DeployCloudRun(cloudRunConfigIndex)
+	if functionToURL == nil {
+		log.Fatal("Failed to deploy the functions to Cloud Run.")
+	}
+
+	for _, function := range functions {
+		endpoint, ok := functionToURL[function.Name]
+		if !ok {
+			log.Fatalf("No URL reported for function %s deployed to Cloud Run.", function.Name)
+		}
+
+		function.Endpoint = endpoint
+		log.Debugf("Function %s set to %s", function.Name, function.Endpoint)
+	}
+}
+
+// CleanCloudRun deletes the Cloud Run services listed in serverless-<index>.yml and then the file itself
+func CleanCloudRun() {
+	path := fmt.Sprintf("./serverless-%d.yml", cloudRunConfigIndex)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Errorf("Failed to read %s: %v", path, err)
+		return
+	}
+
+	var s Serverless
+	if err = yaml.Unmarshal(data, &s); err != nil {
+		log.Errorf("Failed to parse %s: %v", path, err)
+		return
+	}
+
+	for _, f := range s.Functions {
+		cmd := exec.Command("gcloud", "run", "services", "delete", f.Name, "--region", s.Provider.Region, "--quiet")
+		if stdoutStderr, err := cmd.CombinedOutput(); err != nil {
+			log.Errorf("Failed to delete Cloud Run service %s: %v\n%s", f.Name, err, stdoutStderr)
+		}
+	}
+
+	if err = os.Remove(path); err != nil {
+		log.Errorf("Failed to delete %s: %v", path, err)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RetryBackoff is an exponential back-off between deployment attempts
+type RetryBackoff struct {
+	InitialMs  int
+	MaxMs      int
+	Multiplier float64
+}
+
+// deploymentAttempts is the number of attempts to deploy each serverless.yml file of an experiment
+const deploymentAttempts = 3
+
+// DefaultRetryBackoff waits 1s, 2s, 4s, ... and at most 30s between the attempts
+var DefaultRetryBackoff = RetryBackoff{InitialMs: 1000, MaxMs: 30_000, Multiplier: 2}
+
+// delay returns the back-off before the given retry (starting at 1)
+func (b RetryBackoff) delay(retry int) time.Duration {
+	delayMs := float64(b.InitialMs) * math.Pow(b.Multiplier, float64(retry-1))
+	if b.MaxMs > 0 {
+		delayMs = math.Min(delayMs, float64(b.MaxMs))
+	}
+
+	return time.Duration(delayMs) * time.Millisecond
+}
+
+// retrySleep waits between deployment attempts, replaced in tests
+var retrySleep = time.Sleep
+
+// isTransientDeploymentError reports whether a failed deployment may succeed if retried. Errors of the Serverless
+// framework itself (e.g., an invalid serverless.yml) are permanent, whereas timeouts are transient.
+func isTransientDeploymentError(err error) bool {
+	var deployErr *slsDeployError
+	if !errors.As(err, &deployErr) {
+		return false
+	}
+
+	output := strings.ToLower(deployErr.output)
+	if strings.Contains(deployErr.output, "Serverless Error") {
+		return false
+	}
+
+	return strings.Contains(output, "timeout")
+}
+
+// DeployServerlessWithRetry deploys serverless-<index>.yml like DeployServerless, retrying transient failures up
+// to maxAttempts attempts in total with the given back-off
+func DeployServerlessWithRetry(index int, maxAttempts int, backoff RetryBackoff) (map[int]string, error) {
+	path := fmt.Sprintf("./serverless-%d.yml", index)
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var functionToURL map[int]string
+		if functionToURL, err = deployServerlessFile(path); err == nil {
+			return functionToURL, nil
+		}
+
+		if !isTransientDeploymentError(err) {
+			return nil, err
+		}
+
+		if attempt < maxAttempts {
+			delay := backoff.delay(attempt)
+			log.Warnf("Deployment of %s failed transiently (attempt %d/%d), retrying in %v", path, attempt, maxAttempts, delay)
+			retrySleep(delay)
+		}
+	}
+
+	return nil, fmt.Errorf("giving up after %d attempts - %w", maxAttempts, err)
+}
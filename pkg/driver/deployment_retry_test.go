@@ -0,0 +1,103 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// mockSlsDeploy replaces `sls deploy` with the given outputs, one per attempt
+func mockSlsDeploy(t *testing.T, outputs []string, failures int) *int {
+	attempts := 0
+
+	originalDeploy, originalSleep := runSlsDeploy, retrySleep
+	runSlsDeploy = func(path string) ([]byte, error) {
+		attempts++
+		output := outputs[attempts-1]
+		if attempts <= failures {
+			return []byte(output), errors.New("exit status 1")
+		}
+		return []byte(output), nil
+	}
+	retrySleep = func(time.Duration) {}
+
+	t.Cleanup(func() {
+		runSlsDeploy, retrySleep = originalDeploy, originalSleep
+	})
+
+	return &attempts
+}
+
+func TestDeployServerlessWithRetryRecoversFromTransientFailures(t *testing.T) {
+	attempts := mockSlsDeploy(t, []string{
+		"Error: connect ETIMEDOUT - request timeout",
+		"Error: socket timeout",
+		"endpoint: https://abc.lambda-url.us-east-1.on.aws/",
+	}, 2)
+
+	var delays []time.Duration
+	retrySleep = func(delay time.Duration) {
+		delays = append(delays, delay)
+	}
+
+	functionToURL, err := DeployServerlessWithRetry(0, 5, RetryBackoff{InitialMs: 100, MaxMs: 150, Multiplier: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d.", *attempts)
+	}
+	if functionToURL[0] != "https://abc.lambda-url.us-east-1.on.aws/" {
+		t.Errorf("Unexpected function URLs %v.", functionToURL)
+	}
+	if len(delays) != 2 || delays[0] != 100*time.Millisecond || delays[1] != 150*time.Millisecond {
+		t.Errorf("Unexpected back-off delays %v.", delays)
+	}
+}
+
+func TestDeployServerlessWithRetryFailsOnPermanentErrors(t *testing.T) {
+	attempts := mockSlsDeploy(t, []string{"Serverless Error ----- Configuration error: unrecognized property timeout"}, 1)
+
+	if _, err := DeployServerlessWithRetry(0, 5, DefaultRetryBackoff); err == nil {
+		t.Fatal("Expected the permanent failure to be reported.")
+	}
+	if *attempts != 1 {
+		t.Errorf("Expected a single attempt for a permanent failure, got %d.", *attempts)
+	}
+}
+
+func TestDeployServerlessWithRetryGivesUp(t *testing.T) {
+	attempts := mockSlsDeploy(t, []string{"timeout", "timeout", "timeout"}, 3)
+
+	if _, err := DeployServerlessWithRetry(0, 3, DefaultRetryBackoff); err == nil {
+		t.Fatal("Expected the deployment to fail after the last attempt.")
+	}
+	if *attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d.", *attempts)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MaxAllowedSkewMs is the clock offset of a peer above which ClockSyncCheck warns that the invocations of the
+// distributed experiment may not be dispatched consistently
+var MaxAllowedSkewMs int64 = 50
+
+// clockSyncRounds is the number of timestamp exchanges with each peer. The offset is computed from the exchange with
+// the shortest round trip, as it is the least affected by queuing delays.
+const clockSyncRounds = 4
+
+const clockSyncTimeout = time.Second
+
+var errClockSyncMismatch = errors.New("clock sync response does not match the request")
+
+// ClockSyncTransport exchanges the timestamps of a clock synchronization round trip with a peer
+type ClockSyncTransport interface {
+	// Exchange sends the transmit time of the request to the peer and returns the times at which the peer received
+	// the request and transmitted the response, as read from the clock of the peer
+	Exchange(peer string, transmit time.Time) (peerReceive time.Time, peerTransmit time.Time, err error)
+}
+
+// clockSyncTransport is replaced by the tests to exchange timestamps with in-process peers
+var clockSyncTransport ClockSyncTransport = udpClockSyncTransport{}
+
+// ClockSyncCheck estimates the offset of the clock of each peer (host:port running ServeClockSync) relative to the
+// local clock the same way NTP does. A positive offset means the clock of the peer is ahead.
+func ClockSyncCheck(peers []string) (map[string]time.Duration, error) {
+	offsets := make(map[string]time.Duration, len(peers))
+
+	for _, peer := range peers {
+		offset, err := clockOffset(clockSyncTransport, peer)
+		if err != nil {
+			return nil, fmt.Errorf("clock sync with %s failed: %w", peer, err)
+		}
+
+		offsets[peer] = offset
+		if offset.Abs() > time.Duration(MaxAllowedSkewMs)*time.Millisecond {
+			log.Warnf("Clock of %s is off by %v, which exceeds the maximum allowed skew of %dms.", peer, offset, MaxAllowedSkewMs)
+		}
+	}
+
+	return offsets, nil
+}
+
+func clockOffset(transport ClockSyncTransport, peer string) (time.Duration, error) {
+	var offset time.Duration
+	minRoundTrip := time.Duration(-1)
+
+	for i := 0; i < clockSyncRounds; i++ {
+		t0 := time.Now()
+		t1, t2, err := transport.Exchange(peer, t0)
+		if err != nil {
+			return 0, err
+		}
+		t3 := time.Now()
+
+		roundTrip := t3.Sub(t0) - t2.Sub(t1)
+		if minRoundTrip < 0 || roundTrip < minRoundTrip {
+			minRoundTrip = roundTrip
+			offset = (t1.Sub(t0) + t2.Sub(t3)) / 2
+		}
+	}
+
+	return offset, nil
+}
+
+// ScheduledDispatchTime returns the time, as read from the clock of the peer, at which the peer should dispatch an
+// invocation scheduled sinceStart after the start of the experiment on the local clock
+func ScheduledDispatchTime(experimentStart time.Time, sinceStart time.Duration, peerOffset time.Duration) time.Time {
+	return experimentStart.Add(sinceStart).Add(peerOffset)
+}
+
+// udpClockSyncTransport exchanges the timestamps as nanoseconds since the epoch, the request holding the transmit
+// time of the requester and the response echoing it, followed by the receive and transmit time of the peer
+type udpClockSyncTransport struct{}
+
+func (udpClockSyncTransport) Exchange(peer string, transmit time.Time) (time.Time, time.Time, error) {
+	conn, err := net.Dial("udp", peer)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	defer conn.Close()
+
+	if err = conn.SetDeadline(time.Now().Add(clockSyncTimeout)); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	request := binary.BigEndian.AppendUint64(nil, uint64(transmit.UnixNano()))
+	if _, err = conn.Write(request); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	response := make([]byte, 24)
+	if n, err := conn.Read(response); err != nil {
+		return time.Time{}, time.Time{}, err
+	} else if n != len(response) || binary.BigEndian.Uint64(response) != uint64(transmit.UnixNano()) {
+		return time.Time{}, time.Time{}, errClockSyncMismatch
+	}
+
+	peerReceive := time.Unix(0, int64(binary.BigEndian.Uint64(response[8:])))
+	peerTransmit := time.Unix(0, int64(binary.BigEndian.Uint64(response[16:])))
+
+	return peerReceive, peerTransmit, nil
+}
+
+// ServeClockSync answers the clock sync requests of ClockSyncCheck until the connection is closed
+func ServeClockSync(conn net.PacketConn) error {
+	request := make([]byte, 8)
+
+	for {
+		n, addr, err := conn.ReadFrom(request)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		receive := time.Now()
+
+		if n != len(request) {
+			log.Debugf("Ignoring malformed clock sync request from %s.", addr)
+			continue
+		}
+
+		response := append(make([]byte, 0, 24), request...)
+		response = binary.BigEndian.AppendUint64(response, uint64(receive.UnixNano()))
+		response = binary.BigEndian.AppendUint64(response, uint64(time.Now().UnixNano()))
+
+		if _, err = conn.WriteTo(response, addr); err != nil {
+			log.Debugf("Failed to answer the clock sync request from %s: %v", addr, err)
+		}
+	}
+}
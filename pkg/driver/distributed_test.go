@@ -0,0 +1,145 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+type clockSyncRequest struct {
+	transmit time.Time
+	response chan [2]time.Time
+}
+
+// channelClockSyncTransport exchanges the timestamps with in-process peers, each running on its own skewed clock
+type channelClockSyncTransport struct {
+	peers map[string]chan clockSyncRequest
+}
+
+func newChannelClockSyncTransport(skews map[string]time.Duration) *channelClockSyncTransport {
+	transport := &channelClockSyncTransport{peers: make(map[string]chan clockSyncRequest)}
+
+	for peer, skew := range skews {
+		requests := make(chan clockSyncRequest)
+		transport.peers[peer] = requests
+
+		go func(skew time.Duration) {
+			for request := range requests {
+				receive := time.Now().Add(skew)
+				time.Sleep(time.Millisecond) // processing time of the peer
+				request.response <- [2]time.Time{receive, time.Now().Add(skew)}
+			}
+		}(skew)
+	}
+
+	return transport
+}
+
+func (c *channelClockSyncTransport) Exchange(peer string, transmit time.Time) (time.Time, time.Time, error) {
+	requests, ok := c.peers[peer]
+	if !ok {
+		return time.Time{}, time.Time{}, errors.New("unknown peer")
+	}
+
+	response := make(chan [2]time.Time)
+	requests <- clockSyncRequest{transmit: transmit, response: response}
+	timestamps := <-response
+
+	return timestamps[0], timestamps[1], nil
+}
+
+func (c *channelClockSyncTransport) close() {
+	for _, requests := range c.peers {
+		close(requests)
+	}
+}
+
+func TestClockSyncCheck(t *testing.T) {
+	skews := map[string]time.Duration{
+		"loader-1:9000": 0,
+		"loader-2:9000": 200 * time.Millisecond,
+		"loader-3:9000": -75 * time.Millisecond,
+	}
+	transport := newChannelClockSyncTransport(skews)
+	defer transport.close()
+
+	clockSyncTransport = transport
+	defer func() { clockSyncTransport = udpClockSyncTransport{} }()
+
+	offsets, err := ClockSyncCheck([]string{"loader-1:9000", "loader-2:9000", "loader-3:9000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for peer, skew := range skews {
+		if deviation := (offsets[peer] - skew).Abs(); deviation > 5*time.Millisecond {
+			t.Errorf("Expected the offset of %s to be %v, got %v.", peer, skew, offsets[peer])
+		}
+	}
+
+	if _, err = ClockSyncCheck([]string{"loader-4:9000"}); err == nil {
+		t.Error("Expected the clock sync with an unknown peer to fail.")
+	}
+}
+
+func TestScheduledDispatchTime(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	dispatch := ScheduledDispatchTime(start, 30*time.Second, 200*time.Millisecond)
+	if expected := start.Add(30*time.Second + 200*time.Millisecond); !dispatch.Equal(expected) {
+		t.Errorf("Expected the peer ahead by 200ms to dispatch at %v, got %v.", expected, dispatch)
+	}
+
+	dispatch = ScheduledDispatchTime(start, 30*time.Second, -75*time.Millisecond)
+	if expected := start.Add(30*time.Second - 75*time.Millisecond); !dispatch.Equal(expected) {
+		t.Errorf("Expected the peer behind by 75ms to dispatch at %v, got %v.", expected, dispatch)
+	}
+}
+
+func TestServeClockSync(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	served := make(chan error)
+	go func() { served <- ServeClockSync(conn) }()
+
+	offsets, err := ClockSyncCheck([]string{conn.LocalAddr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset := offsets[conn.LocalAddr().String()]; offset.Abs() > 5*time.Millisecond {
+		t.Errorf("Expected no offset to a peer on the same machine, got %v.", offset)
+	}
+
+	conn.Close()
+	if err = <-served; err != nil {
+		t.Errorf("Expected the server to stop once the connection is closed, got %v.", err)
+	}
+}
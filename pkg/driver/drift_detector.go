@@ -0,0 +1,219 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// DriftSignificance is the p-value below which the trend of the latency of a function is reported
+const DriftSignificance = 0.05
+
+// minDriftMinutes is the length of the latency series from which its trend is tested, as the normal approximation of
+// the Mann-Kendall statistic is inaccurate for shorter series
+const minDriftMinutes = 10
+
+// DriftReport is a monotonic trend in the per-minute mean latency of a function
+type DriftReport struct {
+	FunctionName     string
+	Minute           int     // at the end of which the trend was detected
+	SlopeUsPerMinute float64 // Theil-Sen estimate, positive if the latency increases
+	Z                float64
+	PValue           float64
+}
+
+type latencySeries struct {
+	minutes []float64 // of the points, minutes without invocations are skipped
+	means   []float64 // µs
+
+	// Mann-Kendall S statistic and number of occurrences of each value, updated as the series grows
+	s    int
+	ties map[float64]int
+
+	pendingSum   float64
+	pendingCount int
+	drifting     bool // as of the last test
+}
+
+func (l *latencySeries) add(minute float64, mean float64) {
+	for _, previous := range l.means {
+		switch {
+		case mean > previous:
+			l.s++
+		case mean < previous:
+			l.s--
+		}
+	}
+
+	if l.ties == nil {
+		l.ties = make(map[float64]int)
+	}
+	l.ties[mean]++
+
+	l.minutes = append(l.minutes, minute)
+	l.means = append(l.means, mean)
+}
+
+// DriftDetector tests the per-minute mean latency of each function for a monotonic trend with the Mann-Kendall test
+// at the end of each minute, detecting performance drifts over long experiments (e.g., due to host maintenance). A
+// drift is reported when it is first detected and again once it reappears after having vanished. Safe for
+// concurrent use.
+type DriftDetector struct {
+	mutex   sync.Mutex
+	series  map[string]*latencySeries
+	minute  int
+	reports []DriftReport
+}
+
+func NewDriftDetector() *DriftDetector {
+	return &DriftDetector{series: make(map[string]*latencySeries)}
+}
+
+// Record adds the latency in microseconds of an invocation of the function to the current minute
+func (d *DriftDetector) Record(function string, latencyUs float64) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	series, ok := d.series[function]
+	if !ok {
+		series = &latencySeries{}
+		d.series[function] = series
+	}
+
+	series.pendingSum += latencyUs
+	series.pendingCount++
+}
+
+// EndMinute adds the mean latency of the minute to the series of each invoked function and returns the drifts
+// detected in the series
+func (d *DriftDetector) EndMinute() []DriftReport {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var functions []string
+	for function := range d.series {
+		functions = append(functions, function)
+	}
+	sort.Strings(functions)
+
+	var drifts []DriftReport
+	for _, function := range functions {
+		series := d.series[function]
+		if series.pendingCount > 0 {
+			series.add(float64(d.minute), series.pendingSum/float64(series.pendingCount))
+			series.pendingSum, series.pendingCount = 0, 0
+		}
+
+		if len(series.means) < minDriftMinutes {
+			continue
+		}
+
+		_, z, pValue := mannKendallScore(series.s, len(series.means), series.ties)
+		drifting := pValue < DriftSignificance
+		if drifting && !series.drifting {
+			drifts = append(drifts, DriftReport{
+				FunctionName:     function,
+				Minute:           d.minute,
+				SlopeUsPerMinute: theilSenSlope(series.minutes, series.means),
+				Z:                z,
+				PValue:           pValue,
+			})
+		}
+		series.drifting = drifting
+	}
+	d.minute++
+
+	d.reports = append(d.reports, drifts...)
+	return drifts
+}
+
+// Reports returns all the drifts detected so far
+func (d *DriftDetector) Reports() []DriftReport {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return append([]DriftReport(nil), d.reports...)
+}
+
+// mannKendall computes the Mann-Kendall S statistic of the series, its variance corrected for ties, the continuity
+// corrected normal score, and the two-sided p-value of the hypothesis that the series has no monotonic trend
+func mannKendall(series []float64) (s int, variance float64, z float64, pValue float64) {
+	l := &latencySeries{}
+	for i, value := range series {
+		l.add(float64(i), value)
+	}
+
+	variance, z, pValue = mannKendallScore(l.s, len(series), l.ties)
+	return l.s, variance, z, pValue
+}
+
+// mannKendallScore computes the variance, the normal score, and the p-value of the S statistic of n values whose
+// occurrences are given by ties
+func mannKendallScore(s int, n int, ties map[float64]int) (variance float64, z float64, pValue float64) {
+	variance = float64(n*(n-1)*(2*n+5)) / 18
+	for _, t := range ties {
+		if t > 1 {
+			variance -= float64(t*(t-1)*(2*t+5)) / 18
+		}
+	}
+
+	if variance <= 0 {
+		return variance, 0, 1
+	}
+
+	switch {
+	case s > 0:
+		z = float64(s-1) / math.Sqrt(variance)
+	case s < 0:
+		z = float64(s+1) / math.Sqrt(variance)
+	}
+
+	return variance, z, math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// theilSenSlope returns the median of the slopes between all pairs of points
+func theilSenSlope(x []float64, y []float64) float64 {
+	var slopes []float64
+	for i := 0; i < len(x)-1; i++ {
+		for j := i + 1; j < len(x); j++ {
+			if x[j] != x[i] {
+				slopes = append(slopes, (y[j]-y[i])/(x[j]-x[i]))
+			}
+		}
+	}
+
+	if len(slopes) == 0 {
+		return 0
+	}
+	sort.Float64s(slopes)
+
+	middle := len(slopes) / 2
+	if len(slopes)%2 == 0 {
+		return (slopes[middle-1] + slopes[middle]) / 2
+	}
+	return slopes[middle]
+}
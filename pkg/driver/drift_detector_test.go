@@ -0,0 +1,155 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMannKendall(t *testing.T) {
+	tests := []struct {
+		testName         string
+		series           []float64
+		expectedS        int
+		expectedVariance float64
+		expectedZ        float64
+	}{
+		{
+			testName:         "increasing",
+			series:           []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			expectedS:        45,
+			expectedVariance: 125,
+			expectedZ:        44 / math.Sqrt(125),
+		},
+		{
+			testName:         "decreasing",
+			series:           []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+			expectedS:        -45,
+			expectedVariance: 125,
+			expectedZ:        -44 / math.Sqrt(125),
+		},
+		{
+			testName:         "ties",
+			series:           []float64{1, 2, 2, 3},
+			expectedS:        5,
+			expectedVariance: 52.0/6 - 1,
+			expectedZ:        4 / math.Sqrt(52.0/6-1),
+		},
+		{
+			testName:         "constant",
+			series:           []float64{7, 7, 7, 7},
+			expectedS:        0,
+			expectedVariance: 0,
+			expectedZ:        0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			s, variance, z, pValue := mannKendall(test.series)
+
+			if s != test.expectedS || math.Abs(variance-test.expectedVariance) > 1e-9 || math.Abs(z-test.expectedZ) > 1e-9 {
+				t.Errorf("Expected S = %d, Var(S) = %f, Z = %f, got %d, %f, %f",
+					test.expectedS, test.expectedVariance, test.expectedZ, s, variance, z)
+			}
+			if expected := math.Erfc(math.Abs(test.expectedZ) / math.Sqrt2); math.Abs(pValue-expected) > 1e-12 {
+				t.Errorf("Expected p = %f, got %f", expected, pValue)
+			}
+		})
+	}
+
+	// Two-sided p-value of Z = 3.9355 is about 8.3e-5
+	if _, _, _, pValue := mannKendall([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}); pValue < 8e-5 || pValue > 8.6e-5 {
+		t.Errorf("Expected p of about 8.3e-5, got %g", pValue)
+	}
+}
+
+func TestTheilSenSlope(t *testing.T) {
+	x := []float64{0, 1, 2, 3, 4}
+	y := []float64{10, 12, 14, 100, 18} // 2 per step, with an outlier
+
+	if slope := theilSenSlope(x, y); slope != 2 {
+		t.Errorf("Expected a slope of 2, got %f", slope)
+	}
+	if slope := theilSenSlope([]float64{3}, []float64{1}); slope != 0 {
+		t.Errorf("Expected no slope of a single point, got %f", slope)
+	}
+}
+
+func recordMinute(d *DriftDetector, function string, meanUs float64) {
+	// Three invocations around the mean
+	for _, offset := range []float64{-30, 0, 30} {
+		d.Record(function, meanUs+offset)
+	}
+}
+
+func TestDriftDetector(t *testing.T) {
+	detector := NewDriftDetector()
+
+	var reports []DriftReport
+	for minute := 0; minute < 15; minute++ {
+		noise := 20.0
+		if minute%2 == 1 {
+			noise = -20
+		}
+
+		recordMinute(detector, "increasing", 1000+50*float64(minute)+noise)
+		recordMinute(detector, "stable", 1000+noise)
+		if minute != 4 {
+			recordMinute(detector, "decreasing", 5000-10*float64(minute)+noise)
+		}
+
+		drifts := detector.EndMinute()
+		if minute < minDriftMinutes-1 && len(drifts) != 0 {
+			t.Fatalf("Expected no drift before %d minutes, got %v", minDriftMinutes, drifts)
+		}
+		reports = append(reports, drifts...)
+	}
+
+	if len(reports) != 2 {
+		t.Fatalf("Expected a single drift of each trending function, got %v", reports)
+	}
+
+	// The decreasing function skipped a minute, so its drift is detected one minute later
+	increasing, decreasing := reports[0], reports[1]
+	if increasing.FunctionName != "increasing" || increasing.Minute != minDriftMinutes-1 || increasing.PValue >= DriftSignificance {
+		t.Errorf("Unexpected drift of the increasing function %+v", increasing)
+	}
+	if math.Abs(increasing.SlopeUsPerMinute-50) > 10 {
+		t.Errorf("Expected a slope of about 50 us/minute, got %f", increasing.SlopeUsPerMinute)
+	}
+
+	if decreasing.FunctionName != "decreasing" || decreasing.Minute != minDriftMinutes || decreasing.Z >= 0 {
+		t.Errorf("Unexpected drift of the decreasing function %+v", decreasing)
+	}
+	if decreasing.SlopeUsPerMinute >= 0 {
+		t.Errorf("Expected a negative slope, got %f", decreasing.SlopeUsPerMinute)
+	}
+
+	if len(detector.Reports()) != 2 {
+		t.Errorf("Expected 2 reports in total, got %v", detector.Reports())
+	}
+}
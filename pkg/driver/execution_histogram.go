@@ -0,0 +1,107 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// histogramBuckets covers all uint32 durations, with bucket i > 0 holding [2^(i-1), 2^i) µs and bucket 0 holding 0 µs
+	histogramBuckets  = 33
+	histogramBarWidth = 50
+)
+
+// ExecutionHistogram counts the execution times of function invocations in logarithmic buckets. Safe for concurrent
+// use without locking.
+type ExecutionHistogram struct {
+	buckets [histogramBuckets]atomic.Int64
+}
+
+func NewExecutionHistogram() *ExecutionHistogram {
+	return &ExecutionHistogram{}
+}
+
+// Add records an execution time in microseconds
+func (h *ExecutionHistogram) Add(durationInMicroSec uint32) {
+	h.buckets[bits.Len32(durationInMicroSec)].Add(1)
+}
+
+func (h *ExecutionHistogram) BucketCount(bucket int) int64 {
+	return h.buckets[bucket].Load()
+}
+
+func (h *ExecutionHistogram) Count() int64 {
+	var count int64
+	for i := range h.buckets {
+		count += h.buckets[i].Load()
+	}
+
+	return count
+}
+
+// bucketBounds returns the inclusive lower and exclusive upper bound of the bucket in microseconds
+func bucketBounds(bucket int) (uint64, uint64) {
+	if bucket == 0 {
+		return 0, 1
+	}
+
+	return 1 << (bucket - 1), 1 << bucket
+}
+
+// PrintHistogram writes an ASCII histogram spanning from the lowest to the highest non-empty bucket
+func PrintHistogram(w io.Writer, h *ExecutionHistogram) {
+	var counts [histogramBuckets]int64
+	first, last := -1, -1
+	var maxCount int64
+
+	for i := range counts {
+		counts[i] = h.BucketCount(i)
+		if counts[i] > 0 {
+			if first == -1 {
+				first = i
+			}
+			last = i
+			maxCount = max(maxCount, counts[i])
+		}
+	}
+
+	if first == -1 {
+		fmt.Fprintln(w, "Execution time histogram: no invocations completed yet")
+		return
+	}
+
+	fmt.Fprintln(w, "Execution time histogram [ms]:")
+	for i := first; i <= last; i++ {
+		low, high := bucketBounds(i)
+		bar := strings.Repeat("#", int(counts[i]*histogramBarWidth/maxCount))
+
+		fmt.Fprintf(w, "[%10.3f, %10.3f) | %-*s %d\n", float64(low)/1e3, float64(high)/1e3, histogramBarWidth, bar, counts[i])
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestExecutionHistogramBucketCounts(t *testing.T) {
+	h := NewExecutionHistogram()
+
+	// 10 goroutines add 100 values each: 10 values in [2^k, 2^(k+1)) µs for k = 0..9
+	wg := sync.WaitGroup{}
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < 10; k++ {
+				for i := 0; i < 10; i++ {
+					h.Add(uint32(1<<k + i%(1<<k)))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if h.Count() != 1000 {
+		t.Fatalf("Expected 1000 values, got %d.", h.Count())
+	}
+	if h.BucketCount(0) != 0 {
+		t.Errorf("Expected an empty zero bucket, got %d.", h.BucketCount(0))
+	}
+	for bucket := 1; bucket <= 10; bucket++ {
+		if h.BucketCount(bucket) != 100 {
+			t.Errorf("Expected 100 values in bucket %d, got %d.", bucket, h.BucketCount(bucket))
+		}
+	}
+	for bucket := 11; bucket < histogramBuckets; bucket++ {
+		if h.BucketCount(bucket) != 0 {
+			t.Errorf("Expected bucket %d to be empty, got %d.", bucket, h.BucketCount(bucket))
+		}
+	}
+
+	h.Add(0)
+	h.Add(^uint32(0))
+	if h.BucketCount(0) != 1 || h.BucketCount(histogramBuckets-1) != 1 {
+		t.Error("Extreme values were not recorded in the outermost buckets.")
+	}
+}
+
+func TestPrintHistogram(t *testing.T) {
+	h := NewExecutionHistogram()
+
+	var buffer bytes.Buffer
+	PrintHistogram(&buffer, h)
+	if !strings.Contains(buffer.String(), "no invocations") {
+		t.Errorf("Unexpected output for an empty histogram:\n%s", buffer.String())
+	}
+
+	for i := 0; i < 10; i++ {
+		h.Add(1500) // [1.024, 2.048) ms
+	}
+	h.Add(5000) // [4.096, 8.192) ms
+
+	buffer.Reset()
+	PrintHistogram(&buffer, h)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+
+	// header, [1.024, 2.048), [2.048, 4.096), [4.096, 8.192)
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines, got:\n%s", buffer.String())
+	}
+	if !strings.Contains(lines[1], strings.Repeat("#", histogramBarWidth)) || !strings.HasSuffix(lines[1], " 10") {
+		t.Errorf("Unexpected line of the fullest bucket: %s", lines[1])
+	}
+	if strings.Contains(lines[2], "#") || !strings.HasSuffix(lines[2], " 0") {
+		t.Errorf("Unexpected line of an empty bucket: %s", lines[2])
+	}
+	if !strings.Contains(lines[3], "#####") || !strings.HasSuffix(lines[3], " 1") {
+		t.Errorf("Unexpected line of the last bucket: %s", lines[3])
+	}
+}
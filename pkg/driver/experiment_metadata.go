@@ -0,0 +1,67 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ExperimentMetadata describes the conditions under which an experiment was run. It is written next to the
+// experiment results so that the measurements can be interpreted correctly afterwards.
+type ExperimentMetadata struct {
+	TraceChecksum string   `json:"TraceChecksum,omitempty"` // SHA-256 of the invocation trace file
+	Notes         []string `json:"Notes"`
+
+	mutex sync.Mutex
+}
+
+// AddNote records a remark about the experiment setup. Safe for concurrent use.
+func (m *ExperimentMetadata) AddNote(note string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.Notes = append(m.Notes, note)
+}
+
+func (d *Driver) writeExperimentMetadata() {
+	filename := fmt.Sprintf("%s_metadata_%d.json", d.Configuration.LoaderConfiguration.OutputPathPrefix, d.Configuration.TraceDuration)
+
+	d.Metadata.mutex.Lock()
+	data, err := json.MarshalIndent(d.Metadata, "", "  ")
+	d.Metadata.mutex.Unlock()
+	if err != nil {
+		log.Errorf("Failed to serialize experiment metadata: %s", err)
+		return
+	}
+
+	if err = os.WriteFile(filename, data, 0644); err != nil {
+		log.Errorf("Failed to write experiment metadata to %s: %s", filename, err)
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// fleetEventBuffer is the number of health transitions kept for a slow consumer before they are dropped
+const fleetEventBuffer = 128
+
+type endpointProbe func(endpoint string, timeout time.Duration) (time.Duration, error)
+
+// HealthStatus is the health of an endpoint as of its last check
+type HealthStatus struct {
+	LastCheck           time.Time
+	Healthy             bool
+	ConsecutiveFailures int
+}
+
+// HealthEvent reports that an endpoint became unhealthy or recovered
+type HealthEvent struct {
+	Endpoint string
+	Healthy  bool
+	Time     time.Time
+	Err      error // of the failed check that made the endpoint unhealthy
+}
+
+// FleetMonitor polls the health of the function endpoints while the experiment runs, with the same probes as the
+// preflight check. Endpoints are considered healthy until their first failed check. Safe for concurrent use.
+type FleetMonitor struct {
+	probe endpointProbe
+
+	mutex     sync.RWMutex
+	healthMap map[string]HealthStatus
+
+	events   chan HealthEvent
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+func NewFleetMonitor() *FleetMonitor {
+	return newFleetMonitorWithProbe(probeEndpoint)
+}
+
+func newFleetMonitorWithProbe(probe endpointProbe) *FleetMonitor {
+	return &FleetMonitor{
+		probe:     probe,
+		healthMap: make(map[string]HealthStatus),
+		events:    make(chan HealthEvent, fleetEventBuffer),
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start polls each endpoint every interval, with the interval as the timeout of a check, until Stop is called
+func (m *FleetMonitor) Start(endpoints []string, interval time.Duration) {
+	m.mutex.Lock()
+	for _, endpoint := range endpoints {
+		m.healthMap[endpoint] = HealthStatus{Healthy: true}
+	}
+	m.mutex.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			m.poll(endpoints, interval)
+
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (m *FleetMonitor) poll(endpoints []string, timeout time.Duration) {
+	wg := sync.WaitGroup{}
+
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+
+		go func(endpoint string) {
+			defer wg.Done()
+
+			_, err := m.probe(endpoint, timeout)
+			m.update(endpoint, err, time.Now())
+		}(endpoint)
+	}
+
+	wg.Wait()
+}
+
+func (m *FleetMonitor) update(endpoint string, err error, now time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	status := m.healthMap[endpoint]
+	wasHealthy := status.Healthy
+
+	status.LastCheck = now
+	status.Healthy = err == nil
+	if err != nil {
+		status.ConsecutiveFailures++
+	} else {
+		status.ConsecutiveFailures = 0
+	}
+	m.healthMap[endpoint] = status
+
+	if status.Healthy == wasHealthy {
+		return
+	}
+
+	if status.Healthy {
+		log.Infof("Endpoint %s recovered.", endpoint)
+	} else {
+		log.Warnf("Endpoint %s became unhealthy - %v", endpoint, err)
+	}
+
+	select {
+	case m.events <- HealthEvent{Endpoint: endpoint, Healthy: status.Healthy, Time: now, Err: err}:
+	default:
+		log.Warnf("Dropping the health event of %s as the consumer is not keeping up.", endpoint)
+	}
+}
+
+// Events returns the channel of health transitions, which is closed by Stop
+func (m *FleetMonitor) Events() <-chan HealthEvent {
+	return m.events
+}
+
+// HealthMap returns the health of each monitored endpoint
+func (m *FleetMonitor) HealthMap() map[string]HealthStatus {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	result := make(map[string]HealthStatus, len(m.healthMap))
+	for endpoint, status := range m.healthMap {
+		result[endpoint] = status
+	}
+
+	return result
+}
+
+// GetUnhealthy returns the sorted endpoints that failed their last check
+func (m *FleetMonitor) GetUnhealthy() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var result []string
+	for endpoint, status := range m.healthMap {
+		if !status.Healthy {
+			result = append(result, endpoint)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// Stop waits for the ongoing poll to finish and closes the event channel
+func (m *FleetMonitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+		m.wg.Wait()
+		close(m.events)
+	})
+}
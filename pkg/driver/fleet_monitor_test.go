@@ -0,0 +1,144 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeProbe struct {
+	mutex sync.Mutex
+	polls map[string]int
+	// failing reports whether the n-th poll of the endpoint (starting from 1) fails
+	failing func(endpoint string, n int) bool
+}
+
+func (p *fakeProbe) probe(endpoint string, _ time.Duration) (time.Duration, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.polls[endpoint]++
+	if p.failing(endpoint, p.polls[endpoint]) {
+		return 0, errors.New("connection refused")
+	}
+
+	return time.Millisecond, nil
+}
+
+func nextHealthEvent(t *testing.T, events <-chan HealthEvent) HealthEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a health event.")
+		return HealthEvent{}
+	}
+}
+
+func TestFleetMonitorDetectsFailure(t *testing.T) {
+	probe := &fakeProbe{
+		polls: make(map[string]int),
+		failing: func(endpoint string, n int) bool {
+			return endpoint == "failing" && n > 3
+		},
+	}
+
+	monitor := newFleetMonitorWithProbe(probe.probe)
+	monitor.Start([]string{"stable", "failing"}, 5*time.Millisecond)
+	defer monitor.Stop()
+
+	event := nextHealthEvent(t, monitor.Events())
+	if event.Endpoint != "failing" || event.Healthy || event.Err == nil {
+		t.Errorf("Expected the failing endpoint to become unhealthy, got %+v", event)
+	}
+
+	probe.mutex.Lock()
+	polls := probe.polls["failing"]
+	probe.mutex.Unlock()
+	if polls < 4 {
+		t.Errorf("Expected the transition on the 4th poll, got it after %d polls", polls)
+	}
+
+	if unhealthy := monitor.GetUnhealthy(); !reflect.DeepEqual(unhealthy, []string{"failing"}) {
+		t.Errorf("Expected only the failing endpoint to be unhealthy, got %v", unhealthy)
+	}
+
+	healthMap := monitor.HealthMap()
+	if !healthMap["stable"].Healthy || healthMap["stable"].ConsecutiveFailures != 0 || healthMap["stable"].LastCheck.IsZero() {
+		t.Errorf("Unexpected status of the stable endpoint %+v", healthMap["stable"])
+	}
+	if healthMap["failing"].Healthy || healthMap["failing"].ConsecutiveFailures < 1 {
+		t.Errorf("Unexpected status of the failing endpoint %+v", healthMap["failing"])
+	}
+}
+
+func TestFleetMonitorDetectsRecovery(t *testing.T) {
+	probe := &fakeProbe{
+		polls: make(map[string]int),
+		failing: func(_ string, n int) bool {
+			return n <= 2
+		},
+	}
+
+	monitor := newFleetMonitorWithProbe(probe.probe)
+	monitor.Start([]string{"flaky"}, 5*time.Millisecond)
+
+	if event := nextHealthEvent(t, monitor.Events()); event.Healthy {
+		t.Errorf("Expected the endpoint to become unhealthy first, got %+v", event)
+	}
+	if event := nextHealthEvent(t, monitor.Events()); !event.Healthy || event.Err != nil {
+		t.Errorf("Expected the endpoint to recover, got %+v", event)
+	}
+
+	monitor.Stop()
+	monitor.Stop()
+
+	// No transitions after the recovery, and the channel is closed
+	for event := range monitor.Events() {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	if len(monitor.GetUnhealthy()) != 0 {
+		t.Errorf("Expected no unhealthy endpoints, got %v", monitor.GetUnhealthy())
+	}
+}
+
+func TestFleetMonitorPollsGRPCHealth(t *testing.T) {
+	endpoint, healthServer := startHealthServer(t)
+
+	monitor := NewFleetMonitor()
+	monitor.Start([]string{endpoint}, 20*time.Millisecond)
+	defer monitor.Stop()
+
+	healthServer.Shutdown()
+
+	event := nextHealthEvent(t, monitor.Events())
+	if event.Endpoint != endpoint || event.Healthy {
+		t.Errorf("Expected %s to become unhealthy, got %+v", endpoint, event)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// functionBudgetTracker accumulates the estimated cost of the invocations of a function issued in the current minute
+type functionBudgetTracker struct {
+	budget *common.FunctionBudget
+
+	minute     int
+	spentCents float64
+}
+
+func newFunctionBudgetTracker(budget *common.FunctionBudget) *functionBudgetTracker {
+	return &functionBudgetTracker{budget: budget}
+}
+
+// admit charges the invocation to the budget of the minute, unless its requested runtime and memory would exceed it
+func (b *functionBudgetTracker) admit(minute int, runtimeSpec *common.RuntimeSpecification) bool {
+	if minute != b.minute {
+		b.minute = minute
+		b.spentCents = 0
+	}
+
+	gbSeconds := float64(runtimeSpec.Runtime) / 1e3 * float64(runtimeSpec.Memory) / 1024
+	cost := EstimateAWSCost(gbSeconds, 1)
+	if b.spentCents+cost > b.budget.MaxUSDCentsPerMinute {
+		return false
+	}
+
+	b.spentCents += cost
+	return true
+}
@@ -0,0 +1,93 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gocarina/gocsv"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestFunctionBudgetTracker(t *testing.T) {
+	// 10 GB-s per invocation, i.e. 0.0166867 cents
+	spec := &common.RuntimeSpecification{Runtime: 1000, Memory: 10240}
+	budget := newFunctionBudgetTracker(&common.FunctionBudget{MaxUSDCentsPerMinute: 0.1})
+
+	for minute := 0; minute < 2; minute++ {
+		admitted := 0
+		for i := 0; i < 10; i++ {
+			if budget.admit(minute, spec) {
+				admitted++
+			}
+		}
+
+		if admitted != 5 {
+			t.Errorf("Expected 5 invocations within the budget of minute %d, got %d.", minute, admitted)
+		}
+	}
+}
+
+func TestDriverFunctionBudget(t *testing.T) {
+	driver := createTestDriver()
+	driver.Configuration.LoaderConfiguration.OutputPathPrefix = filepath.Join(t.TempDir(), "test")
+	driver.Configuration.TraceGranularity = common.SecondGranularity
+	driver.Configuration.TraceDuration = 2
+
+	function := driver.Configuration.Functions[0]
+	function.InvocationStats.Invocations = []int{10, 3}
+	function.Budget = &common.FunctionBudget{MaxUSDCentsPerMinute: 0.1} // $0.001
+	function.RuntimeStats = &common.FunctionRuntimeStats{
+		Average: 1000, Count: 100, Minimum: 1000, Maximum: 1000,
+		Percentile0: 1000, Percentile1: 1000, Percentile25: 1000, Percentile50: 1000, Percentile75: 1000,
+		Percentile99: 1000, Percentile100: 1000,
+	}
+	function.MemoryStats = &common.FunctionMemoryStats{
+		Average: 10240, Count: 100,
+		Percentile1: 10240, Percentile5: 10240, Percentile25: 10240, Percentile50: 10240, Percentile75: 10240,
+		Percentile95: 10240, Percentile99: 10240, Percentile100: 10240,
+	}
+
+	driver.RunExperiment(false, false)
+
+	f, err := os.Open(driver.outputFilename("duration"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var records []metric.ExecutionRecordBase
+	if err = gocsv.UnmarshalFile(f, &records); err != nil {
+		t.Fatal(err)
+	}
+
+	// 5 invocations of 10 GB-s fit into the budget of the first minute, all 3 in the second one
+	if len(records) != 8 {
+		t.Errorf("Expected 8 dispatched invocations, got %d.", len(records))
+	}
+}
@@ -36,13 +36,14 @@ import (
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 
 	mc "github.com/vhive-serverless/loader/pkg/metric"
 )
 
-func InvokeGRPC(function *common.Function, runtimeSpec *common.RuntimeSpecification, cfg *config.LoaderConfiguration) (bool, *mc.ExecutionRecord) {
+func InvokeGRPC(function *common.Function, runtimeSpec *common.RuntimeSpecification, cfg *config.LoaderConfiguration, opts ...GRPCInvocationOption) (bool, *mc.ExecutionRecord) {
 	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
 	record := &mc.ExecutionRecord{
@@ -60,12 +61,30 @@ func InvokeGRPC(function *common.Function, runtimeSpec *common.RuntimeSpecificat
 	dialContext, cancelDialing := context.WithTimeout(context.Background(), time.Duration(cfg.GRPCConnectionTimeoutSeconds)*time.Second)
 	defer cancelDialing()
 
+	invocationOptions := &grpcInvocationOptions{}
+	for _, opt := range opts {
+		opt(invocationOptions)
+	}
+
+	var interceptors []grpc.UnaryClientInterceptor
+	if cfg.EnableZipkinTracing {
+		// NOTE: if enabled it will exclude Istio span from the Zipkin trace
+		interceptors = append(interceptors, otelgrpc.UnaryClientInterceptor())
+	}
+	interceptors = append(interceptors, invocationOptions.unaryInterceptors...)
+
 	var dialOptions []grpc.DialOption
 	dialOptions = append(dialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	dialOptions = append(dialOptions, grpc.WithBlock())
-	if cfg.EnableZipkinTracing {
-		// NOTE: if enabled it will exclude Istio span from the Zipkin trace
-		dialOptions = append(dialOptions, grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()))
+	if len(interceptors) > 0 {
+		dialOptions = append(dialOptions, grpc.WithChainUnaryInterceptor(interceptors...))
+	}
+	if invocationOptions.keepalive != nil {
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                invocationOptions.keepalive.Time,
+			Timeout:             invocationOptions.keepalive.Timeout,
+			PermitWithoutStream: invocationOptions.keepalive.PermitWithoutStream,
+		}))
 	}
 
 	grpcStart := time.Now()
@@ -85,7 +104,12 @@ func InvokeGRPC(function *common.Function, runtimeSpec *common.RuntimeSpecificat
 
 	grpcClient := proto.NewExecutorClient(conn)
 
-	executionCxt, cancelExecution := context.WithTimeout(context.Background(), time.Duration(cfg.GRPCFunctionTimeoutSeconds)*time.Second)
+	functionTimeout := time.Duration(cfg.GRPCFunctionTimeoutSeconds) * time.Second
+	if invocationOptions.functionTimeout != 0 {
+		functionTimeout = invocationOptions.functionTimeout
+	}
+
+	executionCxt, cancelExecution := context.WithTimeout(context.Background(), functionTimeout)
 	defer cancelExecution()
 
 	response, err := grpcClient.Execute(executionCxt, &proto.FaasRequest{
@@ -76,7 +76,7 @@ func TestGRPCClientWithServerReachable(t *testing.T) {
 	address, port := "localhost", 8080
 	testFunction.Endpoint = fmt.Sprintf("%s:%d", address, port)
 
-	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "", "")
 
 	// make sure that the gRPC server is running
 	time.Sleep(2 * time.Second)
@@ -109,7 +109,7 @@ func TestGRPCClientWithServerBatchWorkload(t *testing.T) {
 	address, port := "localhost", 8081
 	testFunction.Endpoint = fmt.Sprintf("%s:%d", address, port)
 
-	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "", "")
 
 	// make sure that the gRPC server is running
 	time.Sleep(2 * time.Second)
@@ -131,3 +131,23 @@ func TestGRPCClientWithServerBatchWorkload(t *testing.T) {
 		}
 	}
 }
+
+func TestGRPCClientWithFunctionTimeout(t *testing.T) {
+	address, port := "localhost", 8086
+	function := testFunction
+	function.Endpoint = fmt.Sprintf("%s:%d", address, port)
+
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "", "")
+
+	// make sure that the gRPC server is running
+	time.Sleep(2 * time.Second)
+
+	cfg := createFakeLoaderConfiguration()
+	runtimeSpecs := common.RuntimeSpecification{Runtime: 500, Memory: 128}
+
+	success, record := InvokeGRPC(&function, &runtimeSpecs, cfg, WithFunctionTimeout(50*time.Millisecond))
+
+	if success || !record.FunctionTimeout || record.ResponseTime >= 500000 {
+		t.Error("Expected the invocation to time out after the given function timeout.")
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+type grpcInvocationOptions struct {
+	unaryInterceptors []grpc.UnaryClientInterceptor
+	functionTimeout   time.Duration // overrides GRPCFunctionTimeoutSeconds if non-zero
+	keepalive         *KeepaliveConfig
+}
+
+// KeepaliveConfig makes the client ping the function server to detect broken connections. gRPC enforces a minimum
+// Time of 10 seconds, and the server must tolerate pings at that rate (see standard.ServerKeepaliveConfig).
+type KeepaliveConfig struct {
+	Time                time.Duration // ping after this long without any activity on the connection
+	Timeout             time.Duration // close the connection if the ping is not acknowledged within this time
+	PermitWithoutStream bool          // ping also without active RPCs
+}
+
+// GRPCInvocationOption customizes the gRPC connection established by InvokeGRPC
+type GRPCInvocationOption func(*grpcInvocationOptions)
+
+// WithUnaryInterceptors chains the interceptors (in the given order) into every gRPC call towards the function
+func WithUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) GRPCInvocationOption {
+	return func(o *grpcInvocationOptions) {
+		o.unaryInterceptors = append(o.unaryInterceptors, interceptors...)
+	}
+}
+
+// WithFunctionTimeout replaces the configured function timeout of the invocation
+func WithFunctionTimeout(timeout time.Duration) GRPCInvocationOption {
+	return func(o *grpcInvocationOptions) {
+		o.functionTimeout = timeout
+	}
+}
+
+// WithKeepalive enables client-side keepalive pings on the connection
+func WithKeepalive(cfg KeepaliveConfig) GRPCInvocationOption {
+	return func(o *grpcInvocationOptions) {
+		o.keepalive = &cfg
+	}
+}
+
+// LoggingInterceptor logs the method, duration, and error of each gRPC call
+func LoggingInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+
+	log.Tracef("(gRPC)\t %s on %s: %.2f[ms], error: %v", method, cc.Target(), float64(time.Since(start).Microseconds())/1e3, err)
+
+	return err
+}
+
+// MetricsInterceptor counts the gRPC calls in metric.GRPCCalls, labelled by method and status code
+func MetricsInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	mc.GRPCCalls.WithLabelValues(method, status.Code(err).String()).Inc()
+
+	return err
+}
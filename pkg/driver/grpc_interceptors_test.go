@@ -0,0 +1,173 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"github.com/vhive-serverless/loader/pkg/workload/standard"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestGRPCInterceptorChain(t *testing.T) {
+	address, port := "localhost", 8082
+	function := &common.Function{
+		Name:     "test-function",
+		Endpoint: fmt.Sprintf("%s:%d", address, port),
+	}
+
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "", "")
+
+	// make sure the gRPC server is running
+	time.Sleep(2 * time.Second)
+
+	cfg := createFakeLoaderConfiguration()
+	cfg.EnableZipkinTracing = false
+
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+	previousLevel := logrus.GetLevel()
+	logrus.SetLevel(logrus.TraceLevel)
+	defer logrus.SetLevel(previousLevel)
+
+	var order []string
+	recordingInterceptor := func(name string) grpc.UnaryClientInterceptor {
+		return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			order = append(order, name)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+
+	const numberOfCalls = 3
+	const method = "/faas.Executor/Execute"
+	calls := mc.GRPCCalls.WithLabelValues(method, codes.OK.String())
+	callsBefore := testutil.ToFloat64(calls)
+
+	for i := 0; i < numberOfCalls; i++ {
+		success, _ := InvokeGRPC(function, &testRuntimeSpecs, cfg,
+			WithUnaryInterceptors(recordingInterceptor("first"), LoggingInterceptor),
+			WithUnaryInterceptors(MetricsInterceptor, recordingInterceptor("last")),
+		)
+		if !success {
+			t.Fatal("Invocation failed.")
+		}
+	}
+
+	if counted := testutil.ToFloat64(calls) - callsBefore; counted != numberOfCalls {
+		t.Errorf("MetricsInterceptor counted %.0f successful calls of %s, expected %d.", counted, method, numberOfCalls)
+	}
+
+	loggedCalls := 0
+	for _, entry := range hook.AllEntries() {
+		if strings.Contains(entry.Message, method) {
+			loggedCalls++
+		}
+	}
+	if loggedCalls != numberOfCalls {
+		t.Errorf("LoggingInterceptor logged %d calls, expected %d.", loggedCalls, numberOfCalls)
+	}
+
+	expectedOrder := strings.Repeat("first,last,", numberOfCalls)
+	if strings.Join(order, ",")+"," != expectedOrder {
+		t.Errorf("Unexpected interceptor order: %v", order)
+	}
+}
+
+func startKeepaliveServer(t *testing.T, cfg standard.ServerKeepaliveConfig) string {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer(cfg.ServerOptions()...)
+	standard.RegisterHealthServer(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestServerKeepaliveClosesIdleConnections(t *testing.T) {
+	endpoint := startKeepaliveServer(t, standard.ServerKeepaliveConfig{MaxConnectionIdle: 100 * time.Millisecond})
+
+	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	idleSince := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for state := conn.GetState(); state == connectivity.Ready; state = conn.GetState() {
+		if !conn.WaitForStateChange(ctx, state) {
+			t.Fatal("The server did not close the idle connection.")
+		}
+	}
+
+	// The server checks for idleness once per MaxConnectionIdle
+	if elapsed := time.Since(idleSince); elapsed > 300*time.Millisecond {
+		t.Errorf("The idle connection was closed only after %v.", elapsed)
+	}
+}
+
+func TestGRPCClientWithKeepalive(t *testing.T) {
+	address, port := "localhost", 8087
+	function := testFunction
+	function.Endpoint = fmt.Sprintf("%s:%d", address, port)
+
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "", "")
+
+	// make sure that the gRPC server is running
+	time.Sleep(2 * time.Second)
+
+	// gRPC raises the ping interval to its minimum of 10 seconds
+	keepalive := WithKeepalive(KeepaliveConfig{Time: 100 * time.Millisecond, Timeout: time.Second, PermitWithoutStream: true})
+
+	for i := 0; i < 3; i++ {
+		if success, _ := InvokeGRPC(&function, &testRuntimeSpecs, createFakeLoaderConfiguration(), keepalive); !success {
+			t.Errorf("Invocation %d with keepalive failed.", i)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// executeMethod is the full name of the RPC executing a function invocation
+var executeMethod = fmt.Sprintf("/%s/Execute", proto.Executor_ServiceDesc.ServiceName)
+
+// ErrDraining is returned by GetStream once the pool is being drained
+var ErrDraining = errors.New("gRPC pool is draining")
+
+// GrpcConnectionFactory establishes the connection to an endpoint
+type GrpcConnectionFactory func(endpoint string) (*grpc.ClientConn, error)
+
+const (
+	maxPanicRecoveries = 5
+	panicWindow        = time.Minute
+)
+
+// PanicRecoveryFactory converts the panics of the factory into errors, so that the endpoint is dialed again on the
+// next use instead of the panic tearing down the pool. More than maxPanicRecoveries panics within panicWindow are
+// fatal, as the factory is then considered broken for good.
+func PanicRecoveryFactory(factory GrpcConnectionFactory) GrpcConnectionFactory {
+	return panicRecoveryFactoryWithClock(factory, time.Now, log.Fatalf)
+}
+
+func panicRecoveryFactoryWithClock(factory GrpcConnectionFactory, clock func() time.Time,
+	fatalf func(format string, args ...interface{})) GrpcConnectionFactory {
+
+	mutex := sync.Mutex{}
+	var panics []time.Time // within the last panicWindow
+
+	return func(endpoint string) (conn *grpc.ClientConn, err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			conn, err = nil, fmt.Errorf("gRPC connection factory panicked while dialing %s: %v", endpoint, r)
+			log.Warn(err)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			now := clock()
+			for len(panics) > 0 && now.Sub(panics[0]) > panicWindow {
+				panics = panics[1:]
+			}
+			panics = append(panics, now)
+
+			if len(panics) > maxPanicRecoveries {
+				fatalf("gRPC connection factory panicked %d times within %v", len(panics), panicWindow)
+			}
+		}()
+
+		return factory(endpoint)
+	}
+}
+
+// MultiplexedGrpcPool multiplexes the invocations towards each endpoint as concurrent streams on a single
+// connection, unlike InvokeGRPC that establishes a connection per invocation. At most MaxConcurrentStreams streams
+// are open per endpoint at a time; GetStream blocks until a stream is released. Safe for concurrent use.
+type MultiplexedGrpcPool struct {
+	MaxConcurrentStreams int
+
+	factory GrpcConnectionFactory
+
+	mutex       sync.Mutex
+	connections map[string]*grpc.ClientConn
+	semaphores  map[string]chan struct{}
+	cancels     map[grpc.ClientStream]context.CancelFunc
+
+	inFlight  atomic.Int64 // streams taken and not released yet, including the ones waiting for a slot
+	draining  bool
+	drained   chan struct{} // closed once draining and no stream is in flight
+	drainOnce sync.Once
+}
+
+// NewMultiplexedGrpcPool creates a pool dialing the endpoints with the given options, by default without TLS
+func NewMultiplexedGrpcPool(maxConcurrentStreams int, dialOptions ...grpc.DialOption) *MultiplexedGrpcPool {
+	if len(dialOptions) == 0 {
+		dialOptions = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	return NewMultiplexedGrpcPoolWithFactory(maxConcurrentStreams, func(endpoint string) (*grpc.ClientConn, error) {
+		return grpc.Dial(endpoint, dialOptions...)
+	})
+}
+
+// NewMultiplexedGrpcPoolWithFactory creates a pool establishing the connections to the endpoints with the factory,
+// whose panics are recovered by PanicRecoveryFactory
+func NewMultiplexedGrpcPoolWithFactory(maxConcurrentStreams int, factory GrpcConnectionFactory) *MultiplexedGrpcPool {
+	if maxConcurrentStreams < 1 {
+		maxConcurrentStreams = 1
+	}
+
+	return &MultiplexedGrpcPool{
+		MaxConcurrentStreams: maxConcurrentStreams,
+		factory:              PanicRecoveryFactory(factory),
+		connections:          make(map[string]*grpc.ClientConn),
+		semaphores:           make(map[string]chan struct{}),
+		cancels:              make(map[grpc.ClientStream]context.CancelFunc),
+		drained:              make(chan struct{}),
+	}
+}
+
+// endpoint returns the connection and the stream semaphore of the endpoint, dialing it on first use
+func (p *MultiplexedGrpcPool) endpoint(endpoint string) (*grpc.ClientConn, chan struct{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	conn, ok := p.connections[endpoint]
+	if !ok {
+		var err error
+		if conn, err = p.factory(endpoint); err != nil {
+			return nil, nil, err
+		}
+
+		p.connections[endpoint] = conn
+		p.semaphores[endpoint] = make(chan struct{}, p.MaxConcurrentStreams)
+	}
+
+	return conn, p.semaphores[endpoint], nil
+}
+
+// GetStream opens a stream of the Execute RPC on the connection to the endpoint, waiting for a free stream slot. The
+// caller sends a single FaasRequest, closes the sending side, receives the FaasReply, and then calls ReleaseStream.
+// Returns ErrDraining once DrainAndDestroy has been called.
+func (p *MultiplexedGrpcPool) GetStream(endpoint string) (grpc.ClientStream, error) {
+	p.mutex.Lock()
+	if p.draining {
+		p.mutex.Unlock()
+		return nil, ErrDraining
+	}
+	p.inFlight.Add(1)
+	p.mutex.Unlock()
+
+	conn, semaphore, err := p.endpoint(endpoint)
+	if err != nil {
+		p.streamDone()
+		return nil, err
+	}
+
+	semaphore <- struct{}{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{StreamName: "Execute"}, executeMethod)
+	if err != nil {
+		cancel()
+		<-semaphore
+		p.streamDone()
+
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	p.cancels[stream] = cancel
+	p.mutex.Unlock()
+
+	return stream, nil
+}
+
+// ReleaseStream frees the stream slot of the endpoint taken by GetStream, aborting the stream if it is still active
+func (p *MultiplexedGrpcPool) ReleaseStream(endpoint string, stream grpc.ClientStream) {
+	p.mutex.Lock()
+	cancel, ok := p.cancels[stream]
+	delete(p.cancels, stream)
+	semaphore := p.semaphores[endpoint]
+	p.mutex.Unlock()
+
+	if !ok {
+		log.Warnf("Releasing a stream to %s that was not taken from the pool", endpoint)
+		return
+	}
+
+	cancel()
+	if semaphore != nil { // nil once the pool is closed
+		<-semaphore
+	}
+	p.streamDone()
+}
+
+// streamDone accounts for a stream that is no longer in flight
+func (p *MultiplexedGrpcPool) streamDone() {
+	if p.inFlight.Add(-1) > 0 {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.draining && p.inFlight.Load() == 0 {
+		p.drainOnce.Do(func() { close(p.drained) })
+	}
+}
+
+// DrainAndDestroy stops handing out streams, waits for the streams in flight to be released for at most the timeout,
+// and then closes the connections to all endpoints, aborting the streams still in flight
+func (p *MultiplexedGrpcPool) DrainAndDestroy(timeout time.Duration) {
+	p.mutex.Lock()
+	p.draining = true
+	if p.inFlight.Load() == 0 {
+		p.drainOnce.Do(func() { close(p.drained) })
+	}
+	p.mutex.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-p.drained:
+	case <-timer.C:
+		log.Warnf("Closing the gRPC pool with %d streams still in flight after draining for %v", p.inFlight.Load(), timeout)
+	}
+
+	p.Close()
+}
+
+// Close closes the connections to all endpoints
+func (p *MultiplexedGrpcPool) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for endpoint, conn := range p.connections {
+		gRPCConnectionClose(conn)
+		delete(p.connections, endpoint)
+		delete(p.semaphores, endpoint)
+	}
+}
@@ -0,0 +1,324 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// concurrencyServer replies after the requested runtime, tracking the peak number of concurrent invocations
+type concurrencyServer struct {
+	proto.UnimplementedExecutorServer
+
+	active atomic.Int32
+	peak   atomic.Int32
+}
+
+func (s *concurrencyServer) Execute(_ context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
+	active := s.active.Add(1)
+	defer s.active.Add(-1)
+	for peak := s.peak.Load(); active > peak && !s.peak.CompareAndSwap(peak, active); peak = s.peak.Load() {
+	}
+
+	time.Sleep(time.Duration(req.RuntimeInMilliSec) * time.Millisecond)
+
+	return &proto.FaasReply{Message: "OK", DurationInMicroSec: req.RuntimeInMilliSec * 1000}, nil
+}
+
+func startConcurrencyServer(tb testing.TB) (string, *concurrencyServer) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	server := &concurrencyServer{}
+	grpcServer := grpc.NewServer()
+	proto.RegisterExecutorServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	tb.Cleanup(grpcServer.Stop)
+
+	return listener.Addr().String(), server
+}
+
+func executeOnStream(pool *MultiplexedGrpcPool, endpoint string, runtimeMs uint32) (*proto.FaasReply, error) {
+	stream, err := pool.GetStream(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer pool.ReleaseStream(endpoint, stream)
+
+	if err = stream.SendMsg(&proto.FaasRequest{RuntimeInMilliSec: runtimeMs}); err != nil {
+		return nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	reply := &proto.FaasReply{}
+	if err = stream.RecvMsg(reply); err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+func TestMultiplexedGrpcPool(t *testing.T) {
+	endpoint, server := startConcurrencyServer(t)
+
+	pool := NewMultiplexedGrpcPool(2)
+	defer pool.Close()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			reply, err := executeOnStream(pool, endpoint, 50)
+			if err != nil {
+				t.Error(err)
+			} else if reply.DurationInMicroSec != 50000 {
+				t.Errorf("Unexpected reply %v", reply)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak := server.peak.Load(); peak != 2 {
+		t.Errorf("Expected 2 concurrent streams at most, got %d.", peak)
+	}
+	if len(pool.connections) != 1 {
+		t.Errorf("Expected a single connection to the endpoint, got %d.", len(pool.connections))
+	}
+	if len(pool.semaphores[endpoint]) != 0 || len(pool.cancels) != 0 {
+		t.Error("Expected all streams to be released.")
+	}
+}
+
+func waitForInFlight(t *testing.T, pool *MultiplexedGrpcPool, server *concurrencyServer, n int32) {
+	deadline := time.Now().Add(5 * time.Second)
+	for server.active.Load() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d invocations in flight, got %d.", n, server.active.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if inFlight := pool.inFlight.Load(); inFlight != int64(n) {
+		t.Errorf("Expected %d streams in flight, got %d.", n, inFlight)
+	}
+}
+
+func TestMultiplexedGrpcPoolDrainAndDestroy(t *testing.T) {
+	endpoint, server := startConcurrencyServer(t)
+	pool := NewMultiplexedGrpcPool(4)
+
+	var completed atomic.Int32
+	wg := sync.WaitGroup{}
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := executeOnStream(pool, endpoint, 200); err != nil {
+				t.Error(err)
+				return
+			}
+			completed.Add(1)
+		}()
+	}
+	waitForInFlight(t, pool, server, 3)
+
+	drained := make(chan struct{})
+	go func() {
+		pool.DrainAndDestroy(5 * time.Second)
+		close(drained)
+	}()
+
+	// New streams are refused once draining has started
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stream, err := pool.GetStream(endpoint)
+		if errors.Is(err, ErrDraining) {
+			break
+		} else if err != nil || time.Now().After(deadline) {
+			t.Fatalf("Expected %v while draining, got %v.", ErrDraining, err)
+		}
+
+		pool.ReleaseStream(endpoint, stream)
+		time.Sleep(time.Millisecond)
+	}
+
+	<-drained
+	if completed.Load() != 3 {
+		t.Errorf("Expected the 3 invocations in flight to complete before the pool is closed, got %d.", completed.Load())
+	}
+	wg.Wait()
+
+	if len(pool.connections) != 0 {
+		t.Errorf("Expected the connections to be closed, got %d.", len(pool.connections))
+	}
+}
+
+func TestMultiplexedGrpcPoolDrainTimeout(t *testing.T) {
+	endpoint, server := startConcurrencyServer(t)
+	pool := NewMultiplexedGrpcPool(4)
+
+	failed := make(chan error, 1)
+	go func() {
+		_, err := executeOnStream(pool, endpoint, 5000)
+		failed <- err
+	}()
+	waitForInFlight(t, pool, server, 1)
+
+	start := time.Now()
+	pool.DrainAndDestroy(50 * time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected draining to give up after the timeout, took %v.", elapsed)
+	}
+
+	if err := <-failed; err == nil {
+		t.Error("Expected the invocation still in flight to be aborted.")
+	}
+
+	// Nothing in flight
+	idle := NewMultiplexedGrpcPool(1)
+	start = time.Now()
+	idle.DrainAndDestroy(5 * time.Second)
+	if time.Since(start) > time.Second {
+		t.Error("Expected an idle pool to be closed immediately.")
+	}
+}
+
+func TestPanicRecoveryFactory(t *testing.T) {
+	endpoint, _ := startConcurrencyServer(t)
+
+	calls := 0
+	pool := NewMultiplexedGrpcPoolWithFactory(1, func(endpoint string) (*grpc.ClientConn, error) {
+		calls++
+		if calls == 2 {
+			var metadata map[string]*string
+			_ = *metadata[endpoint] // nil pointer dereference
+		}
+
+		return grpc.Dial(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	})
+	defer pool.Close()
+
+	if _, err := executeOnStream(pool, endpoint, 1); err != nil {
+		t.Fatal(err)
+	}
+	pool.Close()
+
+	if _, err := executeOnStream(pool, endpoint, 1); err == nil || !strings.Contains(err.Error(), "panicked") {
+		t.Errorf("Expected the panic of the factory as an error, got %v", err)
+	}
+	if _, err := executeOnStream(pool, endpoint, 1); err != nil {
+		t.Errorf("Expected the pool to dial again after the panic, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls to the factory, got %d.", calls)
+	}
+}
+
+func TestPanicRecoveryFactoryFatal(t *testing.T) {
+	now := time.Unix(0, 0)
+	var fatal string
+
+	factory := panicRecoveryFactoryWithClock(func(string) (*grpc.ClientConn, error) {
+		panic("broken")
+	}, func() time.Time { return now }, func(format string, args ...interface{}) {
+		fatal = fmt.Sprintf(format, args...)
+	})
+
+	for i := 0; i < maxPanicRecoveries; i++ {
+		if conn, err := factory("endpoint"); conn != nil || err == nil {
+			t.Fatalf("Expected the panic as an error, got %v", err)
+		}
+		now = now.Add(panicWindow / maxPanicRecoveries)
+	}
+	if fatal != "" {
+		t.Fatalf("Unexpected fatal error after %d panics: %s", maxPanicRecoveries, fatal)
+	}
+
+	// The first panic is out of the window by now
+	now = now.Add(time.Second)
+	_, _ = factory("endpoint")
+	if fatal != "" {
+		t.Fatalf("Unexpected fatal error with %d panics in the window: %s", maxPanicRecoveries, fatal)
+	}
+
+	_, _ = factory("endpoint")
+	if fatal == "" {
+		t.Errorf("Expected a fatal error after %d panics in the window.", maxPanicRecoveries+1)
+	}
+}
+
+func BenchmarkGrpcConnections(b *testing.B) {
+	endpoint, _ := startConcurrencyServer(b)
+
+	b.Run("multiplexed", func(b *testing.B) {
+		pool := NewMultiplexedGrpcPool(64)
+		defer pool.Close()
+
+		b.SetParallelism(16)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := executeOnStream(pool, endpoint, 1); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+
+	b.Run("connection-per-invocation", func(b *testing.B) {
+		function := &common.Function{Name: "benchmark", Endpoint: endpoint}
+		runtimeSpec := &common.RuntimeSpecification{Runtime: 1, Memory: 128}
+		cfg := &config.LoaderConfiguration{GRPCConnectionTimeoutSeconds: 5, GRPCFunctionTimeoutSeconds: 5}
+
+		b.SetParallelism(16)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if success, _ := InvokeGRPC(function, runtimeSpec, cfg); !success {
+					b.Error("Invocation failed.")
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,281 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var errStreamBroken = errors.New("invocation stream broken")
+
+// StreamingInvoker multiplexes the invocations towards each endpoint over a single bidirectional stream of the
+// StreamingExecutor service, saving the connection and stream setup of every invocation. The replies are correlated
+// with the invocations by their request ID. A broken stream fails its pending invocations and is reopened by the next
+// invocation. Safe for concurrent use.
+type StreamingInvoker struct {
+	FunctionTimeout time.Duration
+
+	dialOptions   []grpc.DialOption
+	nextRequestID atomic.Uint64
+
+	mutex       sync.Mutex
+	connections map[string]*grpc.ClientConn
+	streams     map[string]*invocationStream
+}
+
+// invocationStream is the stream of an endpoint along with the invocations waiting for a reply on it
+type invocationStream struct {
+	stream proto.StreamingExecutor_ExecuteStreamClient
+	cancel context.CancelFunc
+
+	sendMutex sync.Mutex
+
+	mutex   sync.Mutex
+	pending map[uint64]chan *proto.StreamingReply // closed without a reply if the stream breaks
+	broken  bool
+}
+
+// NewStreamingInvoker creates an invoker dialing the endpoints with the given options, by default without TLS
+func NewStreamingInvoker(functionTimeout time.Duration, dialOptions ...grpc.DialOption) *StreamingInvoker {
+	if len(dialOptions) == 0 {
+		dialOptions = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	return &StreamingInvoker{
+		FunctionTimeout: functionTimeout,
+		dialOptions:     dialOptions,
+		connections:     make(map[string]*grpc.ClientConn),
+		streams:         make(map[string]*invocationStream),
+	}
+}
+
+// stream returns the stream to the endpoint, dialing the endpoint and opening the stream on first use
+func (i *StreamingInvoker) stream(endpoint string) (*invocationStream, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if s, ok := i.streams[endpoint]; ok {
+		return s, nil
+	}
+
+	conn, ok := i.connections[endpoint]
+	if !ok {
+		var err error
+		if conn, err = grpc.Dial(endpoint, i.dialOptions...); err != nil {
+			return nil, err
+		}
+		i.connections[endpoint] = conn
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := proto.NewStreamingExecutorClient(conn).ExecuteStream(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	s := &invocationStream{
+		stream:  stream,
+		cancel:  cancel,
+		pending: make(map[uint64]chan *proto.StreamingReply),
+	}
+	i.streams[endpoint] = s
+	go i.receive(endpoint, s)
+
+	return s, nil
+}
+
+// receive dispatches the replies of the stream to the waiting invocations until the stream breaks
+func (i *StreamingInvoker) receive(endpoint string, s *invocationStream) {
+	for {
+		reply, err := s.stream.Recv()
+		if err != nil {
+			log.Debugf("Invocation stream to %s broken - %v", endpoint, err)
+			i.discard(endpoint, s)
+
+			return
+		}
+
+		s.mutex.Lock()
+		replyChannel, ok := s.pending[reply.RequestId]
+		delete(s.pending, reply.RequestId)
+		s.mutex.Unlock()
+
+		if ok {
+			replyChannel <- reply
+		} else {
+			log.Debugf("Discarding the reply to the abandoned invocation %d on %s", reply.RequestId, endpoint)
+		}
+	}
+}
+
+// discard forgets a broken stream and fails its pending invocations
+func (i *StreamingInvoker) discard(endpoint string, s *invocationStream) {
+	i.mutex.Lock()
+	if i.streams[endpoint] == s {
+		delete(i.streams, endpoint)
+	}
+	i.mutex.Unlock()
+
+	s.cancel()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.broken = true
+	for requestID, replyChannel := range s.pending {
+		close(replyChannel)
+		delete(s.pending, requestID)
+	}
+}
+
+// send registers the invocation as pending and sends its request, returning the channel of the reply
+func (s *invocationStream) send(req *proto.StreamingRequest) (chan *proto.StreamingReply, error) {
+	replyChannel := make(chan *proto.StreamingReply, 1)
+
+	s.mutex.Lock()
+	if s.broken {
+		s.mutex.Unlock()
+		return nil, errStreamBroken
+	}
+	s.pending[req.RequestId] = replyChannel
+	s.mutex.Unlock()
+
+	s.sendMutex.Lock()
+	err := s.stream.Send(req)
+	s.sendMutex.Unlock()
+
+	if err != nil {
+		s.abandon(req.RequestId)
+		return nil, err
+	}
+
+	return replyChannel, nil
+}
+
+// abandon stops waiting for the reply of the invocation
+func (s *invocationStream) abandon(requestID uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.pending, requestID)
+}
+
+func (i *StreamingInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
+
+	record := &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+		},
+	}
+
+	start := time.Now()
+	record.StartTime = start.UnixMicro()
+
+	s, err := i.stream(function.Endpoint)
+	if err != nil {
+		log.Debugf("Failed to open an invocation stream to %s - %v", function.Endpoint, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+
+		return false, record
+	}
+
+	requestID := i.nextRequestID.Add(1)
+	replyChannel, err := s.send(&proto.StreamingRequest{
+		RequestId:         requestID,
+		RuntimeInMilliSec: uint32(runtimeSpec.Runtime),
+		MemoryInMebiBytes: uint32(runtimeSpec.Memory),
+	})
+	if err != nil {
+		log.Debugf("Failed to send the invocation of %s on the stream - %v", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+
+		return false, record
+	}
+
+	timer := time.NewTimer(i.FunctionTimeout)
+	defer timer.Stop()
+
+	var reply *proto.StreamingReply
+	select {
+	case reply = <-replyChannel:
+	case <-timer.C:
+		s.abandon(requestID)
+	}
+
+	record.ResponseTime = time.Since(start).Microseconds()
+	if reply == nil {
+		log.Debugf("Streamed invocation of %s timed out or its stream broke", function.Name)
+		record.FunctionTimeout = true
+
+		return false, record
+	}
+
+	record.Instance = extractInstanceName(reply.GetMessage())
+	record.ActualDuration = reply.DurationInMicroSec
+
+	if strings.HasPrefix(reply.GetMessage(), "FAILURE - mem_alloc") {
+		record.MemoryAllocationTimeout = true
+	} else {
+		record.ActualMemoryUsage = common.Kib2Mib(reply.MemoryUsageInKb)
+	}
+
+	log.Tracef("(Replied)\t %s: %s, %.2f[ms], %d[MiB]", function.Name, reply.Message,
+		float64(reply.DurationInMicroSec)/1e3, common.Kib2Mib(reply.MemoryUsageInKb))
+
+	return true, record
+}
+
+// Close closes the streams and the connections to all endpoints
+func (i *StreamingInvoker) Close() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	for endpoint, s := range i.streams {
+		s.cancel()
+		delete(i.streams, endpoint)
+	}
+	for endpoint, conn := range i.connections {
+		gRPCConnectionClose(conn)
+		delete(i.connections, endpoint)
+	}
+}
@@ -0,0 +1,213 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
+	"google.golang.org/grpc"
+)
+
+// streamingServer replies to each streamed invocation after the requested runtime, so that the replies of shorter
+// invocations overtake the earlier ones
+type streamingServer struct {
+	proto.UnimplementedStreamingExecutorServer
+	concurrencyServer
+}
+
+func (s *streamingServer) ExecuteStream(stream proto.StreamingExecutor_ExecuteStreamServer) error {
+	sendMutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	defer wg.Wait()
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			time.Sleep(time.Duration(req.RuntimeInMilliSec) * time.Millisecond)
+
+			sendMutex.Lock()
+			defer sendMutex.Unlock()
+			_ = stream.Send(&proto.StreamingReply{
+				RequestId:          req.RequestId,
+				Message:            "OK",
+				DurationInMicroSec: req.RuntimeInMilliSec * 1000,
+				MemoryUsageInKb:    req.MemoryInMebiBytes * 1024,
+			})
+		}()
+	}
+}
+
+func startStreamingServer(tb testing.TB) string {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	server := &streamingServer{}
+	grpcServer := grpc.NewServer()
+	proto.RegisterExecutorServer(grpcServer, &server.concurrencyServer)
+	proto.RegisterStreamingExecutorServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	tb.Cleanup(grpcServer.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestStreamingInvoker(t *testing.T) {
+	function := &common.Function{Name: "test", Endpoint: startStreamingServer(t)}
+
+	invoker := NewStreamingInvoker(5 * time.Second)
+	defer invoker.Close()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(runtime int) {
+			defer wg.Done()
+
+			success, record := invoker.Invoke(function, &common.RuntimeSpecification{Runtime: runtime, Memory: runtime})
+			if !success {
+				t.Errorf("Invocation with runtime %d failed.", runtime)
+			} else if record.ActualDuration != uint32(runtime*1000) || record.ActualMemoryUsage != uint32(runtime) {
+				t.Errorf("Invocation with runtime %d got the reply of another invocation: %+v", runtime, record)
+			}
+		}(100 - i*10)
+	}
+	wg.Wait()
+
+	if len(invoker.connections) != 1 || len(invoker.streams) != 1 {
+		t.Errorf("Expected a single stream to the endpoint, got %d connections and %d streams.",
+			len(invoker.connections), len(invoker.streams))
+	}
+	if pending := len(invoker.streams[function.Endpoint].pending); pending != 0 {
+		t.Errorf("Expected no pending invocations, got %d.", pending)
+	}
+}
+
+func TestStreamingInvokerTimeout(t *testing.T) {
+	function := &common.Function{Name: "test", Endpoint: startStreamingServer(t)}
+
+	invoker := NewStreamingInvoker(50 * time.Millisecond)
+	defer invoker.Close()
+
+	if success, record := invoker.Invoke(function, &common.RuntimeSpecification{Runtime: 200}); success || !record.FunctionTimeout {
+		t.Errorf("Expected the invocation to time out, got %+v", record)
+	}
+	if success, _ := invoker.Invoke(function, &common.RuntimeSpecification{Runtime: 1}); !success {
+		t.Error("Expected the stream to remain usable after a timeout.")
+	}
+}
+
+func TestStreamingInvokerBrokenStream(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	proto.RegisterStreamingExecutorServer(grpcServer, &streamingServer{})
+	go grpcServer.Serve(listener)
+
+	function := &common.Function{Name: "test", Endpoint: listener.Addr().String()}
+	invoker := NewStreamingInvoker(5 * time.Second)
+	defer invoker.Close()
+
+	time.AfterFunc(50*time.Millisecond, grpcServer.Stop)
+	success, record := invoker.Invoke(function, &common.RuntimeSpecification{Runtime: 1000})
+	if success || !record.FunctionTimeout {
+		t.Errorf("Expected the invocation to fail with the stream, got %+v", record)
+	}
+	if record.ResponseTime >= time.Second.Microseconds() {
+		t.Error("Expected the pending invocation to fail as soon as the stream broke.")
+	}
+	if len(invoker.streams) != 0 {
+		t.Error("Expected the broken stream to be discarded.")
+	}
+}
+
+// BenchmarkStreamingInvoker compares the mean response time of 1000 concurrent invocations multiplexed on a stream
+// against unary RPCs on a connection each
+func BenchmarkStreamingInvoker(b *testing.B) {
+	const concurrency = 1000
+
+	function := &common.Function{Name: "benchmark", Endpoint: startStreamingServer(b)}
+	runtimeSpec := &common.RuntimeSpecification{Runtime: 1, Memory: 128}
+
+	run := func(b *testing.B, invoke func() (bool, *mc.ExecutionRecord)) {
+		var totalResponseTime atomic.Int64
+		for n := 0; n < b.N; n++ {
+			wg := sync.WaitGroup{}
+			for i := 0; i < concurrency; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					success, record := invoke()
+					if !success {
+						b.Error("Invocation failed.")
+					}
+					totalResponseTime.Add(record.ResponseTime)
+				}()
+			}
+			wg.Wait()
+		}
+
+		b.ReportMetric(float64(totalResponseTime.Load())/float64(b.N*concurrency)/1e3, "ms/invocation")
+	}
+
+	b.Run("streaming", func(b *testing.B) {
+		invoker := NewStreamingInvoker(10 * time.Second)
+		defer invoker.Close()
+
+		run(b, func() (bool, *mc.ExecutionRecord) {
+			return invoker.Invoke(function, runtimeSpec)
+		})
+	})
+
+	b.Run("unary", func(b *testing.B) {
+		cfg := &config.LoaderConfiguration{GRPCConnectionTimeoutSeconds: 10, GRPCFunctionTimeoutSeconds: 10}
+
+		run(b, func() (bool, *mc.ExecutionRecord) {
+			return InvokeGRPC(function, runtimeSpec, cfg)
+		})
+	})
+}
@@ -28,16 +28,18 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io"
 	"net/http"
 	"os/exec"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
 	mc "github.com/vhive-serverless/loader/pkg/metric"
 )
 
@@ -51,12 +53,59 @@ type ActivationMetadata struct {
 type HTTPResBody struct {
 	DurationInMicroSec uint32 `json:"DurationInMicroSec"`
 	MemoryUsageInKb    uint32 `json:"MemoryUsageInKb"`
+	IOLatencyMicroSec  uint32 `json:"IOLatencyMicroSec,omitempty"`
+}
+
+type awsLambdaRequest struct {
+	RuntimeInMilliSec int                `json:"RuntimeInMilliSec"`
+	MemoryInMebiBytes int                `json:"MemoryInMebiBytes"`
+	IOWorkload        *common.IOWorkload `json:"IOWorkload,omitempty"`
+	HoldDurationMs    int                `json:"HoldDurationMs,omitempty"`  // read by the memory handler only
+	MemoryAllocMode   string             `json:"MemoryAllocMode,omitempty"` // read by the memory handler only
+	ComputeMode       string             `json:"ComputeMode,omitempty"`
+	IdempotencyKey    string             `json:"IdempotencyKey,omitempty"`
+}
+
+type httpInvocationOptions struct {
+	idempotencyKey string
+	headers        map[string]string
+	pin            *TLSPinConfig
+}
+
+// HTTPInvocationOption customizes a single HTTP invocation of a function
+type HTTPInvocationOption func(*httpInvocationOptions)
+
+// WithIdempotencyKey sets the idempotency key of the invocation, see common.IdempotencyKey. A function deployed with
+// an idempotency table records the response under the key, so that retries carrying the same key receive the recorded
+// response instead of executing the function again.
+func WithIdempotencyKey(key string) HTTPInvocationOption {
+	return func(o *httpInvocationOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// WithTLSPin reuses the clients of the pin across the invocations instead of building them for a single invocation
+func WithTLSPin(pin *TLSPinConfig) HTTPInvocationOption {
+	return func(o *httpInvocationOptions) {
+		o.pin = pin
+	}
+}
+
+// WithInvocationIndex identifies the invocation of the trace to the function through the X-Invitro- headers
+func WithInvocationIndex(minute int, index int) HTTPInvocationOption {
+	return func(o *httpInvocationOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[common.InvitroMinuteHeader] = strconv.Itoa(minute)
+		o.headers[common.InvitroInvocationIndexHeader] = strconv.Itoa(index)
+	}
 }
 
 func InvokeOpenWhisk(function *common.Function, runtimeSpec *common.RuntimeSpecification, AnnounceDoneExe *sync.WaitGroup, ReadOpenWhiskMetadata *sync.Mutex) (bool, *mc.ExecutionRecord) {
 	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
-	success, executionRecordBase, res := httpInvocation("", function, AnnounceDoneExe, true)
+	success, executionRecordBase, res := httpInvocation("", function, nil, AnnounceDoneExe, true, nil)
 	AnnounceDoneExe.Wait() // To postpone querying OpenWhisk during the experiment for performance reasons (Issue 329: https://github.com/vhive-serverless/invitro/issues/329)
 
 	executionRecordBase.RequestedDuration = uint32(runtimeSpec.Runtime * 1e3)
@@ -130,11 +179,40 @@ func parseActivationMetadata(response string) (error, ActivationMetadata) {
 	return nil, result
 }
 
-func InvokeAWSLambda(function *common.Function, runtimeSpec *common.RuntimeSpecification, AnnounceDoneExe *sync.WaitGroup) (bool, *mc.ExecutionRecord) {
+func InvokeAWSLambda(function *common.Function, runtimeSpec *common.RuntimeSpecification, cfg *config.LoaderConfiguration, AnnounceDoneExe *sync.WaitGroup, opts ...HTTPInvocationOption) (bool, *mc.ExecutionRecord) {
 	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
-	dataString := fmt.Sprintf(`{"RuntimeInMilliSec": %d, "MemoryInMebiBytes": %d}`, runtimeSpec.Runtime, runtimeSpec.Memory)
-	success, executionRecordBase, res := httpInvocation(dataString, function, AnnounceDoneExe, false)
+	options := httpInvocationOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	request := awsLambdaRequest{
+		RuntimeInMilliSec: runtimeSpec.Runtime,
+		MemoryInMebiBytes: runtimeSpec.Memory,
+		IOWorkload:        cfg.IOWorkload,
+		ComputeMode:       cfg.ComputeMode,
+	}
+	if cfg.AWSLambdaHandler == common.AwsLambdaHandlerMemory {
+		// The memory handler holds the allocation instead of spinning the CPU for the sampled runtime
+		request.HoldDurationMs = runtimeSpec.Runtime
+		request.MemoryAllocMode = cfg.MemoryAllocMode
+	}
+	request.IdempotencyKey = options.idempotencyKey
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pin := options.pin
+	if pin == nil && cfg.TLSPinnedCertHex != "" {
+		pin = &TLSPinConfig{PinnedCertHex: cfg.TLSPinnedCertHex}
+		defer pin.CloseIdleConnections() // the clients are not reused after this invocation
+	}
+
+	dataString := string(data)
+	success, executionRecordBase, res := httpInvocation(dataString, function, options.headers, AnnounceDoneExe, false, pin)
 
 	executionRecordBase.RequestedDuration = uint32(runtimeSpec.Runtime * 1e3)
 	record := &mc.ExecutionRecord{ExecutionRecordBase: *executionRecordBase}
@@ -161,13 +239,15 @@ func InvokeAWSLambda(function *common.Function, runtimeSpec *common.RuntimeSpeci
 
 	record.ActualDuration = httpResBody.DurationInMicroSec
 	record.ActualMemoryUsage = common.Kib2Mib(httpResBody.MemoryUsageInKb)
+	record.IOLatency = httpResBody.IOLatencyMicroSec
 
 	logInvocationSummary(function, &record.ExecutionRecordBase, res)
 
 	return true, record
 }
 
-func httpInvocation(dataString string, function *common.Function, AnnounceDoneExe *sync.WaitGroup, tlsSkipVerify bool) (bool, *mc.ExecutionRecordBase, *http.Response) {
+// httpInvocation sends the payload to the function with its custom headers, followed by the given headers
+func httpInvocation(dataString string, function *common.Function, headers map[string]string, AnnounceDoneExe *sync.WaitGroup, tlsSkipVerify bool, pin *TLSPinConfig) (bool, *mc.ExecutionRecordBase, *http.Response) {
 	record := &mc.ExecutionRecordBase{}
 
 	start := time.Now()
@@ -175,12 +255,26 @@ func httpInvocation(dataString string, function *common.Function, AnnounceDoneEx
 	record.Instance = function.Name
 	requestURL := function.Endpoint
 
+	client := http.DefaultClient
+	if pin != nil {
+		pinned, err := pin.Client(requestURL)
+		if err != nil {
+			log.Debugf("http request for function %s refused - %s", function.Name, err)
+
+			record.ResponseTime = time.Since(start).Microseconds()
+			record.CertPinViolation = true
+
+			AnnounceDoneExe.Done()
+
+			return false, record, nil
+		}
+		client = pinned
+	}
+
 	if tlsSkipVerify {
 		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	}
 	req, err := http.NewRequest(http.MethodGet, requestURL, bytes.NewBuffer([]byte(dataString)))
-	req.Header.Set("Content-Type", "application/json") // To avoid data being base64encoded
-
 	if err != nil {
 		log.Debugf("http request creation failed for function %s - %s", function.Name, err)
 
@@ -191,13 +285,24 @@ func httpInvocation(dataString string, function *common.Function, AnnounceDoneEx
 
 		return false, record, nil
 	}
+	req.Header.Set("Content-Type", "application/json") // To avoid data being base64encoded
+	for name, value := range function.CustomHeaders {
+		req.Header.Set(name, value)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := client.Do(req)
 	if err != nil {
 		log.Debugf("http request for function %s failed - %s", function.Name, err)
 
 		record.ResponseTime = time.Since(start).Microseconds()
-		record.ConnectionTimeout = true
+		if errors.Is(err, ErrCertPinViolation) {
+			record.CertPinViolation = true
+		} else {
+			record.ConnectionTimeout = true
+		}
 
 		AnnounceDoneExe.Done()
 
@@ -209,6 +314,7 @@ func httpInvocation(dataString string, function *common.Function, AnnounceDoneEx
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.ConnectionTimeout = true
+		record.Throttled = res.StatusCode == http.StatusTooManyRequests
 
 		AnnounceDoneExe.Done()
 
@@ -0,0 +1,134 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestInvokeAWSLambdaWithIdempotency(t *testing.T) {
+	requests := make(chan awsLambdaRequest, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request awsLambdaRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Error(err)
+		}
+		requests <- request
+
+		_ = json.NewEncoder(w).Encode(HTTPResBody{DurationInMicroSec: 1000, MemoryUsageInKb: 1024})
+	}))
+	defer server.Close()
+
+	function := &common.Function{Name: "trace-func-0", Endpoint: server.URL}
+	runtimeSpec := &common.RuntimeSpecification{Runtime: 1, Memory: 128}
+	key := common.IdempotencyKey("experiment", function.Name, 1, 2, 42)
+
+	announceDone := &sync.WaitGroup{}
+	announceDone.Add(2)
+	if success, _ := InvokeAWSLambda(function, runtimeSpec, &config.LoaderConfiguration{}, announceDone); !success {
+		t.Fatal("Invocation without idempotency failed.")
+	}
+	if success, _ := InvokeAWSLambda(function, runtimeSpec, &config.LoaderConfiguration{}, announceDone,
+		WithIdempotencyKey(key)); !success {
+		t.Fatal("Idempotent invocation failed.")
+	}
+
+	if plain := <-requests; plain.IdempotencyKey != "" {
+		t.Errorf("Expected no idempotency key without the option, got %+v.", plain)
+	}
+	if idempotent := <-requests; idempotent.IdempotencyKey != key {
+		t.Errorf("Expected the idempotency key to be sent, got %+v.", idempotent)
+	}
+	if key != common.IdempotencyKey("experiment", function.Name, 1, 2, 42) || key == common.IdempotencyKey("experiment", function.Name, 1, 3, 42) {
+		t.Error("Expected the idempotency key to identify the invocation deterministically.")
+	}
+	if key == common.IdempotencyKey("another-experiment", function.Name, 1, 2, 42) {
+		t.Error("Expected the idempotency keys of different experiments to differ.")
+	}
+}
+
+func TestInvokeAWSLambdaRecordsIOLatency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(HTTPResBody{DurationInMicroSec: 30000, MemoryUsageInKb: 1024, IOLatencyMicroSec: 20000})
+	}))
+	defer server.Close()
+
+	function := &common.Function{Name: "trace-func-0", Endpoint: server.URL}
+	runtimeSpec := &common.RuntimeSpecification{Runtime: 30, Memory: 128}
+	cfg := &config.LoaderConfiguration{IOWorkload: &common.IOWorkload{}}
+
+	announceDone := &sync.WaitGroup{}
+	announceDone.Add(1)
+	success, record := InvokeAWSLambda(function, runtimeSpec, cfg, announceDone)
+	if !success {
+		t.Fatal("Invocation failed.")
+	}
+	if record.IOLatency != 20000 || record.ActualDuration != 30000 {
+		t.Errorf("Expected the I/O latency of 20000µs to be recorded, got %+v.", record)
+	}
+}
+
+func TestInvokeAWSLambdaHeaders(t *testing.T) {
+	headers := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Clone()
+
+		_ = json.NewEncoder(w).Encode(HTTPResBody{DurationInMicroSec: 1000, MemoryUsageInKb: 1024})
+	}))
+	defer server.Close()
+
+	function := &common.Function{
+		Name:          "trace-func-0",
+		Endpoint:      server.URL,
+		CustomHeaders: map[string]string{"X-Tenant": "tenant-a", "Authorization": "Bearer token"},
+	}
+	runtimeSpec := &common.RuntimeSpecification{Runtime: 1, Memory: 128}
+
+	announceDone := &sync.WaitGroup{}
+	announceDone.Add(1)
+	if success, _ := InvokeAWSLambda(function, runtimeSpec, &config.LoaderConfiguration{}, announceDone, WithInvocationIndex(3, 7)); !success {
+		t.Fatal("Invocation failed.")
+	}
+
+	received := <-headers
+	for name, expected := range map[string]string{
+		"X-Tenant":                          "tenant-a",
+		"Authorization":                     "Bearer token",
+		common.InvitroMinuteHeader:          "3",
+		common.InvitroInvocationIndexHeader: "7",
+		"Content-Type":                      "application/json",
+	} {
+		if value := received.Get(name); value != expected {
+			t.Errorf("Expected header %s to be %q, got %q.", name, expected, value)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// LambdaImageTargetBytes is the image size OptimizeImageForLambda aims for, that of the largest unzipped .zip
+// deployment package, as the cold-start latency of AWS Lambda grows with the size of the image
+const LambdaImageTargetBytes = 250 * 1024 * 1024
+
+// runDockerCommand runs the Docker CLI with the given arguments and returns its standard output, replaced in tests
+var runDockerCommand = func(args ...string) ([]byte, error) {
+	return exec.Command("docker", args...).Output()
+}
+
+type LayerInfo struct {
+	Digest    string // "<missing>" for the layers of pulled base images
+	SizeBytes int64
+	CreatedBy string // instruction of the Dockerfile that created the layer
+}
+
+// ImageAnalysis breaks down the size of a local container image by layer, newest layer first
+type ImageAnalysis struct {
+	TotalBytes int64
+	Layers     []LayerInfo
+}
+
+// dockerHistoryEntry is a line of `docker history --format '{{json .}}'`
+type dockerHistoryEntry struct {
+	ID        string `json:"ID"`
+	CreatedBy string `json:"CreatedBy"`
+	Size      string `json:"Size"`
+}
+
+// AnalyzeImageSize inspects the size of the local image and of its layers with the Docker CLI
+func AnalyzeImageSize(imageName string) (ImageAnalysis, error) {
+	analysis := ImageAnalysis{}
+
+	output, err := runDockerCommand("image", "inspect", "--format", "{{json .Size}}", imageName)
+	if err != nil {
+		return analysis, fmt.Errorf("failed to inspect image %s: %w", imageName, err)
+	}
+	if err = json.Unmarshal(bytes.TrimSpace(output), &analysis.TotalBytes); err != nil {
+		return analysis, fmt.Errorf("invalid size of image %s: %w", imageName, err)
+	}
+
+	output, err = runDockerCommand("history", "--human=false", "--no-trunc", "--format", "{{json .}}", imageName)
+	if err != nil {
+		return analysis, fmt.Errorf("failed to read the history of image %s: %w", imageName, err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // long RUN instructions
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry dockerHistoryEntry
+		if err = json.Unmarshal(line, &entry); err != nil {
+			return analysis, fmt.Errorf("invalid history entry of image %s: %w", imageName, err)
+		}
+
+		size, err := strconv.ParseInt(entry.Size, 10, 64)
+		if err != nil {
+			return analysis, fmt.Errorf("invalid layer size %q of image %s: %w", entry.Size, imageName, err)
+		}
+
+		analysis.Layers = append(analysis.Layers, LayerInfo{
+			Digest:    entry.ID,
+			SizeBytes: size,
+			CreatedBy: strings.TrimSpace(entry.CreatedBy),
+		})
+	}
+
+	return analysis, scanner.Err()
+}
+
+// OptimizeImageForLambda suggests the layers of the local image to remove or slim down, largest first, to bring the
+// image below LambdaImageTargetBytes. Nothing is suggested if the image is small enough or cannot be analyzed.
+func OptimizeImageForLambda(imageName string) []string {
+	analysis, err := AnalyzeImageSize(imageName)
+	if err != nil {
+		log.Warnf("Failed to analyze the size of image %s: %s", imageName, err)
+		return nil
+	}
+
+	return suggestLayerRemovals(analysis, LambdaImageTargetBytes)
+}
+
+func suggestLayerRemovals(analysis ImageAnalysis, targetBytes int64) []string {
+	layers := append([]LayerInfo(nil), analysis.Layers...)
+	sort.SliceStable(layers, func(i, j int) bool {
+		return layers[i].SizeBytes > layers[j].SizeBytes
+	})
+
+	var suggestions []string
+	remaining := analysis.TotalBytes
+	for _, layer := range layers {
+		if remaining <= targetBytes || layer.SizeBytes == 0 {
+			break
+		}
+
+		remaining -= layer.SizeBytes
+		suggestions = append(suggestions, fmt.Sprintf("Remove or slim down layer %s (%.1f MiB): %s",
+			layer.Digest, float64(layer.SizeBytes)/(1024*1024), layer.CreatedBy))
+	}
+
+	if remaining > targetBytes {
+		log.Warnf("Removing all the layers suggested leaves %.1f MiB, above the target of %.1f MiB",
+			float64(remaining)/(1024*1024), float64(targetBytes)/(1024*1024))
+	}
+
+	return suggestions
+}
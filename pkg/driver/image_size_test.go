@@ -0,0 +1,151 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDockerHistory = `{"Comment":"","CreatedAt":"2024-01-01T00:00:00Z","CreatedBy":"CMD [\"/app/server\"]","CreatedSince":"1 month ago","ID":"sha256:c3","Size":"0"}
+{"Comment":"","CreatedAt":"2024-01-01T00:00:00Z","CreatedBy":"COPY /build/server /app/server # buildkit","CreatedSince":"1 month ago","ID":"sha256:c2","Size":"20971520"}
+{"Comment":"","CreatedAt":"2024-01-01T00:00:00Z","CreatedBy":"RUN /bin/sh -c apt-get install -y build-essential","CreatedSince":"1 month ago","ID":"<missing>","Size":"209715200"}
+{"Comment":"","CreatedAt":"2024-01-01T00:00:00Z","CreatedBy":"/bin/sh -c #(nop) ADD file:0a1b in /","CreatedSince":"2 months ago","ID":"<missing>","Size":"83886080"}
+`
+
+func mockDocker(t *testing.T, outputs map[string]string, err error) *[][]string {
+	var calls [][]string
+
+	originalRun := runDockerCommand
+	runDockerCommand = func(args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		if err != nil {
+			return nil, err
+		}
+
+		return []byte(outputs[args[0]]), nil
+	}
+	t.Cleanup(func() {
+		runDockerCommand = originalRun
+	})
+
+	return &calls
+}
+
+func TestAnalyzeImageSize(t *testing.T) {
+	calls := mockDocker(t, map[string]string{"image": "314572800\n", "history": testDockerHistory}, nil)
+
+	analysis, err := AnalyzeImageSize("trace-func:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ImageAnalysis{
+		TotalBytes: 314572800,
+		Layers: []LayerInfo{
+			{Digest: "sha256:c3", SizeBytes: 0, CreatedBy: `CMD ["/app/server"]`},
+			{Digest: "sha256:c2", SizeBytes: 20971520, CreatedBy: "COPY /build/server /app/server # buildkit"},
+			{Digest: "<missing>", SizeBytes: 209715200, CreatedBy: "RUN /bin/sh -c apt-get install -y build-essential"},
+			{Digest: "<missing>", SizeBytes: 83886080, CreatedBy: "/bin/sh -c #(nop) ADD file:0a1b in /"},
+		},
+	}
+	if !reflect.DeepEqual(analysis, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, analysis)
+	}
+
+	if len(*calls) != 2 || strings.Join((*calls)[0], " ") != "image inspect --format {{json .Size}} trace-func:latest" ||
+		strings.Join((*calls)[1], " ") != "history --human=false --no-trunc --format {{json .}} trace-func:latest" {
+
+		t.Errorf("Unexpected Docker commands %v", *calls)
+	}
+}
+
+func TestAnalyzeImageSizeErrors(t *testing.T) {
+	tests := []struct {
+		testName string
+		outputs  map[string]string
+		err      error
+	}{
+		{testName: "docker_failure", err: errors.New("no such image")},
+		{testName: "invalid_size", outputs: map[string]string{"image": "\"large\"", "history": testDockerHistory}},
+		{testName: "invalid_history", outputs: map[string]string{"image": "1", "history": "not json"}},
+		{testName: "human_readable_layer_size", outputs: map[string]string{"image": "1", "history": `{"ID":"sha256:c1","Size":"20MB"}`}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			mockDocker(t, test.outputs, test.err)
+
+			if _, err := AnalyzeImageSize("trace-func:latest"); err == nil {
+				t.Error("Expected an error.")
+			}
+		})
+	}
+}
+
+func TestOptimizeImageForLambda(t *testing.T) {
+	// 300 MiB, of which removing the 200 MiB layer suffices
+	mockDocker(t, map[string]string{"image": "314572800", "history": testDockerHistory}, nil)
+
+	suggestions := OptimizeImageForLambda("trace-func:latest")
+	if len(suggestions) != 1 || !strings.Contains(suggestions[0], "(200.0 MiB): RUN /bin/sh -c apt-get install") {
+		t.Errorf("Expected the build tools layer to be suggested, got %v", suggestions)
+	}
+
+	// Small enough already
+	mockDocker(t, map[string]string{"image": "104857600", "history": testDockerHistory}, nil)
+	if suggestions = OptimizeImageForLambda("trace-func:latest"); len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions, got %v", suggestions)
+	}
+
+	mockDocker(t, nil, errors.New("docker not installed"))
+	if suggestions = OptimizeImageForLambda("trace-func:latest"); suggestions != nil {
+		t.Errorf("Expected no suggestions without analysis, got %v", suggestions)
+	}
+}
+
+func TestSuggestLayerRemovals(t *testing.T) {
+	analysis := ImageAnalysis{
+		TotalBytes: 1000,
+		Layers: []LayerInfo{
+			{Digest: "a", SizeBytes: 100},
+			{Digest: "b", SizeBytes: 400},
+			{Digest: "c", SizeBytes: 300},
+			{Digest: "d", SizeBytes: 200},
+		},
+	}
+
+	suggestions := suggestLayerRemovals(analysis, 400)
+	if len(suggestions) != 2 || !strings.Contains(suggestions[0], "layer b ") || !strings.Contains(suggestions[1], "layer c ") {
+		t.Errorf("Expected the two largest layers, got %v", suggestions)
+	}
+
+	// Unreachable target: all the layers are suggested
+	if suggestions = suggestLayerRemovals(analysis, 0); len(suggestions) != 4 {
+		t.Errorf("Expected all layers, got %v", suggestions)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// Invoker issues a single invocation of a function and reports its outcome
+type Invoker interface {
+	Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord)
+}
+
+// SimulatedInvoker emulates function invocations without contacting any platform. Each invocation returns
+// immediately and reports the requested runtime as the execution time of the function.
+//
+// Every invocation occupies an instance of the function until it completes. An invocation arriving while all
+// instances of the function are busy starts a new instance and pays the cold-start latency. Idle instances are kept
+// alive forever unless a ContainerReuseModel is set.
+type SimulatedInvoker struct {
+	// NetworkLatency is added to the runtime of the function to obtain the response time
+	NetworkLatency time.Duration
+	// ColdStartLatency is added to the response time of the invocations that start a new instance
+	ColdStartLatency time.Duration
+	// ContainerReuse bounds how long and how many idle instances are kept alive, nil for no bound
+	ContainerReuse *ContainerReuseModel
+
+	clock       func() time.Time
+	invocations atomic.Int64
+	coldStarts  atomic.Int64
+
+	instancesMutex sync.Mutex
+	instances      map[string][]time.Time // per function, the time at which each instance becomes idle
+}
+
+// ContainerReuseModel decides which idle instances the platform keeps alive. An instance idle for more than
+// KeepAliveSeconds is shut down, so the next invocation of the function is a cold start. At most MaxContainers
+// instances of all the functions are kept, shutting down the least recently used idle instance to make room for a
+// new one.
+type ContainerReuseModel struct {
+	KeepAliveSeconds int
+	MaxContainers    int // 0 for no limit
+}
+
+func NewSimulatedInvoker(networkLatency time.Duration) *SimulatedInvoker {
+	return newSimulatedInvokerWithClock(networkLatency, time.Now)
+}
+
+func newSimulatedInvokerWithClock(networkLatency time.Duration, clock func() time.Time) *SimulatedInvoker {
+	return &SimulatedInvoker{
+		NetworkLatency: networkLatency,
+		clock:          clock,
+		instances:      make(map[string][]time.Time),
+	}
+}
+
+func (s *SimulatedInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	return s.InvokeAt(function, runtimeSpec, s.clock())
+}
+
+// InvokeAt simulates an invocation of the function arriving at the given time
+func (s *SimulatedInvoker) InvokeAt(function *common.Function, runtimeSpec *common.RuntimeSpecification, at time.Time) (bool, *mc.ExecutionRecord) {
+	s.invocations.Add(1)
+
+	runtime := time.Duration(runtimeSpec.Runtime) * time.Millisecond
+	responseTime := runtime + s.NetworkLatency
+	if s.acquireInstance(function.Name, at, runtime) {
+		responseTime += s.ColdStartLatency
+	}
+
+	return true, &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			Instance:          function.Name,
+			StartTime:         at.UnixMicro(),
+			RequestedDuration: uint32(runtime.Microseconds()),
+			ResponseTime:      responseTime.Microseconds(),
+			ActualDuration:    uint32(runtime.Microseconds()),
+		},
+		ActualMemoryUsage: uint32(runtimeSpec.Memory),
+	}
+}
+
+// acquireInstance occupies an idle instance of the function for the given runtime, starting a new instance if none
+// is idle at the time of arrival. Returns whether the invocation is a cold start.
+func (s *SimulatedInvoker) acquireInstance(function string, at time.Time, runtime time.Duration) bool {
+	s.instancesMutex.Lock()
+	defer s.instancesMutex.Unlock()
+
+	if s.ContainerReuse != nil {
+		s.expireInstances(function, at)
+	}
+
+	instances := s.instances[function]
+	for i, idleAt := range instances {
+		if !idleAt.After(at) {
+			instances[i] = at.Add(runtime)
+			return false
+		}
+	}
+
+	if s.ContainerReuse != nil && s.ContainerReuse.MaxContainers > 0 {
+		s.evictLeastRecentlyUsed(at)
+	}
+
+	s.instances[function] = append(s.instances[function], at.Add(s.ColdStartLatency+runtime))
+	s.coldStarts.Add(1)
+
+	return true
+}
+
+// expireInstances shuts down the instances of the function idle for longer than the keep-alive at the given time.
+// Should be called only when instancesMutex is locked.
+func (s *SimulatedInvoker) expireInstances(function string, at time.Time) {
+	keepAlive := time.Duration(s.ContainerReuse.KeepAliveSeconds) * time.Second
+
+	var alive []time.Time
+	for _, idleAt := range s.instances[function] {
+		if at.Sub(idleAt) <= keepAlive {
+			alive = append(alive, idleAt)
+		}
+	}
+	s.instances[function] = alive
+}
+
+// evictLeastRecentlyUsed shuts down the idle instances that have been idle the longest until there is room for a new
+// instance. Busy instances are never shut down. Should be called only when instancesMutex is locked.
+func (s *SimulatedInvoker) evictLeastRecentlyUsed(at time.Time) {
+	for {
+		total := 0
+		lruFunction, lruIndex := "", -1
+		for function, instances := range s.instances {
+			total += len(instances)
+			for i, idleAt := range instances {
+				if !idleAt.After(at) && (lruIndex < 0 || idleAt.Before(s.instances[lruFunction][lruIndex])) {
+					lruFunction, lruIndex = function, i
+				}
+			}
+		}
+
+		if total < s.ContainerReuse.MaxContainers || lruIndex < 0 {
+			return
+		}
+
+		instances := s.instances[lruFunction]
+		s.instances[lruFunction] = append(instances[:lruIndex], instances[lruIndex+1:]...)
+	}
+}
+
+// Invocations returns the number of simulated invocations so far
+func (s *SimulatedInvoker) Invocations() int64 {
+	return s.invocations.Load()
+}
+
+// ColdStarts returns the number of simulated invocations that started a new instance
+func (s *SimulatedInvoker) ColdStarts() int64 {
+	return s.coldStarts.Load()
+}
@@ -0,0 +1,170 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestSimulatedInvokerColdStarts(t *testing.T) {
+	invoker := NewSimulatedInvoker(time.Millisecond)
+	invoker.ColdStartLatency = 500 * time.Millisecond
+
+	start := time.Unix(0, 0)
+	spec := &common.RuntimeSpecification{Runtime: 100, Memory: 128}
+
+	_, first := invoker.InvokeAt(&testFunction, spec, start)
+	// The first instance is still starting up
+	_, concurrent := invoker.InvokeAt(&testFunction, spec, start.Add(100*time.Millisecond))
+	// The first instance is idle again
+	_, warm := invoker.InvokeAt(&testFunction, spec, start.Add(time.Second))
+
+	if first.ResponseTime != (601 * time.Millisecond).Microseconds() {
+		t.Errorf("Expected the first invocation to be a cold start, got a response time of %d μs.", first.ResponseTime)
+	}
+	if concurrent.ResponseTime != first.ResponseTime {
+		t.Errorf("Expected the concurrent invocation to be a cold start, got a response time of %d μs.", concurrent.ResponseTime)
+	}
+	if warm.ResponseTime != (101 * time.Millisecond).Microseconds() {
+		t.Errorf("Expected the last invocation to be warm, got a response time of %d μs.", warm.ResponseTime)
+	}
+	if invoker.ColdStarts() != 2 || invoker.Invocations() != 3 {
+		t.Errorf("Expected 2 cold starts out of 3 invocations, got %d out of %d.", invoker.ColdStarts(), invoker.Invocations())
+	}
+}
+
+func TestSimulatedInvokerKeepAlive(t *testing.T) {
+	invoker := NewSimulatedInvoker(0)
+	invoker.ColdStartLatency = 500 * time.Millisecond
+	invoker.ContainerReuse = &ContainerReuseModel{KeepAliveSeconds: 600}
+
+	start := time.Unix(0, 0)
+	spec := &common.RuntimeSpecification{Runtime: 100, Memory: 128}
+
+	_, first := invoker.InvokeAt(&testFunction, spec, start)
+	// Idle since 600 ms for 5 minutes
+	_, withinKeepAlive := invoker.InvokeAt(&testFunction, spec, start.Add(5*time.Minute))
+	// Idle since 5 minutes 100 ms for more than 10 minutes
+	_, beyondKeepAlive := invoker.InvokeAt(&testFunction, spec, start.Add(16*time.Minute))
+
+	if first.ResponseTime != (600 * time.Millisecond).Microseconds() {
+		t.Errorf("Expected the first invocation to be a cold start, got a response time of %d μs.", first.ResponseTime)
+	}
+	if withinKeepAlive.ResponseTime != (100 * time.Millisecond).Microseconds() {
+		t.Errorf("Expected the invocation within the keep-alive to be warm, got a response time of %d μs.", withinKeepAlive.ResponseTime)
+	}
+	if beyondKeepAlive.ResponseTime != first.ResponseTime {
+		t.Errorf("Expected the invocation beyond the keep-alive to be a cold start, got a response time of %d μs.", beyondKeepAlive.ResponseTime)
+	}
+	if invoker.ColdStarts() != 2 {
+		t.Errorf("Expected 2 cold starts, got %d.", invoker.ColdStarts())
+	}
+}
+
+func TestSimulatedInvokerMaxContainers(t *testing.T) {
+	invoker := NewSimulatedInvoker(0)
+	invoker.ContainerReuse = &ContainerReuseModel{KeepAliveSeconds: 600, MaxContainers: 2}
+
+	start := time.Unix(0, 0)
+	spec := &common.RuntimeSpecification{Runtime: 100, Memory: 128}
+	functions := []*common.Function{{Name: "f0"}, {Name: "f1"}, {Name: "f2"}}
+
+	invoker.InvokeAt(functions[0], spec, start)
+	invoker.InvokeAt(functions[1], spec, start.Add(time.Second))
+	invoker.InvokeAt(functions[0], spec, start.Add(2*time.Second)) // warm, f1 is now the least recently used
+	invoker.InvokeAt(functions[2], spec, start.Add(3*time.Second)) // shuts down the instance of f1
+	if invoker.ColdStarts() != 3 {
+		t.Fatalf("Expected 3 cold starts, got %d.", invoker.ColdStarts())
+	}
+
+	invoker.InvokeAt(functions[0], spec, start.Add(4*time.Second))
+	if invoker.ColdStarts() != 3 {
+		t.Errorf("Expected the most recently used instance to be kept, got %d cold starts.", invoker.ColdStarts())
+	}
+	invoker.InvokeAt(functions[1], spec, start.Add(5*time.Second))
+	if invoker.ColdStarts() != 4 {
+		t.Errorf("Expected the least recently used instance to be shut down, got %d cold starts.", invoker.ColdStarts())
+	}
+
+	// Busy instances are never shut down
+	for i := 0; i < 3; i++ {
+		invoker.InvokeAt(functions[0], spec, start.Add(time.Minute))
+	}
+	if invoker.ColdStarts() != 6 {
+		t.Errorf("Expected the concurrent invocations to start instances beyond the limit, got %d cold starts.", invoker.ColdStarts())
+	}
+}
+
+// simulateResponseTimes issues the invocations of the specification at the times given by its IATs and returns the
+// sorted response times in milliseconds
+func simulateResponseTimes(function *common.Function, spec *common.FunctionSpecification) []float64 {
+	invoker := NewSimulatedInvoker(time.Millisecond)
+	invoker.ColdStartLatency = 500 * time.Millisecond
+
+	var responseTimes []float64
+	for minute, row := range spec.IAT {
+		at := time.Unix(int64(minute*60), 0)
+		for i := 0; i < len(row)-1; i++ {
+			at = at.Add(time.Duration(row[i]) * time.Microsecond)
+
+			_, record := invoker.InvokeAt(function, &spec.RuntimeSpecification[minute][i], at)
+			responseTimes = append(responseTimes, float64(record.ResponseTime)/1000)
+		}
+	}
+
+	sort.Float64s(responseTimes)
+
+	return responseTimes
+}
+
+func TestSpikeModeIncreasesTailLatency(t *testing.T) {
+	function := &common.Function{
+		Name:            "spiky-function",
+		InvocationStats: &common.FunctionInvocationStats{Invocations: []int{60, 60, 60, 60, 60}},
+		RuntimeStats:    &common.FunctionRuntimeStats{Count: 1, Percentile0: 200, Percentile1: 200, Percentile25: 200, Percentile50: 200, Percentile75: 200, Percentile99: 200, Percentile100: 200},
+		MemoryStats:     &common.FunctionMemoryStats{Count: 1, Percentile1: 128, Percentile5: 128, Percentile25: 128, Percentile50: 128, Percentile75: 128, Percentile95: 128, Percentile99: 128, Percentile100: 128},
+	}
+
+	var p99 [2]float64
+	for i, spikeMode := range []bool{false, true} {
+		driver := NewDriver(&DriverConfiguration{
+			LoaderConfiguration: createFakeLoaderConfiguration(),
+			IATDistribution:     common.Equidistant,
+			TraceGranularity:    common.MinuteGranularity,
+			TraceDuration:       5,
+			SpikeMode:           spikeMode,
+		})
+
+		p99[i] = percentileOfSorted(simulateResponseTimes(function, driver.generateSpecification(function)), 0.99)
+	}
+
+	if p99[1] <= p99[0] {
+		t.Errorf("Expected spike mode to increase the p99 response time, got %.1f ms (normal) and %.1f ms (spike).", p99[0], p99[1])
+	}
+}
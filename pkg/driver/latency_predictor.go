@@ -0,0 +1,192 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"math"
+	"os"
+	"sync"
+
+	"github.com/gocarina/gocsv"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"gonum.org/v1/gonum/mat"
+)
+
+// latencyFeatures is the number of coefficients of the model: intercept, runtime, memory, and first invocation
+const latencyFeatures = 4
+
+// latencyRidge regularizes the normal equations, which are singular as long as a feature has not varied (e.g., all
+// the functions requested the same memory)
+const latencyRidge = 1e-6
+
+// LatencyPredictor predicts the execution time of an invocation in microseconds from its runtime specification and
+// whether it is the first invocation of the function since the start of the experiment (likely a cold start). The
+// ordinary least squares model is retrained at the end of each minute on all the invocations observed so far.
+// Safe for concurrent use.
+type LatencyPredictor struct {
+	mutex sync.Mutex
+
+	// Sufficient statistics of the regression on all observed invocations
+	xtx *mat.SymDense
+	xty *mat.VecDense
+
+	coefficients *mat.VecDense // nil until trained
+	retrains     int
+
+	invoked map[string]bool // functions invoked since the start of the experiment
+
+	minuteSamples  int
+	minuteAbsError float64
+	aggregates     []mc.MinuteInvocationRecord
+}
+
+func NewLatencyPredictor() *LatencyPredictor {
+	return &LatencyPredictor{
+		xtx:     mat.NewSymDense(latencyFeatures, nil),
+		xty:     mat.NewVecDense(latencyFeatures, nil),
+		invoked: make(map[string]bool),
+	}
+}
+
+func latencyFeatureVector(spec common.RuntimeSpecification, isFirst bool) []float64 {
+	first := 0.0
+	if isFirst {
+		first = 1
+	}
+
+	return []float64{1, float64(spec.Runtime), float64(spec.Memory), first}
+}
+
+// PredictLatency returns the predicted execution time in microseconds, or 0 before the first training
+func (p *LatencyPredictor) PredictLatency(spec common.RuntimeSpecification, isFirst bool) float64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.predict(latencyFeatureVector(spec, isFirst))
+}
+
+func (p *LatencyPredictor) predict(features []float64) float64 {
+	if p.coefficients == nil {
+		return 0
+	}
+
+	return mat.Dot(mat.NewVecDense(latencyFeatures, features), p.coefficients)
+}
+
+// Record adds the measured execution time of an invocation to the training data, accounting the error of its
+// prediction by the current model to the current minute
+func (p *LatencyPredictor) Record(spec common.RuntimeSpecification, isFirst bool, durationUs float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.record(latencyFeatureVector(spec, isFirst), durationUs)
+}
+
+func (p *LatencyPredictor) record(features []float64, durationUs float64) {
+	p.minuteSamples++
+	p.minuteAbsError += math.Abs(durationUs - p.predict(features))
+
+	x := mat.NewVecDense(latencyFeatures, features)
+	p.xtx.SymRankOne(p.xtx, 1, x)
+	p.xty.AddScaledVec(p.xty, durationUs, x)
+}
+
+// Observe records the execution of a successful invocation of the function, determining whether it is the first
+// invocation of the function
+func (p *LatencyPredictor) Observe(function string, spec common.RuntimeSpecification, record *mc.ExecutionRecord) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	isFirst := !p.invoked[function]
+	p.invoked[function] = true
+
+	p.record(latencyFeatureVector(spec, isFirst), float64(record.ActualDuration))
+}
+
+// EndMinute retrains the model on all the invocations observed so far and returns the mean absolute prediction
+// error in microseconds of the invocations of the minute, which were predicted by the model of the previous minute
+func (p *LatencyPredictor) EndMinute() float64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	predictionError := 0.0
+	if p.minuteSamples > 0 {
+		predictionError = p.minuteAbsError / float64(p.minuteSamples)
+	}
+	p.aggregates = append(p.aggregates, mc.MinuteInvocationRecord{
+		MinuteIdx:       len(p.aggregates),
+		NumFuncInvoked:  len(p.invoked),
+		PredictionError: predictionError,
+	})
+	p.minuteSamples, p.minuteAbsError = 0, 0
+
+	regularized := mat.NewSymDense(latencyFeatures, nil)
+	regularized.CopySym(p.xtx)
+	for i := 0; i < latencyFeatures; i++ {
+		regularized.SetSym(i, i, regularized.At(i, i)+latencyRidge)
+	}
+
+	var coefficients mat.VecDense
+	if err := coefficients.SolveVec(regularized, p.xty); err != nil {
+		log.Debugf("Keeping the previous latency model, failed to retrain: %v", err)
+		return predictionError
+	}
+	p.coefficients = &coefficients
+	p.retrains++
+
+	return predictionError
+}
+
+// Retrains returns how many times the model has been trained
+func (p *LatencyPredictor) Retrains() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.retrains
+}
+
+// Aggregates returns the prediction error of each minute
+func (p *LatencyPredictor) Aggregates() []mc.MinuteInvocationRecord {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]mc.MinuteInvocationRecord(nil), p.aggregates...)
+}
+
+func (d *Driver) writeLatencyPrediction() {
+	file, err := os.Create(d.outputFilename("latency_prediction"))
+	if err != nil {
+		log.Errorf("Failed to create the latency prediction report: %s", err)
+		return
+	}
+	defer file.Close()
+
+	aggregates := d.predictor.Aggregates()
+	if err = gocsv.Marshal(&aggregates, file); err != nil {
+		log.Errorf("Failed to write the latency prediction report: %s", err)
+	}
+}
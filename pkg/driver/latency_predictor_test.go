@@ -0,0 +1,100 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestLatencyPredictorUntrained(t *testing.T) {
+	predictor := NewLatencyPredictor()
+
+	if prediction := predictor.PredictLatency(common.RuntimeSpecification{Runtime: 100, Memory: 128}, false); prediction != 0 {
+		t.Errorf("Expected no prediction before training, got %f", prediction)
+	}
+	if predictor.Retrains() != 0 {
+		t.Errorf("Expected no training, got %d", predictor.Retrains())
+	}
+}
+
+func TestLatencyPredictorConverges(t *testing.T) {
+	// Execution time of 1 ms per ms of runtime with a 500 us overhead, and a 200 ms cold start on the first invocation
+	truth := func(spec common.RuntimeSpecification, isFirst bool) float64 {
+		latency := 1000*float64(spec.Runtime) + 500
+		if isFirst {
+			latency += 200_000
+		}
+		return latency
+	}
+
+	predictor := NewLatencyPredictor()
+	rng := rand.New(rand.NewSource(42))
+
+	var errors []float64
+	for minute := 0; minute < 5; minute++ {
+		for i := 0; i < 100; i++ {
+			function := fmt.Sprintf("f-%d", minute*100+i)
+			spec := common.RuntimeSpecification{Runtime: 1 + rng.Intn(1000), Memory: 128 * (1 + rng.Intn(8))}
+
+			for _, isFirst := range []bool{true, false} {
+				record := &mc.ExecutionRecord{}
+				record.ActualDuration = uint32(truth(spec, isFirst) + rng.NormFloat64()*100)
+				predictor.Observe(function, spec, record)
+			}
+		}
+
+		errors = append(errors, predictor.EndMinute())
+		if predictor.Retrains() != minute+1 {
+			t.Fatalf("Expected %d trainings after minute %d, got %d", minute+1, minute, predictor.Retrains())
+		}
+	}
+
+	// Once trained, the error drops to the noise of about 80 us
+	if errors[4] >= errors[0] || errors[1] >= errors[0] {
+		t.Errorf("Expected the prediction error to decrease after the first minute, got %v", errors)
+	}
+	if errors[4] > 200 {
+		t.Errorf("Expected a prediction error in the order of the noise in the last minute, got %f", errors[4])
+	}
+
+	for _, aggregate := range predictor.Aggregates() {
+		if aggregate.PredictionError != errors[aggregate.MinuteIdx] {
+			t.Errorf("Minute %d reports an error of %f instead of %f", aggregate.MinuteIdx, aggregate.PredictionError, errors[aggregate.MinuteIdx])
+		}
+	}
+
+	spec := common.RuntimeSpecification{Runtime: 250, Memory: 256}
+	for _, isFirst := range []bool{true, false} {
+		if prediction := predictor.PredictLatency(spec, isFirst); math.Abs(prediction-truth(spec, isFirst)) > 100 {
+			t.Errorf("Predicted %f instead of %f for first invocation %t", prediction, truth(spec, isFirst), isFirst)
+		}
+	}
+}
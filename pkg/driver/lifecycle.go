@@ -0,0 +1,114 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Phases of the lifecycle of the functions of an experiment, in the order they are reached
+const (
+	LifecycleDeployed           = "Deployed"
+	LifecyclePreflightPassed    = "PreflightPassed"
+	LifecycleWarmupComplete     = "WarmupComplete"
+	LifecycleExperimentStarted  = "ExperimentStarted"
+	LifecycleExperimentFinished = "ExperimentFinished"
+	LifecycleCleaned            = "Cleaned"
+)
+
+// LifecycleEvent marks the time the functions of the experiment reached a phase of their lifecycle
+type LifecycleEvent struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Event         string    `json:"event"`
+	FunctionCount int       `json:"functionCount"`
+}
+
+// LifecycleTracker records the phases the functions of the experiment go through, from their deployment to their
+// clean-up. Safe for concurrent use.
+type LifecycleTracker struct {
+	mutex  sync.Mutex
+	events []LifecycleEvent
+	now    func() time.Time
+}
+
+func NewLifecycleTracker() *LifecycleTracker {
+	return newLifecycleTrackerWithClock(time.Now)
+}
+
+func newLifecycleTrackerWithClock(now func() time.Time) *LifecycleTracker {
+	return &LifecycleTracker{now: now}
+}
+
+// Record marks the given phase as reached by functionCount functions now
+func (l *LifecycleTracker) Record(event string, functionCount int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.events = append(l.events, LifecycleEvent{Timestamp: l.now(), Event: event, FunctionCount: functionCount})
+}
+
+// Events returns the events recorded so far, in the order they were recorded
+func (l *LifecycleTracker) Events() []LifecycleEvent {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return append([]LifecycleEvent(nil), l.events...)
+}
+
+// ComputeLifecycleDuration returns the time between each two consecutive events, keyed by "<event>-><next event>"
+func ComputeLifecycleDuration(events []LifecycleEvent) map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	for i := 1; i < len(events); i++ {
+		durations[fmt.Sprintf("%s->%s", events[i-1].Event, events[i].Event)] = events[i].Timestamp.Sub(events[i-1].Timestamp)
+	}
+
+	return durations
+}
+
+// lifecycleFilename places lifecycle-<experimentID>.json next to the other output files
+func lifecycleFilename(outputPathPrefix string, experimentID string) string {
+	return filepath.Join(filepath.Dir(outputPathPrefix), fmt.Sprintf("lifecycle-%s.json", experimentID))
+}
+
+func (d *Driver) writeLifecycle() {
+	filename := lifecycleFilename(d.Configuration.LoaderConfiguration.OutputPathPrefix, d.status.experimentID)
+
+	data, err := json.MarshalIndent(d.lifecycle.Events(), "", "  ")
+	if err != nil {
+		log.Errorf("Failed to serialize the lifecycle events: %s", err)
+		return
+	}
+
+	if err = os.WriteFile(filename, data, 0644); err != nil {
+		log.Errorf("Failed to write the lifecycle events to %s: %s", filename, err)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestLifecycleTracker(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	now := start
+	tracker := newLifecycleTrackerWithClock(func() time.Time { return now })
+
+	phases := []string{LifecycleDeployed, LifecyclePreflightPassed, LifecycleWarmupComplete,
+		LifecycleExperimentStarted, LifecycleExperimentFinished, LifecycleCleaned}
+	for i, phase := range phases {
+		now = now.Add(time.Duration(i) * time.Minute)
+		tracker.Record(phase, 10)
+	}
+
+	events := tracker.Events()
+	if len(events) != len(phases) {
+		t.Fatalf("Expected %d events, got %v", len(phases), events)
+	}
+	for i, event := range events {
+		if event.Event != phases[i] || event.FunctionCount != 10 {
+			t.Errorf("Expected the phase %s with 10 functions, got %+v", phases[i], event)
+		}
+		if i > 0 && !event.Timestamp.After(events[i-1].Timestamp) {
+			t.Errorf("Expected %s to be recorded after %s", event.Event, events[i-1].Event)
+		}
+	}
+
+	expected := map[string]time.Duration{
+		"Deployed->PreflightPassed":             1 * time.Minute,
+		"PreflightPassed->WarmupComplete":       2 * time.Minute,
+		"WarmupComplete->ExperimentStarted":     3 * time.Minute,
+		"ExperimentStarted->ExperimentFinished": 4 * time.Minute,
+		"ExperimentFinished->Cleaned":           5 * time.Minute,
+	}
+	if durations := ComputeLifecycleDuration(events); !reflect.DeepEqual(durations, expected) {
+		t.Errorf("Expected the durations %v, got %v", expected, durations)
+	}
+	if durations := ComputeLifecycleDuration(events[:1]); len(durations) != 0 {
+		t.Errorf("Expected no durations for a single event, got %v", durations)
+	}
+}
+
+func TestDriverLifecycle(t *testing.T) {
+	driver := createTestDriver()
+	driver.Configuration.LoaderConfiguration.OutputPathPrefix = filepath.Join(t.TempDir(), "test")
+	driver.Configuration.TraceGranularity = common.SecondGranularity
+
+	driver.RunExperiment(false, false)
+
+	var phases []string
+	for _, event := range driver.lifecycle.Events() {
+		phases = append(phases, event.Event)
+	}
+
+	expected := []string{LifecycleDeployed, LifecycleExperimentStarted, LifecycleExperimentFinished, LifecycleCleaned}
+	if !reflect.DeepEqual(phases, expected) {
+		t.Errorf("Expected the phases %v, got %v", expected, phases)
+	}
+
+	driver.writeLifecycle()
+
+	data, err := os.ReadFile(lifecycleFilename(driver.Configuration.LoaderConfiguration.OutputPathPrefix, driver.status.experimentID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var written []LifecycleEvent
+	if err = json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != len(expected) || written[0].Event != LifecycleDeployed || written[0].FunctionCount != 1 {
+		t.Errorf("Unexpected lifecycle events written %+v", written)
+	}
+}
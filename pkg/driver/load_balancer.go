@@ -0,0 +1,100 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+type balancedEndpoint struct {
+	name     string
+	invoker  Invoker
+	inFlight atomic.Int32
+	routed   atomic.Int32
+}
+
+// LeastConnectionsInvoker spreads the invocations over several endpoints, each invocation being routed to the
+// endpoint with the fewest invocations in flight
+type LeastConnectionsInvoker struct {
+	endpoints []*balancedEndpoint
+}
+
+// NewLeastConnectionsInvoker balances the invocations over the invokers, keyed by the name of their endpoint
+func NewLeastConnectionsInvoker(invokers map[string]Invoker) *LeastConnectionsInvoker {
+	var names []string
+	for name := range invokers {
+		names = append(names, name)
+	}
+	sort.Strings(names) // ties are broken in a deterministic order
+
+	l := &LeastConnectionsInvoker{}
+	for _, name := range names {
+		l.endpoints = append(l.endpoints, &balancedEndpoint{name: name, invoker: invokers[name]})
+	}
+
+	return l
+}
+
+// acquire reserves a slot on the least-loaded endpoint
+func (l *LeastConnectionsInvoker) acquire() *balancedEndpoint {
+	for {
+		var selected *balancedEndpoint
+		var selectedLoad int32
+
+		for _, endpoint := range l.endpoints {
+			if load := endpoint.inFlight.Load(); selected == nil || load < selectedLoad {
+				selected, selectedLoad = endpoint, load
+			}
+		}
+
+		// Retry if another invocation was routed to the endpoint in the meantime
+		if selected.inFlight.CompareAndSwap(selectedLoad, selectedLoad+1) {
+			return selected
+		}
+	}
+}
+
+func (l *LeastConnectionsInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	endpoint := l.acquire()
+	defer endpoint.inFlight.Add(-1)
+
+	endpoint.routed.Add(1)
+
+	return endpoint.invoker.Invoke(function, runtimeSpec)
+}
+
+// GetLoadDistribution returns the number of invocations routed to each endpoint so far
+func (l *LeastConnectionsInvoker) GetLoadDistribution() map[string]int32 {
+	distribution := make(map[string]int32)
+	for _, endpoint := range l.endpoints {
+		distribution[endpoint.name] = endpoint.routed.Load()
+	}
+
+	return distribution
+}
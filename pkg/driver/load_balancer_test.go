@@ -0,0 +1,85 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// delayedInvoker completes each invocation successfully after a fixed delay
+type delayedInvoker struct {
+	delay time.Duration
+}
+
+func (d *delayedInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	time.Sleep(d.delay)
+	return true, &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{Instance: function.Name}}
+}
+
+func TestLeastConnectionsInvoker(t *testing.T) {
+	balancer := NewLeastConnectionsInvoker(map[string]Invoker{
+		"fast":   &delayedInvoker{delay: time.Millisecond},
+		"medium": &delayedInvoker{delay: 5 * time.Millisecond},
+		"slow":   &delayedInvoker{delay: 20 * time.Millisecond},
+	})
+
+	const invocations, concurrency = 1000, 30
+
+	wg := sync.WaitGroup{}
+	slots := make(chan struct{}, concurrency)
+	for i := 0; i < invocations; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if success, _ := balancer.Invoke(&testFunction, &testRuntimeSpecs); !success {
+				t.Error("Invocation failed.")
+			}
+		}()
+	}
+	wg.Wait()
+
+	distribution := balancer.GetLoadDistribution()
+	if distribution["fast"]+distribution["medium"]+distribution["slow"] != invocations {
+		t.Fatalf("Expected %d invocations in total, got %v.", invocations, distribution)
+	}
+	if distribution["fast"] <= distribution["medium"] || distribution["medium"] <= distribution["slow"] {
+		t.Errorf("Expected faster endpoints to complete more invocations, got %v.", distribution)
+	}
+
+	for _, endpoint := range balancer.endpoints {
+		if endpoint.inFlight.Load() != 0 {
+			t.Errorf("Endpoint %s still has %d invocations in flight.", endpoint.name, endpoint.inFlight.Load())
+		}
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"container/list"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const (
+	// ShedDropNewest drops the invocations arriving while the driver is overloaded
+	ShedDropNewest = "drop-newest"
+	// ShedDropOldest queues the invocations arriving while the driver is overloaded until goroutine slots free up,
+	// dropping the oldest queued invocation once the queue is full
+	ShedDropOldest = "drop-oldest"
+)
+
+// ShedPolicy protects the driver from overload by shedding invocations instead of spawning more goroutines
+type ShedPolicy struct {
+	MaxGoroutines int    // the driver is overloaded once the process runs this many goroutines
+	Strategy      string // ShedDropNewest or ShedDropOldest
+}
+
+type pendingInvocation struct {
+	scope string
+	run   func()
+	shed  func()
+}
+
+// LoadShedder spawns the invocation goroutines as long as the goroutine count stays below the limit of the policy.
+// With ShedDropOldest, the queue of invocations waiting for a goroutine slot holds at most MaxGoroutines elements.
+// Safe for concurrent use by the function drivers, which submit their invocations under their own scope.
+type LoadShedder struct {
+	policy     ShedPolicy
+	goroutines func() int
+
+	mutex     sync.Mutex
+	pending   *list.List
+	running   int        // invocations spawned by the shedder that have not completed yet
+	completed *sync.Cond // signalled whenever an invocation completes
+
+	shed atomic.Int64
+}
+
+func NewLoadShedder(policy ShedPolicy) *LoadShedder {
+	if policy.Strategy != ShedDropNewest && policy.Strategy != ShedDropOldest {
+		log.Fatalf("Unsupported load shedding strategy %q.", policy.Strategy)
+	}
+
+	shedder := &LoadShedder{
+		policy:     policy,
+		goroutines: runtime.NumGoroutine,
+		pending:    list.New(),
+	}
+	shedder.completed = sync.NewCond(&shedder.mutex)
+
+	return shedder
+}
+
+// Submit runs the invocation in a new goroutine, or calls shed if the invocation is dropped. Under the
+// ShedDropOldest strategy, the invocation may also be deferred until an earlier invocation completes.
+func (s *LoadShedder) Submit(scope string, run func(), shed func()) {
+	invocation := &pendingInvocation{scope: scope, run: run, shed: shed}
+
+	s.mutex.Lock()
+	if s.goroutines() < s.policy.MaxGoroutines && s.pending.Len() == 0 {
+		s.spawnLocked(invocation)
+		s.mutex.Unlock()
+		return
+	}
+
+	if s.policy.Strategy == ShedDropNewest {
+		s.mutex.Unlock()
+		s.drop(invocation)
+		return
+	}
+
+	var dropped *pendingInvocation
+	if s.pending.Len() >= s.policy.MaxGoroutines {
+		dropped = s.pending.Remove(s.pending.Front()).(*pendingInvocation)
+	}
+	s.pending.PushBack(invocation)
+	s.mutex.Unlock()
+
+	if dropped != nil {
+		s.drop(dropped)
+	}
+}
+
+// Flush returns once none of the invocations submitted under the scope is queued anymore, spawning them as
+// goroutine slots free up. If no invocation of the shedder is running to free a slot, the oldest queued invocation
+// of the scope is spawned regardless of the goroutine count so that none of them is left behind.
+func (s *LoadShedder) Flush(scope string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for {
+		element := s.oldestLocked(scope)
+		if element == nil {
+			return
+		}
+
+		if s.running == 0 || s.goroutines() < s.policy.MaxGoroutines {
+			s.spawnLocked(s.pending.Remove(element).(*pendingInvocation))
+			continue
+		}
+
+		s.completed.Wait()
+	}
+}
+
+// Shed returns the number of invocations dropped so far
+func (s *LoadShedder) Shed() int64 {
+	return s.shed.Load()
+}
+
+func (s *LoadShedder) spawnLocked(invocation *pendingInvocation) {
+	s.running++
+
+	go func() {
+		invocation.run()
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.running--
+		s.completed.Broadcast()
+
+		// The completed invocation hands its goroutine slot over to the oldest queued invocation
+		if next := s.pending.Front(); next != nil {
+			s.spawnLocked(s.pending.Remove(next).(*pendingInvocation))
+		}
+	}()
+}
+
+// oldestLocked returns the oldest queued invocation of the scope, if any
+func (s *LoadShedder) oldestLocked(scope string) *list.Element {
+	for element := s.pending.Front(); element != nil; element = element.Next() {
+		if element.Value.(*pendingInvocation).scope == scope {
+			return element
+		}
+	}
+
+	return nil
+}
+
+func (s *LoadShedder) drop(invocation *pendingInvocation) {
+	s.shed.Add(1)
+	mc.ShedTotal.Inc()
+
+	invocation.shed()
+}
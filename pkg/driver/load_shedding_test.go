@@ -0,0 +1,200 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// blockingInvocations are submitted to a shedder and run until released, recording which of them ran or were shed
+type blockingInvocations struct {
+	running atomic.Int32
+	started chan struct{}
+	release chan struct{}
+	done    sync.WaitGroup
+
+	mutex sync.Mutex
+	ran   []int
+	shed  []int
+}
+
+// newShedderWithBlockingInvocations creates a shedder that counts the invocations it spawned as goroutines
+func newShedderWithBlockingInvocations(strategy string) (*LoadShedder, *blockingInvocations) {
+	invocations := &blockingInvocations{started: make(chan struct{}, 100), release: make(chan struct{})}
+
+	shedder := NewLoadShedder(ShedPolicy{MaxGoroutines: 5, Strategy: strategy})
+	shedder.goroutines = func() int { return shedder.running }
+
+	return shedder, invocations
+}
+
+func (b *blockingInvocations) submit(shedder *LoadShedder, scope string, index int) {
+	b.done.Add(1)
+	shedder.Submit(scope, func() {
+		defer b.done.Done()
+
+		b.running.Add(1)
+		b.started <- struct{}{}
+		<-b.release
+
+		b.mutex.Lock()
+		b.ran = append(b.ran, index)
+		b.mutex.Unlock()
+		b.running.Add(-1)
+	}, func() {
+		defer b.done.Done()
+
+		b.mutex.Lock()
+		b.shed = append(b.shed, index)
+		b.mutex.Unlock()
+	})
+}
+
+// saturate submits as many invocations as there are goroutine slots and waits for all of them to start
+func (b *blockingInvocations) saturate(shedder *LoadShedder) {
+	for i := 0; i < shedder.policy.MaxGoroutines; i++ {
+		b.submit(shedder, "saturating", i)
+		<-b.started
+	}
+}
+
+// finish releases all the invocations, including the ones started later, and waits for them to complete
+func (b *blockingInvocations) finish() {
+	close(b.release)
+	b.done.Wait()
+
+	sort.Ints(b.ran)
+	sort.Ints(b.shed)
+}
+
+func TestLoadSheddingDropNewest(t *testing.T) {
+	shedder, invocations := newShedderWithBlockingInvocations(ShedDropNewest)
+	shedBefore := testutil.ToFloat64(mc.ShedTotal)
+
+	invocations.saturate(shedder)
+	for i := 5; i < 8; i++ {
+		invocations.submit(shedder, "function", i)
+	}
+	invocations.finish()
+
+	if len(invocations.ran) != 5 || invocations.ran[4] != 4 {
+		t.Errorf("Expected the first 5 invocations to run, got %v.", invocations.ran)
+	}
+	if len(invocations.shed) != 3 || invocations.shed[0] != 5 || shedder.Shed() != 3 {
+		t.Errorf("Expected the 3 newest invocations to be shed, got %v.", invocations.shed)
+	}
+	if shed := testutil.ToFloat64(mc.ShedTotal) - shedBefore; shed != 3 {
+		t.Errorf("Expected invitro_shed_total to increase by 3, got %.0f.", shed)
+	}
+}
+
+func TestLoadSheddingDropOldest(t *testing.T) {
+	shedder, invocations := newShedderWithBlockingInvocations(ShedDropOldest)
+	shedBefore := testutil.ToFloat64(mc.ShedTotal)
+
+	invocations.saturate(shedder)
+	// Invocations 5-9 fill the queue, after which 10 and 11 push out the oldest queued ones
+	for i := 5; i < 12; i++ {
+		invocations.submit(shedder, "function", i)
+	}
+	if shedder.Shed() != 2 {
+		t.Fatalf("Expected 2 invocations to be shed while the driver is overloaded, got %d.", shedder.Shed())
+	}
+	invocations.finish()
+
+	if len(invocations.shed) != 2 || invocations.shed[0] != 5 || invocations.shed[1] != 6 {
+		t.Errorf("Expected the 2 oldest queued invocations to be shed, got %v.", invocations.shed)
+	}
+	if len(invocations.ran) != 10 || invocations.ran[5] != 7 || invocations.ran[9] != 11 {
+		t.Errorf("Expected the queued invocations to run once goroutine slots free up, got %v.", invocations.ran)
+	}
+	if shed := testutil.ToFloat64(mc.ShedTotal) - shedBefore; shed != 2 {
+		t.Errorf("Expected invitro_shed_total to increase by 2, got %.0f.", shed)
+	}
+}
+
+func TestLoadShedderFlush(t *testing.T) {
+	shedder, invocations := newShedderWithBlockingInvocations(ShedDropOldest)
+
+	invocations.saturate(shedder)
+	invocations.submit(shedder, "other", 5)
+	invocations.submit(shedder, "flushed", 6)
+	invocations.submit(shedder, "flushed", 7)
+
+	flushed := make(chan struct{})
+	go func() {
+		shedder.Flush("flushed")
+		close(flushed)
+	}()
+
+	// Each completed invocation hands its slot over to the oldest queued invocation, whatever its scope
+	for i := 0; i < 3; i++ {
+		select {
+		case <-flushed:
+			t.Fatalf("Flush returned while invocations of its scope were queued, after %d completions.", i)
+		default:
+		}
+
+		invocations.release <- struct{}{}
+		<-invocations.started
+		if running := invocations.running.Load(); running > 5 {
+			t.Errorf("Expected at most 5 invocations to run while flushing, got %d.", running)
+		}
+	}
+	<-flushed
+	invocations.finish()
+
+	if len(invocations.ran) != 8 || shedder.Shed() != 0 {
+		t.Errorf("Expected all the invocations to run, got %v (shed %d).", invocations.ran, shedder.Shed())
+	}
+}
+
+func TestLoadShedderFlushWithoutRunningInvocations(t *testing.T) {
+	shedder, invocations := newShedderWithBlockingInvocations(ShedDropOldest)
+	// Goroutines of other function drivers occupy all the slots
+	shedder.goroutines = func() int { return shedder.policy.MaxGoroutines }
+	close(invocations.release)
+
+	for i := 0; i < 3; i++ {
+		invocations.submit(shedder, "flushed", i)
+	}
+	invocations.submit(shedder, "other", 3)
+	shedder.Flush("flushed")
+	if shedder.Shed() != 0 {
+		t.Errorf("Expected no invocation to be shed, got %d.", shedder.Shed())
+	}
+
+	// The invocation of the other scope is spawned by the last completed invocation
+	invocations.done.Wait()
+	if len(invocations.ran) != 4 {
+		t.Errorf("Expected the flushed invocations to run, got %v.", invocations.ran)
+	}
+}
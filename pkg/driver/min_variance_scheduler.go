@@ -0,0 +1,97 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"runtime"
+	"time"
+)
+
+// DefaultSpinMargin is the time before the dispatch the MinVarianceScheduler stops waiting on its ticker and spins
+const DefaultSpinMargin = 2 * time.Millisecond
+
+// MinVarianceScheduler dispatches the invocations of a function with sub-millisecond precision, for benchmarks that
+// are sensitive to the timing of the invocations. It waits on a ticker until shortly before the dispatch and spins for
+// the rest of the wait, at the cost of a busy core during SpinMargin of every IAT. The dispatch times are derived from
+// the cumulative IATs since the start of the schedule, so that the lateness of an invocation does not delay the
+// following ones. Not safe for concurrent use.
+type MinVarianceScheduler struct {
+	SpinMargin time.Duration
+
+	ticker *time.Ticker
+	next   time.Time // scheduled dispatch time of the previous invocation, zero before the first one
+}
+
+func NewMinVarianceScheduler() *MinVarianceScheduler {
+	ticker := time.NewTicker(time.Hour)
+	ticker.Stop()
+
+	return &MinVarianceScheduler{
+		SpinMargin: DefaultSpinMargin,
+		ticker:     ticker,
+	}
+}
+
+// Start sets the start of the schedule, from which the IATs passed to Wait accumulate
+func (s *MinVarianceScheduler) Start(start time.Time) {
+	s.next = start
+}
+
+// Wait waits until the next invocation is due, iat after the scheduled dispatch time of the previous one or after
+// the start of the schedule, and returns by how much the dispatch is late
+func (s *MinVarianceScheduler) Wait(iat time.Duration) time.Duration {
+	if s.next.IsZero() {
+		s.next = time.Now()
+	}
+	s.next = s.next.Add(iat)
+
+	return s.WaitUntil(s.next)
+}
+
+// WaitUntil waits until the given dispatch time and returns by how much the dispatch is late
+func (s *MinVarianceScheduler) WaitUntil(dispatchTime time.Time) time.Duration {
+	if coarseWait := time.Until(dispatchTime) - s.SpinMargin; coarseWait > 0 {
+		s.ticker.Reset(coarseWait)
+		<-s.ticker.C
+		s.ticker.Stop()
+
+		// Discard a tick that fired in the meantime for short waits
+		select {
+		case <-s.ticker.C:
+		default:
+		}
+	}
+
+	for time.Now().Before(dispatchTime) {
+		runtime.Gosched()
+	}
+
+	return time.Since(dispatchTime)
+}
+
+// Stop releases the ticker of the scheduler
+func (s *MinVarianceScheduler) Stop() {
+	s.ticker.Stop()
+}
@@ -0,0 +1,122 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestMinVarianceScheduler(t *testing.T) {
+	scheduler := NewMinVarianceScheduler()
+	defer scheduler.Stop()
+
+	const invocations = 100
+	const iat = 5 * time.Millisecond
+
+	start := time.Now()
+	scheduler.Start(start)
+
+	var lateness []time.Duration
+	for i := 0; i < invocations; i++ {
+		if i == invocations/2 {
+			// The delay of a slow dispatch must not shift the following invocations
+			time.Sleep(3 * iat)
+		}
+
+		l := scheduler.Wait(iat)
+		if l < 0 {
+			t.Fatalf("Invocation %d dispatched %v early.", i, -l)
+		}
+		lateness = append(lateness, l)
+	}
+
+	if elapsed := time.Since(start); elapsed > invocations*iat+5*time.Millisecond {
+		t.Errorf("Expected the schedule to catch up with the slow dispatch, took %v.", elapsed)
+	}
+
+	// The median is robust to the preemptions of the test process
+	sort.Slice(lateness, func(i, j int) bool { return lateness[i] < lateness[j] })
+	if median := lateness[invocations/2]; median > 100*time.Microsecond {
+		t.Errorf("Median dispatch lateness of %v.", median)
+	}
+}
+
+func TestMinVarianceSchedulerPastDispatchTime(t *testing.T) {
+	scheduler := NewMinVarianceScheduler()
+	defer scheduler.Stop()
+
+	if lateness := scheduler.WaitUntil(time.Now().Add(-time.Second)); lateness < time.Second {
+		t.Errorf("Expected the dispatch to be a second late, got %v.", lateness)
+	}
+}
+
+// BenchmarkMinVarianceScheduler reports the mean and p99 dispatch jitter of 1000 invocations with an IAT of 10 ms,
+// compared to sleeping between the invocations
+func BenchmarkMinVarianceScheduler(b *testing.B) {
+	const invocations = 1000
+	const iat = 10 * time.Millisecond
+
+	report := func(b *testing.B, jitter []time.Duration) {
+		sort.Slice(jitter, func(i, j int) bool { return jitter[i] < jitter[j] })
+
+		var total time.Duration
+		for _, j := range jitter {
+			total += j
+		}
+
+		b.ReportMetric(float64(total.Microseconds())/float64(len(jitter)), "mean-jitter-µs")
+		b.ReportMetric(float64(jitter[len(jitter)*99/100].Microseconds()), "p99-jitter-µs")
+	}
+
+	b.Run("ticker", func(b *testing.B) {
+		var jitter []time.Duration
+		for n := 0; n < b.N; n++ {
+			scheduler := NewMinVarianceScheduler()
+			scheduler.Start(time.Now())
+			for i := 0; i < invocations; i++ {
+				jitter = append(jitter, scheduler.Wait(iat))
+			}
+			scheduler.Stop()
+		}
+
+		report(b, jitter)
+	})
+
+	b.Run("sleep", func(b *testing.B) {
+		var jitter []time.Duration
+		for n := 0; n < b.N; n++ {
+			next := time.Now()
+			for i := 0; i < invocations; i++ {
+				next = next.Add(iat)
+				time.Sleep(time.Until(next))
+				jitter = append(jitter, time.Since(next))
+			}
+		}
+
+		report(b, jitter)
+	})
+}
@@ -0,0 +1,121 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ScheduleDriftThreshold is the lag behind the schedule after which the dispatcher reports a schedule drift
+const ScheduleDriftThreshold = 5 * time.Second
+
+type DroppingPolicy struct {
+	// DropOldest drops the oldest pending invocations of a window once the schedule drift exceeds the threshold
+	DropOldest bool
+}
+
+// MinuteDispatcher keeps track of how far the driver lags behind the schedule when dispatching the invocations of a
+// dispatch window (minute or second, depending on the trace granularity). The lag at the end of a window carries
+// over as schedule drift and is subtracted from the time allotted to the next window.
+type MinuteDispatcher struct {
+	window time.Duration
+	policy DroppingPolicy
+	clock  func() time.Time
+
+	drift time.Duration // lag behind the schedule not caught up with yet
+}
+
+func NewMinuteDispatcher(window time.Duration, policy DroppingPolicy) *MinuteDispatcher {
+	return newMinuteDispatcherWithClock(window, policy, time.Now)
+}
+
+func newMinuteDispatcherWithClock(window time.Duration, policy DroppingPolicy, clock func() time.Time) *MinuteDispatcher {
+	return &MinuteDispatcher{
+		window: window,
+		policy: policy,
+		clock:  clock,
+	}
+}
+
+// Dispatch runs the dispatch function of the invocation scheduled at the given time and records by how much the
+// invocation lags behind the schedule once dispatched
+func (m *MinuteDispatcher) Dispatch(scheduled time.Time, dispatch func()) {
+	dispatch()
+	m.drift = lagBehind(m.clock(), scheduled)
+}
+
+// EndWindow closes the window scheduled to end at the given time and returns the time allotted to the next one, from
+// which the lag at the end of the window is subtracted
+func (m *MinuteDispatcher) EndWindow(scheduledEnd time.Time) time.Duration {
+	m.drift = lagBehind(m.clock(), scheduledEnd)
+
+	if m.drift > ScheduleDriftThreshold {
+		log.Warnf("ScheduleDrift: dispatching is %v behind the schedule.", m.drift)
+	}
+
+	return m.AllottedTime()
+}
+
+func lagBehind(now time.Time, scheduled time.Time) time.Duration {
+	if lag := now.Sub(scheduled); lag > 0 {
+		return lag
+	}
+
+	return 0
+}
+
+// AllottedTime returns the time available for the next window after subtracting the drift
+func (m *MinuteDispatcher) AllottedTime() time.Duration {
+	if m.drift >= m.window {
+		return 0
+	}
+
+	return m.window - m.drift
+}
+
+func (m *MinuteDispatcher) Drift() time.Duration {
+	return m.drift
+}
+
+// InvocationsToDrop returns how many of the oldest pending invocations of the next window should be dropped to
+// catch up with the schedule. The IATs of the window are in microseconds, with the last element being the tail of
+// the window after the last invocation. The span of the dropped invocations is deducted from the drift.
+func (m *MinuteDispatcher) InvocationsToDrop(iat []float64) int {
+	if !m.policy.DropOldest || m.drift <= ScheduleDriftThreshold {
+		return 0
+	}
+
+	dropped, droppedSpan := 0, 0.0
+	for dropped < len(iat)-1 && droppedSpan+iat[dropped] <= float64(m.drift.Microseconds()) {
+		droppedSpan += iat[dropped]
+		dropped++
+	}
+
+	m.drift -= time.Duration(droppedSpan) * time.Microsecond
+
+	return dropped
+}
@@ -0,0 +1,146 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestMinuteDispatcherDriftTracking(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := &fakeClock{now: start}
+	dispatcher := newMinuteDispatcherWithClock(time.Minute, DroppingPolicy{}, clock.Now)
+
+	const dispatchDelay = 500 * time.Millisecond
+	slowDispatch := func() { clock.Advance(dispatchDelay) }
+
+	// A burst of 8 invocations scheduled 2s before the end of the minute overruns it by 2s
+	clock.now = start.Add(58 * time.Second)
+	for i := 0; i < 8; i++ {
+		dispatcher.Dispatch(start.Add(58*time.Second), slowDispatch)
+	}
+	if dispatcher.Drift() != 4*time.Second {
+		t.Errorf("Expected the last invocation to lag 4s behind the schedule, got %v.", dispatcher.Drift())
+	}
+	if allotted := dispatcher.EndWindow(start.Add(time.Minute)); allotted != time.Minute-2*time.Second {
+		t.Errorf("Expected 58s allotted to the next minute, got %v.", allotted)
+	}
+
+	// The next minute is scheduled from the scheduled end of the previous one, whose lag thus carries over
+	next := start.Add(time.Minute)
+	clock.now = next.Add(59 * time.Second)
+	for i := 0; i < 14; i++ {
+		dispatcher.Dispatch(next.Add(59*time.Second), slowDispatch)
+	}
+	dispatcher.EndWindow(next.Add(time.Minute))
+
+	if dispatcher.Drift() != 6*time.Second {
+		t.Errorf("Expected a drift of 6s, got %v.", dispatcher.Drift())
+	}
+	if dispatcher.Drift() <= ScheduleDriftThreshold {
+		t.Error("Drift should have exceeded the threshold.")
+	}
+	if dropped := dispatcher.InvocationsToDrop([]float64{0, 1e6, 1e6, 1e6}); dropped != 0 {
+		t.Errorf("Invocations should not be dropped without the DropOldest policy, dropped %d.", dropped)
+	}
+}
+
+func TestMinuteDispatcherLagCaughtUpWithinWindow(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := &fakeClock{now: start}
+	dispatcher := newMinuteDispatcherWithClock(time.Minute, DroppingPolicy{}, clock.Now)
+
+	for i := 0; i < 4; i++ {
+		dispatcher.Dispatch(start, func() { clock.Advance(500 * time.Millisecond) })
+	}
+	if dispatcher.Drift() != 2*time.Second {
+		t.Errorf("Expected the last invocation to lag 2s behind the schedule, got %v.", dispatcher.Drift())
+	}
+
+	// The lag is absorbed by the rest of the minute
+	clock.now = start.Add(time.Minute)
+	if allotted := dispatcher.EndWindow(start.Add(time.Minute)); allotted != time.Minute {
+		t.Errorf("Expected the whole next minute to be allotted, got %v.", allotted)
+	}
+}
+
+func TestMinuteDispatcherDropOldest(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := &fakeClock{now: start}
+	dispatcher := newMinuteDispatcherWithClock(time.Minute, DroppingPolicy{DropOldest: true}, clock.Now)
+
+	clock.now = start.Add(time.Minute)
+	for i := 0; i < 14; i++ {
+		dispatcher.Dispatch(start.Add(time.Minute), func() { clock.Advance(500 * time.Millisecond) })
+	}
+	dispatcher.EndWindow(start.Add(time.Minute))
+
+	// 7s behind the schedule with invocations 2.5s apart
+	iat := []float64{0, 2.5e6, 2.5e6, 2.5e6, 2.5e6, 50e6}
+	if dropped := dispatcher.InvocationsToDrop(iat); dropped != 3 {
+		t.Errorf("Expected the 3 oldest invocations to be dropped, dropped %d.", dropped)
+	}
+	if dispatcher.Drift() != 2*time.Second {
+		t.Errorf("Expected the remaining drift to be 2s, got %v.", dispatcher.Drift())
+	}
+	if dropped := dispatcher.InvocationsToDrop(iat); dropped != 0 {
+		t.Errorf("No invocations should be dropped below the threshold, dropped %d.", dropped)
+	}
+}
+
+func TestMinuteDispatcherAllottedTimeNonNegative(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := &fakeClock{now: start}
+	dispatcher := newMinuteDispatcherWithClock(time.Second, DroppingPolicy{}, clock.Now)
+
+	dispatcher.Dispatch(start, func() { clock.Advance(3 * time.Second) })
+	if allotted := dispatcher.EndWindow(start.Add(time.Second)); allotted != 0 {
+		t.Errorf("Expected no time allotted to the next window, got %v.", allotted)
+	}
+}
+
+func TestMinuteDispatcherAheadOfSchedule(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := &fakeClock{now: start}
+	dispatcher := newMinuteDispatcherWithClock(time.Minute, DroppingPolicy{}, clock.Now)
+
+	dispatcher.Dispatch(start.Add(time.Second), func() {})
+	if dispatcher.Drift() != 0 {
+		t.Errorf("Expected no drift for an invocation dispatched early, got %v.", dispatcher.Drift())
+	}
+}
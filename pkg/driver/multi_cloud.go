@@ -0,0 +1,193 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
+)
+
+// MultiCloudDeployment holds one serverless.yml definition per cloud provider. Providers left nil are not deployed.
+type MultiCloudDeployment struct {
+	AWS   *Serverless `yaml:"aws,omitempty"`
+	GCP   *Serverless `yaml:"gcp,omitempty"`
+	Azure *Serverless `yaml:"azure,omitempty"`
+}
+
+// deployServerlessProvider deploys the serverless.yml definition of a single provider and returns the URLs of its
+// functions by function name. Overridden in tests.
+var deployServerlessProvider = func(provider string, serverless *Serverless) (map[string]string, error) {
+	// The Serverless.com framework expects the configuration file in the working directory
+	path := fmt.Sprintf("./serverless-%s.yml", provider)
+	if err := serverless.WriteServerlessConfigFile(path); err != nil {
+		return nil, err
+	}
+	defer os.Remove(path)
+
+	if output, err := runSlsDeploy(path); err != nil {
+		return nil, &slsDeployError{path: path, err: err, output: string(output)}
+	}
+
+	info, err := runSlsInfo(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the deployed functions of %s: %w\n%s", path, err, string(info))
+	}
+	log.Debug("CMD response: ", string(info))
+
+	return parseSlsInfoEndpoints(provider, string(info), serverless.Functions)
+}
+
+// runSlsInfo runs `sls info --verbose` on the given file and returns its combined output, replaced in tests
+var runSlsInfo = func(path string) ([]byte, error) {
+	return exec.Command("sls", "info", "--verbose", "--config", path).CombinedOutput()
+}
+
+var (
+	// awsInfoEndpointRegex matches the lines of the endpoints section, e.g., "  trace-func-0: https://<id>.lambda-url.<region>.on.aws/"
+	awsInfoEndpointRegex = regexp.MustCompile(`^\s*([\w-]+): (https://\S+)$`)
+	// gcpInfoURLRegex matches the URL printed on the line after the name of a deployed function
+	gcpInfoURLRegex = regexp.MustCompile(`^\s*(https://\S+)$`)
+	// azureInfoEndpointRegex matches the lines listing the deployed functions, e.g., "-> trace-func-0: [GET] <app>.azurewebsites.net/api/trace-func-0"
+	azureInfoEndpointRegex = regexp.MustCompile(`->\s*([\w-]+): \[[A-Z,]+\] (\S+)`)
+)
+
+// parseSlsInfoEndpoints extracts the URL of each of the functions from the output of `sls info --verbose`, whose
+// format depends on the provider plugin. An error is returned if the URL of any function is missing.
+func parseSlsInfoEndpoints(provider string, info string, functions map[string]*slsFunction) (map[string]string, error) {
+	functionToURL := make(map[string]string)
+
+	lines := strings.Split(info, "\n")
+	for i, line := range lines {
+		switch provider {
+		case "aws":
+			if match := awsInfoEndpointRegex.FindStringSubmatch(line); match != nil {
+				functionToURL[match[1]] = match[2]
+			}
+		case "gcp":
+			name := strings.TrimSpace(line)
+			if _, ok := functions[name]; ok && i+1 < len(lines) {
+				if match := gcpInfoURLRegex.FindStringSubmatch(lines[i+1]); match != nil {
+					functionToURL[name] = match[1]
+				}
+			}
+		case "azure":
+			if match := azureInfoEndpointRegex.FindStringSubmatch(line); match != nil {
+				url := match[2]
+				if !strings.HasPrefix(url, "https://") {
+					url = "https://" + url
+				}
+				functionToURL[match[1]] = url
+			}
+		default:
+			return nil, fmt.Errorf("unsupported provider %s", provider)
+		}
+	}
+
+	for name := range functionToURL {
+		if _, ok := functions[name]; !ok {
+			delete(functionToURL, name)
+		}
+	}
+	for name := range functions {
+		if _, ok := functionToURL[name]; !ok {
+			return nil, fmt.Errorf("no URL of function %s in the %s service information", name, provider)
+		}
+	}
+
+	return functionToURL, nil
+}
+
+// ReadMultiCloudConfig parses a YAML file with the top-level keys aws, gcp and azure, each holding a serverless.yml body
+func ReadMultiCloudConfig(path string) (*MultiCloudDeployment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment := &MultiCloudDeployment{}
+	if err = yaml.Unmarshal(data, deployment); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return deployment, nil
+}
+
+func (mc MultiCloudDeployment) providers() map[string]*Serverless {
+	providers := make(map[string]*Serverless)
+	if mc.AWS != nil {
+		providers["aws"] = mc.AWS
+	}
+	if mc.GCP != nil {
+		providers["gcp"] = mc.GCP
+	}
+	if mc.Azure != nil {
+		providers["azure"] = mc.Azure
+	}
+
+	return providers
+}
+
+// DeployAll deploys to all configured providers in parallel and returns the function URLs (by function name) per
+// provider. The first deployment error is returned once all deployments have finished.
+func DeployAll(mc MultiCloudDeployment) (map[string]map[string]string, error) {
+	providers := mc.providers()
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no cloud provider configured")
+	}
+
+	var mutex sync.Mutex
+	endpoints := make(map[string]map[string]string)
+
+	var group errgroup.Group
+	for provider, serverless := range providers {
+		provider, serverless := provider, serverless
+
+		group.Go(func() error {
+			functionToURL, err := deployServerlessProvider(provider, serverless)
+			if err != nil {
+				return fmt.Errorf("%s: %w", provider, err)
+			}
+			log.Debugf("Deployed %d functions to %s", len(functionToURL), provider)
+
+			mutex.Lock()
+			endpoints[provider] = functionToURL
+			mutex.Unlock()
+
+			return nil
+		})
+	}
+
+	err := group.Wait()
+
+	return endpoints, err
+}
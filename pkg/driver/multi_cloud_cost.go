@@ -0,0 +1,103 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"io"
+	"sort"
+
+	"github.com/gocarina/gocsv"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// pricingModel charges the invocations of a function by request and by compute time, in USD cents
+type pricingModel struct {
+	centsPerRequest  float64
+	centsPerGBSecond float64
+}
+
+// On-demand pricing of the providers deployed to by DeployMultiCloud
+var pricingModels = map[string]pricingModel{
+	"aws":   {centsPerRequest: awsCentsPerRequest, centsPerGBSecond: awsCentsPerGBSecond},
+	"gcp":   {centsPerRequest: 0.00004, centsPerGBSecond: 0.00025},
+	"azure": {centsPerRequest: 0.00002, centsPerGBSecond: 0.0016},
+}
+
+type CostEstimate struct {
+	Provider          string  `csv:"Provider"`
+	TotalInvocations  int64   `csv:"TotalInvocations"`
+	TotalGBSeconds    float64 `csv:"TotalGBSeconds"`
+	RequestUSDCents   float64 `csv:"RequestUSDCents"`
+	ComputeUSDCents   float64 `csv:"ComputeUSDCents"`
+	EstimatedUSDCents float64 `csv:"EstimatedUSDCents"`
+}
+
+// EstimateMultiCloudCost estimates the charges of the invocations of the specification on each of the providers
+// (aws, gcp, azure), assuming that every invocation runs for its requested runtime with its requested memory
+func EstimateMultiCloudCost(spec *common.FunctionSpecification, providers []string) map[string]CostEstimate {
+	var invocations int64
+	var gbSeconds float64
+	for _, minute := range spec.RuntimeSpecification {
+		for _, invocation := range minute {
+			invocations++
+			gbSeconds += float64(invocation.Runtime) / 1e3 * float64(invocation.Memory) / 1024
+		}
+	}
+
+	estimates := make(map[string]CostEstimate)
+	for _, provider := range providers {
+		pricing, ok := pricingModels[provider]
+		if !ok {
+			log.Warnf("No pricing model for provider %s, skipping its cost estimate", provider)
+			continue
+		}
+
+		estimate := CostEstimate{
+			Provider:         provider,
+			TotalInvocations: invocations,
+			TotalGBSeconds:   gbSeconds,
+			RequestUSDCents:  float64(invocations) * pricing.centsPerRequest,
+			ComputeUSDCents:  gbSeconds * pricing.centsPerGBSecond,
+		}
+		estimate.EstimatedUSDCents = estimate.RequestUSDCents + estimate.ComputeUSDCents
+		estimates[provider] = estimate
+	}
+
+	return estimates
+}
+
+// WriteCostComparison writes the estimates as a table with one row per provider, ordered by provider
+func WriteCostComparison(w io.Writer, estimates map[string]CostEstimate) error {
+	rows := make([]CostEstimate, 0, len(estimates))
+	for _, estimate := range estimates {
+		rows = append(rows, estimate)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Provider < rows[j].Provider
+	})
+
+	return gocsv.Marshal(rows, w)
+}
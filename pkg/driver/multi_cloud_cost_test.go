@@ -0,0 +1,96 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestEstimateMultiCloudCost(t *testing.T) {
+	// 1000 invocations of 1 second with 1 GB over two minutes, i.e. 1000 GB-s
+	spec := &common.FunctionSpecification{RuntimeSpecification: common.RuntimeSpecificationMatrix{
+		make([]common.RuntimeSpecification, 400),
+		make([]common.RuntimeSpecification, 600),
+	}}
+	for _, minute := range spec.RuntimeSpecification {
+		for i := range minute {
+			minute[i] = common.RuntimeSpecification{Runtime: 1000, Memory: 1024}
+		}
+	}
+
+	estimates := EstimateMultiCloudCost(spec, []string{"aws", "gcp", "azure", "ibm"})
+	if len(estimates) != 3 {
+		t.Fatalf("Expected an estimate per known provider, got %v", estimates)
+	}
+
+	expected := map[string]CostEstimate{
+		"aws":   {Provider: "aws", TotalInvocations: 1000, TotalGBSeconds: 1000, RequestUSDCents: 0.02, ComputeUSDCents: 1.66667},
+		"gcp":   {Provider: "gcp", TotalInvocations: 1000, TotalGBSeconds: 1000, RequestUSDCents: 0.04, ComputeUSDCents: 0.25},
+		"azure": {Provider: "azure", TotalInvocations: 1000, TotalGBSeconds: 1000, RequestUSDCents: 0.02, ComputeUSDCents: 1.6},
+	}
+	for provider, e := range expected {
+		estimate := estimates[provider]
+		if estimate.Provider != e.Provider || estimate.TotalInvocations != e.TotalInvocations ||
+			math.Abs(estimate.TotalGBSeconds-e.TotalGBSeconds) > 1e-9 ||
+			math.Abs(estimate.RequestUSDCents-e.RequestUSDCents) > 1e-9 ||
+			math.Abs(estimate.ComputeUSDCents-e.ComputeUSDCents) > 1e-9 ||
+			math.Abs(estimate.EstimatedUSDCents-e.RequestUSDCents-e.ComputeUSDCents) > 1e-9 {
+
+			t.Errorf("Unexpected estimate for %s: %+v", provider, estimate)
+		}
+	}
+
+	// AWS and Azure charge the same per request, but not per GB-s
+	if estimates["aws"].RequestUSDCents != estimates["azure"].RequestUSDCents {
+		t.Error("Expected AWS and Azure to charge the same for the requests.")
+	}
+	if estimates["gcp"].EstimatedUSDCents == estimates["aws"].EstimatedUSDCents ||
+		estimates["gcp"].EstimatedUSDCents == estimates["azure"].EstimatedUSDCents {
+
+		t.Error("Expected the GCP estimate to differ.")
+	}
+}
+
+func TestWriteCostComparison(t *testing.T) {
+	spec := &common.FunctionSpecification{RuntimeSpecification: common.RuntimeSpecificationMatrix{
+		{{Runtime: 1000, Memory: 1024}},
+	}}
+
+	var output strings.Builder
+	if err := WriteCostComparison(&output, EstimateMultiCloudCost(spec, []string{"gcp", "azure", "aws"})); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 4 || lines[0] != "Provider,TotalInvocations,TotalGBSeconds,RequestUSDCents,ComputeUSDCents,EstimatedUSDCents" ||
+		!strings.HasPrefix(lines[1], "aws,1,1,") || !strings.HasPrefix(lines[2], "azure,") || !strings.HasPrefix(lines[3], "gcp,") {
+
+		t.Errorf("Unexpected table:\n%s", output.String())
+	}
+}
@@ -0,0 +1,280 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func mockDeployServerlessProvider(t *testing.T, deploy func(provider string, serverless *Serverless) (map[string]string, error)) {
+	original := deployServerlessProvider
+	deployServerlessProvider = deploy
+
+	t.Cleanup(func() {
+		deployServerlessProvider = original
+	})
+}
+
+func TestDeployAllRunsInParallel(t *testing.T) {
+	var started sync.WaitGroup
+	started.Add(3)
+
+	mockDeployServerlessProvider(t, func(provider string, serverless *Serverless) (map[string]string, error) {
+		started.Done()
+
+		// Every deployment waits until all of them have started, which only succeeds if they run in parallel
+		allStarted := make(chan struct{})
+		go func() {
+			started.Wait()
+			close(allStarted)
+		}()
+
+		select {
+		case <-allStarted:
+		case <-time.After(5 * time.Second):
+			return nil, errors.New("deployments did not run in parallel")
+		}
+
+		return map[string]string{"f": "https://" + provider + ".example.com/" + serverless.Service}, nil
+	})
+
+	endpoints, err := DeployAll(MultiCloudDeployment{
+		AWS:   &Serverless{Service: "a"},
+		GCP:   &Serverless{Service: "g"},
+		Azure: &Serverless{Service: "z"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"aws":   "https://aws.example.com/a",
+		"gcp":   "https://gcp.example.com/g",
+		"azure": "https://azure.example.com/z",
+	}
+	if len(endpoints) != len(expected) {
+		t.Fatalf("Expected %d providers, got %d", len(expected), len(endpoints))
+	}
+	for provider, url := range expected {
+		if endpoints[provider]["f"] != url {
+			t.Errorf("Unexpected URL for %s: %s", provider, endpoints[provider]["f"])
+		}
+	}
+}
+
+func TestDeployAllSkipsUnconfiguredProviders(t *testing.T) {
+	var mutex sync.Mutex
+	var deployed []string
+
+	mockDeployServerlessProvider(t, func(provider string, serverless *Serverless) (map[string]string, error) {
+		mutex.Lock()
+		deployed = append(deployed, provider)
+		mutex.Unlock()
+
+		return map[string]string{}, nil
+	})
+
+	if _, err := DeployAll(MultiCloudDeployment{GCP: &Serverless{}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(deployed) != 1 || deployed[0] != "gcp" {
+		t.Errorf("Expected only gcp to be deployed, got %v", deployed)
+	}
+
+	if _, err := DeployAll(MultiCloudDeployment{}); err == nil {
+		t.Error("Expected an error when no provider is configured")
+	}
+}
+
+func TestDeployAllPropagatesErrors(t *testing.T) {
+	mockDeployServerlessProvider(t, func(provider string, serverless *Serverless) (map[string]string, error) {
+		if provider == "azure" {
+			return nil, errors.New("quota exceeded")
+		}
+
+		return map[string]string{"f": "https://" + provider}, nil
+	})
+
+	endpoints, err := DeployAll(MultiCloudDeployment{AWS: &Serverless{}, Azure: &Serverless{}})
+	if err == nil || err.Error() != "azure: quota exceeded" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if endpoints["aws"]["f"] != "https://aws" {
+		t.Error("Expected the successful deployment to be reported")
+	}
+}
+
+func TestReadMultiCloudConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "multi-cloud.yml")
+	content := `aws:
+    service: loader-aws
+    frameworkVersion: "3"
+    provider:
+        name: aws
+        runtime: go1.x
+azure:
+    service: loader-azure
+    provider:
+        name: azure
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deployment, err := ReadMultiCloudConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if deployment.AWS == nil || deployment.AWS.Service != "loader-aws" || deployment.AWS.Provider.Name != "aws" {
+		t.Errorf("Unexpected AWS configuration: %+v", deployment.AWS)
+	}
+	if deployment.GCP != nil {
+		t.Error("Expected GCP to be unconfigured")
+	}
+	if deployment.Azure == nil || deployment.Azure.Service != "loader-azure" {
+		t.Errorf("Unexpected Azure configuration: %+v", deployment.Azure)
+	}
+}
+
+func TestParseSlsInfoEndpoints(t *testing.T) {
+	functions := map[string]*slsFunction{"trace-func-0": {}, "trace-func-1": {}}
+
+	tests := []struct {
+		provider string
+		info     string
+		expected map[string]string
+	}{
+		{
+			provider: "aws",
+			info: `service: loader-aws
+stage: dev
+region: us-east-1
+stack: loader-aws-dev
+endpoints:
+  trace-func-0: https://abc.lambda-url.us-east-1.on.aws/
+  trace-func-1: https://def.lambda-url.us-east-1.on.aws/
+functions:
+  trace-func-0: loader-aws-dev-trace-func-0
+  trace-func-1: loader-aws-dev-trace-func-1
+
+Stack Outputs:
+  ServerlessDeploymentBucketName: https://s3.amazonaws.com/loader-aws-dev-bucket`,
+			expected: map[string]string{
+				"trace-func-0": "https://abc.lambda-url.us-east-1.on.aws/",
+				"trace-func-1": "https://def.lambda-url.us-east-1.on.aws/",
+			},
+		},
+		{
+			provider: "gcp",
+			info: `Service Information
+service: loader-gcp
+project: loader-project
+stage: dev
+region: us-central1
+
+Deployed functions
+trace-func-0
+  https://us-central1-loader-project.cloudfunctions.net/loader-gcp-dev-trace-func-0
+trace-func-1
+  https://us-central1-loader-project.cloudfunctions.net/loader-gcp-dev-trace-func-1`,
+			expected: map[string]string{
+				"trace-func-0": "https://us-central1-loader-project.cloudfunctions.net/loader-gcp-dev-trace-func-0",
+				"trace-func-1": "https://us-central1-loader-project.cloudfunctions.net/loader-gcp-dev-trace-func-1",
+			},
+		},
+		{
+			provider: "azure",
+			info: `Resource Group Name: sls-eus-dev-loader-rg
+Function App Name: sls-eus-dev-loader
+Deployed serverless functions:
+-> trace-func-0: [GET,POST] sls-eus-dev-loader.azurewebsites.net/api/trace-func-0
+-> trace-func-1: [GET,POST] sls-eus-dev-loader.azurewebsites.net/api/trace-func-1`,
+			expected: map[string]string{
+				"trace-func-0": "https://sls-eus-dev-loader.azurewebsites.net/api/trace-func-0",
+				"trace-func-1": "https://sls-eus-dev-loader.azurewebsites.net/api/trace-func-1",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.provider, func(t *testing.T) {
+			endpoints, err := parseSlsInfoEndpoints(test.provider, test.info, functions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(endpoints, test.expected) {
+				t.Errorf("Unexpected endpoints %v", endpoints)
+			}
+		})
+	}
+
+	if _, err := parseSlsInfoEndpoints("aws", "endpoints:\n  trace-func-0: https://abc.lambda-url.us-east-1.on.aws/", functions); err == nil {
+		t.Error("Expected an error for a function without URL")
+	}
+}
+
+func TestDeployServerlessProviderRemovesConfigFile(t *testing.T) {
+	previousDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	originalDeploy, originalInfo := runSlsDeploy, runSlsInfo
+	runSlsDeploy = func(path string) ([]byte, error) {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to exist during the deployment", path)
+		}
+		return []byte("Deploying loader-aws to stage dev (us-east-1)\nhttps://unrelated.example.com"), nil
+	}
+	runSlsInfo = func(path string) ([]byte, error) {
+		return []byte("endpoints:\n  trace-func-0: https://abc.lambda-url.us-east-1.on.aws/"), nil
+	}
+	t.Cleanup(func() {
+		runSlsDeploy, runSlsInfo = originalDeploy, originalInfo
+		_ = os.Chdir(previousDir)
+	})
+
+	serverless := &Serverless{Service: "loader-aws", Functions: map[string]*slsFunction{"trace-func-0": {}}}
+	endpoints, err := deployServerlessProvider("aws", serverless)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 || endpoints["trace-func-0"] != "https://abc.lambda-url.us-east-1.on.aws/" {
+		t.Errorf("Unexpected endpoints %v", endpoints)
+	}
+	if _, err = os.Stat("serverless-aws.yml"); !os.IsNotExist(err) {
+		t.Error("Expected serverless-aws.yml to be removed after the deployment")
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// ErrNetworkPartition is the cause of the invocations failed by PartitionMiddleware
+var ErrNetworkPartition = errors.New("network partition")
+
+type partitionedInvoker struct {
+	invoker        Invoker
+	partitionStart time.Time
+	partitionEnd   time.Time
+	clock          func() time.Time
+}
+
+// PartitionMiddleware simulates a network partition between partitionStart (inclusive) and partitionEnd (exclusive).
+// Invocations dispatched within the partition fail as if the endpoint was unreachable, and all the other invocations
+// are forwarded to the wrapped invoker.
+func PartitionMiddleware(invoker Invoker, partitionStart, partitionEnd time.Time) Invoker {
+	return partitionMiddlewareWithClock(invoker, partitionStart, partitionEnd, time.Now)
+}
+
+func partitionMiddlewareWithClock(invoker Invoker, partitionStart, partitionEnd time.Time, clock func() time.Time) Invoker {
+	return &partitionedInvoker{
+		invoker:        invoker,
+		partitionStart: partitionStart,
+		partitionEnd:   partitionEnd,
+		clock:          clock,
+	}
+}
+
+func (p *partitionedInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	now := p.clock()
+	if now.Before(p.partitionStart) || !now.Before(p.partitionEnd) {
+		return p.invoker.Invoke(function, runtimeSpec)
+	}
+
+	log.Debugf("Failed to invoke %s: %v", function.Name, ErrNetworkPartition)
+
+	return false, &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			StartTime:         now.UnixMicro(),
+			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+			ConnectionTimeout: true,
+		},
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartitionMiddleware(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	invoker := newSimulatedInvokerWithClock(time.Millisecond, clock.Now)
+
+	partitionStart := clock.Now().Add(10 * time.Second)
+	partitionEnd := partitionStart.Add(5 * time.Second)
+	partitioned := partitionMiddlewareWithClock(invoker, partitionStart, partitionEnd, clock.Now)
+
+	// One invocation per second, before, during, and after the partition
+	for second := 0; second < 20; second++ {
+		success, record := partitioned.Invoke(&testFunction, &testRuntimeSpecs)
+
+		inPartition := second >= 10 && second < 15
+		if success == inPartition || record.ConnectionTimeout != inPartition {
+			t.Errorf("Invocation at %ds: expected failure %t, got success %t and connection timeout %t.", second, inPartition, success, record.ConnectionTimeout)
+		}
+
+		clock.Advance(time.Second)
+	}
+
+	if invoker.Invocations() != 15 {
+		t.Errorf("Expected the invocations outside of the partition to be forwarded, got %d.", invoker.Invocations())
+	}
+}
+
+func TestDriverRecordsPartitionFailures(t *testing.T) {
+	experiment := createScheduledExperiment(t, "partitioned", 4, 10)
+
+	partitionStart := time.Now()
+	invoker := PartitionMiddleware(NewSimulatedInvoker(time.Millisecond), partitionStart, partitionStart.Add(5*time.Second))
+
+	summaries := NewScheduler(invoker).Schedule([]ScheduledExperiment{experiment})
+
+	if summaries[0].Issued != 4 || summaries[0].Failed != 4 || summaries[0].Successful != 0 {
+		t.Errorf("Expected all invocations during the partition to be recorded as failures: %+v", summaries[0])
+	}
+}
@@ -0,0 +1,172 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// PreflightReport summarizes the reachability of the function endpoints before the experiment starts
+type PreflightReport struct {
+	HealthyEndpoints   []string
+	UnhealthyEndpoints []string
+	Latencies          map[string]time.Duration // of the healthy endpoints
+}
+
+// PreflightCheck probes the endpoint of each function in parallel. HTTP(S) endpoints (AWS Lambda, OpenWhisk) are
+// probed with a HEAD request, whereas the remaining endpoints (Knative, Dirigent) are probed with the
+// grpc.health.v1 Check RPC.
+func PreflightCheck(functions []*common.Function, timeout time.Duration) PreflightReport {
+	report := PreflightReport{Latencies: make(map[string]time.Duration)}
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+
+	endpoints := make(map[string]bool)
+	for _, function := range functions {
+		endpoints[function.Endpoint] = true
+	}
+
+	for endpoint := range endpoints {
+		wg.Add(1)
+
+		go func(endpoint string) {
+			defer wg.Done()
+
+			latency, err := probeEndpoint(endpoint, timeout)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if err != nil {
+				log.Debugf("Preflight check of %s failed - %v", endpoint, err)
+				report.UnhealthyEndpoints = append(report.UnhealthyEndpoints, endpoint)
+			} else {
+				report.HealthyEndpoints = append(report.HealthyEndpoints, endpoint)
+				report.Latencies[endpoint] = latency
+			}
+		}(endpoint)
+	}
+	wg.Wait()
+
+	sort.Strings(report.HealthyEndpoints)
+	sort.Strings(report.UnhealthyEndpoints)
+
+	return report
+}
+
+func probeEndpoint(endpoint string, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	if strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		client := &http.Client{
+			Transport: &http.Transport{
+				// OpenWhisk deployments use self-signed certificates
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return 0, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+		}
+	} else {
+		conn, err := grpc.DialContext(ctx, endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+		if err != nil {
+			return 0, err
+		}
+		defer gRPCConnectionClose(conn)
+
+		if err = checkGRPCHealth(ctx, conn); err != nil {
+			return 0, err
+		}
+	}
+
+	return time.Since(start), nil
+}
+
+// checkGRPCHealth queries the grpc.health.v1 service of the server. Servers that do not implement the health service
+// (e.g., third-party function images) are considered healthy once the connection has been established.
+func checkGRPCHealth(ctx context.Context, conn *grpc.ClientConn) error {
+	response, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if response.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("endpoint reported health status %s", response.Status)
+	}
+
+	return nil
+}
+
+// filterHealthyFunctions removes the functions whose endpoints failed the preflight check
+func filterHealthyFunctions(functions []*common.Function, report PreflightReport) []*common.Function {
+	unhealthy := make(map[string]bool)
+	for _, endpoint := range report.UnhealthyEndpoints {
+		unhealthy[endpoint] = true
+	}
+
+	var result []*common.Function
+	for _, function := range functions {
+		if unhealthy[function.Endpoint] {
+			log.Warnf("Excluding function %s from the experiment as its endpoint %s is unreachable.", function.Name, function.Endpoint)
+			continue
+		}
+
+		result = append(result, function)
+	}
+
+	return result
+}
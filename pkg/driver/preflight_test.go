@@ -0,0 +1,145 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/workload/standard"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestPreflightCheck(t *testing.T) {
+	healthyHTTP := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Expected a HEAD request, got %s.", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthyHTTP.Close()
+
+	failingHTTP := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failingHTTP.Close()
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	// Reserve a port without a server behind it
+	closedListener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unreachableGRPC := closedListener.Addr().String()
+	closedListener.Close()
+
+	functions := []*common.Function{
+		{Name: "healthy-http", Endpoint: healthyHTTP.URL},
+		{Name: "failing-http", Endpoint: failingHTTP.URL},
+		{Name: "healthy-grpc", Endpoint: listener.Addr().String()},
+		{Name: "unreachable-grpc", Endpoint: unreachableGRPC},
+	}
+
+	report := PreflightCheck(functions, time.Second)
+
+	if len(report.HealthyEndpoints) != 2 || len(report.UnhealthyEndpoints) != 2 {
+		t.Fatalf("Unexpected preflight report: %+v", report)
+	}
+	for _, endpoint := range []string{healthyHTTP.URL, listener.Addr().String()} {
+		if _, ok := report.Latencies[endpoint]; !ok {
+			t.Errorf("Missing latency of healthy endpoint %s.", endpoint)
+		}
+	}
+	for _, endpoint := range []string{failingHTTP.URL, unreachableGRPC} {
+		if _, ok := report.Latencies[endpoint]; ok {
+			t.Errorf("Unhealthy endpoint %s should not have a latency.", endpoint)
+		}
+	}
+
+	healthy := filterHealthyFunctions(functions, report)
+	if len(healthy) != 2 || healthy[0].Name != "healthy-http" || healthy[1].Name != "healthy-grpc" {
+		t.Errorf("Unhealthy functions were not excluded: %v", healthy)
+	}
+}
+
+func startHealthServer(t *testing.T) (string, *health.Server) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grpcServer := grpc.NewServer()
+	healthServer := standard.RegisterHealthServer(grpcServer)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	return listener.Addr().String(), healthServer
+}
+
+func TestPreflightCheckUsesGRPCHealth(t *testing.T) {
+	serving, _ := startHealthServer(t)
+	draining, drainingHealth := startHealthServer(t)
+	drainingHealth.Shutdown()
+
+	notServingListener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notServingServer := grpc.NewServer()
+	notServingHealth := health.NewServer()
+	notServingHealth.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(notServingServer, notServingHealth)
+	go notServingServer.Serve(notServingListener)
+	defer notServingServer.Stop()
+	notServing := notServingListener.Addr().String()
+
+	functions := []*common.Function{
+		{Name: "serving", Endpoint: serving},
+		{Name: "draining", Endpoint: draining},
+		{Name: "not-serving", Endpoint: notServing},
+	}
+
+	report := PreflightCheck(functions, time.Second)
+
+	if len(report.HealthyEndpoints) != 1 || report.HealthyEndpoints[0] != serving {
+		t.Errorf("Expected only %s to be healthy, got %v.", serving, report.HealthyEndpoints)
+	}
+	if len(report.UnhealthyEndpoints) != 2 {
+		t.Errorf("Expected the draining and the not serving endpoints to be unhealthy, got %v.", report.UnhealthyEndpoints)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// DefaultWarmupSpec is the low-resource invocation sent to warm up the deployed functions
+var DefaultWarmupSpec = common.RuntimeSpecification{Runtime: 100, Memory: 128}
+
+// warmupTimeout bounds a warm-up invocation, which includes the cold start of the function
+const warmupTimeout = 30 * time.Second
+
+// WarmupDeployedFunctions invokes each deployed function (index to URL, as returned by DeployServerless) attempts times
+// with the warm-up specification, so that the experiment does not start with the cold starts of the deployment. The
+// functions are warmed up concurrently and the attempts of a function one after another. An error is returned for
+// each function of which all the attempts failed.
+func WarmupDeployedFunctions(urlMap map[int]string, warmupSpec common.RuntimeSpecification, attempts int) []error {
+	payload, err := json.Marshal(awsLambdaRequest{
+		RuntimeInMilliSec: warmupSpec.Runtime,
+		MemoryInMebiBytes: warmupSpec.Memory,
+	})
+	if err != nil {
+		return []error{err}
+	}
+
+	client := &http.Client{Timeout: warmupTimeout}
+
+	type failure struct {
+		index int
+		err   error
+	}
+	var failures []failure
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+
+	for index, url := range urlMap {
+		wg.Add(1)
+
+		go func(index int, url string) {
+			defer wg.Done()
+
+			var lastErr error
+			succeeded := 0
+			for attempt := 0; attempt < attempts; attempt++ {
+				start := time.Now()
+				if lastErr = warmupInvocation(client, url, payload); lastErr != nil {
+					log.Debugf("Warm-up invocation %d of %s failed after %v - %v", attempt, url, time.Since(start), lastErr)
+					continue
+				}
+
+				succeeded++
+				log.Debugf("Warm-up invocation %d of %s took %v", attempt, url, time.Since(start))
+			}
+
+			if attempts > 0 && succeeded == 0 {
+				mutex.Lock()
+				failures = append(failures, failure{index: index, err: fmt.Errorf("all %d warm-up invocations of function %d (%s) failed - %w", attempts, index, url, lastErr)})
+				mutex.Unlock()
+			}
+		}(index, url)
+	}
+	wg.Wait()
+
+	sort.Slice(failures, func(i, j int) bool { return failures[i].index < failures[j].index })
+
+	var errs []error
+	for _, f := range failures {
+		errs = append(errs, f.err)
+	}
+
+	return errs
+}
+
+func warmupInvocation(client *http.Client, url string, payload []byte) error {
+	response, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("function responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// preWarmDeployedFunctions warms up the deployed functions, warning about those that could not be warmed up
+func (d *Driver) preWarmDeployedFunctions(attempts int) {
+	urlMap := make(map[int]string, len(d.Configuration.Functions))
+	for i, function := range d.Configuration.Functions {
+		urlMap[i] = function.Endpoint
+	}
+
+	log.Infof("Warming up the %d deployed functions with %d invocations each.", len(urlMap), attempts)
+	for _, err := range WarmupDeployedFunctions(urlMap, DefaultWarmupSpec, attempts) {
+		log.Warn(err)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// startWarmupServer counts the warm-up invocations, failing those for which fail returns true
+func startWarmupServer(t *testing.T, requests *atomic.Int32, fail func(n int32) bool) string {
+	var received atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		n := received.Add(1)
+
+		var request awsLambdaRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil ||
+			request.RuntimeInMilliSec != DefaultWarmupSpec.Runtime || request.MemoryInMebiBytes != DefaultWarmupSpec.Memory {
+
+			t.Errorf("Unexpected warm-up request %+v (%v)", request, err)
+		}
+
+		if fail(n) {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"DurationInMicroSec":100000}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func TestWarmupDeployedFunctions(t *testing.T) {
+	var requests atomic.Int32
+
+	urlMap := map[int]string{
+		0: startWarmupServer(t, &requests, func(int32) bool { return false }),
+		1: startWarmupServer(t, &requests, func(n int32) bool { return n == 1 }), // cold start failure
+		2: startWarmupServer(t, &requests, func(int32) bool { return true }),
+	}
+
+	errs := WarmupDeployedFunctions(urlMap, DefaultWarmupSpec, 3)
+
+	if requests.Load() != int32(len(urlMap)*3) {
+		t.Errorf("Expected %d warm-up invocations, got %d.", len(urlMap)*3, requests.Load())
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "function 2") || !strings.Contains(errs[0].Error(), "status 502") {
+		t.Errorf("Expected only function 2 to fail persistently, got %v.", errs)
+	}
+}
+
+func TestWarmupDeployedFunctionsUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	if errs := WarmupDeployedFunctions(map[int]string{0: url}, DefaultWarmupSpec, 2); len(errs) != 1 {
+		t.Errorf("Expected the unreachable function to fail, got %v.", errs)
+	}
+	if errs := WarmupDeployedFunctions(map[int]string{0: url}, DefaultWarmupSpec, 0); len(errs) != 0 {
+		t.Errorf("Expected no warm-up without attempts, got %v.", errs)
+	}
+}
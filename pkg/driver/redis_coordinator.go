@@ -0,0 +1,165 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+)
+
+// RedisMinuteReportsChannel is the Redis pub/sub channel the workers of a distributed experiment publish their
+// MinuteReport to
+const RedisMinuteReportsChannel = "invitro:minute-reports"
+
+// MinuteReport is the progress of a worker of a distributed experiment in a minute
+type MinuteReport struct {
+	ExperimentID          string `json:"experimentID"`
+	WorkerID              string `json:"workerID"`
+	Minute                int    `json:"minute"`
+	InvocationsDispatched int64  `json:"invocationsDispatched"`
+	ErrorsThisMinute      int64  `json:"errorsThisMinute"`
+}
+
+// RedisCoordinator shares the progress of the workers of distributed experiments over Redis pub/sub. Every worker
+// publishes a MinuteReport per minute and keeps the reports of all the workers, including its own, in a shared
+// experiment state. Safe for concurrent use.
+type RedisCoordinator struct {
+	workerID string
+	client   *redis.Client
+	pubsub   *redis.PubSub
+	done     chan struct{}
+
+	mutex sync.Mutex
+	// experiment ID -> worker ID -> minute -> report, a report replacing the earlier one of the same minute
+	reports map[string]map[string]map[int]MinuteReport
+}
+
+// NewRedisCoordinator connects to the Redis server at addr (host:port) and subscribes to the reports of the workers
+func NewRedisCoordinator(addr string, workerID string) (*RedisCoordinator, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+
+	// Receiving the confirmation of the subscription ensures no report published afterward is missed
+	pubsub := client.Subscribe(context.Background(), RedisMinuteReportsChannel)
+	if _, err := pubsub.Receive(context.Background()); err != nil {
+		_ = pubsub.Close()
+		_ = client.Close()
+
+		return nil, fmt.Errorf("failed to subscribe to %s on %s: %w", RedisMinuteReportsChannel, addr, err)
+	}
+
+	c := &RedisCoordinator{
+		workerID: workerID,
+		client:   client,
+		pubsub:   pubsub,
+		done:     make(chan struct{}),
+		reports:  make(map[string]map[string]map[int]MinuteReport),
+	}
+	go c.receiveReports()
+
+	return c, nil
+}
+
+// PublishMinuteReport publishes the report of this worker for a minute of the experiment
+func (c *RedisCoordinator) PublishMinuteReport(experimentID string, minute int, invocationsDispatched int64, errorsThisMinute int64) error {
+	data, err := json.Marshal(MinuteReport{
+		ExperimentID:          experimentID,
+		WorkerID:              c.workerID,
+		Minute:                minute,
+		InvocationsDispatched: invocationsDispatched,
+		ErrorsThisMinute:      errorsThisMinute,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.client.Publish(context.Background(), RedisMinuteReportsChannel, data).Err()
+}
+
+func (c *RedisCoordinator) receiveReports() {
+	defer close(c.done)
+
+	for message := range c.pubsub.Channel() {
+		var report MinuteReport
+		if err := json.Unmarshal([]byte(message.Payload), &report); err != nil {
+			log.Warnf("Ignoring malformed minute report %q: %s", message.Payload, err)
+			continue
+		}
+
+		c.mutex.Lock()
+		workers, ok := c.reports[report.ExperimentID]
+		if !ok {
+			workers = make(map[string]map[int]MinuteReport)
+			c.reports[report.ExperimentID] = workers
+		}
+		minutes, ok := workers[report.WorkerID]
+		if !ok {
+			minutes = make(map[int]MinuteReport)
+			workers[report.WorkerID] = minutes
+		}
+		minutes[report.Minute] = report
+		c.mutex.Unlock()
+	}
+}
+
+// GetGlobalThroughput returns the number of invocations dispatched by all the workers of the experiment, as reported
+// so far
+func (c *RedisCoordinator) GetGlobalThroughput(experimentID string) float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var invocations int64
+	for _, minutes := range c.reports[experimentID] {
+		for _, report := range minutes {
+			invocations += report.InvocationsDispatched
+		}
+	}
+
+	return float64(invocations)
+}
+
+// Workers returns the number of workers that reported on the experiment so far
+func (c *RedisCoordinator) Workers(experimentID string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.reports[experimentID])
+}
+
+// Close unsubscribes from the reports and disconnects from Redis
+func (c *RedisCoordinator) Close() error {
+	err := c.pubsub.Close()
+	<-c.done
+
+	if closeErr := c.client.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,99 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// waitFor polls the condition until it holds or a second elapses
+func waitFor(condition func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	return true
+}
+
+func TestRedisCoordinator(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	var workers []*RedisCoordinator
+	for i := 0; i < 3; i++ {
+		coordinator, err := NewRedisCoordinator(server.Addr(), fmt.Sprintf("worker-%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer coordinator.Close()
+
+		workers = append(workers, coordinator)
+	}
+
+	// Each worker dispatches 100 * (i + 1) invocations per minute for 2 minutes
+	for i, worker := range workers {
+		for minute := 0; minute < 2; minute++ {
+			if err := worker.PublishMinuteReport("experiment-1", minute, int64(100*(i+1)), int64(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// The report of a minute published again replaces the earlier one
+	if err := workers[0].PublishMinuteReport("experiment-1", 1, 50, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := workers[1].PublishMinuteReport("experiment-2", 0, 1000, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, worker := range workers {
+		if !waitFor(func() bool { return worker.GetGlobalThroughput("experiment-1") == 1150 }) {
+			t.Errorf("Expected %s to see 1150 invocations of all the workers, got %f.", worker.workerID, worker.GetGlobalThroughput("experiment-1"))
+		}
+		if worker.Workers("experiment-1") != 3 {
+			t.Errorf("Expected %s to see 3 workers, got %d.", worker.workerID, worker.Workers("experiment-1"))
+		}
+		if !waitFor(func() bool { return worker.GetGlobalThroughput("experiment-2") == 1000 }) {
+			t.Errorf("Expected the experiments to be kept apart, got %f.", worker.GetGlobalThroughput("experiment-2"))
+		}
+	}
+}
+
+func TestRedisCoordinatorUnreachable(t *testing.T) {
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	server.Close()
+
+	if _, err := NewRedisCoordinator(addr, "worker-0"); err == nil {
+		t.Error("Expected an error for an unreachable Redis server.")
+	}
+}
@@ -0,0 +1,254 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const (
+	// DefaultResultCacheCapacity is the number of invocation results kept by the cache of the driver
+	DefaultResultCacheCapacity = 10000
+	// ResultCacheTolerance is the relative difference in runtime and memory up to which a cached result is reused
+	ResultCacheTolerance = 0.05
+)
+
+type resultCacheEntry struct {
+	Function string
+	Runtime  int // milliseconds
+	Memory   int // MiB
+	Record   *mc.ExecutionRecord
+}
+
+func (e *resultCacheEntry) key() string {
+	return resultCacheKey(e.Function, e.Runtime, e.Memory)
+}
+
+func resultCacheKey(function string, runtimeMs int, memoryMib int) string {
+	return fmt.Sprintf("%s-%d-%d", function, runtimeMs, memoryMib)
+}
+
+// ResultCache keeps the records of the most recently used invocation results, so that repeated experiments can
+// reuse them instead of invoking the functions again. Meant for iterating on the analysis of the results, not for
+// measurements. Safe for concurrent use.
+type ResultCache struct {
+	capacity int
+
+	mutex   sync.Mutex
+	entries *list.List // of *resultCacheEntry, the most recently used first
+	index   map[string]*list.Element
+
+	hits   int64
+	misses int64
+}
+
+func NewResultCache(capacity int) *ResultCache {
+	return &ResultCache{
+		capacity: capacity,
+		entries:  list.New(),
+		index:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns a copy of the cached record of an invocation of the function whose runtime and memory are within
+// ResultCacheTolerance of the given specification, preferring an exact match
+func (c *ResultCache) Get(function string, spec *common.RuntimeSpecification) (*mc.ExecutionRecord, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.index[resultCacheKey(function, spec.Runtime, spec.Memory)]
+	if !ok {
+		for e := c.entries.Front(); e != nil; e = e.Next() {
+			entry := e.Value.(*resultCacheEntry)
+			if entry.Function == function && withinTolerance(entry.Runtime, spec.Runtime) && withinTolerance(entry.Memory, spec.Memory) {
+				element, ok = e, true
+				break
+			}
+		}
+	}
+
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	c.hits++
+	c.entries.MoveToFront(element)
+
+	record := *element.Value.(*resultCacheEntry).Record
+	record.CacheHit = true
+
+	return &record, true
+}
+
+// Put caches the record of an invocation, evicting the least recently used record if the cache is full
+func (c *ResultCache) Put(function string, spec *common.RuntimeSpecification, record *mc.ExecutionRecord) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached := *record // the caller goes on to modify the record
+	c.put(&resultCacheEntry{Function: function, Runtime: spec.Runtime, Memory: spec.Memory, Record: &cached})
+}
+
+func (c *ResultCache) put(entry *resultCacheEntry) {
+	if element, ok := c.index[entry.key()]; ok {
+		element.Value = entry
+		c.entries.MoveToFront(element)
+		return
+	}
+
+	c.index[entry.key()] = c.entries.PushFront(entry)
+	if c.entries.Len() > c.capacity {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.index, oldest.Value.(*resultCacheEntry).key())
+	}
+}
+
+func (c *ResultCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.entries.Len()
+}
+
+// HitRate returns the fraction of the lookups answered from the cache
+func (c *ResultCache) HitRate() float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.hits+c.misses == 0 {
+		return 0
+	}
+
+	return float64(c.hits) / float64(c.hits+c.misses)
+}
+
+// Save writes the cached records to a JSON file, from the most to the least recently used
+func (c *ResultCache) Save(path string) error {
+	c.mutex.Lock()
+	entries := make([]*resultCacheEntry, 0, c.entries.Len())
+	for e := c.entries.Front(); e != nil; e = e.Next() {
+		entries = append(entries, e.Value.(*resultCacheEntry))
+	}
+	c.mutex.Unlock()
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadResultCache reads a cache written by Save, or returns an empty cache if the file does not exist
+func LoadResultCache(path string, capacity int) (*ResultCache, error) {
+	cache := NewResultCache(capacity)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var entries []*resultCacheEntry
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse the result cache %s: %w", path, err)
+	}
+
+	// Insert the least recently used first to restore the order
+	for i := len(entries) - 1; i >= 0; i-- {
+		cache.put(entries[i])
+	}
+
+	return cache, nil
+}
+
+func withinTolerance(cached int, requested int) bool {
+	if cached == requested {
+		return true
+	}
+
+	return math.Abs(float64(cached-requested)) <= ResultCacheTolerance*float64(requested)
+}
+
+// resultCachePath is shared by the experiments writing their results to the same directory
+func (d *Driver) resultCachePath() string {
+	return filepath.Join(filepath.Dir(d.Configuration.LoaderConfiguration.OutputPathPrefix), "result_cache.json")
+}
+
+func (d *Driver) loadResultCache() {
+	cache, err := LoadResultCache(d.resultCachePath(), DefaultResultCacheCapacity)
+	if err != nil {
+		log.Warnf("Starting with an empty result cache: %v", err)
+		cache = NewResultCache(DefaultResultCacheCapacity)
+	}
+
+	log.Infof("Loaded %d cached invocation results from %s", cache.Len(), d.resultCachePath())
+	d.resultCache = cache
+}
+
+func (d *Driver) saveResultCache() {
+	log.Infof("Result cache hit rate: %.2f%%", d.resultCache.HitRate()*100)
+
+	if err := d.resultCache.Save(d.resultCachePath()); err != nil {
+		log.Warnf("Failed to save the result cache: %v", err)
+	}
+}
+
+// invokeCached answers the invocation from the result cache if possible, and caches the result of the successful
+// invocations otherwise
+func (d *Driver) invokeCached(function *common.Function, runtimeSpec *common.RuntimeSpecification,
+	announceDoneExe *sync.WaitGroup, invoke func() (bool, *mc.ExecutionRecord)) (bool, *mc.ExecutionRecord) {
+
+	if record, ok := d.resultCache.Get(function.Name, runtimeSpec); ok {
+		record.StartTime = time.Now().UnixMicro()
+
+		// The invocation is not issued
+		announceDoneExe.Done()
+
+		return true, record
+	}
+
+	success, record := invoke()
+	if success && record != nil {
+		d.resultCache.Put(function.Name, runtimeSpec, record)
+	}
+
+	return success, record
+}
@@ -0,0 +1,170 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func cachedRecord(responseTime int64) *mc.ExecutionRecord {
+	return &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{ResponseTime: responseTime}}
+}
+
+func TestResultCacheLRUEviction(t *testing.T) {
+	cache := NewResultCache(2)
+
+	cache.Put("f", &common.RuntimeSpecification{Runtime: 100, Memory: 128}, cachedRecord(1))
+	cache.Put("f", &common.RuntimeSpecification{Runtime: 200, Memory: 128}, cachedRecord(2))
+
+	// Using the first entry makes the second the least recently used
+	if _, ok := cache.Get("f", &common.RuntimeSpecification{Runtime: 100, Memory: 128}); !ok {
+		t.Fatal("Expected a cache hit")
+	}
+	cache.Put("f", &common.RuntimeSpecification{Runtime: 300, Memory: 128}, cachedRecord(3))
+
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 cached results, got %d", cache.Len())
+	}
+	if _, ok := cache.Get("f", &common.RuntimeSpecification{Runtime: 200, Memory: 128}); ok {
+		t.Error("Expected the least recently used result to be evicted")
+	}
+	for _, runtime := range []int{100, 300} {
+		if _, ok := cache.Get("f", &common.RuntimeSpecification{Runtime: runtime, Memory: 128}); !ok {
+			t.Errorf("Expected the result with runtime %d to be cached", runtime)
+		}
+	}
+}
+
+func TestResultCacheTolerance(t *testing.T) {
+	cache := NewResultCache(10)
+	cache.Put("f", &common.RuntimeSpecification{Runtime: 1000, Memory: 1000}, cachedRecord(1))
+
+	tests := []struct {
+		name     string
+		function string
+		spec     common.RuntimeSpecification
+		hit      bool
+	}{
+		{name: "exact", function: "f", spec: common.RuntimeSpecification{Runtime: 1000, Memory: 1000}, hit: true},
+		{name: "within_tolerance", function: "f", spec: common.RuntimeSpecification{Runtime: 1040, Memory: 960}, hit: true},
+		{name: "runtime_outside_tolerance", function: "f", spec: common.RuntimeSpecification{Runtime: 1100, Memory: 1000}},
+		{name: "memory_outside_tolerance", function: "f", spec: common.RuntimeSpecification{Runtime: 1000, Memory: 900}},
+		{name: "other_function", function: "g", spec: common.RuntimeSpecification{Runtime: 1000, Memory: 1000}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			record, ok := cache.Get(test.function, &test.spec)
+			if ok != test.hit {
+				t.Fatalf("Expected hit %t, got %t", test.hit, ok)
+			}
+			if ok && (!record.CacheHit || record.ResponseTime != 1) {
+				t.Errorf("Unexpected cached record %+v", record.ExecutionRecordBase)
+			}
+		})
+	}
+}
+
+func TestResultCacheHitRate(t *testing.T) {
+	cache := NewResultCache(10)
+	if cache.HitRate() != 0 {
+		t.Errorf("Expected a hit rate of 0 without lookups, got %f", cache.HitRate())
+	}
+
+	spec := &common.RuntimeSpecification{Runtime: 100, Memory: 128}
+	cache.Get("f", spec)
+	cache.Put("f", spec, cachedRecord(1))
+	cache.Get("f", spec)
+	cache.Get("f", spec)
+	cache.Get("g", spec)
+
+	if cache.HitRate() != 0.5 {
+		t.Errorf("Wrong hit rate - got %f, expected 0.5", cache.HitRate())
+	}
+}
+
+func TestResultCacheSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "result_cache.json")
+
+	cache := NewResultCache(3)
+	for runtime := 100; runtime <= 300; runtime += 100 {
+		cache.Put("f", &common.RuntimeSpecification{Runtime: runtime, Memory: 128}, cachedRecord(int64(runtime)))
+	}
+	if err := cache.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadResultCache(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Len() != 2 {
+		t.Fatalf("Expected 2 results, got %d", loaded.Len())
+	}
+	if _, ok := loaded.Get("f", &common.RuntimeSpecification{Runtime: 100, Memory: 128}); ok {
+		t.Error("Expected the least recently used result not to be loaded")
+	}
+	if record, ok := loaded.Get("f", &common.RuntimeSpecification{Runtime: 300, Memory: 128}); !ok || record.ResponseTime != 300 {
+		t.Error("Expected the most recently used result to be loaded")
+	}
+
+	if empty, err := LoadResultCache(filepath.Join(t.TempDir(), "missing.json"), 2); err != nil || empty.Len() != 0 {
+		t.Errorf("Expected an empty cache for a missing file, got %v", err)
+	}
+}
+
+func TestInvokeCached(t *testing.T) {
+	d := &Driver{resultCache: NewResultCache(10)}
+	spec := &common.RuntimeSpecification{Runtime: 100, Memory: 128}
+
+	invocations := 0
+	invoke := func() (bool, *mc.ExecutionRecord) {
+		invocations++
+		return true, cachedRecord(42)
+	}
+
+	announceDoneExe := sync.WaitGroup{}
+	announceDoneExe.Add(2)
+
+	if _, record := d.invokeCached(&testFunction, spec, &announceDoneExe, invoke); record.CacheHit {
+		t.Error("Expected the first invocation to be issued")
+	}
+	announceDoneExe.Done() // by the invocation
+
+	success, record := d.invokeCached(&testFunction, spec, &announceDoneExe, invoke)
+	if !success || !record.CacheHit || record.ResponseTime != 42 {
+		t.Errorf("Expected the second invocation to be answered from the cache, got %+v", record.ExecutionRecordBase)
+	}
+	if invocations != 1 {
+		t.Errorf("Expected 1 issued invocation, got %d", invocations)
+	}
+
+	announceDoneExe.Wait()
+}
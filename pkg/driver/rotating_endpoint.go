@@ -0,0 +1,149 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// DefaultBackoffSeconds is how long an endpoint is skipped after throttling an invocation by default
+const DefaultBackoffSeconds = 10
+
+// ErrAllEndpointsThrottled is the cause of the invocations failed because every endpoint is backing off
+var ErrAllEndpointsThrottled = errors.New("all endpoints are backing off")
+
+// RotatingEndpointInvoker spreads the invocations of a function round-robin over several URLs of the function, e.g.
+// AWS Lambda function URLs, which are throttled at 10k requests per second each. Each invocation is forwarded to the
+// wrapped invoker with the endpoint of the function set to the next URL, and a URL whose invocation is throttled is
+// skipped for BackoffSeconds. Safe for concurrent use.
+type RotatingEndpointInvoker struct {
+	BackoffSeconds int
+
+	invoker Invoker
+	now     func() time.Time
+
+	mutex        sync.Mutex
+	urls         []string
+	next         int
+	backoffUntil map[string]time.Time
+}
+
+func NewRotatingEndpointInvoker(invoker Invoker, urls []string, backoffSeconds int) *RotatingEndpointInvoker {
+	return newRotatingEndpointInvokerWithClock(invoker, urls, backoffSeconds, time.Now)
+}
+
+func newRotatingEndpointInvokerWithClock(invoker Invoker, urls []string, backoffSeconds int, now func() time.Time) *RotatingEndpointInvoker {
+	return &RotatingEndpointInvoker{
+		BackoffSeconds: backoffSeconds,
+		invoker:        invoker,
+		now:            now,
+		urls:           append([]string(nil), urls...),
+		backoffUntil:   make(map[string]time.Time),
+	}
+}
+
+// Invoke invokes the function on the next URL not backing off. The invocation fails without being issued if all
+// the URLs are backing off.
+func (r *RotatingEndpointInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	url, err := r.nextURL()
+	if err != nil {
+		log.Debugf("Failed to invoke %s: %v", function.Name, err)
+
+		return false, &mc.ExecutionRecord{
+			ExecutionRecordBase: mc.ExecutionRecordBase{
+				Instance:          function.Name,
+				StartTime:         r.now().UnixMicro(),
+				RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+				ConnectionTimeout: true,
+				Throttled:         true,
+			},
+		}
+	}
+
+	rotated := *function
+	rotated.Endpoint = url
+
+	success, record := r.invoker.Invoke(&rotated, runtimeSpec)
+	if record != nil && record.Throttled {
+		r.backOff(url)
+	}
+
+	return success, record
+}
+
+// BackingOff returns the URLs currently skipped
+func (r *RotatingEndpointInvoker) BackingOff() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var urls []string
+	for _, url := range r.urls {
+		if r.isBackingOff(url) {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
+}
+
+func (r *RotatingEndpointInvoker) nextURL() (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i := 0; i < len(r.urls); i++ {
+		url := r.urls[r.next]
+		r.next = (r.next + 1) % len(r.urls)
+
+		if !r.isBackingOff(url) {
+			return url, nil
+		}
+	}
+
+	return "", ErrAllEndpointsThrottled
+}
+
+// isBackingOff must be called with the mutex held
+func (r *RotatingEndpointInvoker) isBackingOff(url string) bool {
+	until, ok := r.backoffUntil[url]
+	if ok && !r.now().Before(until) {
+		delete(r.backoffUntil, url)
+		return false
+	}
+
+	return ok
+}
+
+func (r *RotatingEndpointInvoker) backOff(url string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.backoffUntil[url] = r.now().Add(time.Duration(r.BackoffSeconds) * time.Second)
+}
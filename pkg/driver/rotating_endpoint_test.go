@@ -0,0 +1,138 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// endpointInvoker issues a plain HTTP request to the endpoint of the function
+type endpointInvoker struct{}
+
+func (endpointInvoker) Invoke(function *common.Function, _ *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	success, record, res := httpInvocation("", function, nil, &wg, false, nil)
+	if res != nil {
+		_ = res.Body.Close()
+	}
+
+	return success, &mc.ExecutionRecord{ExecutionRecordBase: *record}
+}
+
+func TestRotatingEndpointInvoker(t *testing.T) {
+	var counts [3]atomic.Int32
+	var throttling atomic.Bool
+	throttling.Store(true)
+
+	var urls []string
+	for i := range counts {
+		i := i
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			counts[i].Add(1)
+			if i == 1 && throttling.Load() {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_, _ = w.Write([]byte("OK"))
+		}))
+		defer server.Close()
+
+		urls = append(urls, server.URL)
+	}
+
+	now := time.Unix(0, 0)
+	clockMutex := sync.Mutex{}
+	clock := func() time.Time {
+		clockMutex.Lock()
+		defer clockMutex.Unlock()
+		return now
+	}
+	invoker := newRotatingEndpointInvokerWithClock(endpointInvoker{}, urls, 5, clock)
+	endpoint := testFunction.Endpoint
+
+	if success, _ := invoker.Invoke(&testFunction, &testRuntimeSpecs); !success {
+		t.Fatal("Expected the first endpoint to succeed.")
+	}
+	if success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs); success || !record.Throttled {
+		t.Fatalf("Expected the second endpoint to throttle, got %+v.", record)
+	}
+	if backingOff := invoker.BackingOff(); len(backingOff) != 1 || backingOff[0] != urls[1] {
+		t.Fatalf("Expected the second endpoint to back off, got %v.", backingOff)
+	}
+
+	for i := 0; i < 10; i++ {
+		if success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs); !success {
+			t.Fatalf("Expected the invocation to go to the other endpoints, got %+v.", record)
+		}
+	}
+	if counts[0].Load() != 6 || counts[1].Load() != 1 || counts[2].Load() != 5 {
+		t.Errorf("Expected the invocations to alternate between the other endpoints, got %d, %d, and %d.",
+			counts[0].Load(), counts[1].Load(), counts[2].Load())
+	}
+	if testFunction.Endpoint != endpoint {
+		t.Errorf("Expected the endpoint of the function not to be modified, got %s.", testFunction.Endpoint)
+	}
+
+	throttling.Store(false)
+	clockMutex.Lock()
+	now = now.Add(5 * time.Second)
+	clockMutex.Unlock()
+
+	for i := 0; i < 3; i++ {
+		if success, _ := invoker.Invoke(&testFunction, &testRuntimeSpecs); !success {
+			t.Fatal("Expected the endpoints to succeed after the backoff.")
+		}
+	}
+	if counts[1].Load() != 2 || len(invoker.BackingOff()) != 0 {
+		t.Errorf("Expected the second endpoint to recover after the backoff, got %d invocations.", counts[1].Load())
+	}
+}
+
+func TestRotatingEndpointInvokerAllThrottled(t *testing.T) {
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	invoker := NewRotatingEndpointInvoker(endpointInvoker{}, []string{server.URL}, DefaultBackoffSeconds)
+	if success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs); success || !record.Throttled {
+		t.Fatalf("Expected the endpoint to throttle, got %+v.", record)
+	}
+	if success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs); success || !record.Throttled || count.Load() != 1 {
+		t.Errorf("Expected the invocation to fail without being issued, got %+v after %d requests.", record, count.Load())
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// ScheduleJitter delays each invocation by a random offset, so that the invocations of different functions
+// scheduled for the same instant do not hit the endpoints all at once. The invocations of a minute may thus spill
+// over the end of the minute by at most MaxJitterMs.
+type ScheduleJitter struct {
+	MaxJitterMs int
+}
+
+// Offset draws the delay of an invocation uniformly from [0, MaxJitterMs]
+func (j ScheduleJitter) Offset(rng *rand.Rand) time.Duration {
+	return time.Duration(rng.Int63n(int64(j.MaxJitterMs)*int64(time.Millisecond) + 1))
+}
+
+// newJitterRand seeds the jitter of each function differently, as the same sequence of offsets for all the
+// functions would keep their invocations aligned
+func newJitterRand(seed int64, functionName string) *rand.Rand {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(functionName))
+
+	return rand.New(rand.NewSource(seed ^ int64(hash.Sum64())))
+}
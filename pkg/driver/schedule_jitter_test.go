@@ -0,0 +1,98 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestScheduleJitterOffset(t *testing.T) {
+	jitter := ScheduleJitter{MaxJitterMs: 100}
+	rng := newJitterRand(42, "trace-func-0")
+
+	const samples, buckets = 10000, 10
+	histogram := make([]int, buckets)
+	for i := 0; i < samples; i++ {
+		offset := jitter.Offset(rng)
+		if offset < 0 || offset > 100*time.Millisecond {
+			t.Fatalf("Offset %v is outside of [0, 100ms].", offset)
+		}
+		histogram[common.MinOf(int(offset/(10*time.Millisecond)), buckets-1)]++
+	}
+
+	for i, count := range histogram {
+		if count < samples/buckets*85/100 || count > samples/buckets*115/100 {
+			t.Errorf("Expected the offsets to be uniform, got %d samples in [%d, %d) ms.", count, i*10, (i+1)*10)
+		}
+	}
+
+	if newJitterRand(42, "trace-func-0").Int63() == newJitterRand(42, "trace-func-1").Int63() {
+		t.Error("Expected the functions to draw different offsets.")
+	}
+}
+
+func TestScheduleJitterDispatch(t *testing.T) {
+	jitter := ScheduleJitter{MaxJitterMs: 100}
+	rng := newJitterRand(42, "trace-func-0")
+
+	const invocations = 200
+	offsets := make([]time.Duration, invocations)
+	for i := range offsets {
+		offsets[i] = jitter.Offset(rng)
+	}
+
+	// All the invocations are scheduled for the same instant
+	dispatched := make([]time.Duration, invocations)
+	wg := sync.WaitGroup{}
+	start := time.Now()
+	for i := 0; i < invocations; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			time.Sleep(offsets[i])
+			dispatched[i] = time.Since(start)
+		}(i)
+	}
+	wg.Wait()
+
+	quarters := make([]int, 4)
+	for _, at := range dispatched {
+		if at > 150*time.Millisecond {
+			t.Fatalf("Invocation dispatched %v after the scheduled time, beyond the 100ms jitter window.", at)
+		}
+		quarters[common.MinOf(int(at/(25*time.Millisecond)), 3)]++
+	}
+
+	for i, count := range quarters {
+		if count < invocations/4/2 || count > invocations/4*2 {
+			t.Errorf("Expected the dispatch times to spread over the jitter window, got %d invocations in [%d, %d) ms.", count, i*25, (i+1)*25)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// ScheduledExperiment is one of the experiments run back-to-back by the Scheduler
+type ScheduledExperiment struct {
+	Name          string
+	Configuration *DriverConfiguration
+
+	// DelayBetweenRunsMinutes is the pause after the experiment before the next one starts
+	DelayBetweenRunsMinutes int
+	// WaitForWarmup invokes each function once and waits for the responses before the experiment starts
+	WaitForWarmup bool
+}
+
+type ExperimentSummary struct {
+	Name      string
+	StartTime time.Time
+	EndTime   time.Time
+
+	Issued     int64
+	Successful int64
+	Failed     int64
+
+	MeanResponseTimeMs float64
+	P50ResponseTimeMs  float64
+	P99ResponseTimeMs  float64
+
+	TimeoutSeries []time.Duration // function timeout in effect during each minute, if the adaptive timeout is enabled
+	Anomalies     []AnomalyReport // if the anomaly detection is enabled
+	Drifts        []DriftReport   // if the drift detection is enabled
+
+	Aggregate *ExperimentAggregate // if the results are aggregated
+}
+
+// Scheduler runs multiple experiments sequentially
+type Scheduler struct {
+	invoker Invoker
+	sleep   func(time.Duration)
+}
+
+// NewScheduler creates a scheduler invoking the functions through the given invoker, or on the configured platform
+// if the invoker is nil
+func NewScheduler(invoker Invoker) *Scheduler {
+	return &Scheduler{
+		invoker: invoker,
+		sleep:   time.Sleep,
+	}
+}
+
+// Schedule runs the experiments in the given order and returns one summary per experiment
+func (s *Scheduler) Schedule(experiments []ScheduledExperiment) []ExperimentSummary {
+	var summaries []ExperimentSummary
+
+	for i, experiment := range experiments {
+		log.Infof("Starting experiment %s (%d/%d)", experiment.Name, i+1, len(experiments))
+
+		d := NewDriver(experiment.Configuration)
+		d.Invoker = s.invoker
+
+		if experiment.WaitForWarmup {
+			d.warmUpFunctions()
+		}
+
+		start := time.Now()
+		d.RunExperiment(false, false)
+		summaries = append(summaries, d.summarize(experiment.Name, start, time.Now()))
+
+		if experiment.DelayBetweenRunsMinutes > 0 && i != len(experiments)-1 {
+			log.Infof("Waiting %d minutes before the next experiment", experiment.DelayBetweenRunsMinutes)
+			s.sleep(time.Duration(experiment.DelayBetweenRunsMinutes) * time.Minute)
+		}
+	}
+
+	return summaries
+}
+
+// warmUpFunctions issues one minimal invocation to each function and waits for all of them to complete
+func (d *Driver) warmUpFunctions() {
+	wg := sync.WaitGroup{}
+	announceDoneExe := sync.WaitGroup{}
+	readOpenWhiskMetadata := sync.Mutex{}
+
+	announceDoneExe.Add(len(d.Configuration.Functions))
+	for _, function := range d.Configuration.Functions {
+		wg.Add(1)
+
+		go func(function *common.Function) {
+			defer wg.Done()
+
+			warmupSpec := &common.RuntimeSpecification{Runtime: common.MinExecTimeMilli, Memory: common.MinMemQuotaMib}
+			if success, _ := d.invoke(function, warmupSpec, &announceDoneExe, &readOpenWhiskMetadata, nil); !success {
+				log.Warnf("Warm-up invocation of function %s failed.", function.Name)
+			}
+		}(function)
+	}
+
+	wg.Wait()
+}
+
+func (d *Driver) summarize(name string, start time.Time, end time.Time) ExperimentSummary {
+	summary := ExperimentSummary{
+		Name:       name,
+		StartTime:  start,
+		EndTime:    end,
+		Issued:     d.invocationCounts.issued,
+		Successful: d.invocationCounts.successful,
+		Failed:     d.invocationCounts.failed,
+	}
+	if d.adaptiveTimeout != nil {
+		summary.TimeoutSeries = d.adaptiveTimeout.Series()
+	}
+	if d.anomalies != nil {
+		summary.Anomalies = d.anomalies.Reports()
+	}
+	if d.drift != nil {
+		summary.Drifts = d.drift.Reports()
+	}
+	summary.Aggregate = d.experimentAggregate
+
+	records, err := d.readExecutionRecords()
+	if err != nil {
+		log.Warnf("Failed to read the results of experiment %s: %s", name, err)
+		return summary
+	}
+
+	var responseTimes []float64
+	for _, record := range records {
+		if !record.ConnectionTimeout && !record.FunctionTimeout && !record.Shed {
+			responseTimes = append(responseTimes, float64(record.ResponseTime)/1e3)
+		}
+	}
+	if len(responseTimes) == 0 {
+		return summary
+	}
+
+	sort.Float64s(responseTimes)
+
+	sum := 0.0
+	for _, responseTime := range responseTimes {
+		sum += responseTime
+	}
+	summary.MeanResponseTimeMs = sum / float64(len(responseTimes))
+	summary.P50ResponseTimeMs = percentileOfSorted(responseTimes, 0.50)
+	summary.P99ResponseTimeMs = percentileOfSorted(responseTimes, 0.99)
+
+	return summary
+}
+
+// percentileOfSorted returns the nearest-rank percentile of a sorted sample
+func percentileOfSorted(sorted []float64, percentile float64) float64 {
+	rank := int(percentile*float64(len(sorted))+0.5) - 1
+	rank = common.MaxOf(0, common.MinOf(rank, len(sorted)-1))
+
+	return sorted[rank]
+}
+
+// ComparisonMatrix compares experiments (rows) across metrics (columns)
+type ComparisonMatrix struct {
+	Experiments []string
+	Metrics     []string
+	Values      [][]float64
+}
+
+// CompareSummaries tabulates the summaries of the experiments for a side-by-side comparison
+func CompareSummaries(summaries []ExperimentSummary) ComparisonMatrix {
+	matrix := ComparisonMatrix{
+		Metrics: []string{"Issued", "Successful", "Failed", "FailureRate", "DurationSec", "MeanResponseTimeMs", "P50ResponseTimeMs", "P99ResponseTimeMs"},
+	}
+
+	for _, summary := range summaries {
+		failureRate := 0.0
+		if summary.Issued > 0 {
+			failureRate = float64(summary.Failed) / float64(summary.Issued)
+		}
+
+		matrix.Experiments = append(matrix.Experiments, summary.Name)
+		matrix.Values = append(matrix.Values, []float64{
+			float64(summary.Issued),
+			float64(summary.Successful),
+			float64(summary.Failed),
+			failureRate,
+			summary.EndTime.Sub(summary.StartTime).Seconds(),
+			summary.MeanResponseTimeMs,
+			summary.P50ResponseTimeMs,
+			summary.P99ResponseTimeMs,
+		})
+	}
+
+	return matrix
+}
+
+func (m ComparisonMatrix) String() string {
+	var builder strings.Builder
+	writer := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(writer, "Experiment\t%s\n", strings.Join(m.Metrics, "\t"))
+	for i, experiment := range m.Experiments {
+		row := make([]string, len(m.Values[i]))
+		for j, value := range m.Values[i] {
+			row[j] = fmt.Sprintf("%.2f", value)
+		}
+		fmt.Fprintf(writer, "%s\t%s\n", experiment, strings.Join(row, "\t"))
+	}
+
+	writer.Flush()
+	return builder.String()
+}
+
+// readExecutionRecords parses the invocation records written by the last experiment run
+func (d *Driver) readExecutionRecords() ([]*mc.ExecutionRecord, error) {
+	file, err := os.Open(d.outputFilename("duration"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []*mc.ExecutionRecord
+	if err = gocsv.UnmarshalFile(file, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
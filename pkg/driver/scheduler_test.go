@@ -0,0 +1,133 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func createScheduledExperiment(t *testing.T, name string, invocations int, runtime float64) ScheduledExperiment {
+	cfg := createFakeLoaderConfiguration()
+	cfg.OutputPathPrefix = filepath.Join(t.TempDir(), name)
+
+	return ScheduledExperiment{
+		Name: name,
+		Configuration: &DriverConfiguration{
+			LoaderConfiguration: cfg,
+			IATDistribution:     common.Equidistant,
+			TraceGranularity:    common.SecondGranularity,
+			TraceDuration:       1,
+			Functions: []*common.Function{
+				{
+					Name:            fmt.Sprintf("%s-function", name),
+					InvocationStats: &common.FunctionInvocationStats{Invocations: []int{invocations}},
+					RuntimeStats: &common.FunctionRuntimeStats{
+						Average: runtime, Count: 100, Minimum: runtime, Maximum: runtime,
+						Percentile0: runtime, Percentile1: runtime, Percentile25: runtime, Percentile50: runtime,
+						Percentile75: runtime, Percentile99: runtime, Percentile100: runtime,
+					},
+					MemoryStats: &common.FunctionMemoryStats{
+						Average: 128, Count: 100,
+						Percentile1: 128, Percentile5: 128, Percentile25: 128, Percentile50: 128,
+						Percentile75: 128, Percentile95: 128, Percentile99: 128, Percentile100: 128,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestSchedulerRunsExperimentsBackToBack(t *testing.T) {
+	invoker := NewSimulatedInvoker(5 * time.Millisecond)
+	scheduler := NewScheduler(invoker)
+
+	var delays []time.Duration
+	scheduler.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	experiments := []ScheduledExperiment{
+		createScheduledExperiment(t, "small", 2, 10),
+		createScheduledExperiment(t, "medium", 4, 100),
+		createScheduledExperiment(t, "large", 8, 1000),
+	}
+	experiments[0].DelayBetweenRunsMinutes = 2
+	experiments[1].WaitForWarmup = true
+	experiments[2].DelayBetweenRunsMinutes = 5 // last experiment, no delay expected
+
+	summaries := scheduler.Schedule(experiments)
+
+	if len(summaries) != len(experiments) {
+		t.Fatalf("Expected %d summaries, got %d.", len(experiments), len(summaries))
+	}
+	if invoker.Invocations() != 2+1+4+8 {
+		t.Errorf("Expected 15 invocations including the warm-up, got %d.", invoker.Invocations())
+	}
+	if len(delays) != 1 || delays[0] != 2*time.Minute {
+		t.Errorf("Unexpected delays between runs: %v", delays)
+	}
+
+	expected := []struct {
+		issued       int64
+		meanResponse float64
+	}{
+		{issued: 2, meanResponse: 15},
+		{issued: 4, meanResponse: 105},
+		{issued: 8, meanResponse: 1005},
+	}
+	for i, summary := range summaries {
+		if summary.Name != experiments[i].Name {
+			t.Errorf("Summary %d belongs to %s instead of %s.", i, summary.Name, experiments[i].Name)
+		}
+		if summary.Issued != expected[i].issued || summary.Successful != expected[i].issued || summary.Failed != 0 {
+			t.Errorf("Unexpected invocation counts of %s: %+v", summary.Name, summary)
+		}
+		if summary.MeanResponseTimeMs != expected[i].meanResponse || summary.P99ResponseTimeMs != expected[i].meanResponse {
+			t.Errorf("Unexpected response times of %s: %+v", summary.Name, summary)
+		}
+		if i > 0 && summary.StartTime.Before(summaries[i-1].EndTime) {
+			t.Errorf("Experiment %s started before the previous one finished.", summary.Name)
+		}
+	}
+
+	matrix := CompareSummaries(summaries)
+	if len(matrix.Experiments) != 3 || len(matrix.Values) != 3 || len(matrix.Values[0]) != len(matrix.Metrics) {
+		t.Fatalf("Unexpected comparison matrix dimensions: %+v", matrix)
+	}
+	if matrix.Values[2][0] != 8 {
+		t.Errorf("Expected 8 issued invocations for the large experiment, got %f.", matrix.Values[2][0])
+	}
+
+	table := matrix.String()
+	for _, name := range []string{"small", "medium", "large", "MeanResponseTimeMs"} {
+		if !strings.Contains(table, name) {
+			t.Errorf("Comparison table is missing %s:\n%s", name, table)
+		}
+	}
+}
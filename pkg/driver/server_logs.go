@@ -0,0 +1,114 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// serverLogsReader reads the chunks of the log streamed by the function server
+type serverLogsReader struct {
+	stream  proto.ServerLogs_GetServerLogsClient
+	pending []byte
+
+	cancel context.CancelFunc
+	conn   *grpc.ClientConn
+}
+
+// GetServerLogs streams the execution trace log of the function server at the endpoint, which is written if the
+// server runs with -server-trace-log. Closing the reader aborts the stream.
+func GetServerLogs(endpoint string) (io.ReadCloser, error) {
+	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := proto.NewServerLogsClient(conn).GetServerLogs(ctx, &emptypb.Empty{})
+	if err != nil {
+		cancel()
+		conn.Close()
+		return nil, err
+	}
+
+	return &serverLogsReader{stream: stream, cancel: cancel, conn: conn}, nil
+}
+
+func (r *serverLogsReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			// io.EOF once the whole log has been streamed
+			return 0, err
+		}
+		r.pending = chunk.Value
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+
+	return n, nil
+}
+
+func (r *serverLogsReader) Close() error {
+	r.cancel()
+	return r.conn.Close()
+}
+
+// collectServerLogs saves the execution trace log of the server of each function next to the other outputs
+func (d *Driver) collectServerLogs() {
+	for _, function := range d.Configuration.Functions {
+		path := fmt.Sprintf("%s_server_trace_%s.jsonl", d.Configuration.LoaderConfiguration.OutputPathPrefix, function.Name)
+		if err := saveServerLogs(function.Endpoint, path); err != nil {
+			log.Warnf("Failed to collect the server logs of function %s: %s", function.Name, err)
+		}
+	}
+}
+
+func saveServerLogs(endpoint string, path string) error {
+	logs, err := GetServerLogs(endpoint)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, logs)
+	return err
+}
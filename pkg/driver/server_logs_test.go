@@ -0,0 +1,85 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/workload/standard"
+)
+
+func TestGetServerLogs(t *testing.T) {
+	address, port := "localhost", 8088
+	function := testFunction
+	function.Endpoint = fmt.Sprintf("%s:%d", address, port)
+
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "", filepath.Join(t.TempDir(), "trace.log"))
+
+	// make sure that the gRPC server is running
+	time.Sleep(2 * time.Second)
+
+	cfg := createFakeLoaderConfiguration()
+	runtimes := []int{10, 20, 30}
+	for _, runtime := range runtimes {
+		if success, _ := InvokeGRPC(&function, &common.RuntimeSpecification{Runtime: runtime, Memory: 128}, cfg); !success {
+			t.Fatal("Invocation failed.")
+		}
+	}
+
+	logs, err := GetServerLogs(function.Endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logs.Close()
+
+	var entries []standard.TraceLogEntry
+	scanner := bufio.NewScanner(logs)
+	for scanner.Scan() {
+		var entry standard.TraceLogEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != len(runtimes) {
+		t.Fatalf("Expected %d logged invocations, got %d.", len(runtimes), len(entries))
+	}
+	for i, entry := range entries {
+		if entry.RuntimeMs != uint32(runtimes[i]) || entry.MemoryMib != 128 || entry.EndTimeUnixUs < entry.StartTimeUnixUs ||
+			entry.RequestID == "" || entry.NodeID == "" {
+			t.Errorf("Unexpected log entry %+v.", entry)
+		}
+	}
+}
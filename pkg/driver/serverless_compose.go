@@ -0,0 +1,141 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const serverlessComposeFile = "./serverless-compose.yml"
+
+// ServerlessCompose describes the serverless-compose.yml contents, which deploys multiple services in dependency order
+type ServerlessCompose struct {
+	Services map[string]ComposeService `yaml:"services"`
+}
+
+type ComposeService struct {
+	Path      string   `yaml:"path"`
+	Config    string   `yaml:"config,omitempty"` // name of the serverless.yml file within Path
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+}
+
+// GenerateComposeFile wires together the serverless-<index>.yml files of the services, where the index is the position
+// of the service in the slice. deps maps a service name to the names of the services deployed before it.
+func GenerateComposeFile(services []*Serverless, deps map[string][]string) *ServerlessCompose {
+	compose := &ServerlessCompose{Services: make(map[string]ComposeService, len(services))}
+
+	for i, service := range services {
+		compose.Services[service.Service] = ComposeService{
+			Path:   ".",
+			Config: fmt.Sprintf("serverless-%d.yml", i),
+		}
+	}
+
+	for name, dependencies := range deps {
+		service, ok := compose.Services[name]
+		if !ok {
+			log.Fatalf("Dependencies defined for unknown service %s", name)
+		}
+
+		service.DependsOn = append([]string(nil), dependencies...)
+		sort.Strings(service.DependsOn)
+		compose.Services[name] = service
+	}
+
+	if _, err := compose.DeploymentOrder(); err != nil {
+		log.Fatal(err)
+	}
+
+	return compose
+}
+
+// DeploymentOrder returns the services ordered such that every service comes after its dependencies. Independent
+// services are ordered by name.
+func (c *ServerlessCompose) DeploymentOrder() ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	names := make([]string, 0, len(c.Services))
+	for name := range c.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	state := make(map[string]int, len(names))
+	order := make([]string, 0, len(names))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("cyclic dependency involving service %s", name)
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		for _, dependency := range c.Services[name].DependsOn {
+			if _, ok := c.Services[dependency]; !ok {
+				return fmt.Errorf("service %s depends on unknown service %s", name, dependency)
+			}
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// CreateComposeFile dumps the contents of the ServerlessCompose struct into serverless-compose.yml
+func CreateComposeFile(compose *ServerlessCompose) {
+	data, err := yaml.Marshal(compose)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = os.WriteFile(serverlessComposeFile, data, os.FileMode(0644))
+	if err != nil {
+		log.Fatal(err)
+	}
+}
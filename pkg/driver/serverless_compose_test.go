@@ -0,0 +1,107 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func createComposeServices(count int) []*Serverless {
+	services := make([]*Serverless, count)
+	for i := range services {
+		services[i] = &Serverless{}
+		services[i].CreateHeader(i, "aws")
+	}
+
+	return services
+}
+
+func TestGenerateComposeFile(t *testing.T) {
+	compose := GenerateComposeFile(createComposeServices(4), map[string][]string{
+		"loader-0": {"loader-3", "loader-1"},
+		"loader-1": {"loader-2"},
+	})
+
+	if len(compose.Services) != 4 {
+		t.Fatalf("Expected 4 services, got %d", len(compose.Services))
+	}
+	if service := compose.Services["loader-2"]; service.Path != "." || service.Config != "serverless-2.yml" || len(service.DependsOn) != 0 {
+		t.Errorf("Unexpected service loader-2: %+v", service)
+	}
+
+	order, err := compose.DeploymentOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(order, ",") != "loader-2,loader-1,loader-3,loader-0" {
+		t.Errorf("Services are not ordered by their dependencies: %v", order)
+	}
+}
+
+func TestDeploymentOrderDetectsInvalidDependencies(t *testing.T) {
+	cyclic := &ServerlessCompose{Services: map[string]ComposeService{
+		"loader-0": {Path: ".", DependsOn: []string{"loader-1"}},
+		"loader-1": {Path: ".", DependsOn: []string{"loader-2"}},
+		"loader-2": {Path: ".", DependsOn: []string{"loader-0"}},
+	}}
+	if _, err := cyclic.DeploymentOrder(); err == nil {
+		t.Error("Expected an error for cyclic dependencies.")
+	}
+
+	unknown := &ServerlessCompose{Services: map[string]ComposeService{
+		"loader-0": {Path: ".", DependsOn: []string{"loader-9"}},
+	}}
+	if _, err := unknown.DeploymentOrder(); err == nil {
+		t.Error("Expected an error for an unknown dependency.")
+	}
+}
+
+func TestCreateComposeFile(t *testing.T) {
+	compose := GenerateComposeFile(createComposeServices(2), map[string][]string{"loader-1": {"loader-0"}})
+
+	CreateComposeFile(compose)
+	t.Cleanup(func() { os.Remove(serverlessComposeFile) })
+
+	data, err := os.ReadFile(serverlessComposeFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), "loader-1:\n        path: .\n        config: serverless-1.yml\n        dependsOn:\n            - loader-0") {
+		t.Errorf("Unexpected serverless-compose.yml:\n%s", string(data))
+	}
+
+	var parsed ServerlessCompose
+	if err = yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Services) != 2 || parsed.Services["loader-1"].DependsOn[0] != "loader-0" {
+		t.Errorf("Unexpected services after parsing: %+v", parsed.Services)
+	}
+}
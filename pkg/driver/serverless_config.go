@@ -31,10 +31,33 @@ import (
 	"gopkg.in/yaml.v3"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
+const (
+	// CloudFormationTemplateMaxBytes is the size limit of the template deployed by the Serverless Framework per service
+	// https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/cloudformation-limits.html
+	CloudFormationTemplateMaxBytes = 51200
+	// cloudFormationTemplateBaseBytes is the size of the template of a service without any functions (deployment
+	// bucket, IAM role, and outputs)
+	cloudFormationTemplateBaseBytes = 6144
+	// maxFunctionsPerServerless keeps the services below the CloudFormation template resource limit (500 resources per
+	// template) and the IAM maximum policy size (10240 bytes)
+	maxFunctionsPerServerless = 60
+
+	// LambdaAtEdgeMaxMemoryMiB and LambdaAtEdgeMaxTimeoutSeconds are the limits of viewer-triggered Lambda@Edge functions
+	// https://docs.aws.amazon.com/AmazonCloudFront/latest/DeveloperGuide/edge-functions-restrictions.html
+	LambdaAtEdgeMaxMemoryMiB      = 128
+	LambdaAtEdgeMaxTimeoutSeconds = 5
+
+	// LambdaMaxTimeoutSeconds is the longest timeout of an AWS Lambda function
+	LambdaMaxTimeoutSeconds = 900
+)
+
 // Serverless describes the serverless.yml contents.
 type Serverless struct {
 	Service          string                  `yaml:"service"`
@@ -42,26 +65,185 @@ type Serverless struct {
 	Provider         slsProvider             `yaml:"provider"`
 	Package          slsPackage              `yaml:"package,omitempty"`
 	Functions        map[string]*slsFunction `yaml:"functions"`
+	Plugins          []string                `yaml:"plugins,omitempty"`
+	CustomSection    map[string]interface{}  `yaml:"custom,omitempty"` // configuration of the plugins
+
+	runtime RuntimeType // runtime of the functions added with AddFunctionConfig
 }
 
+// RuntimeType is the language runtime the functions of a service are executed in
+type RuntimeType int
+
+const (
+	RuntimeGo RuntimeType = iota // container image of the trace function
+	RuntimePython311
+	RuntimeNodeJS20
+)
+
+// slsRuntime describes how the functions of a runtime are packaged
+type slsRuntime struct {
+	Name       string
+	Handler    string // empty for container images
+	SourceFile string // included in the package, defines Handler
+	LocalFile  string // trace function copied as SourceFile next to the serverless.yml file
+}
+
+var slsRuntimes = map[RuntimeType]slsRuntime{
+	RuntimeGo:        {Name: "go1.x"},
+	RuntimePython311: {Name: "python3.11", Handler: "index.handler", SourceFile: "index.py", LocalFile: "server/trace-func-py/aws/index.py"},
+	RuntimeNodeJS20:  {Name: "nodejs20.x", Handler: "handler.handler", SourceFile: "handler.js", LocalFile: "server/trace-func-node/aws/handler.js"},
+}
+
+// runtimeSourceDir is the directory the local files of the runtimes are relative to, replaced in tests
+var runtimeSourceDir = "."
+
 type slsProvider struct {
-	Name             string `yaml:"name"`
-	Runtime          string `yaml:"runtime"`
-	Stage            string `yaml:"stage"`
-	Region           string `yaml:"region"`
-	VersionFunctions bool   `yaml:"versionFunctions"`
+	Name             string      `yaml:"name"`
+	Runtime          string      `yaml:"runtime"`
+	Stage            string      `yaml:"stage"`
+	Region           string      `yaml:"region"`
+	VersionFunctions bool        `yaml:"versionFunctions"`
+	Tracing          *slsTracing `yaml:"tracing,omitempty"`
+
+	Environment map[string]string `yaml:"environment,omitempty"`
+}
+
+const (
+	XRayTracingActive      = "Active"
+	XRayTracingPassThrough = "PassThrough"
+)
+
+// slsTracing configures AWS X-Ray tracing of all the functions in the service
+type slsTracing struct {
+	XRayTracingMode string `yaml:"lambda"`
 }
 
 type slsPackage struct {
-	Patterns []string `yaml:"patterns"`
+	Individually bool     `yaml:"individually,omitempty"`
+	Patterns     []string `yaml:"patterns"`
 }
 
 type slsFunction struct {
-	Image       string `yaml:"image"`
-	Description string `yaml:"description"`
-	Name        string `yaml:"name"`
-	Url         bool   `yaml:"url"`
-	Timeout     string `yaml:"timeout"`
+	Image       string      `yaml:"image,omitempty"`
+	Handler     string      `yaml:"handler,omitempty"`
+	Runtime     string      `yaml:"runtime,omitempty"` // overrides the runtime of the provider
+	Package     *slsPackage `yaml:"package,omitempty"`
+	Description string      `yaml:"description"`
+	Name        string      `yaml:"name"`
+	Url         bool        `yaml:"url"`
+	Timeout     string      `yaml:"timeout"`
+	MemorySize  int         `yaml:"memorySize,omitempty"`
+	Events      []slsEvent  `yaml:"events,omitempty"`
+	VPC         *VPCConfig  `yaml:"vpc,omitempty"`
+
+	ReservedConcurrency *int32     `yaml:"reservedConcurrency,omitempty"` // nil means no reservation
+	DeadLetterQueue     *DLQConfig `yaml:"onError,omitempty"`
+
+	EventSources []EventSource `yaml:"-"` // written to serverless.yml as events
+
+	// Cloud Run only
+	Source      string `yaml:"source,omitempty"`      // directory the container image of the service is built from
+	Memory      string `yaml:"memory,omitempty"`      // value of the --memory flag, see CloudRunMemory
+	Concurrency int32  `yaml:"concurrency,omitempty"` // maximum number of concurrent requests per instance
+
+	LambdaAtEdge bool              `yaml:"-"`
+	handlerFiles map[string]string // remote path in the package -> local path
+
+	containerImage string // restored when switching back to RuntimeGo
+}
+
+// VPCConfig places a function inside a VPC. Note that VPC-attached functions experience longer cold starts.
+type VPCConfig struct {
+	SecurityGroupIDs []string `yaml:"securityGroupIds"`
+	SubnetIDs        []string `yaml:"subnetIds"`
+}
+
+const (
+	DLQTypeSQS = "sqs"
+	DLQTypeSNS = "sns"
+)
+
+// dlqARNPattern matches the ARNs of SQS queues and SNS topics, capturing the service
+var dlqARNPattern = regexp.MustCompile(`^arn:aws(?:-cn|-us-gov)?:(sqs|sns):[a-z]{2}(?:-gov)?-[a-z]+-\d:\d{12}:[A-Za-z0-9_-]{1,256}(?:\.fifo)?$`)
+
+// DLQConfig is the SQS queue or SNS topic the failed asynchronous invocations of a function are sent to. It is
+// written to serverless.yml as the ARN only, from which the type is inferred when reading it back.
+type DLQConfig struct {
+	ARN  string
+	Type string // DLQTypeSQS or DLQTypeSNS
+}
+
+func (d DLQConfig) MarshalYAML() (interface{}, error) {
+	return d.ARN, nil
+}
+
+func (d *DLQConfig) UnmarshalYAML(value *yaml.Node) error {
+	if err := value.Decode(&d.ARN); err != nil {
+		return err
+	}
+
+	match := dlqARNPattern.FindStringSubmatch(d.ARN)
+	if match == nil {
+		return fmt.Errorf("invalid dead-letter queue ARN %s", d.ARN)
+	}
+	d.Type = match[1]
+
+	return nil
+}
+
+const (
+	EventSourceSQS      = "sqs"
+	EventSourceKinesis  = "kinesis"
+	EventSourceDynamoDB = "dynamodb"
+
+	// eventSourceMaxBatchSize is the maximum number of records AWS Lambda passes to a function in a batch, for all
+	// source types when the batching window is set
+	eventSourceMaxBatchSize = 10000
+)
+
+// eventSourceARNPattern matches the ARNs of SQS queues, Kinesis streams, and DynamoDB streams, capturing the service
+var eventSourceARNPattern = regexp.MustCompile(`^arn:aws(?:-cn|-us-gov)?:(sqs|kinesis|dynamodb):[a-z]{2}(?:-gov)?-[a-z]+-\d:\d{12}:\S+$`)
+
+// streamStartingPositions are the positions of a Kinesis or DynamoDB stream AWS Lambda can start reading from
+var streamStartingPositions = map[string][]string{
+	EventSourceKinesis:  {"LATEST", "TRIM_HORIZON", "AT_TIMESTAMP"},
+	EventSourceDynamoDB: {"LATEST", "TRIM_HORIZON"},
+}
+
+// EventSource is an SQS queue, Kinesis stream, or DynamoDB stream whose records trigger a function through an event
+// source mapping, for benchmarking event-driven functions
+type EventSource struct {
+	Type             string // EventSourceSQS, EventSourceKinesis, or EventSourceDynamoDB
+	ARN              string
+	BatchSize        int
+	StartingPosition string // streams only
+}
+
+type slsEvent struct {
+	CloudFront *slsCloudFrontEvent `yaml:"preExistingCloudFront,omitempty"`
+	SQS        *slsSQSEvent        `yaml:"sqs,omitempty"`
+	Stream     *slsStreamEvent     `yaml:"stream,omitempty"`
+}
+
+type slsSQSEvent struct {
+	ARN       string `yaml:"arn"`
+	BatchSize int    `yaml:"batchSize"`
+}
+
+type slsStreamEvent struct {
+	Type             string `yaml:"type"`
+	ARN              string `yaml:"arn"`
+	BatchSize        int    `yaml:"batchSize"`
+	StartingPosition string `yaml:"startingPosition"`
+}
+
+// slsCloudFrontEvent attaches a Lambda@Edge function to an existing CloudFront distribution
+// (requires the serverless-lambda-edge-pre-existing-cloudfront plugin)
+type slsCloudFrontEvent struct {
+	DistributionId string `yaml:"distributionId"`
+	EventType      string `yaml:"eventType"`
+	PathPattern    string `yaml:"pathPattern"`
+	IncludeBody    bool   `yaml:"includeBody"`
 }
 
 // CreateHeader sets the fields Service, FrameworkVersion, and Provider
@@ -75,6 +257,15 @@ func (s *Serverless) CreateHeader(index int, provider string) {
 		Region:           common.AwsRegion,
 		VersionFunctions: false,
 	}
+	switch provider {
+	case "cloudrun":
+		// Deployed with `gcloud run deploy` rather than the Serverless Framework, see DeployCloudRun
+		s.Provider.Runtime = cloudRunRuntime
+		s.Provider.Region = common.GcpRegion
+	case "alibaba":
+		s.Provider.Runtime = alibabaRuntime
+		s.Provider.Region = common.AlibabaRegion
+	}
 	s.Functions = map[string]*slsFunction{}
 }
 
@@ -94,6 +285,22 @@ func (s *Serverless) AddPackagePattern(pattern string) {
 	}
 }
 
+// Serverless Framework plugins commonly used with the loader
+const (
+	SlsPluginOffline = "serverless-offline"      // emulates AWS Lambda and API Gateway locally for testing
+	SlsPluginPrune   = "serverless-prune-plugin" // removes old versions of the functions
+
+	// SlsPluginLambdaEdgePreExistingCloudFront provides the preExistingCloudFront event of the Lambda@Edge functions
+	SlsPluginLambdaEdgePreExistingCloudFront = "serverless-lambda-edge-pre-existing-cloudfront"
+)
+
+// AddPlugin adds a plugin to Plugins as long as the plugin is not already in Plugins
+func (s *Serverless) AddPlugin(pluginName string) {
+	if !stringContains(s.Plugins, pluginName) {
+		s.Plugins = append(s.Plugins, pluginName)
+	}
+}
+
 // AddFunctionConfig adds the function configuration for serverless.com deployment
 func (s *Serverless) AddFunctionConfig(function *common.Function, provider string, awsAccountId string) {
 	// Extract trace-func-0 from trace-func-0-2642643831809466437 by splitting on "-"
@@ -104,37 +311,668 @@ func (s *Serverless) AddFunctionConfig(function *common.Function, provider strin
 	switch provider {
 	case "aws":
 		image = fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com/%s:latest", awsAccountId, common.AwsRegion, common.AwsTraceFuncRepositoryName)
-		timeout = "900"
+		timeout = strconv.Itoa(LambdaMaxTimeoutSeconds)
+	case "cloudrun":
+		s.Functions[function.Name] = &slsFunction{
+			Name:        shortName,
+			Source:      cloudRunSource,
+			Url:         true,
+			Timeout:     strconv.Itoa(cloudRunTimeoutSeconds),
+			Memory:      CloudRunMemory(function.MemoryRequestsMiB),
+			Concurrency: 1, // one invocation per instance, as on AWS Lambda
+		}
+		return
+	case "alibaba":
+		s.Functions[function.Name] = &slsFunction{
+			Handler:    alibabaHandler,
+			Name:       shortName,
+			Timeout:    strconv.Itoa(alibabaTimeoutSeconds),
+			MemorySize: AlibabaMemory(function.MemoryRequestsMiB),
+		}
+		return
 	default:
 		log.Fatalf("AddFunctionConfig could not recognize provider %s", provider)
 	}
 
-	f := &slsFunction{Image: image, Description: "", Name: shortName, Url: true, Timeout: timeout}
+	f := &slsFunction{Image: image, Description: "", Name: shortName, Url: true, Timeout: timeout, containerImage: image}
+	f.setRuntime(s.runtime)
 	s.Functions[function.Name] = f
 }
 
-// CreateServerlessConfigFile dumps the contents of the Serverless struct into a yml file (serverless-<index>.yml)
-func (s *Serverless) CreateServerlessConfigFile(index int) {
-	data, err := yaml.Marshal(&s)
-	if err != nil {
-		log.Fatal(err)
+const (
+	cloudRunRuntime        = "nodejs20"
+	cloudRunSource         = "server/trace-func-node/cloudrun" // Node.js 20 trace function served over HTTP
+	cloudRunTimeoutSeconds = 3600
+	cloudRunMaxConcurrency = 1000
+)
+
+// cloudRunMemoryMiB are the values accepted by the --memory flag of Cloud Run services
+var cloudRunMemoryMiB = []int{128, 256, 512, 1024, 2048, 4096, 8192, 16384, 32768}
+
+// CloudRunMemory rounds the memory of a function up to the closest value of the --memory flag of Cloud Run, from
+// 128Mi up to 32Gi
+func CloudRunMemory(memoryMiB int) string {
+	memory := cloudRunMemoryMiB[len(cloudRunMemoryMiB)-1]
+	for _, value := range cloudRunMemoryMiB {
+		if value >= memoryMiB {
+			memory = value
+			break
+		}
+	}
+
+	if memory < 1024 {
+		return fmt.Sprintf("%dMi", memory)
+	}
+	return fmt.Sprintf("%dGi", memory/1024)
+}
+
+const (
+	alibabaRuntime        = "custom.debian10"
+	alibabaHandler        = "handler"
+	alibabaTimeoutSeconds = 600
+
+	alibabaMinMemoryMiB  = 128
+	alibabaMaxMemoryMiB  = 3072
+	alibabaMemoryStepMiB = 64
+)
+
+// AlibabaMemory rounds the memory of a function up to the closest memory size of Alibaba Cloud Function Compute, a
+// multiple of 64 MB from 128 MB up to 3072 MB
+func AlibabaMemory(memoryMiB int) int {
+	memory := (memoryMiB + alibabaMemoryStepMiB - 1) / alibabaMemoryStepMiB * alibabaMemoryStepMiB
+
+	return common.MinOf(alibabaMaxMemoryMiB, common.MaxOf(alibabaMinMemoryMiB, memory))
+}
+
+// SetConcurrency sets the maximum number of concurrent requests an instance of a Cloud Run function serves
+func (s *Serverless) SetConcurrency(functionName string, concurrency int32) error {
+	if concurrency < 1 || concurrency > cloudRunMaxConcurrency {
+		return fmt.Errorf("concurrency must be between 1 and %d, got %d", cloudRunMaxConcurrency, concurrency)
+	}
+
+	f, ok := s.Functions[functionName]
+	if !ok {
+		return fmt.Errorf("function %s not found in service %s", functionName, s.Service)
+	}
+
+	f.Concurrency = concurrency
+	return nil
+}
+
+// WithRuntime executes the functions of the service in the given runtime. Source-based runtimes (Python, Node.js) call
+// the handler defined in their source file instead of running the container image of the trace function.
+func (s *Serverless) WithRuntime(rt RuntimeType) *Serverless {
+	runtime, ok := slsRuntimes[rt]
+	if !ok {
+		log.Fatalf("Unsupported runtime type %d", rt)
+	}
+
+	s.runtime = rt
+	s.Provider.Runtime = runtime.Name
+
+	// Only package the source file of the selected runtime
+	var patterns []string
+	for _, pattern := range s.Package.Patterns {
+		if !isRuntimeSourceFile(pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	s.Package.Patterns = patterns
+	if runtime.SourceFile != "" {
+		s.AddPackagePattern(runtime.SourceFile)
+	}
+
+	for _, f := range s.Functions {
+		f.setRuntime(rt)
+	}
+
+	return s
+}
+
+func isRuntimeSourceFile(pattern string) bool {
+	for _, runtime := range slsRuntimes {
+		if runtime.SourceFile != "" && runtime.SourceFile == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *slsFunction) setRuntime(rt RuntimeType) {
+	if f.containerImage == "" {
+		f.containerImage = f.Image
+	}
+
+	// A function is either deployed from a container image or from the source code
+	f.Handler = slsRuntimes[rt].Handler
+	if f.Handler != "" {
+		f.Image = ""
+	} else {
+		f.Image = f.containerImage
+	}
+}
+
+// FunctionWithRuntime is a function of a MixedRuntimeServerless service along with the runtime it is executed in
+type FunctionWithRuntime struct {
+	Function *common.Function
+	Runtime  RuntimeType
+}
+
+// MixedRuntimeServerless creates the serverless.yml of AWS Lambda functions executed in different runtimes. The
+// functions of RuntimeGo run the container image of the trace function in the runtime of the provider, while the others
+// set their own runtime and the source files of all their runtimes are packaged.
+func MixedRuntimeServerless(index int, functions []FunctionWithRuntime, awsAccountId string) *Serverless {
+	s := &Serverless{}
+	s.CreateHeader(index, "aws")
+	s.Provider.Runtime = slsRuntimes[RuntimeGo].Name
+
+	for _, function := range functions {
+		runtime, ok := slsRuntimes[function.Runtime]
+		if !ok {
+			log.Fatalf("Unsupported runtime type %d of function %s", function.Runtime, function.Function.Name)
+		}
+
+		s.AddFunctionConfig(function.Function, "aws", awsAccountId)
+
+		f := s.Functions[function.Function.Name]
+		f.setRuntime(function.Runtime)
+		if runtime.SourceFile != "" {
+			f.Runtime = runtime.Name
+			s.AddPackagePattern(runtime.SourceFile)
+		}
+	}
+
+	return s
+}
+
+// ValidateMixedRuntime checks that the handler of each function matches its runtime: the functions of RuntimeGo are
+// deployed from a container image without a handler, while the handlers of the other runtimes are defined in a source
+// file of the runtime's language that is packaged with the function.
+func ValidateMixedRuntime(s *Serverless) []error {
+	var names []string
+	for name := range s.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		f := s.Functions[name]
+
+		runtimeName := f.Runtime
+		if runtimeName == "" {
+			runtimeName = s.Provider.Runtime
+		}
+		runtime, ok := runtimeByName(runtimeName)
+		if !ok {
+			errs = append(errs, fmt.Errorf("function %s has the unsupported runtime %q", name, runtimeName))
+			continue
+		}
+
+		if runtime.SourceFile == "" {
+			if f.Image == "" || f.Handler != "" {
+				errs = append(errs, fmt.Errorf("function %s in runtime %s must be deployed from a container image without a handler", name, runtime.Name))
+			}
+			continue
+		}
+
+		if f.Image != "" {
+			errs = append(errs, fmt.Errorf("function %s in runtime %s cannot be deployed from a container image", name, runtime.Name))
+		}
+
+		dot := strings.LastIndex(f.Handler, ".")
+		if dot <= 0 || dot == len(f.Handler)-1 {
+			errs = append(errs, fmt.Errorf("function %s in runtime %s has the invalid handler %q", name, runtime.Name, f.Handler))
+			continue
+		}
+
+		sourceFile := f.Handler[:dot] + filepath.Ext(runtime.SourceFile)
+		packaged := stringContains(s.Package.Patterns, sourceFile)
+		if f.Package != nil {
+			packaged = packaged || stringContains(f.Package.Patterns, sourceFile)
+		}
+		if !packaged {
+			errs = append(errs, fmt.Errorf("source file %s of the handler of function %s is not packaged", sourceFile, name))
+		}
+	}
+
+	return errs
+}
+
+func runtimeByName(name string) (slsRuntime, bool) {
+	for _, runtime := range slsRuntimes {
+		if runtime.Name == name {
+			return runtime, true
+		}
+	}
+	return slsRuntime{}, false
+}
+
+// AddCustomHandlerFile packages the local file at localPath as remotePath with the given function only. The file is
+// copied next to the serverless.yml file when the latter is created.
+func (s *Serverless) AddCustomHandlerFile(functionName, localPath, remotePath string) error {
+	f, ok := s.Functions[functionName]
+	if !ok {
+		return fmt.Errorf("function %s does not exist", functionName)
+	}
+	if filepath.IsAbs(remotePath) || strings.HasPrefix(filepath.Clean(remotePath), "..") {
+		return fmt.Errorf("remote path %s must be relative to the service directory", remotePath)
+	}
+	if _, err := os.Stat(localPath); err != nil {
+		return err
+	}
+
+	if f.handlerFiles == nil {
+		f.handlerFiles = map[string]string{}
+	}
+	f.handlerFiles[remotePath] = localPath
+
+	if f.Package == nil {
+		f.Package = &slsPackage{}
+	}
+	if !stringContains(f.Package.Patterns, remotePath) {
+		f.Package.Patterns = append(f.Package.Patterns, remotePath)
+	}
+	s.Package.Individually = true
+
+	return nil
+}
+
+// copyHandlerFiles copies the source files of the runtimes and the custom handler files of all functions into the given
+// service directory
+func (s *Serverless) copyHandlerFiles(dir string) error {
+	for _, f := range s.Functions {
+		files := map[string]string{}
+
+		runtimeName := f.Runtime
+		if runtimeName == "" {
+			runtimeName = s.Provider.Runtime
+		}
+		if runtime, ok := runtimeByName(runtimeName); ok && runtime.SourceFile != "" {
+			files[runtime.SourceFile] = filepath.Join(runtimeSourceDir, runtime.LocalFile)
+		}
+		for remotePath, localPath := range f.handlerFiles {
+			files[remotePath] = localPath
+		}
+
+		for remotePath, localPath := range files {
+			data, err := os.ReadFile(localPath)
+			if err != nil {
+				return err
+			}
+
+			destination := filepath.Join(dir, remotePath)
+			if err = os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+				return err
+			}
+			if err = os.WriteFile(destination, data, 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddCloudFrontTrigger turns the function into a Lambda@Edge function triggered by the viewer requests of the given
+// CloudFront distribution, and limits its memory size and timeout to the Lambda@Edge constraints
+func (f *slsFunction) AddCloudFrontTrigger(distributionID, cacheBehaviorPath string) {
+	f.LambdaAtEdge = true
+	f.Url = false
+
+	if f.MemorySize == 0 || f.MemorySize > LambdaAtEdgeMaxMemoryMiB {
+		f.MemorySize = LambdaAtEdgeMaxMemoryMiB
+	}
+	if timeout, err := strconv.Atoi(f.Timeout); err != nil || timeout > LambdaAtEdgeMaxTimeoutSeconds {
+		f.Timeout = strconv.Itoa(LambdaAtEdgeMaxTimeoutSeconds)
 	}
 
-	err = os.WriteFile(fmt.Sprintf("./serverless-%d.yml", index), data, os.FileMode(0644))
+	f.Events = append(f.Events, slsEvent{
+		CloudFront: &slsCloudFrontEvent{
+			DistributionId: distributionID,
+			EventType:      "viewer-request",
+			PathPattern:    cacheBehaviorPath,
+			IncludeBody:    true,
+		},
+	})
+}
+
+// ValidateEventSource checks that the ARN is the one of the source type and that the batch size and the starting
+// position are supported by the source type
+func ValidateEventSource(src EventSource) error {
+	startingPositions, isStream := streamStartingPositions[src.Type]
+	if src.Type != EventSourceSQS && !isStream {
+		return fmt.Errorf("unsupported event source type %q", src.Type)
+	}
+
+	match := eventSourceARNPattern.FindStringSubmatch(src.ARN)
+	if match == nil {
+		return fmt.Errorf("invalid event source ARN %s", src.ARN)
+	}
+	if match[1] != src.Type || (isStream && !strings.Contains(src.ARN, "stream/")) {
+		return fmt.Errorf("ARN %s is not the one of an %s event source", src.ARN, src.Type)
+	}
 
+	if src.BatchSize < 1 || src.BatchSize > eventSourceMaxBatchSize {
+		return fmt.Errorf("batch size of %s event source must be between 1 and %d, got %d", src.Type, eventSourceMaxBatchSize, src.BatchSize)
+	}
+
+	if !isStream {
+		if src.StartingPosition != "" {
+			return fmt.Errorf("SQS event sources have no starting position")
+		}
+	} else if !stringContains(startingPositions, src.StartingPosition) {
+		return fmt.Errorf("%s event source requires a starting position among %v, got %q", src.Type, startingPositions, src.StartingPosition)
+	}
+
+	return nil
+}
+
+// AddEventSource triggers the function with the given name by the records of the event source
+func (s *Serverless) AddEventSource(functionName string, src EventSource) error {
+	f, ok := s.Functions[functionName]
+	if !ok {
+		return fmt.Errorf("function %s is not part of service %s", functionName, s.Service)
+	}
+
+	if err := ValidateEventSource(src); err != nil {
+		return err
+	}
+
+	f.EventSources = append(f.EventSources, src)
+	if src.Type == EventSourceSQS {
+		f.Events = append(f.Events, slsEvent{SQS: &slsSQSEvent{ARN: src.ARN, BatchSize: src.BatchSize}})
+	} else {
+		f.Events = append(f.Events, slsEvent{Stream: &slsStreamEvent{
+			Type:             src.Type,
+			ARN:              src.ARN,
+			BatchSize:        src.BatchSize,
+			StartingPosition: src.StartingPosition,
+		}})
+	}
+
+	return nil
+}
+
+// ValidateLambdaAtEdgeConstraints checks that a Lambda@Edge function respects the memory size and timeout limits
+func ValidateLambdaAtEdgeConstraints(fn *slsFunction) error {
+	if !fn.LambdaAtEdge {
+		return nil
+	}
+
+	if fn.MemorySize > LambdaAtEdgeMaxMemoryMiB {
+		return fmt.Errorf("Lambda@Edge function %s has %d MB of memory (max. %d MB)", fn.Name, fn.MemorySize, LambdaAtEdgeMaxMemoryMiB)
+	}
+
+	timeout, err := strconv.Atoi(fn.Timeout)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("Lambda@Edge function %s has an invalid timeout %q", fn.Name, fn.Timeout)
 	}
+	if timeout > LambdaAtEdgeMaxTimeoutSeconds {
+		return fmt.Errorf("Lambda@Edge function %s has a timeout of %d s (max. %d s)", fn.Name, timeout, LambdaAtEdgeMaxTimeoutSeconds)
+	}
+
+	return nil
+}
+
+// ValidateVPCConfig checks that a VPC configuration contains both security groups and subnets
+func ValidateVPCConfig(cfg *VPCConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if len(cfg.SecurityGroupIDs) == 0 {
+		return fmt.Errorf("VPC configuration requires at least one security group ID")
+	}
+	if len(cfg.SubnetIDs) == 0 {
+		return fmt.Errorf("VPC configuration requires at least one subnet ID")
+	}
+
+	return nil
+}
+
+// SetFunctionVPC deploys the function with the given name inside the VPC described by cfg
+func (s *Serverless) SetFunctionVPC(functionName string, cfg VPCConfig) error {
+	f, ok := s.Functions[functionName]
+	if !ok {
+		return fmt.Errorf("function %s is not part of service %s", functionName, s.Service)
+	}
+
+	if err := ValidateVPCConfig(&cfg); err != nil {
+		return err
+	}
+
+	f.VPC = &cfg
+	return nil
+}
+
+// SetReservedConcurrency reserves count concurrent executions for the function with the given name. A reservation
+// of zero throttles all the invocations of the function.
+func (s *Serverless) SetReservedConcurrency(functionName string, count int32) error {
+	f, ok := s.Functions[functionName]
+	if !ok {
+		return fmt.Errorf("function %s is not part of service %s", functionName, s.Service)
+	}
+
+	if count < 0 {
+		return fmt.Errorf("reserved concurrency of function %s must not be negative", functionName)
+	}
+
+	f.ReservedConcurrency = &count
+	return nil
+}
+
+// ThrottleFunction makes AWS Lambda reject all the invocations of the function with the given name
+func (s *Serverless) ThrottleFunction(functionName string) error {
+	return s.SetReservedConcurrency(functionName, 0)
+}
+
+// ValidateDLQConfig checks that the ARN is the one of an SQS queue or SNS topic, as given by the type
+func ValidateDLQConfig(cfg DLQConfig) error {
+	if cfg.Type != DLQTypeSQS && cfg.Type != DLQTypeSNS {
+		return fmt.Errorf("unsupported dead-letter queue type %q", cfg.Type)
+	}
+
+	match := dlqARNPattern.FindStringSubmatch(cfg.ARN)
+	if match == nil {
+		return fmt.Errorf("invalid dead-letter queue ARN %s", cfg.ARN)
+	}
+	if match[1] != cfg.Type {
+		return fmt.Errorf("ARN %s is not the one of an %s dead-letter queue", cfg.ARN, strings.ToUpper(cfg.Type))
+	}
+
+	return nil
+}
+
+// SetDeadLetterQueue sends the failed asynchronous invocations of the function with the given name to the SQS queue
+// or SNS topic (queueType sqs or sns) with the given ARN, keeping them for post-hoc analysis
+func (s *Serverless) SetDeadLetterQueue(functionName, arn, queueType string) error {
+	f, ok := s.Functions[functionName]
+	if !ok {
+		return fmt.Errorf("function %s is not part of service %s", functionName, s.Service)
+	}
+
+	cfg := DLQConfig{ARN: arn, Type: queueType}
+	if err := ValidateDLQConfig(cfg); err != nil {
+		return err
+	}
+
+	f.DeadLetterQueue = &cfg
+	return nil
+}
+
+// EnableXRay enables AWS X-Ray tracing of the functions in the given mode (Active or PassThrough)
+func (s *Serverless) EnableXRay(mode string) error {
+	if mode != XRayTracingActive && mode != XRayTracingPassThrough {
+		return fmt.Errorf("unsupported X-Ray tracing mode %q", mode)
+	}
+
+	s.Provider.Tracing = &slsTracing{XRayTracingMode: mode}
+	return nil
+}
+
+// SetAWSLambdaHandler selects the handler variant of the trace function used by all the functions in the service
+func (s *Serverless) SetAWSLambdaHandler(handler string) error {
+	switch handler {
+	case "", common.AwsLambdaHandlerTrace:
+		delete(s.Provider.Environment, common.AwsLambdaHandlerEnvironmentVariable)
+	case common.AwsLambdaHandlerMemory:
+		if s.Provider.Environment == nil {
+			s.Provider.Environment = map[string]string{}
+		}
+		s.Provider.Environment[common.AwsLambdaHandlerEnvironmentVariable] = handler
+	default:
+		return fmt.Errorf("unsupported AWS Lambda handler %q", handler)
+	}
+
+	return nil
+}
+
+// SetIdempotencyTable configures the DynamoDB table in which all the functions in the service record the responses
+// of the invocations carrying an idempotency key, none if empty
+func (s *Serverless) SetIdempotencyTable(table string) {
+	if table == "" {
+		delete(s.Provider.Environment, common.AwsIdempotencyTableEnvironmentVariable)
+		return
+	}
+
+	if s.Provider.Environment == nil {
+		s.Provider.Environment = map[string]string{}
+	}
+	s.Provider.Environment[common.AwsIdempotencyTableEnvironmentVariable] = table
+}
+
+// annotateExperimentMetadata records the deployment settings that affect the measurements of the experiment
+func (s *Serverless) annotateExperimentMetadata(metadata *ExperimentMetadata) {
+	if metadata == nil {
+		return
+	}
+
+	for name, f := range s.Functions {
+		if f.VPC != nil {
+			metadata.AddNote(fmt.Sprintf("Function %s of service %s is deployed in a VPC (higher cold-start latency expected)", name, s.Service))
+		}
+	}
+}
+
+// OptimalBatchSize estimates how many functions to define per serverless.yml so that the CloudFormation template of
+// each service stays within CloudFormationTemplateMaxBytes, given the average size of the template resources of a
+// function. The functions are spread evenly over the fewest services possible.
+func OptimalBatchSize(totalFunctions int, avgFunctionConfigBytes int) int {
+	if totalFunctions <= 0 {
+		return 0
+	}
+
+	batchSize := maxFunctionsPerServerless
+	if avgFunctionConfigBytes > 0 {
+		batchSize = common.MinOf(batchSize, (CloudFormationTemplateMaxBytes-cloudFormationTemplateBaseBytes)/avgFunctionConfigBytes)
+	}
+	batchSize = common.MaxOf(1, batchSize)
+
+	services := (totalFunctions + batchSize - 1) / batchSize
+
+	return (totalFunctions + services - 1) / services
+}
+
+// SplitServerlessConfig partitions the functions of the service into services of at most maxFunctions functions each,
+// sharing the provider and package settings. The functions are assigned in the order of their names and the services
+// are named <service>-<part>. The service is returned as is if it does not need to be split.
+func SplitServerlessConfig(s *Serverless, maxFunctions int) []*Serverless {
+	if maxFunctions <= 0 || len(s.Functions) <= maxFunctions {
+		return []*Serverless{s}
+	}
+
+	names := make([]string, 0, len(s.Functions))
+	for name := range s.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []*Serverless
+	for i := 0; i < len(names); i += maxFunctions {
+		part := &Serverless{
+			Service:          fmt.Sprintf("%s-%d", s.Service, len(result)),
+			FrameworkVersion: s.FrameworkVersion,
+			Provider:         s.Provider,
+			Package:          s.Package,
+			Functions:        map[string]*slsFunction{},
+			runtime:          s.runtime,
+		}
+		part.Package.Patterns = append([]string(nil), s.Package.Patterns...)
+		if s.Provider.Environment != nil {
+			part.Provider.Environment = make(map[string]string, len(s.Provider.Environment))
+			for key, value := range s.Provider.Environment {
+				part.Provider.Environment[key] = value
+			}
+		}
+
+		for _, name := range names[i:common.MinOf(i+maxFunctions, len(names))] {
+			part.Functions[name] = s.Functions[name]
+		}
+
+		result = append(result, part)
+	}
+
+	return result
+}
+
+// CreateServerlessConfigFile dumps the contents of the Serverless struct into a yml file (serverless-<index>.yml)
+func (s *Serverless) CreateServerlessConfigFile(index int) error {
+	for _, f := range s.Functions {
+		if err := ValidateLambdaAtEdgeConstraints(f); err != nil {
+			return err
+		}
+		if err := ValidateVPCConfig(f.VPC); err != nil {
+			return fmt.Errorf("invalid VPC configuration of function %s: %w", f.Name, err)
+		}
+	}
+
+	if err := s.copyHandlerFiles("."); err != nil {
+		return fmt.Errorf("failed to copy the handler files of service %s: %w", s.Service, err)
+	}
+
+	return s.WriteServerlessConfigFile(fmt.Sprintf("./serverless-%d.yml", index))
 }
 
 // DeployServerless deploys the functions defined in the serverless.com file and returns a map from function name to URL
 func DeployServerless(index int) map[int]string {
-	slsDeployCmd := exec.Command("sls", "deploy", "--config", fmt.Sprintf("./serverless-%d.yml", index))
-	stdoutStderr, err := slsDeployCmd.CombinedOutput()
+	functionToURL, err := deployServerlessFile(fmt.Sprintf("./serverless-%d.yml", index))
 	if err != nil {
-		log.Errorf("Failed to deploy serverless-%d.yml: %v\n%s", index, err, stdoutStderr)
+		log.Error(err)
 		return nil
 	}
+
+	log.Debugf("Deployed serverless-%d.yml", index)
+	return functionToURL
+}
+
+// runSlsDeploy runs `sls deploy` on the given file and returns its combined output, replaced in tests
+var runSlsDeploy = func(path string) ([]byte, error) {
+	return exec.Command("sls", "deploy", "--config", path).CombinedOutput()
+}
+
+// slsDeployError is returned if `sls deploy` fails, keeping its output to tell transient from permanent failures
+type slsDeployError struct {
+	path   string
+	err    error
+	output string
+}
+
+func (e *slsDeployError) Error() string {
+	return fmt.Sprintf("failed to deploy %s: %v\n%s", e.path, e.err, e.output)
+}
+
+func (e *slsDeployError) Unwrap() error {
+	return e.err
+}
+
+// deployServerlessFile deploys the given serverless.yml file and returns the URLs of the functions in the order
+// printed by the Serverless.com console
+func deployServerlessFile(path string) (map[int]string, error) {
+	stdoutStderr, err := runSlsDeploy(path)
+	if err != nil {
+		return nil, &slsDeployError{path: path, err: err, output: string(stdoutStderr)}
+	}
 	log.Debug("CMD response: ", string(stdoutStderr))
 
 	// Extract the URLs from the output
@@ -148,10 +986,78 @@ func DeployServerless(index int) map[int]string {
 		functionToURL[i] = urlMatches[i][0]
 	}
 
-	log.Debugf("Deployed serverless-%d.yml", index)
+	return functionToURL, nil
+}
+
+// runGcloudRunDeploy runs `gcloud run deploy` with the given arguments and returns its combined output, replaced in tests
+var runGcloudRunDeploy = func(args ...string) ([]byte, error) {
+	return exec.Command("gcloud", append([]string{"run", "deploy"}, args...)...).CombinedOutput()
+}
+
+// cloudRunURLRegex matches the line in which `gcloud run deploy` reports the URL of the deployed service
+var cloudRunURLRegex = regexp.MustCompile(`Service URL: (https://\S+)`)
+
+// DeployCloudRun deploys the functions defined in the serverless-<index>.yml file of the cloudrun provider as Cloud
+// Run services built from their source directory, and returns a map from the name of the function to its URL
+func DeployCloudRun(index int) map[string]string {
+	functionToURL, err := deployCloudRunFile(fmt.Sprintf("./serverless-%d.yml", index))
+	if err != nil {
+		log.Error(err)
+		return nil
+	}
+
+	log.Debugf("Deployed serverless-%d.yml to Cloud Run", index)
 	return functionToURL
 }
 
+func deployCloudRunFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Serverless
+	if err = yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// The services are deployed one after the other in the order of the file, in which the functions are sorted
+	var names []string
+	for name := range s.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	functionToURL := make(map[string]string)
+	for _, name := range names {
+		f := s.Functions[name]
+		args := []string{f.Name, "--source", f.Source, "--region", s.Provider.Region, "--allow-unauthenticated", "--quiet"}
+		if f.Memory != "" {
+			args = append(args, "--memory", f.Memory)
+		}
+		if f.Concurrency > 0 {
+			args = append(args, "--concurrency", strconv.Itoa(int(f.Concurrency)))
+		}
+		if f.Timeout != "" {
+			args = append(args, "--timeout", f.Timeout)
+		}
+
+		stdoutStderr, err := runGcloudRunDeploy(args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to deploy function %s to Cloud Run: %w\n%s", f.Name, err, stdoutStderr)
+		}
+		log.Debug("CMD response: ", string(stdoutStderr))
+
+		match := cloudRunURLRegex.FindSubmatch(stdoutStderr)
+		if match == nil {
+			return nil, fmt.Errorf("no service URL in the output of deploying function %s to Cloud Run:\n%s", f.Name, stdoutStderr)
+		}
+		functionToURL[name] = string(match[1])
+	}
+
+	return functionToURL, nil
+}
+
 // CleanServerless removes the deployed service and deletes the serverless-<index>.yml file
 func CleanServerless(index int) bool {
 	// Check if the serverless-<index>.yml file exists
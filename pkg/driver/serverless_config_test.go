@@ -0,0 +1,977 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+func createTestServerless() *Serverless {
+	s := &Serverless{}
+	s.CreateHeader(0, "aws")
+	s.AddFunctionConfig(&common.Function{Name: "trace-func-0-123456789"}, "aws", "123456789012")
+
+	return s
+}
+
+func TestLambdaAtEdgeCloudFrontTrigger(t *testing.T) {
+	s := createTestServerless()
+	f := s.Functions["trace-func-0-123456789"]
+
+	f.MemorySize = 1024
+	f.AddCloudFrontTrigger("E2QWRUHEXAMPLE", "/api/*")
+
+	if !f.LambdaAtEdge || f.Url {
+		t.Error("Function should be deployed as Lambda@Edge without a function URL.")
+	}
+	if f.MemorySize != LambdaAtEdgeMaxMemoryMiB || f.Timeout != "5" {
+		t.Errorf("Lambda@Edge limits were not applied (memory: %d, timeout: %s).", f.MemorySize, f.Timeout)
+	}
+	if err := ValidateLambdaAtEdgeConstraints(f); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"preExistingCloudFront:", "distributionId: E2QWRUHEXAMPLE", "pathPattern: /api/*", "memorySize: 128"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Generated YAML does not contain %q:\n%s", expected, data)
+		}
+	}
+}
+
+func TestCreateServerlessConfigFileLambdaAtEdge(t *testing.T) {
+	previousDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(previousDir) })
+
+	s := createTestServerless()
+	f := s.Functions["trace-func-0-123456789"]
+	f.AddCloudFrontTrigger("E2QWRUHEXAMPLE", "/api/*")
+
+	if err = s.CreateServerlessConfigFile(0); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile("serverless-0.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "plugins:\n    - "+SlsPluginLambdaEdgePreExistingCloudFront) {
+		t.Errorf("Expected the plugin providing the preExistingCloudFront event:\n%s", data)
+	}
+
+	f.MemorySize = 256
+	if err = s.CreateServerlessConfigFile(1); err == nil {
+		t.Error("Expected an error for a Lambda@Edge function with 256 MB of memory.")
+	}
+	if _, err = os.Stat("serverless-1.yml"); !os.IsNotExist(err) {
+		t.Error("Expected no configuration file for an invalid function.")
+	}
+}
+
+func TestValidateLambdaAtEdgeConstraints(t *testing.T) {
+	tests := []struct {
+		testName    string
+		function    slsFunction
+		expectError bool
+	}{
+		{
+			testName:    "regular_function",
+			function:    slsFunction{MemorySize: 10240, Timeout: "900"},
+			expectError: false,
+		},
+		{
+			testName:    "edge_function_within_limits",
+			function:    slsFunction{LambdaAtEdge: true, MemorySize: 128, Timeout: "5"},
+			expectError: false,
+		},
+		{
+			testName:    "edge_function_memory_exceeded",
+			function:    slsFunction{LambdaAtEdge: true, MemorySize: 256, Timeout: "5"},
+			expectError: true,
+		},
+		{
+			testName:    "edge_function_timeout_exceeded",
+			function:    slsFunction{LambdaAtEdge: true, MemorySize: 128, Timeout: "30"},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			err := ValidateLambdaAtEdgeConstraints(&test.function)
+			if (err != nil) != test.expectError {
+				t.Errorf("Expected error: %t, got: %v", test.expectError, err)
+			}
+		})
+	}
+}
+
+func TestSetFunctionVPC(t *testing.T) {
+	s := createTestServerless()
+
+	vpc := VPCConfig{
+		SecurityGroupIDs: []string{"sg-0123456789abcdef0"},
+		SubnetIDs:        []string{"subnet-0123456789abcdef0", "subnet-0fedcba9876543210"},
+	}
+	if err := s.SetFunctionVPC("trace-func-0-123456789", vpc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.SetFunctionVPC("non-existent-function", vpc); err == nil {
+		t.Error("Expected an error for an unknown function.")
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"vpc:", "securityGroupIds:", "sg-0123456789abcdef0", "subnetIds:", "subnet-0fedcba9876543210"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %q in the serverless config:\n%s", expected, string(data))
+		}
+	}
+
+	metadata := &ExperimentMetadata{}
+	s.annotateExperimentMetadata(metadata)
+	if len(metadata.Notes) != 1 || !strings.Contains(metadata.Notes[0], "VPC") {
+		t.Errorf("Expected a VPC note in the experiment metadata, got %v.", metadata.Notes)
+	}
+}
+
+func TestValidateVPCConfig(t *testing.T) {
+	tests := []struct {
+		testName    string
+		config      *VPCConfig
+		expectError bool
+	}{
+		{testName: "no_vpc", config: nil, expectError: false},
+		{testName: "valid", config: &VPCConfig{SecurityGroupIDs: []string{"sg-1"}, SubnetIDs: []string{"subnet-1"}}, expectError: false},
+		{testName: "missing_security_groups", config: &VPCConfig{SubnetIDs: []string{"subnet-1"}}, expectError: true},
+		{testName: "missing_subnets", config: &VPCConfig{SecurityGroupIDs: []string{"sg-1"}}, expectError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			err := ValidateVPCConfig(test.config)
+			if (err != nil) != test.expectError {
+				t.Errorf("Unexpected validation result: %v", err)
+			}
+		})
+	}
+}
+
+func TestVPCOfConfiguration(t *testing.T) {
+	if vpc := vpcOfConfiguration(&config.LoaderConfiguration{}); vpc != nil {
+		t.Errorf("Expected no VPC without security groups and subnets, got %+v", vpc)
+	}
+
+	cfg := &config.LoaderConfiguration{VPCSecurityGroupIDs: []string{"sg-1"}, VPCSubnetIDs: []string{"subnet-1", "subnet-2"}}
+	vpc := vpcOfConfiguration(cfg)
+	if vpc == nil || !reflect.DeepEqual(vpc.SecurityGroupIDs, cfg.VPCSecurityGroupIDs) || !reflect.DeepEqual(vpc.SubnetIDs, cfg.VPCSubnetIDs) {
+		t.Errorf("Unexpected VPC %+v", vpc)
+	}
+
+	s := createTestServerless()
+	if err := s.SetFunctionVPC("trace-func-0-123456789", *vpcOfConfiguration(&config.LoaderConfiguration{VPCSubnetIDs: []string{"subnet-1"}})); err == nil {
+		t.Error("Expected a VPC without security groups to be rejected")
+	}
+}
+
+func TestReservedConcurrency(t *testing.T) {
+	s := createTestServerless()
+	f := s.Functions["trace-func-0-123456789"]
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "reservedConcurrency") {
+		t.Errorf("Unreserved function should not have a reservedConcurrency key:\n%s", string(data))
+	}
+
+	if err = s.SetReservedConcurrency("trace-func-0-123456789", 10); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = yaml.Marshal(s)
+	if !strings.Contains(string(data), "reservedConcurrency: 10") {
+		t.Errorf("Expected a reserved concurrency of 10:\n%s", string(data))
+	}
+
+	if err = s.ThrottleFunction("trace-func-0-123456789"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = yaml.Marshal(s)
+	if f.ReservedConcurrency == nil || *f.ReservedConcurrency != 0 || !strings.Contains(string(data), "reservedConcurrency: 0") {
+		t.Errorf("Throttled function should have a reserved concurrency of 0:\n%s", string(data))
+	}
+
+	if err = s.SetReservedConcurrency("trace-func-0-123456789", -1); err == nil {
+		t.Error("Expected an error for a negative reserved concurrency.")
+	}
+	if err = s.ThrottleFunction("non-existent-function"); err == nil {
+		t.Error("Expected an error for an unknown function.")
+	}
+}
+
+func TestSetDeadLetterQueue(t *testing.T) {
+	s := createTestServerless()
+
+	const queueARN = "arn:aws:sqs:us-east-1:123456789012:loader-failed-invocations"
+	if err := s.SetDeadLetterQueue("trace-func-0-123456789", queueARN, DLQTypeSQS); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.SetDeadLetterQueue("non-existent-function", queueARN, DLQTypeSQS); err == nil {
+		t.Error("Expected an error for an unknown function.")
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "onError: "+queueARN) {
+		t.Errorf("Expected the ARN of the dead-letter queue under onError:\n%s", string(data))
+	}
+
+	var parsed Serverless
+	if err = yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if dlq := parsed.Functions["trace-func-0-123456789"].DeadLetterQueue; dlq == nil || *dlq != (DLQConfig{ARN: queueARN, Type: DLQTypeSQS}) {
+		t.Errorf("Expected the dead-letter queue to be read back, got %+v.", dlq)
+	}
+}
+
+func TestValidateDLQConfig(t *testing.T) {
+	tests := []struct {
+		testName    string
+		config      DLQConfig
+		expectError bool
+	}{
+		{testName: "sqs", config: DLQConfig{ARN: "arn:aws:sqs:us-east-1:123456789012:loader-dlq", Type: DLQTypeSQS}, expectError: false},
+		{testName: "sqs_fifo", config: DLQConfig{ARN: "arn:aws:sqs:eu-west-2:123456789012:loader-dlq.fifo", Type: DLQTypeSQS}, expectError: false},
+		{testName: "sns", config: DLQConfig{ARN: "arn:aws:sns:us-east-1:123456789012:loader_failures", Type: DLQTypeSNS}, expectError: false},
+		{testName: "sns_gov_cloud", config: DLQConfig{ARN: "arn:aws-us-gov:sns:us-gov-west-1:123456789012:loader-failures", Type: DLQTypeSNS}, expectError: false},
+		{testName: "type_mismatch", config: DLQConfig{ARN: "arn:aws:sns:us-east-1:123456789012:loader-failures", Type: DLQTypeSQS}, expectError: true},
+		{testName: "unsupported_type", config: DLQConfig{ARN: "arn:aws:sqs:us-east-1:123456789012:loader-dlq", Type: "eventbridge"}, expectError: true},
+		{testName: "sqs_url", config: DLQConfig{ARN: "https://sqs.us-east-1.amazonaws.com/123456789012/loader-dlq", Type: DLQTypeSQS}, expectError: true},
+		{testName: "short_account_id", config: DLQConfig{ARN: "arn:aws:sqs:us-east-1:1234:loader-dlq", Type: DLQTypeSQS}, expectError: true},
+		{testName: "missing_region", config: DLQConfig{ARN: "arn:aws:sns::123456789012:loader-failures", Type: DLQTypeSNS}, expectError: true},
+		{testName: "invalid_name", config: DLQConfig{ARN: "arn:aws:sqs:us-east-1:123456789012:loader/dlq", Type: DLQTypeSQS}, expectError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			err := ValidateDLQConfig(test.config)
+			if (err != nil) != test.expectError {
+				t.Errorf("Unexpected validation result: %v", err)
+			}
+		})
+	}
+}
+
+func TestAddEventSource(t *testing.T) {
+	s := createTestServerless()
+
+	sources := []EventSource{
+		{Type: EventSourceSQS, ARN: "arn:aws:sqs:us-east-1:123456789012:loader-events", BatchSize: 10},
+		{Type: EventSourceKinesis, ARN: "arn:aws:kinesis:us-east-1:123456789012:stream/loader-events", BatchSize: 100, StartingPosition: "LATEST"},
+		{Type: EventSourceDynamoDB, ARN: "arn:aws:dynamodb:us-east-1:123456789012:table/loader/stream/2024-01-01T00:00:00.000", BatchSize: 1000, StartingPosition: "TRIM_HORIZON"},
+	}
+	for _, src := range sources {
+		if err := s.AddEventSource("trace-func-0-123456789", src); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := s.AddEventSource("non-existent-function", sources[0]); err == nil {
+		t.Error("Expected an error for an unknown function.")
+	}
+	if err := s.AddEventSource("trace-func-0-123456789", EventSource{Type: EventSourceSQS, ARN: sources[0].ARN}); err == nil {
+		t.Error("Expected an error for an invalid event source.")
+	}
+
+	f := s.Functions["trace-func-0-123456789"]
+	if len(f.EventSources) != 3 || len(f.Events) != 3 {
+		t.Fatalf("Expected 3 event sources, got %d sources and %d events.", len(f.EventSources), len(f.Events))
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed Serverless
+	if err = yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	events := parsed.Functions["trace-func-0-123456789"].Events
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events in serverless.yml:\n%s", string(data))
+	}
+	if sqs := events[0].SQS; sqs == nil || *sqs != (slsSQSEvent{ARN: sources[0].ARN, BatchSize: 10}) {
+		t.Errorf("Unexpected SQS event %+v", sqs)
+	}
+	for i, src := range sources[1:] {
+		expected := slsStreamEvent{Type: src.Type, ARN: src.ARN, BatchSize: src.BatchSize, StartingPosition: src.StartingPosition}
+		if stream := events[i+1].Stream; stream == nil || *stream != expected {
+			t.Errorf("Unexpected %s stream event %+v", src.Type, stream)
+		}
+	}
+	if !strings.Contains(string(data), "- stream:") {
+		t.Errorf("Expected the streams under the stream key:\n%s", string(data))
+	}
+}
+
+func TestValidateEventSource(t *testing.T) {
+	const (
+		queueARN   = "arn:aws:sqs:us-east-1:123456789012:loader-events"
+		kinesisARN = "arn:aws:kinesis:us-east-1:123456789012:stream/loader-events"
+		dynamoARN  = "arn:aws:dynamodb:us-east-1:123456789012:table/loader/stream/2024-01-01T00:00:00.000"
+	)
+
+	tests := []struct {
+		testName    string
+		source      EventSource
+		expectError bool
+	}{
+		{testName: "sqs", source: EventSource{Type: EventSourceSQS, ARN: queueARN, BatchSize: 10}, expectError: false},
+		{testName: "sqs_max_batch", source: EventSource{Type: EventSourceSQS, ARN: queueARN, BatchSize: 10000}, expectError: false},
+		{testName: "sqs_empty_batch", source: EventSource{Type: EventSourceSQS, ARN: queueARN, BatchSize: 0}, expectError: true},
+		{testName: "sqs_batch_too_large", source: EventSource{Type: EventSourceSQS, ARN: queueARN, BatchSize: 10001}, expectError: true},
+		{testName: "sqs_starting_position", source: EventSource{Type: EventSourceSQS, ARN: queueARN, BatchSize: 10, StartingPosition: "LATEST"}, expectError: true},
+		{testName: "kinesis", source: EventSource{Type: EventSourceKinesis, ARN: kinesisARN, BatchSize: 1, StartingPosition: "AT_TIMESTAMP"}, expectError: false},
+		{testName: "kinesis_max_batch", source: EventSource{Type: EventSourceKinesis, ARN: kinesisARN, BatchSize: 10000, StartingPosition: "TRIM_HORIZON"}, expectError: false},
+		{testName: "kinesis_batch_too_large", source: EventSource{Type: EventSourceKinesis, ARN: kinesisARN, BatchSize: 10001, StartingPosition: "LATEST"}, expectError: true},
+		{testName: "kinesis_no_starting_position", source: EventSource{Type: EventSourceKinesis, ARN: kinesisARN, BatchSize: 100}, expectError: true},
+		{testName: "kinesis_invalid_starting_position", source: EventSource{Type: EventSourceKinesis, ARN: kinesisARN, BatchSize: 100, StartingPosition: "EARLIEST"}, expectError: true},
+		{testName: "dynamodb", source: EventSource{Type: EventSourceDynamoDB, ARN: dynamoARN, BatchSize: 100, StartingPosition: "LATEST"}, expectError: false},
+		{testName: "dynamodb_empty_batch", source: EventSource{Type: EventSourceDynamoDB, ARN: dynamoARN, BatchSize: 0, StartingPosition: "LATEST"}, expectError: true},
+		{testName: "dynamodb_at_timestamp", source: EventSource{Type: EventSourceDynamoDB, ARN: dynamoARN, BatchSize: 100, StartingPosition: "AT_TIMESTAMP"}, expectError: true},
+		{testName: "dynamodb_table_arn", source: EventSource{Type: EventSourceDynamoDB, ARN: "arn:aws:dynamodb:us-east-1:123456789012:table/loader", BatchSize: 100, StartingPosition: "LATEST"}, expectError: true},
+		{testName: "type_mismatch", source: EventSource{Type: EventSourceKinesis, ARN: queueARN, BatchSize: 100, StartingPosition: "LATEST"}, expectError: true},
+		{testName: "unsupported_type", source: EventSource{Type: "eventbridge", ARN: queueARN, BatchSize: 10}, expectError: true},
+		{testName: "invalid_arn", source: EventSource{Type: EventSourceSQS, ARN: "https://sqs.us-east-1.amazonaws.com/123456789012/loader-events", BatchSize: 10}, expectError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			err := ValidateEventSource(test.source)
+			if (err != nil) != test.expectError {
+				t.Errorf("Unexpected validation result: %v", err)
+			}
+		})
+	}
+}
+
+func TestEnableXRay(t *testing.T) {
+	s := createTestServerless()
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "tracing") {
+		t.Errorf("Tracing should be disabled by default:\n%s", string(data))
+	}
+
+	for _, mode := range []string{XRayTracingActive, XRayTracingPassThrough} {
+		if err = s.EnableXRay(mode); err != nil {
+			t.Fatal(err)
+		}
+
+		data, _ = yaml.Marshal(s)
+		if !strings.Contains(string(data), "tracing:\n        lambda: "+mode) {
+			t.Errorf("Expected tracing mode %s in the provider:\n%s", mode, string(data))
+		}
+	}
+
+	if err = s.EnableXRay("Passive"); err == nil {
+		t.Error("Expected an error for an unsupported tracing mode.")
+	}
+}
+
+func TestAddPlugin(t *testing.T) {
+	s := createTestServerless()
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "plugins:") || strings.Contains(string(data), "custom:") {
+		t.Errorf("Expected no plugins nor custom section by default:\n%s", string(data))
+	}
+
+	s.AddPlugin(SlsPluginOffline)
+	s.AddPlugin(SlsPluginPrune)
+	s.AddPlugin(SlsPluginOffline)
+	s.CustomSection = map[string]interface{}{
+		"prune": map[string]interface{}{"automatic": true, "number": 3},
+	}
+
+	data, err = yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "plugins:\n    - serverless-offline\n    - serverless-prune-plugin\n") {
+		t.Errorf("Expected exactly the two plugins:\n%s", string(data))
+	}
+	if strings.Count(string(data), SlsPluginOffline) != 1 {
+		t.Errorf("Expected %s once:\n%s", SlsPluginOffline, string(data))
+	}
+
+	var parsed Serverless
+	if err = yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.Plugins, []string{SlsPluginOffline, SlsPluginPrune}) {
+		t.Errorf("Expected the plugins to be read back, got %v", parsed.Plugins)
+	}
+	if !reflect.DeepEqual(parsed.CustomSection, s.CustomSection) {
+		t.Errorf("Expected the custom section %v to be read back, got %v", s.CustomSection, parsed.CustomSection)
+	}
+}
+
+func TestSetAWSLambdaHandler(t *testing.T) {
+	s := createTestServerless()
+
+	if err := s.SetAWSLambdaHandler(common.AwsLambdaHandlerMemory); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := yaml.Marshal(s)
+	if !strings.Contains(string(data), "environment:\n        TRACE_FUNC_HANDLER: memory") {
+		t.Errorf("Expected the memory handler to be selected in the provider:\n%s", string(data))
+	}
+
+	if err := s.SetAWSLambdaHandler(common.AwsLambdaHandlerTrace); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = yaml.Marshal(s)
+	if strings.Contains(string(data), "TRACE_FUNC_HANDLER") {
+		t.Errorf("The trace handler should not set the environment variable:\n%s", string(data))
+	}
+
+	if err := s.SetAWSLambdaHandler("cpu"); err == nil {
+		t.Error("Expected an error for an unsupported handler.")
+	}
+}
+
+func TestSetIdempotencyTable(t *testing.T) {
+	s := createTestServerless()
+
+	s.SetIdempotencyTable("loader-idempotency")
+	data, _ := yaml.Marshal(s)
+	if !strings.Contains(string(data), "environment:\n        IDEMPOTENCY_TABLE: loader-idempotency") {
+		t.Errorf("Expected the idempotency table to be set in the provider:\n%s", string(data))
+	}
+
+	s.SetIdempotencyTable("")
+	data, _ = yaml.Marshal(s)
+	if strings.Contains(string(data), "IDEMPOTENCY_TABLE") {
+		t.Errorf("Expected no idempotency table:\n%s", string(data))
+	}
+}
+
+func TestWithRuntime(t *testing.T) {
+	tests := []struct {
+		runtime         RuntimeType
+		expectedName    string
+		expectedHandler string
+		expectedFile    string
+	}{
+		{runtime: RuntimePython311, expectedName: "python3.11", expectedHandler: "index.handler", expectedFile: "index.py"},
+		{runtime: RuntimeNodeJS20, expectedName: "nodejs20.x", expectedHandler: "handler.handler", expectedFile: "handler.js"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expectedName, func(t *testing.T) {
+			s := createTestServerless().WithRuntime(test.runtime)
+			s.AddFunctionConfig(&common.Function{Name: "trace-func-1-123456789"}, "aws", "123456789012")
+
+			if s.Provider.Runtime != test.expectedName {
+				t.Errorf("Expected runtime %s, got %s", test.expectedName, s.Provider.Runtime)
+			}
+			if !stringContains(s.Package.Patterns, test.expectedFile) {
+				t.Errorf("Expected %s to be packaged, got %v", test.expectedFile, s.Package.Patterns)
+			}
+			for name, f := range s.Functions {
+				if f.Handler != test.expectedHandler || f.Image != "" {
+					t.Errorf("Function %s should run handler %s instead of an image, got handler %q and image %q", name, test.expectedHandler, f.Handler, f.Image)
+				}
+			}
+
+			s.WithRuntime(RuntimeGo)
+			if stringContains(s.Package.Patterns, test.expectedFile) {
+				t.Errorf("Expected %s not to be packaged with the Go runtime, got %v", test.expectedFile, s.Package.Patterns)
+			}
+			for name, f := range s.Functions {
+				if f.Handler != "" || !strings.HasSuffix(f.Image, common.AwsTraceFuncRepositoryName+":latest") {
+					t.Errorf("Function %s should run the trace function image again, got handler %q and image %q", name, f.Handler, f.Image)
+				}
+			}
+		})
+	}
+}
+
+// useRepositoryRuntimeSources copies the source files of the runtimes from the repository wherever the test runs
+func useRepositoryRuntimeSources(t *testing.T) {
+	repositoryRoot, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	originalSourceDir := runtimeSourceDir
+	runtimeSourceDir = repositoryRoot
+	t.Cleanup(func() { runtimeSourceDir = originalSourceDir })
+}
+
+func TestCreateServerlessConfigFileRuntimeSources(t *testing.T) {
+	useRepositoryRuntimeSources(t)
+
+	previousDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(previousDir) })
+
+	s := MixedRuntimeServerless(0, []FunctionWithRuntime{
+		{Function: &common.Function{Name: "trace-func-0-123456789"}, Runtime: RuntimeGo},
+		{Function: &common.Function{Name: "trace-func-1-123456789"}, Runtime: RuntimePython311},
+		{Function: &common.Function{Name: "trace-func-2-123456789"}, Runtime: RuntimeNodeJS20},
+	}, "123456789012")
+	if err = s.CreateServerlessConfigFile(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// The handlers are defined in the source files packaged with the functions
+	expectedDefinitions := map[string]string{"index.py": "def handler(event, context):", "handler.js": "exports.handler ="}
+	for sourceFile, definition := range expectedDefinitions {
+		data, err := os.ReadFile(sourceFile)
+		if err != nil {
+			t.Fatalf("Expected %s next to the serverless.yml file: %v", sourceFile, err)
+		}
+		if !strings.Contains(string(data), definition) {
+			t.Errorf("Expected %s to define the handler with %q.", sourceFile, definition)
+		}
+	}
+}
+
+func TestMixedRuntimeServerless(t *testing.T) {
+	s := MixedRuntimeServerless(0, []FunctionWithRuntime{
+		{Function: &common.Function{Name: "trace-func-0-123456789"}, Runtime: RuntimeGo},
+		{Function: &common.Function{Name: "trace-func-1-123456789"}, Runtime: RuntimePython311},
+		{Function: &common.Function{Name: "trace-func-2-123456789"}, Runtime: RuntimeNodeJS20},
+	}, "123456789012")
+
+	if errs := ValidateMixedRuntime(s); len(errs) != 0 {
+		t.Errorf("Unexpected validation errors %v", errs)
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed Serverless
+	if err = yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Provider.Runtime != "go1.x" || !reflect.DeepEqual(parsed.Package.Patterns, []string{"index.py", "handler.js"}) {
+		t.Errorf("Unexpected provider runtime %s or package patterns %v", parsed.Provider.Runtime, parsed.Package.Patterns)
+	}
+
+	expected := map[string][2]string{
+		"trace-func-0-123456789": {"", ""},
+		"trace-func-1-123456789": {"python3.11", "index.handler"},
+		"trace-func-2-123456789": {"nodejs20.x", "handler.handler"},
+	}
+	for name, runtimeAndHandler := range expected {
+		f := parsed.Functions[name]
+		if f.Runtime != runtimeAndHandler[0] || f.Handler != runtimeAndHandler[1] || (f.Handler == "") == (f.Image == "") {
+			t.Errorf("Function %s should have runtime %q and handler %q, got %+v", name, runtimeAndHandler[0], runtimeAndHandler[1], f)
+		}
+	}
+}
+
+func TestValidateMixedRuntime(t *testing.T) {
+	tests := []struct {
+		testName       string
+		modify         func(s *Serverless)
+		expectedErrors int
+	}{
+		{testName: "valid", modify: func(s *Serverless) {}},
+		{
+			testName:       "unsupported_runtime",
+			modify:         func(s *Serverless) { s.Functions["trace-func-1-123456789"].Runtime = "java21" },
+			expectedErrors: 1,
+		},
+		{
+			testName:       "go_function_with_handler",
+			modify:         func(s *Serverless) { s.Functions["trace-func-0-123456789"].Handler = "index.handler" },
+			expectedErrors: 1,
+		},
+		{
+			testName:       "python_handler_in_nodejs",
+			modify:         func(s *Serverless) { s.Functions["trace-func-2-123456789"].Handler = "index.handler" },
+			expectedErrors: 1,
+		},
+		{
+			testName:       "invalid_handler",
+			modify:         func(s *Serverless) { s.Functions["trace-func-1-123456789"].Handler = "handler" },
+			expectedErrors: 1,
+		},
+		{
+			testName:       "source_file_not_packaged",
+			modify:         func(s *Serverless) { s.Package.Patterns = []string{"index.py"} },
+			expectedErrors: 1,
+		},
+		{
+			testName: "source_file_packaged_with_function",
+			modify: func(s *Serverless) {
+				s.Package.Patterns = nil
+				s.Functions["trace-func-1-123456789"].Package = &slsPackage{Patterns: []string{"index.py"}}
+				s.Functions["trace-func-2-123456789"].Package = &slsPackage{Patterns: []string{"handler.js"}}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			s := MixedRuntimeServerless(0, []FunctionWithRuntime{
+				{Function: &common.Function{Name: "trace-func-0-123456789"}, Runtime: RuntimeGo},
+				{Function: &common.Function{Name: "trace-func-1-123456789"}, Runtime: RuntimePython311},
+				{Function: &common.Function{Name: "trace-func-2-123456789"}, Runtime: RuntimeNodeJS20},
+			}, "123456789012")
+			test.modify(s)
+
+			if errs := ValidateMixedRuntime(s); len(errs) != test.expectedErrors {
+				t.Errorf("Expected %d errors, got %v", test.expectedErrors, errs)
+			}
+		})
+	}
+}
+
+func TestAddCustomHandlerFile(t *testing.T) {
+	useRepositoryRuntimeSources(t)
+
+	s := createTestServerless().WithRuntime(RuntimePython311)
+	localPath := filepath.Join(t.TempDir(), "custom.py")
+	if err := os.WriteFile(localPath, []byte("def handler(event, context):\n    return {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.AddCustomHandlerFile("trace-func-0-123456789", localPath, "src/index.py"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddCustomHandlerFile("trace-func-9", localPath, "index.py"); err == nil {
+		t.Error("Expected an error for an unknown function.")
+	}
+	if err := s.AddCustomHandlerFile("trace-func-0-123456789", localPath, "../index.py"); err == nil {
+		t.Error("Expected an error for a path outside of the service directory.")
+	}
+
+	f := s.Functions["trace-func-0-123456789"]
+	if !s.Package.Individually || f.Package == nil || f.Package.Patterns[0] != "src/index.py" {
+		t.Errorf("Expected the file to be packaged with the function only, got %+v", f.Package)
+	}
+
+	dir := t.TempDir()
+	if err := s.copyHandlerFiles(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", "index.py")); err != nil {
+		t.Errorf("Expected the handler file to be copied: %s", err)
+	}
+}
+
+func TestSplitServerlessConfig(t *testing.T) {
+	s := &Serverless{}
+	s.CreateHeader(0, "aws")
+	s.AddPackagePattern("!**")
+	for i := 0; i < 200; i++ {
+		s.AddFunctionConfig(&common.Function{Name: fmt.Sprintf("trace-func-%d-123456789", i)}, "aws", "123456789012")
+	}
+
+	parts := SplitServerlessConfig(s, 20)
+	if len(parts) != 10 {
+		t.Fatalf("Expected 10 services, got %d.", len(parts))
+	}
+
+	services := make(map[string]bool)
+	functions := make(map[string]bool)
+	for _, part := range parts {
+		if len(part.Functions) > 20 {
+			t.Errorf("Service %s has %d functions, expected at most 20.", part.Service, len(part.Functions))
+		}
+		if services[part.Service] {
+			t.Errorf("Duplicate service %s.", part.Service)
+		}
+		services[part.Service] = true
+
+		if !reflect.DeepEqual(part.Provider, s.Provider) || part.FrameworkVersion != s.FrameworkVersion || !stringContains(part.Package.Patterns, "!**") {
+			t.Errorf("Service %s does not share the settings of the original service.", part.Service)
+		}
+
+		for name := range part.Functions {
+			if functions[name] {
+				t.Errorf("Function %s is defined in more than one service.", name)
+			}
+			functions[name] = true
+		}
+	}
+	if len(functions) != 200 {
+		t.Errorf("Expected all 200 functions to be defined, got %d.", len(functions))
+	}
+
+	if parts := SplitServerlessConfig(s, 200); len(parts) != 1 || parts[0] != s {
+		t.Error("Expected a service within the limit not to be split.")
+	}
+}
+
+func TestOptimalBatchSize(t *testing.T) {
+	tests := []struct {
+		name                   string
+		totalFunctions         int
+		avgFunctionConfigBytes int
+		expected               int
+	}{
+		{name: "no_functions", totalFunctions: 0, avgFunctionConfigBytes: 1000, expected: 0},
+		{name: "few_functions", totalFunctions: 10, avgFunctionConfigBytes: 1000, expected: 10},
+		{name: "resource_limit", totalFunctions: 120, avgFunctionConfigBytes: 100, expected: 60},
+		{name: "size_limit", totalFunctions: 200, avgFunctionConfigBytes: 2000, expected: 20},
+		{name: "balanced_services", totalFunctions: 45, avgFunctionConfigBytes: 2000, expected: 15},
+		{name: "oversized_function", totalFunctions: 3, avgFunctionConfigBytes: 100000, expected: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			batchSize := OptimalBatchSize(test.totalFunctions, test.avgFunctionConfigBytes)
+			if batchSize != test.expected {
+				t.Errorf("Expected %d functions per serverless.yml, got %d.", test.expected, batchSize)
+			}
+		})
+	}
+}
+
+func TestCloudRunFunctionConfig(t *testing.T) {
+	s := &Serverless{}
+	s.CreateHeader(0, "cloudrun")
+	s.AddFunctionConfig(&common.Function{Name: "trace-func-0-123456789", MemoryRequestsMiB: 300}, "cloudrun", "")
+
+	if s.Provider.Name != "cloudrun" || s.Provider.Runtime != "nodejs20" || s.Provider.Region != common.GcpRegion {
+		t.Errorf("Unexpected Cloud Run provider %+v.", s.Provider)
+	}
+
+	f := s.Functions["trace-func-0-123456789"]
+	if f.Memory != "512Mi" || f.Concurrency != 1 || f.Image != "" || f.Handler != "" {
+		t.Errorf("Unexpected Cloud Run function %+v.", f)
+	}
+
+	if err := s.SetConcurrency("trace-func-0-123456789", 80); err != nil || f.Concurrency != 80 {
+		t.Errorf("Expected the concurrency to be set, got %d (%v).", f.Concurrency, err)
+	}
+	if err := s.SetConcurrency("trace-func-0-123456789", 0); err == nil {
+		t.Error("Expected an error for a concurrency below 1.")
+	}
+	if err := s.SetConcurrency("non-existent-function", 10); err == nil {
+		t.Error("Expected an error for an unknown function.")
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"runtime: nodejs20", "source: server/trace-func-node/cloudrun", "memory: 512Mi", "concurrency: 80"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %q in the Cloud Run config:\n%s", expected, string(data))
+		}
+	}
+}
+
+func TestAlibabaFunctionConfig(t *testing.T) {
+	s := &Serverless{}
+	s.CreateHeader(3, "alibaba")
+	s.AddFunctionConfig(&common.Function{Name: "trace-func-0-123456789", MemoryRequestsMiB: 300}, "alibaba", "")
+	s.AddFunctionConfig(&common.Function{Name: "trace-func-1-987654321", MemoryRequestsMiB: 8192}, "alibaba", "")
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed map[string]interface{}
+	if err = yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed["service"] != "loader-3" {
+		t.Errorf("Expected the service loader-3, got %v.", parsed["service"])
+	}
+
+	provider := parsed["provider"].(map[string]interface{})
+	if provider["name"] != "alibaba" || provider["runtime"] != "custom.debian10" || provider["region"] != common.AlibabaRegion {
+		t.Errorf("Unexpected Alibaba Cloud provider %v.", provider)
+	}
+
+	functions := parsed["functions"].(map[string]interface{})
+	expectedMemory := map[string]int{"trace-func-0-123456789": 320, "trace-func-1-987654321": 3072}
+	if len(functions) != len(expectedMemory) {
+		t.Fatalf("Expected %d functions, got %v.", len(expectedMemory), functions)
+	}
+	for name, memory := range expectedMemory {
+		f, ok := functions[name].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Function %s is missing:\n%s", name, string(data))
+		}
+
+		if f["handler"] != "handler" || f["timeout"] != "600" || f["memorySize"] != memory || f["image"] != nil {
+			t.Errorf("Unexpected Alibaba Cloud function %s %v.", name, f)
+		}
+	}
+}
+
+func TestAlibabaMemory(t *testing.T) {
+	tests := map[int]int{0: 128, 100: 128, 128: 128, 129: 192, 1000: 1024, 3072: 3072, 5000: 3072}
+
+	for memory, expected := range tests {
+		if actual := AlibabaMemory(memory); actual != expected {
+			t.Errorf("Expected %d MB for %d MiB, got %d.", expected, memory, actual)
+		}
+	}
+}
+
+func TestCloudRunSource(t *testing.T) {
+	// The services are built from the source directory of the Node.js trace function
+	source := filepath.Join("..", "..", cloudRunSource)
+
+	data, err := os.ReadFile(filepath.Join(source, "package.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pkg struct {
+		Main    string            `json:"main"`
+		Engines map[string]string `json:"engines"`
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err = json.Unmarshal(data, &pkg); err != nil {
+		t.Fatal(err)
+	}
+
+	if "nodejs"+pkg.Engines["node"] != cloudRunRuntime || pkg.Scripts["start"] == "" {
+		t.Errorf("Expected a %s service with a start script, got %+v.", cloudRunRuntime, pkg)
+	}
+	if _, err = os.Stat(filepath.Join(source, pkg.Main)); err != nil {
+		t.Errorf("The main file of the service is missing: %v", err)
+	}
+}
+
+func TestCloudRunMemory(t *testing.T) {
+	tests := map[int]string{
+		0:     "128Mi",
+		128:   "128Mi",
+		129:   "256Mi",
+		1000:  "1Gi",
+		2048:  "2Gi",
+		10240: "16Gi",
+		65536: "32Gi",
+	}
+
+	for memoryMiB, expected := range tests {
+		if memory := CloudRunMemory(memoryMiB); memory != expected {
+			t.Errorf("CloudRunMemory(%d) = %s, expected %s", memoryMiB, memory, expected)
+		}
+	}
+}
+
+func TestDeployCloudRun(t *testing.T) {
+	s := &Serverless{}
+	s.CreateHeader(0, "cloudrun")
+	// trace-func-10 is deployed before trace-func-2
+	for _, i := range []int{2, 10} {
+		s.AddFunctionConfig(&common.Function{Name: fmt.Sprintf("trace-func-%d-123456789", i)}, "cloudrun", "")
+	}
+
+	path := filepath.Join(t.TempDir(), "serverless-0.yml")
+	if err := s.WriteServerlessConfigFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	var deployed [][]string
+	originalDeploy := runGcloudRunDeploy
+	runGcloudRunDeploy = func(args ...string) ([]byte, error) {
+		deployed = append(deployed, args)
+		return []byte(fmt.Sprintf("Deploying container to Cloud Run service [%s] in project [loader] region [us-central1]\n"+
+			"OK Deploying new service... Done.\n"+
+			"Service [%s] revision [%s-00001-abc] has been deployed and is serving 100 percent of traffic.\n"+
+			"Service URL: https://%s-abcdefghij-uc.a.run.app\n", args[0], args[0], args[0], args[0])), nil
+	}
+	t.Cleanup(func() {
+		runGcloudRunDeploy = originalDeploy
+	})
+
+	functionToURL, err := deployCloudRunFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"trace-func-2-123456789":  "https://trace-func-2-abcdefghij-uc.a.run.app",
+		"trace-func-10-123456789": "https://trace-func-10-abcdefghij-uc.a.run.app",
+	}
+	if !reflect.DeepEqual(functionToURL, expected) {
+		t.Errorf("Expected URLs %v, got %v.", expected, functionToURL)
+	}
+
+	if len(deployed) != 2 || !strings.Contains(strings.Join(deployed[0], " "), "--source server/trace-func-node/cloudrun --region us-central1 --allow-unauthenticated --quiet --memory 128Mi --concurrency 1 --timeout 3600") {
+		t.Errorf("Unexpected gcloud run deploy arguments %v.", deployed)
+	}
+
+	runGcloudRunDeploy = func(args ...string) ([]byte, error) {
+		return []byte("ERROR: (gcloud.run.deploy) PERMISSION_DENIED"), nil
+	}
+	if _, err = deployCloudRunFile(path); err == nil {
+		t.Error("Expected an error if no service URL is printed.")
+	}
+}
@@ -0,0 +1,212 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// serverlessMigrations holds the transformations that upgrade a serverless.yml from the framework version given
+// by the key to the next major version
+var serverlessMigrations = map[string]func(s *Serverless){
+	"3": migrateServerlessV3ToV4,
+}
+
+// migrateServerlessV3ToV4 applies the breaking changes of Serverless Framework 4
+func migrateServerlessV3ToV4(s *Serverless) {
+	s.FrameworkVersion = "4"
+
+	// The go1.x runtime has been deprecated by AWS and is rejected by v4, Go functions run on the OS-only runtime
+	if s.Provider.Runtime == "go1.x" {
+		s.Provider.Runtime = "provided.al2023"
+	}
+}
+
+// MigrateServerlessConfig returns a copy of the serverless configuration upgraded to the target framework version
+// (e.g. "4" or "v4"). The original configuration is left untouched.
+func MigrateServerlessConfig(s *Serverless, targetVersion string) (*Serverless, error) {
+	target := strings.TrimPrefix(strings.ToLower(targetVersion), "v")
+	desired, err := parseMajorVersion(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target framework version %q", targetVersion)
+	}
+
+	migrated := s.deepCopy()
+	for migrated.FrameworkVersion != target {
+		current, err := parseMajorVersion(migrated.FrameworkVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid framework version %q of service %s", migrated.FrameworkVersion, s.Service)
+		}
+		if current > desired {
+			return nil, fmt.Errorf("downgrading service %s from framework version %s to %s is not supported", s.Service, migrated.FrameworkVersion, target)
+		}
+
+		migration, ok := serverlessMigrations[migrated.FrameworkVersion]
+		if !ok {
+			return nil, fmt.Errorf("no migration available from framework version %s", migrated.FrameworkVersion)
+		}
+		migration(migrated)
+	}
+
+	return migrated, nil
+}
+
+func parseMajorVersion(version string) (int, error) {
+	var major int
+	_, err := fmt.Sscanf(version, "%d", &major)
+
+	return major, err
+}
+
+func (s *Serverless) deepCopy() *Serverless {
+	result := *s
+	result.Package.Patterns = append([]string(nil), s.Package.Patterns...)
+	if s.Provider.Tracing != nil {
+		tracing := *s.Provider.Tracing
+		result.Provider.Tracing = &tracing
+	}
+	if s.Provider.Environment != nil {
+		result.Provider.Environment = make(map[string]string, len(s.Provider.Environment))
+		for key, value := range s.Provider.Environment {
+			result.Provider.Environment[key] = value
+		}
+	}
+
+	result.Plugins = append([]string(nil), s.Plugins...)
+	if s.CustomSection != nil {
+		result.CustomSection = deepCopyYAMLValue(s.CustomSection).(map[string]interface{})
+	}
+
+	result.Functions = make(map[string]*slsFunction, len(s.Functions))
+	for name, f := range s.Functions {
+		function := *f
+		function.Events = nil
+		for _, event := range f.Events {
+			if event.CloudFront != nil {
+				cloudFront := *event.CloudFront
+				event.CloudFront = &cloudFront
+			}
+			if event.SQS != nil {
+				sqs := *event.SQS
+				event.SQS = &sqs
+			}
+			if event.Stream != nil {
+				stream := *event.Stream
+				event.Stream = &stream
+			}
+			function.Events = append(function.Events, event)
+		}
+		function.EventSources = append([]EventSource(nil), f.EventSources...)
+		if f.DeadLetterQueue != nil {
+			deadLetterQueue := *f.DeadLetterQueue
+			function.DeadLetterQueue = &deadLetterQueue
+		}
+		if f.ReservedConcurrency != nil {
+			reservedConcurrency := *f.ReservedConcurrency
+			function.ReservedConcurrency = &reservedConcurrency
+		}
+		if f.Package != nil {
+			function.Package = &slsPackage{Individually: f.Package.Individually, Patterns: append([]string(nil), f.Package.Patterns...)}
+		}
+		if f.handlerFiles != nil {
+			function.handlerFiles = make(map[string]string, len(f.handlerFiles))
+			for remotePath, localPath := range f.handlerFiles {
+				function.handlerFiles[remotePath] = localPath
+			}
+		}
+		if f.VPC != nil {
+			function.VPC = &VPCConfig{
+				SecurityGroupIDs: append([]string(nil), f.VPC.SecurityGroupIDs...),
+				SubnetIDs:        append([]string(nil), f.VPC.SubnetIDs...),
+			}
+		}
+
+		result.Functions[name] = &function
+	}
+
+	return &result
+}
+
+// deepCopyYAMLValue copies the maps and slices of a value decoded from YAML, such as the custom section
+func deepCopyYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			result[key] = deepCopyYAMLValue(element)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[interface{}]interface{}, len(v))
+		for key, element := range v {
+			result[key] = deepCopyYAMLValue(element)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, element := range v {
+			result[i] = deepCopyYAMLValue(element)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// ReadServerlessConfigFile parses an existing serverless.yml file
+func ReadServerlessConfigFile(path string) (*Serverless, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Serverless{}
+	if err = yaml.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// WriteServerlessConfigFile dumps the contents of the Serverless struct into the given file, adding the plugins the
+// events of the functions require
+func (s *Serverless) WriteServerlessConfigFile(path string) error {
+	for _, f := range s.Functions {
+		if f.LambdaAtEdge {
+			s.AddPlugin(SlsPluginLambdaEdgePreExistingCloudFront)
+		}
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, os.FileMode(0644))
+}
@@ -0,0 +1,152 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateServerlessConfigV3ToV4(t *testing.T) {
+	s := createTestServerless()
+	if err := s.SetFunctionVPC("trace-func-0-123456789", VPCConfig{SecurityGroupIDs: []string{"sg-1"}, SubnetIDs: []string{"subnet-1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []string{"4", "v4", "V4"} {
+		migrated, err := MigrateServerlessConfig(s, target)
+		if err != nil {
+			t.Fatalf("Unexpected migration error: %v", err)
+		}
+
+		if migrated.FrameworkVersion != "4" || migrated.Provider.Runtime != "provided.al2023" {
+			t.Errorf("Unexpected migrated header (version: %s, runtime: %s).", migrated.FrameworkVersion, migrated.Provider.Runtime)
+		}
+		if migrated.Service != s.Service || migrated.Provider.Region != s.Provider.Region || len(migrated.Functions) != len(s.Functions) {
+			t.Error("Migration should preserve the service definition.")
+		}
+
+		data, err := yaml.Marshal(migrated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "frameworkVersion: \"4\"") || !strings.Contains(string(data), "runtime: provided.al2023") {
+			t.Errorf("Unexpected v4 serverless config:\n%s", string(data))
+		}
+	}
+
+	// The original configuration must be left untouched
+	if s.FrameworkVersion != "3" || s.Provider.Runtime != "go1.x" {
+		t.Error("Migration modified the original configuration.")
+	}
+
+	migrated, _ := MigrateServerlessConfig(s, "v4")
+	migrated.Functions["trace-func-0-123456789"].VPC.SubnetIDs[0] = "subnet-2"
+	if s.Functions["trace-func-0-123456789"].VPC.SubnetIDs[0] != "subnet-1" {
+		t.Error("Migrated configuration shares the function definitions with the original.")
+	}
+}
+
+func TestMigrateServerlessConfigDeepCopy(t *testing.T) {
+	const name = "trace-func-0-123456789"
+
+	s := createTestServerless()
+	s.AddPlugin(SlsPluginPrune)
+	s.CustomSection = map[string]interface{}{"prune": map[string]interface{}{"automatic": true, "number": 3}}
+	if err := s.SetDeadLetterQueue(name, "arn:aws:sqs:us-east-1:123456789012:loader-failed-invocations", DLQTypeSQS); err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range []EventSource{
+		{Type: EventSourceSQS, ARN: "arn:aws:sqs:us-east-1:123456789012:loader-events", BatchSize: 10},
+		{Type: EventSourceKinesis, ARN: "arn:aws:kinesis:us-east-1:123456789012:stream/loader-events", BatchSize: 100, StartingPosition: "LATEST"},
+	} {
+		if err := s.AddEventSource(name, src); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, err := MigrateServerlessConfig(s, "4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := migrated.Functions[name]
+	f.DeadLetterQueue.ARN = "arn:aws:sqs:us-east-1:123456789012:other"
+	f.EventSources[0].BatchSize = 1
+	f.Events[0].SQS.BatchSize = 1
+	f.Events[1].Stream.StartingPosition = "TRIM_HORIZON"
+	migrated.Plugins[0] = "other-plugin"
+	migrated.CustomSection["prune"].(map[string]interface{})["number"] = 1
+
+	after, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Errorf("Mutating the migrated configuration changed the original:\n%s", string(after))
+	}
+	if s.Functions[name].EventSources[0].BatchSize != 10 {
+		t.Error("Migrated configuration shares the event sources with the original.")
+	}
+}
+
+func TestMigrateServerlessConfigInvalidTarget(t *testing.T) {
+	s := createTestServerless()
+
+	for _, target := range []string{"latest", "2", "5"} {
+		if _, err := MigrateServerlessConfig(s, target); err == nil {
+			t.Errorf("Expected an error when migrating to version %s.", target)
+		}
+	}
+
+	if migrated, err := MigrateServerlessConfig(s, "3"); err != nil || migrated.FrameworkVersion != "3" {
+		t.Errorf("Migrating to the current version should be a no-op (error: %v).", err)
+	}
+}
+
+func TestServerlessConfigFileRoundTrip(t *testing.T) {
+	s := createTestServerless()
+	path := filepath.Join(t.TempDir(), "serverless-0.yml")
+
+	if err := s.WriteServerlessConfigFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadServerlessConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.FrameworkVersion != "3" || read.Functions["trace-func-0-123456789"] == nil {
+		t.Errorf("Unexpected serverless config read back: %+v", read)
+	}
+}
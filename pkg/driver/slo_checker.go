@@ -0,0 +1,134 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+const (
+	DefaultBootstrapResamples = 1000
+	sloConfidenceLevel        = 0.95
+)
+
+// ConfidenceInterval bounds an estimate of a latency percentile, in µs
+type ConfidenceInterval struct {
+	Lower float64
+	Upper float64
+}
+
+// SLOVerdict is the outcome of checking a latency percentile of the candidate run against its threshold
+type SLOVerdict struct {
+	Percentile  float64
+	ThresholdUs float64
+
+	Baseline  ConfidenceInterval
+	Candidate ConfidenceInterval
+
+	// Empirical percentile of the candidate run, which exceeds the threshold on noise alone more often than the
+	// lower bound of its confidence interval does
+	CandidateUs float64
+
+	Violated  bool // the lower bound of the candidate's interval exceeds the threshold
+	Regressed bool // the candidate's interval lies entirely above the baseline's
+}
+
+// StatisticalSLOChecker checks the p50 and p99 latency of a candidate run against the SLO, estimating 95% confidence
+// intervals of the percentiles by bootstrap resampling instead of relying on the percentiles of a single run. The
+// interval of a baseline run is estimated as well to tell whether the candidate regressed.
+type StatisticalSLOChecker struct {
+	P50ThresholdUs float64
+	P99ThresholdUs float64
+	Resamples      int
+	Seed           int64 // of the resampling, for reproducible verdicts
+}
+
+func NewStatisticalSLOChecker(p50ThresholdUs, p99ThresholdUs float64) *StatisticalSLOChecker {
+	return &StatisticalSLOChecker{
+		P50ThresholdUs: p50ThresholdUs,
+		P99ThresholdUs: p99ThresholdUs,
+		Resamples:      DefaultBootstrapResamples,
+	}
+}
+
+// Check returns the verdicts of the p50 and p99 latency, in µs, of the candidate run
+func (c *StatisticalSLOChecker) Check(baseline, candidate []float64) ([]SLOVerdict, error) {
+	if len(baseline) == 0 || len(candidate) == 0 {
+		return nil, errors.New("the SLO cannot be checked on a run without latencies")
+	}
+	if c.Resamples <= 0 {
+		return nil, errors.New("the number of bootstrap resamples must be positive")
+	}
+
+	rng := rand.New(rand.NewSource(c.Seed))
+	sortedCandidate := sortedCopy(candidate)
+
+	var verdicts []SLOVerdict
+	for _, slo := range []struct{ percentile, thresholdUs float64 }{{0.50, c.P50ThresholdUs}, {0.99, c.P99ThresholdUs}} {
+		verdict := SLOVerdict{
+			Percentile:  slo.percentile,
+			ThresholdUs: slo.thresholdUs,
+			Baseline:    BootstrapPercentileCI(baseline, slo.percentile, c.Resamples, rng),
+			Candidate:   BootstrapPercentileCI(candidate, slo.percentile, c.Resamples, rng),
+			CandidateUs: percentileOfSorted(sortedCandidate, slo.percentile),
+		}
+		verdict.Violated = verdict.Candidate.Lower > slo.thresholdUs
+		verdict.Regressed = verdict.Candidate.Lower > verdict.Baseline.Upper
+
+		verdicts = append(verdicts, verdict)
+	}
+
+	return verdicts, nil
+}
+
+// BootstrapPercentileCI estimates the 95% confidence interval of a percentile of the latencies from the distribution
+// of the percentile over the given number of resamples with replacement
+func BootstrapPercentileCI(latencies []float64, percentile float64, resamples int, rng *rand.Rand) ConfidenceInterval {
+	estimates := make([]float64, resamples)
+	resample := make([]float64, len(latencies))
+	for i := range estimates {
+		for j := range resample {
+			resample[j] = latencies[rng.Intn(len(latencies))]
+		}
+		sort.Float64s(resample)
+		estimates[i] = percentileOfSorted(resample, percentile)
+	}
+	sort.Float64s(estimates)
+
+	alpha := (1 - sloConfidenceLevel) / 2
+	return ConfidenceInterval{
+		Lower: percentileOfSorted(estimates, alpha),
+		Upper: percentileOfSorted(estimates, 1-alpha),
+	}
+}
+
+func sortedCopy(values []float64) []float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	return sorted
+}
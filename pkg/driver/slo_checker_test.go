@@ -0,0 +1,112 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// syntheticLatencies returns n latencies of baseUs with the given slow ones, in µs
+func syntheticLatencies(n int, baseUs float64, slow ...float64) []float64 {
+	latencies := make([]float64, 0, n)
+	for i := 0; i < n-len(slow); i++ {
+		latencies = append(latencies, baseUs)
+	}
+
+	return append(latencies, slow...)
+}
+
+func TestStatisticalSLOCheckerNearThreshold(t *testing.T) {
+	// 2% slow invocations spread between 900 and 1200 µs put the empirical p99 just above the threshold
+	var slow []float64
+	for i := 0; i < 20; i++ {
+		slow = append(slow, 900+float64(i)*300/19)
+	}
+	baseline := syntheticLatencies(1000, 500)
+	candidate := syntheticLatencies(1000, 500, slow...)
+
+	checker := NewStatisticalSLOChecker(600, 1000)
+	verdicts, err := checker.Check(baseline, candidate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p50, p99 := verdicts[0], verdicts[1]
+	if p50.Percentile != 0.50 || p50.Violated || p50.Candidate != (ConfidenceInterval{Lower: 500, Upper: 500}) {
+		t.Errorf("Unexpected p50 verdict %+v", p50)
+	}
+
+	if p99.CandidateUs <= p99.ThresholdUs {
+		t.Fatalf("Expected the empirical p99 to exceed the threshold, got %f", p99.CandidateUs)
+	}
+	if p99.Violated {
+		t.Errorf("Expected the CI-based checker to avoid the false positive, got %+v", p99)
+	}
+	if !(p99.Candidate.Lower < p99.ThresholdUs && p99.ThresholdUs < p99.Candidate.Upper) {
+		t.Errorf("Expected the confidence interval to contain the threshold, got %+v", p99.Candidate)
+	}
+	if !p99.Regressed || p99.Baseline != (ConfidenceInterval{Lower: 500, Upper: 500}) {
+		t.Errorf("Expected the candidate to regress from the baseline, got %+v", p99)
+	}
+}
+
+func TestStatisticalSLOCheckerViolation(t *testing.T) {
+	slow := make([]float64, 50)
+	for i := range slow {
+		slow[i] = 2000
+	}
+
+	checker := NewStatisticalSLOChecker(600, 1000)
+	checker.Resamples = 200
+
+	verdicts, err := checker.Check(syntheticLatencies(1000, 500), syntheticLatencies(1000, 500, slow...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verdicts[1].Violated || verdicts[1].Candidate.Lower != 2000 {
+		t.Errorf("Expected the p99 SLO to be violated, got %+v", verdicts[1])
+	}
+
+	if _, err = checker.Check(nil, syntheticLatencies(10, 500)); err == nil {
+		t.Error("Expected an error for a run without latencies.")
+	}
+	checker.Resamples = 0
+	if _, err = checker.Check(syntheticLatencies(10, 500), syntheticLatencies(10, 500)); err == nil {
+		t.Error("Expected an error without resamples.")
+	}
+}
+
+func TestBootstrapPercentileCI(t *testing.T) {
+	latencies := make([]float64, 500)
+	for i := range latencies {
+		latencies[i] = float64(i)
+	}
+
+	ci := BootstrapPercentileCI(latencies, 0.50, DefaultBootstrapResamples, rand.New(rand.NewSource(1)))
+	if !(ci.Lower < 250 && 250 < ci.Upper) || ci.Upper-ci.Lower > 100 {
+		t.Errorf("Expected a narrow interval around the median, got %+v", ci)
+	}
+}
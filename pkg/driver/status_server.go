@@ -0,0 +1,212 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const (
+	StatusPhaseWarmup   = "warmup"
+	StatusPhaseRunning  = "running"
+	StatusPhaseFinished = "finished"
+)
+
+// ExperimentStatus is the progress of the running experiment reported by the StatusServer
+type ExperimentStatus struct {
+	ExperimentID          string  `json:"experimentID"`
+	Phase                 string  `json:"phase"`
+	MinutesDone           int     `json:"minutesDone"`
+	MinutesTotal          int     `json:"minutesTotal"`
+	TotalInvocations      int64   `json:"totalInvocations"`
+	SuccessRate           float64 `json:"successRate"`
+	CurrentP99LatencyUs   int64   `json:"currentP99LatencyUs"`   // over the current minute, or the previous one early in a minute
+	EstimatedCostUSDCents float64 `json:"estimatedCostUSDCents"` // as if the functions ran on AWS Lambda
+}
+
+// statusTracker accumulates the ExperimentStatus while the experiment runs. Safe for concurrent use.
+type statusTracker struct {
+	mutex sync.Mutex
+
+	experimentID  string
+	warmupMinutes int // including the profiling minute
+	minutesTotal  int
+	minutesDone   int
+	finished      bool
+
+	invocations int64
+	successful  int64
+	gbSeconds   float64
+
+	minuteLatencies   []float64 // response times of the successful invocations in the current minute
+	previousMinuteP99 int64
+}
+
+func newStatusTracker(experimentID string, warmupMinutes int, minutesTotal int) *statusTracker {
+	return &statusTracker{
+		experimentID:  experimentID,
+		warmupMinutes: warmupMinutes,
+		minutesTotal:  minutesTotal,
+	}
+}
+
+// newExperimentID names the experiment after its output files and the time it was created at
+func newExperimentID(outputPathPrefix string, created time.Time) string {
+	return fmt.Sprintf("%s-%d", filepath.Base(outputPathPrefix), created.Unix())
+}
+
+func (s *statusTracker) Record(success bool, record *mc.ExecutionRecord) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.invocations++
+	if success {
+		s.successful++
+		s.gbSeconds += billedGBSeconds(record)
+		s.minuteLatencies = append(s.minuteLatencies, float64(record.ResponseTime))
+	}
+}
+
+func (s *statusTracker) EndMinute() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.minutesDone++
+	if len(s.minuteLatencies) > 0 {
+		s.previousMinuteP99 = s.p99()
+	}
+	s.minuteLatencies = nil
+}
+
+func (s *statusTracker) Finish() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.finished = true
+}
+
+func (s *statusTracker) p99() int64 {
+	sort.Float64s(s.minuteLatencies)
+	return int64(percentileOfSorted(s.minuteLatencies, 0.99))
+}
+
+func (s *statusTracker) Status() ExperimentStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	status := ExperimentStatus{
+		ExperimentID:          s.experimentID,
+		Phase:                 StatusPhaseRunning,
+		MinutesDone:           s.minutesDone,
+		MinutesTotal:          s.minutesTotal,
+		TotalInvocations:      s.invocations,
+		CurrentP99LatencyUs:   s.previousMinuteP99,
+		EstimatedCostUSDCents: EstimateAWSCost(s.gbSeconds, s.invocations),
+	}
+
+	if s.finished {
+		status.Phase = StatusPhaseFinished
+	} else if s.minutesDone < s.warmupMinutes {
+		status.Phase = StatusPhaseWarmup
+	}
+	if s.invocations > 0 {
+		status.SuccessRate = float64(s.successful) / float64(s.invocations)
+	}
+	if len(s.minuteLatencies) > 0 {
+		status.CurrentP99LatencyUs = s.p99()
+	}
+
+	return status
+}
+
+// StatusServer reports the progress of the experiment as JSON on GET /status, and the Prometheus metrics of the
+// loader (e.g. invitro_shed_total) on GET /metrics
+type StatusServer struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewStatusServer creates a server reporting the status returned by the given function on the given port
+func NewStatusServer(port int, status func() ExperimentStatus) *StatusServer {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(status()); err != nil {
+			log.Debugf("Failed to write the experiment status: %s", err)
+		}
+	})
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return &StatusServer{
+		server: &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux},
+	}
+}
+
+// Start serves the requests in a background goroutine
+func (s *StatusServer) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Experiment status server failed: %s", err)
+		}
+	}()
+
+	return nil
+}
+
+// Addr returns the address the server listens on once started
+func (s *StatusServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *StatusServer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,154 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func queryStatus(t *testing.T, server *StatusServer) map[string]interface{} {
+	_, port, err := net.SplitHostPort(server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := http.Get(fmt.Sprintf("http://localhost:%s/status", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected status code %d", response.StatusCode)
+	}
+
+	var status map[string]interface{}
+	if err = json.NewDecoder(response.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+
+	return status
+}
+
+func TestStatusServer(t *testing.T) {
+	tracker := newStatusTracker("test-experiment", 1, 3)
+
+	server := NewStatusServer(0, tracker.Status)
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	status := queryStatus(t, server)
+	for _, key := range []string{"experimentID", "phase", "minutesDone", "minutesTotal", "totalInvocations",
+		"successRate", "currentP99LatencyUs", "estimatedCostUSDCents"} {
+		if _, ok := status[key]; !ok {
+			t.Errorf("Status is missing %s", key)
+		}
+	}
+	if status["experimentID"] != "test-experiment" || status["phase"] != StatusPhaseWarmup ||
+		status["minutesDone"] != 0.0 || status["minutesTotal"] != 3.0 || status["totalInvocations"] != 0.0 {
+		t.Errorf("Unexpected initial status %v", status)
+	}
+
+	for i := 0; i < 100; i++ {
+		record := &mc.ExecutionRecord{}
+		record.ResponseTime = int64(i+1) * 1000
+		record.ActualDuration = 100_000
+		record.ActualMemoryUsage = 128 * 1024
+		tracker.Record(i%4 != 0, record)
+	}
+	tracker.EndMinute()
+
+	status = queryStatus(t, server)
+	if status["phase"] != StatusPhaseRunning || status["minutesDone"] != 1.0 || status["totalInvocations"] != 100.0 {
+		t.Errorf("Status not updated after the first minute: %v", status)
+	}
+	if status["successRate"] != 0.75 {
+		t.Errorf("Wrong success rate - got %v, expected 0.75", status["successRate"])
+	}
+	// nearest rank over the 75 successful invocations is the second slowest
+	if status["currentP99LatencyUs"] != 99_000.0 {
+		t.Errorf("Wrong p99 latency - got %v, expected 99000", status["currentP99LatencyUs"])
+	}
+	if status["estimatedCostUSDCents"].(float64) <= 0 {
+		t.Errorf("Expected a positive cost estimate - got %v", status["estimatedCostUSDCents"])
+	}
+
+	tracker.Finish()
+	if status = queryStatus(t, server); status["phase"] != StatusPhaseFinished {
+		t.Errorf("Wrong phase - got %v, expected %s", status["phase"], StatusPhaseFinished)
+	}
+}
+
+func TestStatusServerRejectsNonGet(t *testing.T) {
+	server := NewStatusServer(0, newStatusTracker("test-experiment", 0, 1).Status)
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(server.Addr())
+	response, err := http.Post(fmt.Sprintf("http://localhost:%s/status", port), "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Unexpected status code %d", response.StatusCode)
+	}
+}
+
+func TestStatusServerExposesMetrics(t *testing.T) {
+	server := NewStatusServer(0, newStatusTracker("test-experiment", 0, 1).Status)
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(server.Addr())
+	response, err := http.Get(fmt.Sprintf("http://localhost:%s/metrics", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusOK || !strings.Contains(string(body), "invitro_shed_total") {
+		t.Errorf("Expected the shed counter to be exposed, got status %d.", response.StatusCode)
+	}
+}
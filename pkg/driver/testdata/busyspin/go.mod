@@ -0,0 +1,3 @@
+module busyspin
+
+go 1.24
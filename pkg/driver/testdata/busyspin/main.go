@@ -0,0 +1,33 @@
+// Command busyspin is a Wasm reactor mirroring the busy spinning of the trace function, built with
+// GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared
+package main
+
+import (
+	"math"
+	"time"
+)
+
+const pageSize = 4096
+
+var sink float64
+
+//go:wasmexport execute
+func execute(runtimeMs, memoryMib uint32) uint32 {
+	memory := make([]byte, int(memoryMib)<<20)
+	for i := 0; i < len(memory); i += pageSize {
+		memory[i] = 1
+	}
+
+	var iterations uint32
+	deadline := time.Now().Add(time.Duration(runtimeMs) * time.Millisecond)
+	for time.Now().Before(deadline) {
+		for i := 0; i < 1000; i++ {
+			sink = math.Sqrt(10)
+		}
+		iterations++
+	}
+
+	return iterations
+}
+
+func main() {}
@@ -0,0 +1,85 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CalibrateTimeout suggests the timeout of each function, in seconds, as the 99th percentile of the execution time
+// of its successful invocations multiplied by the safety factor and rounded up. The timeouts are capped at the
+// maximum of AWS Lambda.
+func CalibrateTimeout(results []InvocationResult, safetyFactor float64) map[string]int32 {
+	durations := make(map[string][]float64)
+	for _, result := range results {
+		record := result.Record
+		if record == nil || record.ConnectionTimeout || record.FunctionTimeout || record.Shed {
+			continue
+		}
+		durations[result.Function] = append(durations[result.Function], float64(record.ActualDuration))
+	}
+
+	timeouts := make(map[string]int32, len(durations))
+	for function, functionDurations := range durations {
+		sort.Float64s(functionDurations)
+		p99Seconds := percentileOfSorted(functionDurations, 0.99) / 1e6
+
+		timeout := math.Max(1, math.Ceil(p99Seconds*safetyFactor))
+		if timeout > LambdaMaxTimeoutSeconds {
+			log.Warnf("Function %s needs a timeout of %.0f s, capping it at %d s.", function, timeout, LambdaMaxTimeoutSeconds)
+			timeout = LambdaMaxTimeoutSeconds
+		}
+		timeouts[function] = int32(timeout)
+	}
+
+	return timeouts
+}
+
+// ApplyTimeouts sets the timeout of the functions of the service, e.g. as calibrated by CalibrateTimeout
+func ApplyTimeouts(s *Serverless, timeouts map[string]int32) error {
+	for function, timeout := range timeouts {
+		f, ok := s.Functions[function]
+		if !ok {
+			return fmt.Errorf("function %s not found in service %s", function, s.Service)
+		}
+
+		maxTimeout := int32(LambdaMaxTimeoutSeconds)
+		if f.LambdaAtEdge {
+			maxTimeout = LambdaAtEdgeMaxTimeoutSeconds
+		}
+		if timeout < 1 || timeout > maxTimeout {
+			return fmt.Errorf("timeout of function %s must be between 1 and %d s, got %d", function, maxTimeout, timeout)
+		}
+
+		f.Timeout = strconv.Itoa(int(timeout))
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func createDurationResult(function string, durationUs uint32, failed bool) InvocationResult {
+	return InvocationResult{
+		Function: function,
+		Record: &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{
+			ActualDuration:  durationUs,
+			FunctionTimeout: failed,
+		}},
+	}
+}
+
+func TestCalibrateTimeout(t *testing.T) {
+	var results []InvocationResult
+
+	// Uniform between 0 and 10 s, p99 at 9.9 s
+	for i := 1; i <= 100; i++ {
+		results = append(results, createDurationResult("uniform", uint32(i*100_000), false))
+	}
+	// Exponential with a mean of 1 s, p99 at about 4.6 s
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < 10_000; i++ {
+		results = append(results, createDurationResult("exponential", uint32(rng.ExpFloat64()*1e6), false))
+	}
+	// Constant 100 ms, shorter than the minimum timeout
+	for i := 0; i < 10; i++ {
+		results = append(results, createDurationResult("short", 100_000, false))
+	}
+	// Constant 10 minutes, longer than the maximum timeout once multiplied
+	results = append(results, createDurationResult("long", 600_000_000, false))
+	// The failed invocations are ignored
+	results = append(results, createDurationResult("short", 100_000_000, true))
+
+	expected := map[string]int32{"uniform": 15, "exponential": 7, "short": 1, "long": 900}
+	if timeouts := CalibrateTimeout(results, 1.5); !reflect.DeepEqual(timeouts, expected) {
+		t.Errorf("Expected the timeouts %v, got %v", expected, timeouts)
+	}
+}
+
+func TestApplyTimeouts(t *testing.T) {
+	s := createTestServerless()
+	s.AddFunctionConfig(&common.Function{Name: "trace-func-1-123456789"}, "aws", "123456789012")
+
+	if err := ApplyTimeouts(s, map[string]int32{"trace-func-0-123456789": 15, "trace-func-1-123456789": 900}); err != nil {
+		t.Fatal(err)
+	}
+	if s.Functions["trace-func-0-123456789"].Timeout != "15" || s.Functions["trace-func-1-123456789"].Timeout != "900" {
+		t.Errorf("Timeouts were not applied: %s and %s", s.Functions["trace-func-0-123456789"].Timeout, s.Functions["trace-func-1-123456789"].Timeout)
+	}
+
+	if err := ApplyTimeouts(s, map[string]int32{"non-existent-function": 15}); err == nil {
+		t.Error("Expected an error for an unknown function.")
+	}
+	if err := ApplyTimeouts(s, map[string]int32{"trace-func-0-123456789": 901}); err == nil {
+		t.Error("Expected an error for a timeout above the maximum.")
+	}
+
+	s.Functions["trace-func-0-123456789"].LambdaAtEdge = true
+	if err := ApplyTimeouts(s, map[string]int32{"trace-func-0-123456789": 15}); err == nil {
+		t.Error("Expected an error for a Lambda@Edge timeout above 5 s.")
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// timeoutWindow counts the invocations of a function issued within one minute of the experiment
+type timeoutWindow struct {
+	total    int64
+	timedOut int64
+}
+
+// TimeoutHistogram tracks per function and per minute of the experiment the fraction of invocations that timed out.
+// Safe for concurrent use.
+type TimeoutHistogram struct {
+	mutex   sync.Mutex
+	windows map[string][]timeoutWindow
+	minutes int
+}
+
+func NewTimeoutHistogram() *TimeoutHistogram {
+	return &TimeoutHistogram{
+		windows: make(map[string][]timeoutWindow),
+	}
+}
+
+// isTimeout reports whether the invocation failed to connect or did not complete in time. Non-2xx HTTP responses,
+// such as 504 Gateway Timeout, are recorded as connection timeouts by the HTTP invokers.
+func isTimeout(record *mc.ExecutionRecord) bool {
+	return record.ConnectionTimeout || record.FunctionTimeout
+}
+
+// Record adds an invocation of the function issued in the given minute
+func (h *TimeoutHistogram) Record(function string, minute int, timedOut bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	windows := h.windows[function]
+	for len(windows) <= minute {
+		windows = append(windows, timeoutWindow{})
+	}
+
+	windows[minute].total++
+	if timedOut {
+		windows[minute].timedOut++
+	}
+
+	h.windows[function] = windows
+	h.minutes = max(h.minutes, minute+1)
+}
+
+// Rate returns the fraction of invocations of the function that timed out in the given minute
+func (h *TimeoutHistogram) Rate(function string, minute int) float64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	windows := h.windows[function]
+	if minute >= len(windows) || windows[minute].total == 0 {
+		return 0
+	}
+
+	return float64(windows[minute].timedOut) / float64(windows[minute].total)
+}
+
+// TimedOut returns the total number of invocations that timed out
+func (h *TimeoutHistogram) TimedOut() int64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var timedOut int64
+	for _, windows := range h.windows {
+		for _, window := range windows {
+			timedOut += window.timedOut
+		}
+	}
+
+	return timedOut
+}
+
+// PrintTimeoutTrend writes the timeout rate of every function in every minute of the experiment, followed by the
+// timeout rate across all the functions
+func PrintTimeoutTrend(w io.Writer, h *TimeoutHistogram) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.minutes == 0 {
+		fmt.Fprintln(w, "Timeout trend: no invocations issued yet")
+		return
+	}
+
+	functions := make([]string, 0, len(h.windows))
+	for function := range h.windows {
+		functions = append(functions, function)
+	}
+	sort.Strings(functions)
+
+	header := make([]string, h.minutes)
+	for minute := range header {
+		header[minute] = fmt.Sprintf("m%d", minute)
+	}
+
+	fmt.Fprintln(w, "Timeout rate per minute [%]:")
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(writer, "Function\t%s\t\n", strings.Join(header, "\t"))
+
+	overall := make([]timeoutWindow, h.minutes)
+	for _, function := range functions {
+		row := make([]string, h.minutes)
+		for minute := range row {
+			var window timeoutWindow
+			if minute < len(h.windows[function]) {
+				window = h.windows[function][minute]
+			}
+
+			overall[minute].total += window.total
+			overall[minute].timedOut += window.timedOut
+			row[minute] = formatTimeoutRate(window)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t\n", function, strings.Join(row, "\t"))
+	}
+
+	row := make([]string, h.minutes)
+	for minute, window := range overall {
+		row[minute] = formatTimeoutRate(window)
+	}
+	fmt.Fprintf(writer, "all\t%s\t\n", strings.Join(row, "\t"))
+
+	writer.Flush()
+}
+
+func formatTimeoutRate(window timeoutWindow) string {
+	if window.total == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.1f", 100*float64(window.timedOut)/float64(window.total))
+}
@@ -0,0 +1,103 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"strings"
+	"testing"
+
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestTimeoutHistogramAggregatesPerMinute(t *testing.T) {
+	h := NewTimeoutHistogram()
+
+	// Increasing load: the timeout rate of f1 grows from 0% to 50% and 100%
+	for minute, timeouts := range []int{0, 2, 4} {
+		for i := 0; i < 4; i++ {
+			h.Record("f1", minute, i < timeouts)
+		}
+	}
+	h.Record("f2", 2, true)
+
+	expected := map[string][]float64{
+		"f1": {0, 0.5, 1},
+		"f2": {0, 0, 1},
+	}
+	for function, rates := range expected {
+		for minute, rate := range rates {
+			if h.Rate(function, minute) != rate {
+				t.Errorf("Expected a timeout rate of %.2f for %s in minute %d, got %.2f.", rate, function, minute, h.Rate(function, minute))
+			}
+		}
+	}
+	if h.TimedOut() != 7 {
+		t.Errorf("Expected 7 timeouts, got %d.", h.TimedOut())
+	}
+
+	var output strings.Builder
+	PrintTimeoutTrend(&output, h)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected a header, 2 functions and a total, got:\n%s", output.String())
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "f1 0.0 50.0 100.0" {
+		t.Errorf("Unexpected trend of f1: %s", lines[2])
+	}
+	if fields := strings.Fields(lines[3]); strings.Join(fields, " ") != "f2 - - 100.0" {
+		t.Errorf("Unexpected trend of f2: %s", lines[3])
+	}
+	if fields := strings.Fields(lines[4]); strings.Join(fields, " ") != "all 0.0 50.0 100.0" {
+		t.Errorf("Unexpected overall trend: %s", lines[4])
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	records := []struct {
+		record   mc.ExecutionRecord
+		expected bool
+	}{
+		{record: mc.ExecutionRecord{}, expected: false},
+		{record: mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{ConnectionTimeout: true}}, expected: true},
+		{record: mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{FunctionTimeout: true}}, expected: true},
+		{record: mc.ExecutionRecord{MemoryAllocationTimeout: true}, expected: false},
+	}
+
+	for i, r := range records {
+		if isTimeout(&r.record) != r.expected {
+			t.Errorf("Record %d: expected timeout %t.", i, r.expected)
+		}
+	}
+}
+
+func TestPrintTimeoutTrendWithoutInvocations(t *testing.T) {
+	var output strings.Builder
+	PrintTimeoutTrend(&output, NewTimeoutHistogram())
+
+	if !strings.Contains(output.String(), "no invocations") {
+		t.Errorf("Unexpected output: %s", output.String())
+	}
+}
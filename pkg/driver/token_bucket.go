@@ -0,0 +1,117 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrTokenWaitExceeded is returned when acquiring the tokens would block for longer than the maximum wait
+var ErrTokenWaitExceeded = errors.New("waiting for the tokens would exceed the maximum wait")
+
+// TokenBucketLimiter caps the throughput of the invocations with a token bucket holding up to Capacity tokens, one
+// per request, refilled at RefillRatePerSecond. The bucket starts full, so bursts of up to Capacity requests pass
+// immediately. Acquire reserves the tokens before sleeping, so the callers blocked concurrently are served in order.
+// Safe for concurrent use.
+type TokenBucketLimiter struct {
+	Capacity            float64
+	RefillRatePerSecond float64
+	MaxWaitMs           int // 0 to wait as long as needed
+
+	now   func() time.Time
+	sleep func(time.Duration)
+
+	mutex      sync.Mutex
+	tokens     float64 // negative while tokens are reserved by blocked callers
+	lastRefill time.Time
+}
+
+func NewTokenBucketLimiter(capacity, refillRatePerSecond float64, maxWaitMs int) *TokenBucketLimiter {
+	return newTokenBucketLimiterWithClock(capacity, refillRatePerSecond, maxWaitMs, time.Now, time.Sleep)
+}
+
+func newTokenBucketLimiterWithClock(capacity, refillRatePerSecond float64, maxWaitMs int, now func() time.Time,
+	sleep func(time.Duration)) *TokenBucketLimiter {
+
+	return &TokenBucketLimiter{
+		Capacity:            capacity,
+		RefillRatePerSecond: refillRatePerSecond,
+		MaxWaitMs:           maxWaitMs,
+		now:                 now,
+		sleep:               sleep,
+		tokens:              capacity,
+		lastRefill:          now(),
+	}
+}
+
+// Acquire blocks until n tokens are available and takes them. It returns ErrTokenWaitExceeded without taking any
+// token if that would take longer than MaxWaitMs.
+func (l *TokenBucketLimiter) Acquire(n int) error {
+	if float64(n) > l.Capacity {
+		return fmt.Errorf("cannot acquire %d tokens from a bucket of capacity %.0f", n, l.Capacity)
+	}
+
+	l.mutex.Lock()
+	l.refill()
+
+	l.tokens -= float64(n)
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.RefillRatePerSecond * float64(time.Second))
+	}
+
+	if l.MaxWaitMs > 0 && wait > time.Duration(l.MaxWaitMs)*time.Millisecond {
+		l.tokens += float64(n)
+		l.mutex.Unlock()
+
+		return ErrTokenWaitExceeded
+	}
+	l.mutex.Unlock()
+
+	if wait > 0 {
+		l.sleep(wait)
+	}
+
+	return nil
+}
+
+// GetCurrentTokens returns the number of tokens in the bucket, negative while tokens are reserved by blocked callers
+func (l *TokenBucketLimiter) GetCurrentTokens() float64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.refill()
+	return l.tokens
+}
+
+// refill adds the tokens accumulated since the last refill. Should be called only when the mutex is locked.
+func (l *TokenBucketLimiter) refill() {
+	now := l.now()
+	l.tokens = min(l.Capacity, l.tokens+now.Sub(l.lastRefill).Seconds()*l.RefillRatePerSecond)
+	l.lastRefill = now
+}
@@ -0,0 +1,133 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// fakeSleepClock is a clock advanced by sleeping on it
+type fakeSleepClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *fakeSleepClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *fakeSleepClock) Sleep(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestTokenBucketLimiterThroughput(t *testing.T) {
+	clock := &fakeSleepClock{now: time.Unix(0, 0)}
+	limiter := newTokenBucketLimiterWithClock(1, 100, 0, clock.Now, clock.Sleep)
+
+	start := clock.Now()
+	for i := 0; i < 1000; i++ {
+		if err := limiter.Acquire(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := clock.Now().Sub(start); elapsed < 9900*time.Millisecond {
+		t.Errorf("Expected 1000 invocations at 100/s to take at least 9.9 s, took %s.", elapsed)
+	}
+}
+
+func TestTokenBucketLimiterTokens(t *testing.T) {
+	clock := &fakeSleepClock{now: time.Unix(0, 0)}
+	limiter := newTokenBucketLimiterWithClock(10, 5, 500, clock.Now, func(time.Duration) {})
+
+	if err := limiter.Acquire(8); err != nil || limiter.GetCurrentTokens() != 2 {
+		t.Fatalf("Expected 2 tokens left, got %f and %v.", limiter.GetCurrentTokens(), err)
+	}
+
+	// Missing 2 tokens would take 400 ms
+	if err := limiter.Acquire(4); err != nil || limiter.GetCurrentTokens() != -2 {
+		t.Fatalf("Expected 2 tokens to be reserved, got %f and %v.", limiter.GetCurrentTokens(), err)
+	}
+	// Missing 5 tokens would take 1 s
+	if err := limiter.Acquire(3); !errors.Is(err, ErrTokenWaitExceeded) || limiter.GetCurrentTokens() != -2 {
+		t.Errorf("Expected the wait to be exceeded without taking any token, got %f and %v.", limiter.GetCurrentTokens(), err)
+	}
+
+	clock.Sleep(10 * time.Second)
+	if tokens := limiter.GetCurrentTokens(); tokens != 10 {
+		t.Errorf("Expected the bucket to refill up to its capacity, got %f.", tokens)
+	}
+	if err := limiter.Acquire(11); err == nil {
+		t.Error("Expected an error for more tokens than the capacity.")
+	}
+}
+
+func TestTokenBucketLimiterConcurrent(t *testing.T) {
+	limiter := NewTokenBucketLimiter(10, 1000, 0)
+
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_ = limiter.Acquire(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Errorf("Expected 200 invocations at 1000/s with a burst of 10 to take at least 190 ms, took %s.", elapsed)
+	}
+}
+
+func TestDriverRPSBudget(t *testing.T) {
+	driver := createTestDriver()
+	driver.Configuration.LoaderConfiguration.OutputPathPrefix = filepath.Join(t.TempDir(), "test")
+	driver.Configuration.TraceGranularity = common.SecondGranularity
+	driver.Configuration.RPSBudget = 1
+
+	start := time.Now()
+	driver.RunExperiment(false, false)
+
+	// The 5 invocations of the first second take 4 s at 1 request per second
+	if elapsed := time.Since(start); elapsed < 4*time.Second {
+		t.Errorf("Expected the RPS budget to delay the invocations, the experiment took %s.", elapsed)
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/rand"
 	"os"
 	"strconv"
 	"sync"
@@ -52,8 +53,54 @@ type DriverConfiguration struct {
 	TraceGranularity    common.TraceGranularity
 	TraceDuration       int // in minutes
 
-	YAMLPath string
-	TestMode bool
+	YAMLPath       string
+	TestMode       bool
+	SkipPreflight  bool
+	LiveHistogram  bool   // print the histogram of execution times every minute
+	JaegerEndpoint string // host:port of the Jaeger agent receiving one span per invocation, disabled if empty
+	SpikeMode      bool   // issue all invocations of a minute within its first second
+
+	// CollectServerLogs fetches the execution trace logs of the function servers (Knative only) after the experiment
+	CollectServerLogs bool
+
+	StatusPort int // port of the StatusServer reporting the progress of the experiment, disabled if zero
+
+	// UseResultCache answers the invocations from the results of previous experiments in the same output directory
+	// where possible, see ResultCache
+	UseResultCache bool
+
+	// PredictLatency fits a LatencyPredictor to the execution times during the experiment and reports its error
+	// per minute
+	PredictLatency bool
+
+	// AnomalyThreshold enables the AnomalyDetector flagging the invocations with an execution time more than this many
+	// standard deviations away from the mean of the function, if non-zero
+	AnomalyThreshold float64
+
+	// DetectDrift tests the per-minute mean latency of each function for a monotonic trend, see DriftDetector
+	DetectDrift bool
+
+	// AggregateResults aggregates the results per function and minute and over the experiment while it runs, see
+	// AggregationPipeline
+	AggregateResults bool
+
+	// RPSBudget caps the requests per second of the experiment with a TokenBucketLimiter allowing bursts of up to one
+	// second of requests, if non-zero
+	RPSBudget float64
+
+	// MinAdaptiveTimeout enables the adaptive function timeout of gRPC invocations if non-zero, see AdaptiveTimeout
+	MinAdaptiveTimeout time.Duration
+
+	AbortPolicy *AbortPolicy // never abort the experiment if nil
+	ShedPolicy  *ShedPolicy  // never shed invocations if nil
+	AutoResume  bool         // resume the experiment after the cooldown of the abort policy
+	Notifier    Notifier     // informed about the breaches of the abort policy, logs them if nil
+
+	ScheduleJitter *ScheduleJitter      // dispatch the invocations exactly on schedule if nil
+	WarmKeepalive  *WarmKeepaliveConfig // no keepalive invocations between the invocations of the trace if nil
+
+	// MinVarianceScheduling dispatches the invocations with a MinVarianceScheduler instead of sleeping between them
+	MinVarianceScheduling bool
 
 	Functions []*common.Function
 }
@@ -61,13 +108,84 @@ type DriverConfiguration struct {
 type Driver struct {
 	Configuration          *DriverConfiguration
 	SpecificationGenerator *generator.SpecificationGenerator
+	Metadata               *ExperimentMetadata
+
+	// Invoker replaces the invocations on the configured platform if set. The functions are then neither deployed
+	// nor cleaned up by the driver.
+	Invoker Invoker
+
+	// Outcome of the last experiment run
+	invocationCounts    invocationCounts
+	histogram           *ExecutionHistogram
+	timeouts            *TimeoutHistogram
+	experimentAggregate *ExperimentAggregate // if the results are aggregated
+
+	abortMonitor    *abortMonitor    // set while the experiment runs if an abort policy is configured
+	adaptiveTimeout *AdaptiveTimeout // set while the experiment runs if the adaptive timeout is enabled
+	loadShedder     *LoadShedder     // set while the experiment runs if a shed policy is configured
+	status          *statusTracker
+	lifecycle       *LifecycleTracker
+	resultCache     *ResultCache         // set while the experiment runs if the results are cached
+	predictor       *LatencyPredictor    // set while the experiment runs if the latency is predicted
+	anomalies       *AnomalyDetector     // set while the experiment runs if the anomalies are detected
+	drift           *DriftDetector       // set while the experiment runs if the drifts are detected
+	aggregation     *AggregationPipeline // set if the results are aggregated, closed at the end of the experiment
+	limiter         *TokenBucketLimiter  // set while the experiment runs if the throughput is capped
+	tlsPin          *TLSPinConfig        // set if the certificate of the endpoints is pinned, shared by the invocations
+	shutdown        atomic.Bool
+
+	reloadedConfiguration atomic.Pointer[config.LoaderConfiguration] // set once the configuration is hot-reloaded
+}
+
+type invocationCounts struct {
+	issued     int64
+	successful int64
+	failed     int64
 }
 
 func NewDriver(driverConfig *DriverConfiguration) *Driver {
+	specificationGenerator := generator.NewSpecificationGenerator(driverConfig.LoaderConfiguration.Seed)
+	if correlation := driverConfig.LoaderConfiguration.RuntimeMemoryCorrelation; correlation != 0 {
+		specificationGenerator = generator.NewCorrelatedSpecificationGenerator(driverConfig.LoaderConfiguration.Seed, correlation).SpecificationGenerator
+	}
+	if driverConfig.IATDistribution == common.CompoundPoisson {
+		specificationGenerator.SetBurstSizeDistribution(burstSizeDistribution(driverConfig.LoaderConfiguration))
+	}
+
+	warmupMinutes := 0
+	if driverConfig.WithWarmup() {
+		warmupMinutes = driverConfig.LoaderConfiguration.WarmupDuration + 1 // including the profiling minute
+	}
+
+	var tlsPin *TLSPinConfig
+	if driverConfig.LoaderConfiguration.TLSPinnedCertHex != "" {
+		tlsPin = &TLSPinConfig{PinnedCertHex: driverConfig.LoaderConfiguration.TLSPinnedCertHex}
+	}
+
 	return &Driver{
 		Configuration:          driverConfig,
-		SpecificationGenerator: generator.NewSpecificationGenerator(driverConfig.LoaderConfiguration.Seed),
+		SpecificationGenerator: specificationGenerator,
+		Metadata:               &ExperimentMetadata{},
+		histogram:              NewExecutionHistogram(),
+		timeouts:               NewTimeoutHistogram(),
+		status: newStatusTracker(newExperimentID(driverConfig.LoaderConfiguration.OutputPathPrefix, time.Now()),
+			warmupMinutes, driverConfig.TraceDuration),
+		lifecycle: NewLifecycleTracker(),
+		tlsPin:    tlsPin,
+	}
+}
+
+// burstSizeDistribution returns the configured burst sizes, falling back to the defaults for unset fields
+func burstSizeDistribution(cfg *config.LoaderConfiguration) generator.BurstSizeDistribution {
+	distribution := generator.DefaultBurstSizeDistribution
+	if cfg.BurstSizeProbability != 0 {
+		distribution.Probability = cfg.BurstSizeProbability
+	}
+	if cfg.WithinBurstIATMicroseconds != 0 {
+		distribution.WithinBurstIAT = cfg.WithinBurstIATMicroseconds
 	}
+
+	return distribution
 }
 
 func (c *DriverConfiguration) WithWarmup() bool {
@@ -181,6 +299,7 @@ type InvocationMetadata struct {
 
 	MinuteIndex     int
 	InvocationIndex int
+	ScheduledTime   time.Time // at which the trace issues the invocation
 
 	SuccessCount        *int64
 	FailedCount         *int64
@@ -207,6 +326,97 @@ func composeInvocationID(timeGranularity common.TraceGranularity, minuteIndex in
 	return fmt.Sprintf("%s%d.inv%d", timePrefix, minuteIndex, invocationIndex)
 }
 
+// traceInvocation identifies an invocation of the trace
+type traceInvocation struct {
+	minute int
+	index  int
+}
+
+// invoke issues a single invocation of the function, either through the custom invoker or on the configured platform.
+// The invocation is nil outside the trace, e.g. for warm-up invocations, which are then neither deduplicated nor
+// identified to the function.
+func (d *Driver) invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification,
+	announceDoneExe *sync.WaitGroup, readOpenWhiskMetadata *sync.Mutex, invocation *traceInvocation) (bool, *mc.ExecutionRecord) {
+
+	if d.Invoker != nil {
+		return d.Invoker.Invoke(function, runtimeSpec)
+	}
+
+	cfg := d.loaderConfiguration()
+	switch cfg.Platform {
+	case "Knative":
+		opts := []GRPCInvocationOption{WithUnaryInterceptors(LoggingInterceptor, MetricsInterceptor)}
+		if d.adaptiveTimeout != nil {
+			opts = append(opts, WithFunctionTimeout(d.adaptiveTimeout.Current()))
+		}
+		if seconds := cfg.GRPCKeepaliveSeconds; seconds > 0 {
+			opts = append(opts, WithKeepalive(KeepaliveConfig{
+				Time:                time.Duration(seconds) * time.Second,
+				Timeout:             time.Duration(cfg.GRPCConnectionTimeoutSeconds) * time.Second,
+				PermitWithoutStream: true,
+			}))
+		}
+
+		return InvokeGRPC(
+			function,
+			runtimeSpec,
+			cfg,
+			opts...,
+		)
+	case "OpenWhisk":
+		return InvokeOpenWhisk(
+			function,
+			runtimeSpec,
+			announceDoneExe,
+			readOpenWhiskMetadata,
+		)
+	case "AWSLambda":
+		var opts []HTTPInvocationOption
+		if d.tlsPin != nil {
+			opts = append(opts, WithTLSPin(d.tlsPin))
+		}
+		if invocation != nil {
+			opts = append(opts, WithInvocationIndex(invocation.minute, invocation.index))
+
+			if cfg.IdempotencyTable != "" {
+				key := common.IdempotencyKey(d.status.experimentID, function.Name, invocation.minute, invocation.index, cfg.Seed)
+				opts = append(opts, WithIdempotencyKey(key))
+			}
+		}
+
+		return InvokeAWSLambda(
+			function,
+			runtimeSpec,
+			cfg,
+			announceDoneExe,
+			opts...,
+		)
+	case "CloudRun":
+		// The Cloud Run services take the requests of the AWS Lambda trace function over HTTP
+		var opts []HTTPInvocationOption
+		if d.tlsPin != nil {
+			opts = append(opts, WithTLSPin(d.tlsPin))
+		}
+
+		return InvokeAWSLambda(
+			function,
+			runtimeSpec,
+			cfg,
+			announceDoneExe,
+			opts...,
+		)
+	case "Dirigent":
+		return InvokeDirigent(
+			function,
+			runtimeSpec,
+			cfg,
+		)
+	default:
+		log.Fatal("Unsupported platform.")
+		return false, nil
+	}
+}
+
 func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 	defer metadata.AnnounceDoneWG.Done()
 
@@ -217,45 +427,47 @@ func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 	for node != nil {
 		function := node.Value.(*common.Function)
 		runtimeSpecifications = &function.Specification.RuntimeSpecification[metadata.MinuteIndex][metadata.InvocationIndex]
-		switch d.Configuration.LoaderConfiguration.Platform {
-		case "Knative":
-			success, record = InvokeGRPC(
-				function,
-				runtimeSpecifications,
-				d.Configuration.LoaderConfiguration,
-			)
-		case "OpenWhisk":
-			success, record = InvokeOpenWhisk(
-				function,
-				runtimeSpecifications,
-				metadata.AnnounceDoneExe,
-				metadata.ReadOpenWhiskMetadata,
-			)
-		case "AWSLambda":
-			success, record = InvokeAWSLambda(
-				function,
-				runtimeSpecifications,
-				metadata.AnnounceDoneExe,
-			)
-		case "Dirigent":
-			success, record = InvokeDirigent(
-				function,
-				runtimeSpecifications,
-				d.Configuration.LoaderConfiguration,
-			)
-		default:
-			log.Fatal("Unsupported platform.")
+		invocation := &traceInvocation{minute: metadata.MinuteIndex, index: metadata.InvocationIndex}
+		invoke := func() (bool, *mc.ExecutionRecord) {
+			return d.invoke(function, runtimeSpecifications, metadata.AnnounceDoneExe, metadata.ReadOpenWhiskMetadata, invocation)
+		}
+		if d.resultCache != nil {
+			success, record = d.invokeCached(function, runtimeSpecifications, metadata.AnnounceDoneExe, invoke)
+		} else {
+			success, record = invoke()
 		}
 		record.Phase = int(metadata.Phase)
 		record.InvocationID = composeInvocationID(d.Configuration.TraceGranularity, metadata.MinuteIndex, metadata.InvocationIndex)
 		metadata.RecordOutputChannel <- record
+		d.timeouts.Record(function.Name, metadata.MinuteIndex, isTimeout(record))
 
 		if !success {
 			log.Debugf("Invocation failed at minute: %d for %s", metadata.MinuteIndex, function.Name)
 			break
 		}
+		d.histogram.Add(record.ActualDuration)
+		if d.predictor != nil {
+			d.predictor.Observe(function.Name, *runtimeSpecifications, record)
+		}
+		if d.anomalies != nil {
+			d.anomalies.Record(function.Name, metadata.MinuteIndex, metadata.InvocationIndex, float64(record.ActualDuration))
+		}
+		if d.drift != nil {
+			d.drift.Record(function.Name, float64(record.ResponseTime))
+		}
+		if d.aggregation != nil {
+			d.aggregation.Submit(InvocationResult{Function: function.Name, Minute: metadata.MinuteIndex, Record: record})
+		}
+		if d.adaptiveTimeout != nil {
+			d.adaptiveTimeout.Record(record.ResponseTime)
+		}
 		node = node.Next()
 	}
+	if d.abortMonitor != nil {
+		d.abortMonitor.Record(success)
+	}
+	d.status.Record(success, record)
+
 	if success {
 		atomic.AddInt64(metadata.SuccessCount, 1)
 	} else {
@@ -264,6 +476,27 @@ func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 	}
 }
 
+// shedInvocation records the invocation as shed without issuing it. The record starts when the invocation was
+// scheduled, as a deferred invocation may be shed long after.
+func (d *Driver) shedInvocation(metadata *InvocationMetadata) {
+	defer metadata.AnnounceDoneWG.Done()
+
+	function := metadata.RootFunction.Front().Value.(*common.Function)
+	log.Debugf("Shedding invocation %d of function %s in minute %d.", metadata.InvocationIndex, function.Name, metadata.MinuteIndex)
+
+	record := &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{
+		Phase:        int(metadata.Phase),
+		Instance:     function.Name,
+		InvocationID: composeInvocationID(d.Configuration.TraceGranularity, metadata.MinuteIndex, metadata.InvocationIndex),
+		StartTime:    metadata.ScheduledTime.UnixMicro(),
+		Shed:         true,
+	}}
+	metadata.RecordOutputChannel <- record
+
+	// The invocation is neither issued nor expected to complete
+	metadata.AnnounceDoneExe.Done()
+}
+
 func (d *Driver) functionsDriver(list *list.List, announceFunctionDone *sync.WaitGroup,
 	addInvocationsToGroup *sync.WaitGroup, readOpenWhiskMetadata *sync.Mutex, totalSuccessful *int64,
 	totalFailed *int64, totalIssued *int64, recordOutputChannel chan interface{}) {
@@ -296,6 +529,30 @@ func (d *Driver) functionsDriver(list *list.List, announceFunctionDone *sync.Wai
 		log.Infof("Warmup phase has started.")
 	}
 
+	dispatchWindow := time.Minute
+	if d.Configuration.TraceGranularity == common.SecondGranularity {
+		dispatchWindow = time.Second
+	}
+	dispatcher := NewMinuteDispatcher(dispatchWindow, DroppingPolicy{
+		DropOldest: d.Configuration.LoaderConfiguration.DropOldestOnScheduleDrift,
+	})
+
+	var budget *functionBudgetTracker
+	if function.Budget != nil {
+		budget = newFunctionBudgetTracker(function.Budget)
+	}
+
+	var jitterRand *rand.Rand
+	if d.Configuration.ScheduleJitter != nil {
+		jitterRand = newJitterRand(d.Configuration.LoaderConfiguration.Seed, function.Name)
+	}
+
+	var scheduler *MinVarianceScheduler
+	if d.Configuration.MinVarianceScheduling {
+		scheduler = NewMinVarianceScheduler()
+		defer scheduler.Stop()
+	}
+
 	startOfMinute := time.Now()
 	var previousIATSum int64
 
@@ -303,6 +560,9 @@ func (d *Driver) functionsDriver(list *list.List, announceFunctionDone *sync.Wai
 		if minuteIndex >= totalTraceDuration {
 			// Check whether the end of trace has been reached
 			break
+		} else if d.shutdown.Load() {
+			log.Debugf("Stopping the driver of function %s due to shutdown.", function.Name)
+			break
 		} else if function.InvocationStats.Invocations[minuteIndex] == 0 {
 			// Sleep for a minute if there are no invocations
 			if d.proceedToNextMinute(function, &minuteIndex, &invocationIndex,
@@ -327,22 +587,64 @@ func (d *Driver) functionsDriver(list *list.List, announceFunctionDone *sync.Wai
 		currentTime := time.Now()
 		schedulingDelay := currentTime.Sub(startOfMinute).Microseconds() - previousIATSum
 		sleepFor := iat.Microseconds() - schedulingDelay
-		time.Sleep(time.Duration(sleepFor) * time.Microsecond)
+		if jitterRand != nil {
+			// Not accounted in previousIATSum, so the jitter does not accumulate over the minute
+			sleepFor += d.Configuration.ScheduleJitter.Offset(jitterRand).Microseconds()
+		}
+		if scheduler != nil {
+			scheduler.WaitUntil(currentTime.Add(time.Duration(sleepFor) * time.Microsecond))
+		} else {
+			time.Sleep(time.Duration(sleepFor) * time.Microsecond)
+		}
 
 		previousIATSum += iat.Microseconds()
 
 		if function.InvocationStats.Invocations[minuteIndex] == invocationIndex || hasMinuteExpired(startOfMinute) {
+			endOfWindow := startOfMinute.Add(dispatchWindow)
 			readyToBreak := d.proceedToNextMinute(function, &minuteIndex, &invocationIndex, &startOfMinute,
 				false, &currentPhase, failedInvocationByMinute, &previousIATSum)
 
 			if readyToBreak {
 				break
 			}
+
+			// The lag at the end of the window is taken off the next one, whose invocations are thus dispatched
+			// on their original schedule
+			allotted := dispatcher.EndWindow(endOfWindow)
+			startOfMinute = startOfMinute.Add(allotted - dispatchWindow)
+
+			if minuteIndex < totalTraceDuration && function.InvocationStats.Invocations[minuteIndex] > 0 {
+				if dropped := dispatcher.InvocationsToDrop(IAT[minuteIndex]); dropped > 0 {
+					log.Warnf("Dropping the %d oldest invocations of function %s in minute %d to catch up with the schedule.", dropped, function.Name, minuteIndex)
+
+					// The invocations are neither issued nor expected to complete
+					addInvocationsToGroup.Add(-dropped)
+					for _, droppedIAT := range IAT[minuteIndex][:dropped] {
+						previousIATSum += (time.Duration(droppedIAT) * time.Microsecond).Microseconds()
+					}
+					invocationIndex = dropped
+				}
+			}
+		} else if d.abortMonitor != nil && d.abortMonitor.Paused() {
+			// The invocation is neither issued nor expected to complete
+			addInvocationsToGroup.Done()
+			invocationIndex++
+		} else if budget != nil && !budget.admit(minuteIndex, &function.Specification.RuntimeSpecification[minuteIndex][invocationIndex]) {
+			dropped := function.InvocationStats.Invocations[minuteIndex] - invocationIndex
+			log.Warnf("FunctionBudgetExceeded: dropping the remaining %d invocations of function %s in minute %d.", dropped, function.Name, minuteIndex)
+
+			// The invocations are neither issued nor expected to complete
+			addInvocationsToGroup.Add(-dropped)
+			invocationIndex += dropped
 		} else {
-			if !d.Configuration.TestMode {
-				waitForInvocations.Add(1)
+			if d.limiter != nil {
+				if err := d.limiter.Acquire(1); err != nil {
+					log.Warnf("Failed to acquire a token for function %s: %s", function.Name, err)
+				}
+			}
 
-				go d.invokeFunction(&InvocationMetadata{
+			if !d.Configuration.TestMode {
+				metadata := &InvocationMetadata{
 					RootFunction:          list,
 					Phase:                 currentPhase,
 					MinuteIndex:           minuteIndex,
@@ -354,6 +656,21 @@ func (d *Driver) functionsDriver(list *list.List, announceFunctionDone *sync.Wai
 					AnnounceDoneWG:        &waitForInvocations,
 					AnnounceDoneExe:       addInvocationsToGroup,
 					ReadOpenWhiskMetadata: readOpenWhiskMetadata,
+					ScheduledTime:         startOfMinute.Add(time.Duration(previousIATSum) * time.Microsecond),
+				}
+
+				dispatcher.Dispatch(metadata.ScheduledTime, func() {
+					waitForInvocations.Add(1)
+					if d.loadShedder == nil {
+						go d.invokeFunction(metadata)
+						return
+					}
+
+					d.loadShedder.Submit(function.Name, func() {
+						d.invokeFunction(metadata)
+					}, func() {
+						d.shedInvocation(metadata)
+					})
 				})
 			} else {
 				// To be used from within the Golang testing framework
@@ -372,6 +689,9 @@ func (d *Driver) functionsDriver(list *list.List, announceFunctionDone *sync.Wai
 		}
 	}
 
+	if d.loadShedder != nil {
+		d.loadShedder.Flush(function.Name)
+	}
 	waitForInvocations.Wait()
 
 	log.Debugf("All the invocations for function %s have been completed.\n", function.Name)
@@ -480,6 +800,34 @@ func (d *Driver) globalTimekeeper(totalTraceDuration int, signalReady *sync.Wait
 		<-ticker.C
 
 		log.Debugf("End of minute %d\n", globalTimeCounter)
+		if d.Configuration.LiveHistogram {
+			PrintHistogram(os.Stdout, d.histogram)
+		}
+		if d.abortMonitor != nil && d.abortMonitor.EndMinute() == abortExperiment {
+			d.GracefulShutdown()
+		}
+		if d.adaptiveTimeout != nil {
+			log.Debugf("Function timeout for minute %d: %v\n", globalTimeCounter+1, d.adaptiveTimeout.EndMinute())
+		}
+		if d.predictor != nil {
+			log.Debugf("Latency prediction error in minute %d: %.0f us\n", globalTimeCounter, d.predictor.EndMinute())
+		}
+		if d.anomalies != nil {
+			for _, anomaly := range d.anomalies.EndMinute() {
+				log.Warnf("Anomalous execution time of %s in minute %d: %.0f us (z-score %.1f)",
+					anomaly.FunctionName, anomaly.Minute, anomaly.Duration, anomaly.ZScore)
+			}
+		}
+		if d.drift != nil {
+			for _, drift := range d.drift.EndMinute() {
+				log.Warnf("PerformanceDrift: latency of %s trends by %+.1f us/minute as of minute %d (p = %.4f)",
+					drift.FunctionName, drift.SlopeUsPerMinute, drift.Minute, drift.PValue)
+			}
+		}
+		if d.aggregation != nil {
+			d.aggregation.CloseMinute(globalTimeCounter)
+		}
+		d.status.EndMinute()
 		globalTimeCounter++
 		if globalTimeCounter >= totalTraceDuration {
 			break
@@ -556,7 +904,60 @@ func (d *Driver) startBackgroundProcesses(allRecordsWritten *sync.WaitGroup) (*s
 	return auxiliaryProcessBarrier, globalMetricsCollector, totalIssuedChannel, finishCh
 }
 
+// exportJaegerSpans sends the execution records of the experiment as spans to the Jaeger agent
+func (d *Driver) exportJaegerSpans() {
+	records, err := d.readExecutionRecords()
+	if err != nil {
+		log.Errorf("Failed to read the results for the Jaeger export: %s", err)
+		return
+	}
+
+	exporter, err := mc.NewJaegerExporter(d.Configuration.JaegerEndpoint)
+	if err != nil {
+		log.Errorf("Failed to connect to the Jaeger agent at %s: %s", d.Configuration.JaegerEndpoint, err)
+		return
+	}
+
+	exporter.Export(records)
+	if err = exporter.Close(); err != nil {
+		log.Errorf("Failed to flush the spans to the Jaeger agent: %s", err)
+	}
+}
+
+// GracefulShutdown stops issuing new invocations. The experiment ends once the issued invocations complete.
+func (d *Driver) GracefulShutdown() {
+	if d.shutdown.CompareAndSwap(false, true) {
+		log.Warn("Shutting down the experiment gracefully, waiting for the issued invocations to complete.")
+	}
+}
+
 func (d *Driver) internalRun(iatOnly bool, generated bool) {
+	if d.Configuration.AbortPolicy != nil {
+		d.abortMonitor = newAbortMonitor(*d.Configuration.AbortPolicy, d.Configuration.AutoResume, d.Configuration.Notifier)
+	}
+	if d.Configuration.MinAdaptiveTimeout != 0 {
+		defaultTimeout := time.Duration(d.Configuration.LoaderConfiguration.GRPCFunctionTimeoutSeconds) * time.Second
+		d.adaptiveTimeout = NewAdaptiveTimeout(defaultTimeout, d.Configuration.MinAdaptiveTimeout)
+	}
+	if d.Configuration.ShedPolicy != nil {
+		d.loadShedder = NewLoadShedder(*d.Configuration.ShedPolicy)
+	}
+	if d.Configuration.PredictLatency {
+		d.predictor = NewLatencyPredictor()
+	}
+	if d.Configuration.AnomalyThreshold != 0 {
+		d.anomalies = NewAnomalyDetector(d.Configuration.AnomalyThreshold)
+	}
+	if d.Configuration.DetectDrift {
+		d.drift = NewDriftDetector()
+	}
+	if d.Configuration.AggregateResults {
+		d.aggregation = NewAggregationPipeline()
+	}
+	if d.Configuration.RPSBudget != 0 {
+		d.limiter = NewTokenBucketLimiter(math.Max(1, d.Configuration.RPSBudget), d.Configuration.RPSBudget, 0)
+	}
+
 	var successfulInvocations int64
 	var failedInvocations int64
 	var invocationsIssued int64
@@ -576,12 +977,7 @@ func (d *Driver) internalRun(iatOnly bool, generated bool) {
 			if d.Configuration.LoaderConfiguration.DAGMode {
 				function.InvocationStats.Invocations = d.Configuration.Functions[0].InvocationStats.Invocations
 			}
-			spec := d.SpecificationGenerator.GenerateInvocationData(
-				function,
-				d.Configuration.IATDistribution,
-				d.Configuration.ShiftIAT,
-				d.Configuration.TraceGranularity,
-			)
+			spec := d.generateSpecification(function)
 
 			d.Configuration.Functions[i].Specification = spec
 		}
@@ -603,6 +999,12 @@ func (d *Driver) internalRun(iatOnly bool, generated bool) {
 		}
 	}
 
+	allKeepalivesCompleted := sync.WaitGroup{}
+	stopKeepalives := make(chan struct{})
+	if d.Configuration.WarmKeepalive != nil {
+		d.startKeepalives(&allKeepalivesCompleted, stopKeepalives)
+	}
+
 	if d.Configuration.LoaderConfiguration.DAGMode {
 		log.Infof("Starting DAG invocation driver\n")
 		functionLinkedList := DAGCreation(d.Configuration.Functions)
@@ -638,6 +1040,12 @@ func (d *Driver) internalRun(iatOnly bool, generated bool) {
 		}
 	}
 	allIndividualDriversCompleted.Wait()
+	if d.aggregation != nil {
+		aggregate := d.aggregation.Close()
+		d.experimentAggregate = &aggregate
+	}
+	close(stopKeepalives)
+	allKeepalivesCompleted.Wait()
 	if atomic.LoadInt64(&successfulInvocations)+atomic.LoadInt64(&failedInvocations) != 0 {
 		log.Debugf("Waiting for all the invocations record to be written.\n")
 
@@ -647,21 +1055,92 @@ func (d *Driver) internalRun(iatOnly bool, generated bool) {
 		allRecordsWritten.Wait()
 	}
 
+	d.invocationCounts = invocationCounts{
+		issued:     atomic.LoadInt64(&invocationsIssued) * functionsPerDAG,
+		successful: atomic.LoadInt64(&successfulInvocations),
+		failed:     atomic.LoadInt64(&failedInvocations),
+	}
+
+	d.status.Finish()
+
 	log.Infof("Trace has finished executing function invocation driver\n")
 	log.Infof("Number of successful invocations: \t%d\n", atomic.LoadInt64(&successfulInvocations))
 	log.Infof("Number of failed invocations: \t%d\n", atomic.LoadInt64(&failedInvocations))
+	if d.loadShedder != nil {
+		log.Infof("Number of shed invocations: \t%d\n", d.loadShedder.Shed())
+	}
+
+	if d.timeouts.TimedOut() > 0 {
+		PrintTimeoutTrend(os.Stdout, d.timeouts)
+	}
+}
+
+func (d *Driver) deployFunctions() {
+	switch d.Configuration.LoaderConfiguration.Platform {
+	case "Knative":
+		DeployFunctions(d.Configuration.Functions,
+			d.Configuration.YAMLPath,
+			d.Configuration.LoaderConfiguration.IsPartiallyPanic,
+			d.Configuration.LoaderConfiguration.EndpointPort,
+			d.Configuration.LoaderConfiguration.AutoscalingMetric)
+	case "OpenWhisk":
+		DeployFunctionsOpenWhisk(d.Configuration.Functions)
+	case "AWSLambda":
+		DeployFunctionsAWSLambda(d.Configuration.Functions, d.Configuration.LoaderConfiguration.AWSLambdaHandler,
+			d.Configuration.LoaderConfiguration.IdempotencyTable, vpcOfConfiguration(d.Configuration.LoaderConfiguration), d.Metadata)
+	case "CloudRun":
+		DeployFunctionsCloudRun(d.Configuration.Functions)
+	case "Dirigent":
+		DeployDirigent(d.Configuration.Functions)
+	default:
+		log.Fatal("Unsupported platform.")
+	}
+	d.lifecycle.Record(LifecycleDeployed, len(d.Configuration.Functions))
+}
+
+func (d *Driver) runPreflightCheck(generated bool) {
+	timeout := time.Duration(d.Configuration.LoaderConfiguration.GRPCConnectionTimeoutSeconds) * time.Second
+	report := PreflightCheck(d.Configuration.Functions, timeout)
+	if len(report.UnhealthyEndpoints) == 0 {
+		log.Infof("Preflight check passed for all %d endpoints.", len(report.HealthyEndpoints))
+		return
+	}
+
+	if generated || d.Configuration.LoaderConfiguration.DAGMode {
+		// Pre-generated specifications and DAGs are bound to the complete list of functions
+		log.Warnf("Preflight check failed for endpoints %v, proceeding with all the functions.", report.UnhealthyEndpoints)
+		return
+	}
+
+	d.Configuration.Functions = filterHealthyFunctions(d.Configuration.Functions, report)
+	if len(d.Configuration.Functions) == 0 {
+		log.Fatal("Preflight check failed for all the function endpoints.")
+	}
+}
+
+func (d *Driver) generateSpecification(function *common.Function) *common.FunctionSpecification {
+	spec, err := d.SpecificationGenerator.GenerateInvocationData(
+		function,
+		d.Configuration.IATDistribution,
+		d.Configuration.ShiftIAT,
+		d.Configuration.TraceGranularity,
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if d.Configuration.SpikeMode {
+		generator.ApplySpikeMode(spec, d.Configuration.TraceGranularity)
+	}
+
+	return spec
 }
 
 func (d *Driver) RunExperiment(iatOnly bool, generated bool) {
 	if iatOnly {
 		log.Info("Generating IAT and runtime specifications for all the functions")
 		for i, function := range d.Configuration.Functions {
-			spec := d.SpecificationGenerator.GenerateInvocationData(
-				function,
-				d.Configuration.IATDistribution,
-				d.Configuration.ShiftIAT,
-				d.Configuration.TraceGranularity,
-			)
+			spec := d.generateSpecification(function)
 			d.Configuration.Functions[i].Specification = spec
 
 			file, _ := json.MarshalIndent(spec, "", " ")
@@ -680,32 +1159,76 @@ func (d *Driver) RunExperiment(iatOnly bool, generated bool) {
 
 	trace.ApplyResourceLimits(d.Configuration.Functions, d.Configuration.LoaderConfiguration.CPULimit)
 
-	switch d.Configuration.LoaderConfiguration.Platform {
-	case "Knative":
-		DeployFunctions(d.Configuration.Functions,
-			d.Configuration.YAMLPath,
-			d.Configuration.LoaderConfiguration.IsPartiallyPanic,
-			d.Configuration.LoaderConfiguration.EndpointPort,
-			d.Configuration.LoaderConfiguration.AutoscalingMetric)
-	case "OpenWhisk":
-		DeployFunctionsOpenWhisk(d.Configuration.Functions)
-	case "AWSLambda":
-		DeployFunctionsAWSLambda(d.Configuration.Functions)
-	case "Dirigent":
-		DeployDirigent(d.Configuration.Functions)
-	default:
-		log.Fatal("Unsupported platform.")
+	// Functions invoked through a custom invoker are not deployed on the platform
+	if d.Invoker == nil {
+		d.deployFunctions()
+
+		if !d.Configuration.TestMode && !d.Configuration.SkipPreflight {
+			d.runPreflightCheck(generated)
+			d.lifecycle.Record(LifecyclePreflightPassed, len(d.Configuration.Functions))
+		}
+
+		if cfg := d.Configuration.LoaderConfiguration; cfg.Platform == "AWSLambda" && cfg.PreWarmDeployed > 0 {
+			d.preWarmDeployedFunctions(cfg.PreWarmDeployed)
+			d.lifecycle.Record(LifecycleWarmupComplete, len(d.Configuration.Functions))
+		}
+	}
+
+	if d.Configuration.StatusPort != 0 {
+		statusServer := NewStatusServer(d.Configuration.StatusPort, d.status.Status)
+		if err := statusServer.Start(); err != nil {
+			log.Errorf("Failed to start the experiment status server: %s", err)
+		} else {
+			log.Infof("Reporting the experiment status on http://%s/status", statusServer.Addr())
+			defer statusServer.Close()
+		}
+	}
+
+	if d.Configuration.UseResultCache {
+		d.loadResultCache()
+		defer d.saveResultCache()
 	}
 
 	// Generate load
+	d.lifecycle.Record(LifecycleExperimentStarted, len(d.Configuration.Functions))
 	d.internalRun(iatOnly, generated)
+	d.lifecycle.Record(LifecycleExperimentFinished, len(d.Configuration.Functions))
+	if !d.Configuration.TestMode {
+		d.writeExperimentMetadata()
+
+		if d.Configuration.LoaderConfiguration.Platform == "AWSLambda" {
+			d.writeCostAttributionReport()
+		}
+		if d.predictor != nil {
+			d.writeLatencyPrediction()
+		}
+		if d.aggregation != nil {
+			d.writeMinuteAggregates()
+			d.writeSummaryCSV()
+		}
+		if d.Configuration.JaegerEndpoint != "" {
+			d.exportJaegerSpans()
+		}
+		if d.Configuration.CollectServerLogs && d.Configuration.LoaderConfiguration.Platform == "Knative" {
+			d.collectServerLogs()
+		}
+	}
 
 	// Clean up
-	if d.Configuration.LoaderConfiguration.Platform == "Knative" {
-		CleanKnative()
-	} else if d.Configuration.LoaderConfiguration.Platform == "OpenWhisk" {
-		CleanOpenWhisk(d.Configuration.Functions)
-	} else if d.Configuration.LoaderConfiguration.Platform == "AWSLambda" {
-		CleanAWSLambda(d.Configuration.Functions)
+	if d.Invoker == nil {
+		if d.Configuration.LoaderConfiguration.Platform == "Knative" {
+			CleanKnative()
+		} else if d.Configuration.LoaderConfiguration.Platform == "OpenWhisk" {
+			CleanOpenWhisk(d.Configuration.Functions)
+		} else if d.Configuration.LoaderConfiguration.Platform == "AWSLambda" {
+			CleanAWSLambda(d.Configuration.Functions)
+		} else if d.Configuration.LoaderConfiguration.Platform == "CloudRun" {
+			CleanCloudRun()
+		}
+		d.lifecycle.Record(LifecycleCleaned, len(d.Configuration.Functions))
+	}
+
+	if !d.Configuration.TestMode {
+		d.writeLifecycle()
 	}
 }
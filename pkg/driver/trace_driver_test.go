@@ -128,7 +128,7 @@ func TestInvokeFunctionFromDriver(t *testing.T) {
 				address, port := "localhost", test.port
 				testDriver.Configuration.Functions[0].Endpoint = fmt.Sprintf("%s:%d", address, port)
 
-				go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+				go standard.StartGRPCServer(address, port, standard.TraceFunction, "", "")
 
 				// make sure that the gRPC server is running
 				time.Sleep(2 * time.Second)
@@ -195,7 +195,7 @@ func TestDAGInvocation(t *testing.T) {
 	function := testDriver.Configuration.Functions[0]
 	function.Endpoint = fmt.Sprintf("%s:%d", address, port)
 
-	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "", "")
 	for i := 0; i < len(function.Specification.RuntimeSpecification); i++ {
 		function.Specification.RuntimeSpecification[i] = make([]common.RuntimeSpecification, 3)
 	}
@@ -0,0 +1,161 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// WarmKeepaliveConfig configures the keepalive invocations of the WarmCacheScheduler. Not to be confused with the
+// KeepaliveConfig of the gRPC connections.
+type WarmKeepaliveConfig struct {
+	// MaxGapSeconds is the longest time a function may stay without invocations, which should be below the time after
+	// which the platform evicts idle containers (about 15 minutes on AWS Lambda)
+	MaxGapSeconds int
+	// KeepaliveRuntimeMs is the runtime requested by the keepalive invocations, common.MinExecTimeMilli if zero
+	KeepaliveRuntimeMs uint32
+}
+
+// WarmCacheScheduler keeps the containers of the functions warm by inserting keepalive invocations into the gaps of
+// the schedule longer than MaxGapSeconds. The keepalive invocations use the minimal resources and are not recorded
+// in the results of the experiment.
+type WarmCacheScheduler struct {
+	config WarmKeepaliveConfig
+}
+
+func NewWarmCacheScheduler(config WarmKeepaliveConfig) *WarmCacheScheduler {
+	return &WarmCacheScheduler{config: config}
+}
+
+// Keepalives returns the times of the keepalive invocations of the function, as offsets from the start of the first
+// dispatched minute of its IAT schedule. The window is the duration of a row of the IAT matrix, i.e., a minute or a
+// second depending on the trace granularity. No keepalives are scheduled before the first or after the last invocation.
+func (s *WarmCacheScheduler) Keepalives(function *common.Function, firstMinute int, window time.Duration) []time.Duration {
+	maxGap := time.Duration(s.config.MaxGapSeconds) * time.Second
+	if maxGap <= 0 || function.Specification == nil {
+		return nil
+	}
+
+	var keepalives []time.Duration
+	var previous *time.Duration
+
+	for minute := firstMinute; minute < len(function.Specification.IAT); minute++ {
+		iat := function.Specification.IAT[minute]
+
+		invocations := len(iat)
+		if function.InvocationStats != nil && minute < len(function.InvocationStats.Invocations) {
+			invocations = common.MinOf(invocations, function.InvocationStats.Invocations[minute])
+		}
+
+		startOfMinute := time.Duration(minute-firstMinute) * window
+		sinceStartOfMinute := 0.0
+		for i := 0; i < invocations; i++ {
+			sinceStartOfMinute += iat[i]
+			invocation := startOfMinute + time.Duration(sinceStartOfMinute)*time.Microsecond
+
+			if previous != nil {
+				for keepalive := *previous + maxGap; keepalive < invocation; keepalive += maxGap {
+					keepalives = append(keepalives, keepalive)
+				}
+			}
+			previous = &invocation
+		}
+	}
+
+	return keepalives
+}
+
+// RuntimeSpecification returns the specification of the keepalive invocations
+func (s *WarmCacheScheduler) RuntimeSpecification() *common.RuntimeSpecification {
+	runtime := common.MinExecTimeMilli
+	if s.config.KeepaliveRuntimeMs > 0 {
+		runtime = int(s.config.KeepaliveRuntimeMs)
+	}
+
+	return &common.RuntimeSpecification{Runtime: runtime, Memory: common.MinMemQuotaMib}
+}
+
+// startKeepalives keeps the containers of all the functions warm while the drivers of the functions dispatch the
+// invocations of the trace
+func (d *Driver) startKeepalives(allKeepalivesCompleted *sync.WaitGroup, done <-chan struct{}) {
+	scheduler := NewWarmCacheScheduler(*d.Configuration.WarmKeepalive)
+
+	firstMinute := 0
+	if d.Configuration.WithWarmup() {
+		firstMinute = 1 // the drivers skip the profiling minute
+	}
+	window := time.Minute
+	if d.Configuration.TraceGranularity == common.SecondGranularity {
+		window = time.Second
+	}
+
+	start := time.Now()
+	for _, function := range d.Configuration.Functions {
+		keepalives := scheduler.Keepalives(function, firstMinute, window)
+		if len(keepalives) == 0 {
+			continue
+		}
+		log.Debugf("Scheduling %d keepalive invocations of function %s.", len(keepalives), function.Name)
+
+		allKeepalivesCompleted.Add(1)
+		go func(function *common.Function) {
+			defer allKeepalivesCompleted.Done()
+			d.keepWarm(function, scheduler, keepalives, start, done)
+		}(function)
+	}
+}
+
+// keepWarm issues the keepalive invocations of the function at the given offsets from the start until done is
+// closed, and waits for all of them to complete
+func (d *Driver) keepWarm(function *common.Function, scheduler *WarmCacheScheduler, keepalives []time.Duration,
+	start time.Time, done <-chan struct{}) {
+
+	announceDoneExe := sync.WaitGroup{}
+	readOpenWhiskMetadata := sync.Mutex{}
+	inFlight := sync.WaitGroup{}
+	defer inFlight.Wait()
+
+	for _, keepalive := range keepalives {
+		select {
+		case <-done:
+			return
+		case <-time.After(time.Until(start.Add(keepalive))):
+		}
+
+		inFlight.Add(1)
+		announceDoneExe.Add(1)
+		go func() {
+			defer inFlight.Done()
+
+			if success, _ := d.invoke(function, scheduler.RuntimeSpecification(), &announceDoneExe, &readOpenWhiskMetadata, nil); !success {
+				log.Debugf("Keepalive invocation of function %s failed.", function.Name)
+			}
+		}()
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestWarmCacheSchedulerKeepalives(t *testing.T) {
+	second := 1e6 // microseconds
+
+	function := &common.Function{
+		Name:            "keepalive",
+		InvocationStats: &common.FunctionInvocationStats{Invocations: []int{1, 2, 0, 0, 1}},
+		Specification: &common.FunctionSpecification{
+			IAT: common.IATMatrix{
+				{10 * second, 50 * second},
+				{5 * second, 40 * second, 15 * second},
+				{60 * second},
+				{60 * second},
+				{20 * second, 40 * second},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		maxGapSeconds int
+		firstMinute   int
+		expected      []time.Duration
+	}{
+		{
+			// invocations at 10s, 65s, 105s and 260s
+			name:          "gaps_exceed_max_gap",
+			maxGapSeconds: 60,
+			expected:      []time.Duration{165 * time.Second, 225 * time.Second},
+		},
+		{
+			name:          "max_gap_longer_than_all_gaps",
+			maxGapSeconds: 155,
+		},
+		{
+			name:          "gap_equal_to_max_gap",
+			maxGapSeconds: 55,
+			expected:      []time.Duration{160 * time.Second, 215 * time.Second},
+		},
+		{
+			// invocations at 5s, 45s and 200s relative to the start of the second minute
+			name:          "skipping_profiling_minute",
+			maxGapSeconds: 60,
+			firstMinute:   1,
+			expected:      []time.Duration{105 * time.Second, 165 * time.Second},
+		},
+		{
+			name:          "disabled",
+			maxGapSeconds: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scheduler := NewWarmCacheScheduler(WarmKeepaliveConfig{MaxGapSeconds: test.maxGapSeconds})
+
+			keepalives := scheduler.Keepalives(function, test.firstMinute, time.Minute)
+			if !reflect.DeepEqual(keepalives, test.expected) {
+				t.Errorf("Wrong keepalives - got %v, expected %v", keepalives, test.expected)
+			}
+		})
+	}
+}
+
+func TestWarmCacheSchedulerRuntimeSpecification(t *testing.T) {
+	spec := NewWarmCacheScheduler(WarmKeepaliveConfig{MaxGapSeconds: 60}).RuntimeSpecification()
+	if spec.Runtime != common.MinExecTimeMilli || spec.Memory != common.MinMemQuotaMib {
+		t.Errorf("Unexpected default keepalive specification %+v", spec)
+	}
+
+	spec = NewWarmCacheScheduler(WarmKeepaliveConfig{MaxGapSeconds: 60, KeepaliveRuntimeMs: 5}).RuntimeSpecification()
+	if spec.Runtime != 5 || spec.Memory != common.MinMemQuotaMib {
+		t.Errorf("Unexpected keepalive specification %+v", spec)
+	}
+}
+
+func TestKeepWarm(t *testing.T) {
+	invoker := NewSimulatedInvoker(0)
+	d := NewDriver(&DriverConfiguration{
+		LoaderConfiguration: createFakeLoaderConfiguration(),
+		Functions:           []*common.Function{&testFunction},
+	})
+	d.Invoker = invoker
+
+	done := make(chan struct{})
+	start := time.Now()
+	scheduler := NewWarmCacheScheduler(WarmKeepaliveConfig{MaxGapSeconds: 1})
+
+	d.keepWarm(&testFunction, scheduler, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, start, done)
+	if invoker.Invocations() != 2 {
+		t.Errorf("Expected 2 keepalive invocations, got %d", invoker.Invocations())
+	}
+
+	close(done)
+	d.keepWarm(&testFunction, scheduler, []time.Duration{time.Hour}, start, done)
+	if invoker.Invocations() != 2 {
+		t.Errorf("Expected no keepalive invocations after stopping, got %d", invoker.Invocations()-2)
+	}
+}
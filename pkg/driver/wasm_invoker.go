@@ -0,0 +1,126 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// WasmExecuteFunction is the function the Wasm module exports to run an invocation. It takes the runtime in
+// milliseconds and the memory in MiB as 32-bit integers and returns the number of iterations it spun for.
+const WasmExecuteFunction = "execute"
+
+// WasmInvoker runs every invocation in the Wasm module at WasmPath, see InvokeWasm
+type WasmInvoker struct {
+	WasmPath string
+}
+
+func (w *WasmInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	return InvokeWasm(function, runtimeSpec, w.WasmPath)
+}
+
+// InvokeWasm runs an invocation with the given runtime specification in a Wasm module compiled as a WASI reactor,
+// e.g. with GOOS=wasip1 GOARCH=wasm and -buildmode=c-shared, so the load can be tested offline without a cloud
+// provider. The module is loaded for every invocation, which is part of the response time but not of the actual
+// duration, and sees the real clocks of the host.
+func InvokeWasm(function *common.Function, runtimeSpec *common.RuntimeSpecification, wasmPath string) (bool, *mc.ExecutionRecord) {
+	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
+
+	start := time.Now()
+	record := &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			Instance:          function.Name,
+			StartTime:         start.UnixMicro(),
+			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+		},
+	}
+
+	ctx := context.Background()
+	runtime := wazero.NewRuntime(ctx)
+	defer runtime.Close(ctx)
+
+	execute, err := instantiateWasm(ctx, runtime, wasmPath, runtimeSpec)
+	if err != nil {
+		log.Debugf("Failed to load the Wasm module of function %s - %v", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+
+		return false, record
+	}
+
+	executionStart := time.Now()
+	results, err := execute.Call(ctx, uint64(runtimeSpec.Runtime), uint64(runtimeSpec.Memory))
+	record.ActualDuration = uint32(time.Since(executionStart).Microseconds())
+	record.ResponseTime = time.Since(start).Microseconds()
+	if err != nil {
+		log.Debugf("Wasm execution failed for function %s - %v", function.Name, err)
+
+		record.FunctionTimeout = true
+
+		return false, record
+	}
+
+	log.Tracef("(Replied)\t %s: %d iterations, %.2f[ms]", function.Name, uint32(results[0]),
+		float64(record.ActualDuration)/1e3)
+
+	return true, record
+}
+
+func instantiateWasm(ctx context.Context, runtime wazero.Runtime, wasmPath string, runtimeSpec *common.RuntimeSpecification) (api.Function, error) {
+	if runtimeSpec.Runtime < 0 || runtimeSpec.Memory < 0 {
+		return nil, fmt.Errorf("invalid runtime specification %+v", *runtimeSpec)
+	}
+
+	binary, err := os.ReadFile(wasmPath)
+	if err != nil {
+		return nil, err
+	}
+
+	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+
+	module, err := runtime.InstantiateWithConfig(ctx, binary, wazero.NewModuleConfig().
+		WithStartFunctions("_initialize").WithSysWalltime().WithSysNanotime())
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate %s: %w", wasmPath, err)
+	}
+
+	execute := module.ExportedFunction(WasmExecuteFunction)
+	if execute == nil {
+		return nil, fmt.Errorf("%s does not export the function %s", wasmPath, WasmExecuteFunction)
+	}
+
+	return execute, nil
+}
@@ -0,0 +1,92 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func buildBusySpinModule(t *testing.T) string {
+	wasmPath := filepath.Join(t.TempDir(), "busyspin.wasm")
+
+	cmd := exec.Command("go", "build", "-buildmode=c-shared", "-o", wasmPath, ".")
+	cmd.Dir = filepath.Join("testdata", "busyspin")
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("Could not compile the Wasm module: %v\n%s", err, output)
+	}
+
+	return wasmPath
+}
+
+func TestWasmInvoker(t *testing.T) {
+	invoker := &WasmInvoker{WasmPath: buildBusySpinModule(t)}
+
+	success, record := invoker.Invoke(&testFunction, &common.RuntimeSpecification{Runtime: 100, Memory: 16})
+	if !success {
+		t.Fatalf("Expected the invocation to succeed, got %+v.", record)
+	}
+	if record.ActualDuration < 100e3 || record.ActualDuration > 1e6 {
+		t.Errorf("Expected the invocation to spin for about 100 ms, took %d us.", record.ActualDuration)
+	}
+	if record.RequestedDuration != 100e3 || record.ResponseTime < int64(record.ActualDuration) {
+		t.Errorf("Expected the requested duration and the response time to be recorded, got %+v.", record)
+	}
+
+	if success, record = invoker.Invoke(&testFunction, &common.RuntimeSpecification{}); !success {
+		t.Errorf("Expected an empty invocation to succeed, got %+v.", record)
+	}
+}
+
+func TestInvokeWasmErrors(t *testing.T) {
+	invalidPath := filepath.Join(t.TempDir(), "invalid.wasm")
+	if err := os.WriteFile(invalidPath, []byte("not wasm"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		testName    string
+		wasmPath    string
+		runtimeSpec common.RuntimeSpecification
+	}{
+		{testName: "missing_module", wasmPath: filepath.Join(t.TempDir(), "missing.wasm")},
+		{testName: "invalid_module", wasmPath: invalidPath},
+		{testName: "negative_runtime", wasmPath: invalidPath, runtimeSpec: common.RuntimeSpecification{Runtime: -1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			success, record := InvokeWasm(&testFunction, &test.runtimeSpec, test.wasmPath)
+			if success || !record.ConnectionTimeout {
+				t.Errorf("Expected the invocation to fail, got %+v.", record)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// Archetype is a class of real-world serverless workloads with characteristic invocation, runtime and memory patterns
+type Archetype int
+
+const (
+	WebAPI           Archetype = iota // many short, lightweight requests following a diurnal pattern
+	BatchProcessing                   // rare, periodic, long-running jobs
+	StreamProcessing                  // steady rate of very short invocations
+	MLInference                       // bursty requests with a high memory footprint and moderate runtime
+)
+
+// FunctionsPerArchetype is the number of synthetic functions generated for an archetype
+const FunctionsPerArchetype = 5
+
+func (a Archetype) String() string {
+	switch a {
+	case WebAPI:
+		return "WebAPI"
+	case BatchProcessing:
+		return "BatchProcessing"
+	case StreamProcessing:
+		return "StreamProcessing"
+	case MLInference:
+		return "MLInference"
+	default:
+		return fmt.Sprintf("Archetype(%d)", int(a))
+	}
+}
+
+// archetypeProfile describes the ranges from which the statistics of the functions of an archetype are drawn
+type archetypeProfile struct {
+	runtimeMedianMs [2]float64 // range of the median runtime
+	memoryMedianMib [2]float64 // range of the median memory
+
+	// invocations returns the number of invocations in the given minute for a function whose base rate was
+	// drawn from baseIPM
+	baseIPM     [2]float64
+	invocations func(minute int, baseRate float64, r *rand.Rand) int
+}
+
+// Multipliers of the median yielding the other percentiles of the runtime and memory distributions
+var (
+	runtimePercentileFactors = [7]float64{0.2, 0.3, 0.7, 1, 1.4, 3, 4}               // p0, p1, p25, p50, p75, p99, p100
+	memoryPercentileFactors  = [8]float64{0.8, 0.85, 0.95, 1, 1.05, 1.15, 1.2, 1.25} // p1, p5, p25, p50, p75, p95, p99, p100
+)
+
+var archetypeProfiles = map[Archetype]archetypeProfile{
+	WebAPI: {
+		runtimeMedianMs: [2]float64{20, 100},
+		memoryMedianMib: [2]float64{128, 256},
+		baseIPM:         [2]float64{100, 1000},
+		invocations: func(minute int, baseRate float64, r *rand.Rand) int {
+			// Daily cycle peaking at noon, never dropping below 20% of the base rate
+			diurnal := 0.6 - 0.4*math.Cos(2*math.Pi*float64(minute%1440)/1440)
+			return int(baseRate * diurnal * (0.9 + 0.2*r.Float64()))
+		},
+	},
+	BatchProcessing: {
+		runtimeMedianMs: [2]float64{5_000, 14_000},
+		memoryMedianMib: [2]float64{512, 1024},
+		baseIPM:         [2]float64{1, 3},
+		invocations: func(minute int, baseRate float64, r *rand.Rand) int {
+			// Jobs triggered every 15 minutes
+			if minute%15 != 0 {
+				return 0
+			}
+			return int(baseRate)
+		},
+	},
+	StreamProcessing: {
+		runtimeMedianMs: [2]float64{2, 20},
+		memoryMedianMib: [2]float64{128, 256},
+		baseIPM:         [2]float64{300, 600},
+		invocations: func(minute int, baseRate float64, r *rand.Rand) int {
+			return int(baseRate * (0.95 + 0.1*r.Float64()))
+		},
+	},
+	MLInference: {
+		runtimeMedianMs: [2]float64{200, 1000},
+		memoryMedianMib: [2]float64{2048, 4096},
+		baseIPM:         [2]float64{20, 100},
+		invocations: func(minute int, baseRate float64, r *rand.Rand) int {
+			// Occasional bursts of up to five times the base rate
+			if r.Float64() < 0.1 {
+				return int(baseRate * (2 + 3*r.Float64()))
+			}
+			return int(baseRate * (0.5 + r.Float64()))
+		},
+	},
+}
+
+func uniformIn(bounds [2]float64, r *rand.Rand) float64 {
+	return bounds[0] + r.Float64()*(bounds[1]-bounds[0])
+}
+
+// GenerateArchetypeTrace generates FunctionsPerArchetype synthetic functions with the invocation, runtime and memory
+// statistics characteristic of the archetype. The trace is deterministic for a given archetype and duration.
+func GenerateArchetypeTrace(archetype Archetype, durationMinutes int) []*common.Function {
+	profile, ok := archetypeProfiles[archetype]
+	if !ok {
+		return nil
+	}
+
+	r := rand.New(rand.NewSource(int64(archetype)))
+
+	var result []*common.Function
+	for i := 0; i < FunctionsPerArchetype; i++ {
+		hash := fmt.Sprintf("%s-%d", strings.ToLower(archetype.String()), i)
+
+		baseRate := uniformIn(profile.baseIPM, r)
+		invocations := make([]int, durationMinutes)
+		for minute := range invocations {
+			invocations[minute] = profile.invocations(minute, baseRate, r)
+		}
+
+		runtimeMedian := uniformIn(profile.runtimeMedianMs, r)
+		memoryMedian := uniformIn(profile.memoryMedianMib, r)
+
+		f := runtimePercentileFactors
+		m := memoryPercentileFactors
+
+		result = append(result, &common.Function{
+			Name: fmt.Sprintf("%s-%s", common.FunctionNamePrefix, hash),
+
+			HashOwner: archetype.String(),
+			HashApp:   archetype.String(),
+
+			InvocationStats: &common.FunctionInvocationStats{
+				HashOwner:    archetype.String(),
+				HashApp:      archetype.String(),
+				HashFunction: hash,
+				Trigger:      archetype.String(),
+				Invocations:  invocations,
+			},
+			RuntimeStats: &common.FunctionRuntimeStats{
+				HashOwner:     archetype.String(),
+				HashApp:       archetype.String(),
+				HashFunction:  hash,
+				Average:       runtimeMedian * 1.2, // right-skewed
+				Count:         1000,
+				Minimum:       runtimeMedian * f[0],
+				Maximum:       runtimeMedian * f[6],
+				Percentile0:   runtimeMedian * f[0],
+				Percentile1:   runtimeMedian * f[1],
+				Percentile25:  runtimeMedian * f[2],
+				Percentile50:  runtimeMedian * f[3],
+				Percentile75:  runtimeMedian * f[4],
+				Percentile99:  runtimeMedian * f[5],
+				Percentile100: runtimeMedian * f[6],
+			},
+			MemoryStats: &common.FunctionMemoryStats{
+				HashOwner:     archetype.String(),
+				HashApp:       archetype.String(),
+				HashFunction:  hash,
+				Count:         1000,
+				Average:       memoryMedian,
+				Percentile1:   memoryMedian * m[0],
+				Percentile5:   memoryMedian * m[1],
+				Percentile25:  memoryMedian * m[2],
+				Percentile50:  memoryMedian * m[3],
+				Percentile75:  memoryMedian * m[4],
+				Percentile95:  memoryMedian * m[5],
+				Percentile99:  memoryMedian * m[6],
+				Percentile100: memoryMedian * m[7],
+			},
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,113 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func meanInvocationsPerMinute(function *common.Function) float64 {
+	total := 0
+	for _, count := range function.InvocationStats.Invocations {
+		total += count
+	}
+
+	return float64(total) / float64(len(function.InvocationStats.Invocations))
+}
+
+func TestGenerateArchetypeTrace(t *testing.T) {
+	tests := []struct {
+		archetype          Archetype
+		minRuntimeMedianMs float64
+		maxRuntimeMedianMs float64
+		minMemoryMedianMib float64
+		maxMemoryMedianMib float64
+		minMeanIPM         float64
+		maxMeanIPM         float64
+	}{
+		{WebAPI, 20, 100, 128, 256, 10, 1000}, // the first hour is the trough of the daily cycle
+		{BatchProcessing, 5_000, 14_000, 512, 1024, 0, 1},
+		{StreamProcessing, 2, 20, 128, 256, 280, 630},
+		{MLInference, 200, 1000, 2048, 4096, 10, 200},
+	}
+
+	for _, test := range tests {
+		t.Run(test.archetype.String(), func(t *testing.T) {
+			functions := GenerateArchetypeTrace(test.archetype, 60)
+			if len(functions) != FunctionsPerArchetype {
+				t.Fatalf("Expected %d functions, got %d.", FunctionsPerArchetype, len(functions))
+			}
+
+			names := make(map[string]bool)
+			for _, function := range functions {
+				names[function.Name] = true
+
+				if len(function.InvocationStats.Invocations) != 60 {
+					t.Errorf("Function %s has %d minutes of invocations, expected 60.", function.Name, len(function.InvocationStats.Invocations))
+				}
+
+				runtimeMedian := function.RuntimeStats.Percentile50
+				if runtimeMedian < test.minRuntimeMedianMs || runtimeMedian > test.maxRuntimeMedianMs {
+					t.Errorf("Function %s has a median runtime of %.0fms.", function.Name, runtimeMedian)
+				}
+				if function.RuntimeStats.Percentile100 > common.MaxExecTimeMilli {
+					t.Errorf("Function %s exceeds the maximum runtime.", function.Name)
+				}
+
+				memoryMedian := function.MemoryStats.Percentile50
+				if memoryMedian < test.minMemoryMedianMib || memoryMedian > test.maxMemoryMedianMib {
+					t.Errorf("Function %s has a median memory of %.0fMiB.", function.Name, memoryMedian)
+				}
+
+				if ipm := meanInvocationsPerMinute(function); ipm < test.minMeanIPM || ipm > test.maxMeanIPM {
+					t.Errorf("Function %s has %.1f invocations per minute on average.", function.Name, ipm)
+				}
+
+				// The specification generator must accept the synthetic statistics
+				if _, err := NewSpecificationGenerator(42).GenerateInvocationData(function, common.Exponential, false, common.MinuteGranularity); err != nil {
+					t.Error(err)
+				}
+			}
+
+			if len(names) != FunctionsPerArchetype {
+				t.Error("Function names are not unique.")
+			}
+		})
+	}
+}
+
+func TestGenerateArchetypeTraceIsDeterministic(t *testing.T) {
+	first, second := GenerateArchetypeTrace(MLInference, 10), GenerateArchetypeTrace(MLInference, 10)
+
+	for i := range first {
+		for minute := range first[i].InvocationStats.Invocations {
+			if first[i].InvocationStats.Invocations[minute] != second[i].InvocationStats.Invocations[minute] {
+				t.Fatal("Archetype traces differ between two calls.")
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"math/rand"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// BurstSizeDistribution is the Geometric(Probability) distribution of the number of invocations issued together by
+// one event of a compound Poisson process
+type BurstSizeDistribution struct {
+	Probability float64 // of a burst ending after each invocation, in (0, 1]
+	// WithinBurstIAT separates the invocations of a burst, in microseconds
+	WithinBurstIAT float64
+}
+
+// DefaultBurstSizeDistribution yields bursts of 2 invocations on average, issued 1 μs apart
+var DefaultBurstSizeDistribution = BurstSizeDistribution{Probability: 0.5, WithinBurstIAT: 1}
+
+// Sample draws a burst size of at least one invocation
+func (d BurstSizeDistribution) Sample(rng *rand.Rand) int {
+	if d.Probability >= 1 {
+		return 1
+	}
+
+	// Inverse transform sampling of the number of Bernoulli trials until the first success
+	return 1 + int(math.Floor(math.Log(1-rng.Float64())/math.Log(1-d.Probability)))
+}
+
+func (d BurstSizeDistribution) validate() {
+	if d.Probability <= 0 || d.Probability > 1 {
+		log.Fatalf("Invalid burst size probability %f, must be in (0, 1].", d.Probability)
+	}
+	if d.WithinBurstIAT < 0 {
+		log.Fatal("The IAT within bursts must not be negative.")
+	}
+}
+
+// SetBurstSizeDistribution configures the bursts of the compound Poisson IAT distribution
+func (s *SpecificationGenerator) SetBurstSizeDistribution(distribution BurstSizeDistribution) {
+	distribution.validate()
+	s.burstSizes = distribution
+}
+
+// generateCompoundPoissonIAT splits the invocations into bursts of geometrically distributed sizes. Bursts are
+// separated by exponentially distributed IATs scaled to the rest of the minute, while the invocations of a burst are
+// WithinBurstIAT apart. Returns the IATs and their non-scaled duration.
+func (s *SpecificationGenerator) generateCompoundPoissonIAT(numberOfInvocations int, granularity common.TraceGranularity) ([]float64, float64) {
+	period := common.OneSecondInMicroseconds
+	if granularity == common.MinuteGranularity {
+		period *= 60.0
+	}
+
+	iatResult := make([]float64, 0, numberOfInvocations)
+	withinBurst := make([]bool, 0, numberOfInvocations)
+	totalDuration := 0.0 // total non-scaled duration between the bursts
+	withinBurstCount := 0
+
+	for len(iatResult) < numberOfInvocations {
+		burstSize := common.MinOf(s.burstSizes.Sample(s.iatRand), numberOfInvocations-len(iatResult))
+
+		// Each IAT precedes the next invocation, hence the IAT after the last invocation of a burst starts a new burst
+		for i := 0; i < burstSize-1; i++ {
+			iatResult = append(iatResult, s.burstSizes.WithinBurstIAT)
+			withinBurst = append(withinBurst, true)
+		}
+		withinBurstCount += burstSize - 1
+
+		iat := s.iatRand.ExpFloat64()
+		if iat == 0 {
+			log.Fatal("Generated IAT is equal to zero (unsupported). Consider increasing the clock precision.")
+		}
+
+		iatResult = append(iatResult, iat)
+		withinBurst = append(withinBurst, false)
+		totalDuration += iat
+	}
+
+	betweenBurstsDuration := period - float64(withinBurstCount)*s.burstSizes.WithinBurstIAT
+	if betweenBurstsDuration <= 0 {
+		log.Fatal("The bursts do not fit into the minute. Consider decreasing the IAT within bursts.")
+	}
+	for i := range iatResult {
+		if !withinBurst[i] {
+			iatResult[i] = iatResult[i] / totalDuration * betweenBurstsDuration
+		}
+	}
+
+	return iatResult, totalDuration
+}
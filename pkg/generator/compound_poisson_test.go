@@ -0,0 +1,105 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestBurstSizeDistributionSample(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+
+	for _, p := range []float64{0.1, 0.5, 1} {
+		distribution := BurstSizeDistribution{Probability: p}
+
+		sum := 0
+		samples := 100000
+		for i := 0; i < samples; i++ {
+			size := distribution.Sample(rng)
+			if size < 1 {
+				t.Fatalf("Burst size %d is smaller than one invocation.", size)
+			}
+			sum += size
+		}
+
+		if mean := float64(sum) / float64(samples); math.Abs(mean-1/p)/(1/p) > 0.02 {
+			t.Errorf("Expected a mean burst size of %.2f for p=%.1f, got %.2f.", 1/p, p, mean)
+		}
+	}
+}
+
+func TestGenerateCompoundPoissonIAT(t *testing.T) {
+	invocations := []int{1, 100, 0, 1000}
+
+	for _, withinBurstIAT := range []float64{1, 0, 250} {
+		for _, shiftIAT := range []bool{false, true} {
+			sg := NewSpecificationGenerator(123456789)
+			sg.SetBurstSizeDistribution(BurstSizeDistribution{Probability: 0.2, WithinBurstIAT: withinBurstIAT})
+
+			testFunction.InvocationStats = &common.FunctionInvocationStats{Invocations: invocations}
+			spec, err := sg.GenerateInvocationData(&testFunction, common.CompoundPoisson, shiftIAT, common.MinuteGranularity)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			total := 0
+			for minute, count := range invocations {
+				row := spec.IAT[minute]
+				if count == 0 {
+					if len(row) != 0 {
+						t.Errorf("Minute %d without invocations should have an empty IAT row.", minute)
+					}
+					continue
+				}
+				if len(row) != count+1 {
+					t.Fatalf("Minute %d has %d IATs, expected %d.", minute, len(row), count+1)
+				}
+				total += len(row) - 1
+
+				// With bursts of 5 invocations on average, about 4 out of 5 IATs separate invocations of a burst
+				withinBurst := 0
+				for _, iat := range row[1 : len(row)-1] {
+					if iat == withinBurstIAT {
+						withinBurst++
+					}
+				}
+				if count >= 100 && (withinBurst < count/2 || withinBurst >= count) {
+					t.Errorf("Expected most of the %d invocations of minute %d to be issued within bursts, got %d.", count, minute, withinBurst)
+				}
+			}
+
+			if total != 1101 {
+				t.Errorf("Expected 1101 invocations in total, got %d.", total)
+			}
+			if hasSpillover(spec.IAT, common.MinuteGranularity) {
+				t.Errorf("The IATs should sum up to a minute (within-burst IAT: %f, shift: %t).", withinBurstIAT, shiftIAT)
+			}
+		}
+	}
+}
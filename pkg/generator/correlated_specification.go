@@ -0,0 +1,106 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// CorrelatedSpecificationGenerator samples the runtime and the memory of an invocation from a bivariate normal
+// (Gaussian) copula, such that memory-intensive invocations tend to run longer for a positive correlation. The
+// marginal distributions of runtime and memory remain those given by the percentiles of the trace.
+type CorrelatedSpecificationGenerator struct {
+	*SpecificationGenerator
+
+	// Correlation is the Pearson correlation coefficient of the copula, in [-1, 1]
+	Correlation float64
+}
+
+func NewCorrelatedSpecificationGenerator(seed int64, correlation float64) *CorrelatedSpecificationGenerator {
+	if correlation < -1 || correlation > 1 || math.IsNaN(correlation) {
+		log.Fatalf("Invalid runtime-memory correlation %f, must be in [-1, 1].", correlation)
+	}
+
+	generator := &CorrelatedSpecificationGenerator{
+		SpecificationGenerator: NewSpecificationGenerator(seed),
+		Correlation:            correlation,
+	}
+	generator.specQuantiles = generator.correlatedQuantiles
+
+	return generator
+}
+
+// standardNormalCDF maps a standard normal variable to a quantile in [0, 1)
+func standardNormalCDF(z float64) float64 {
+	quantile := 0.5 * (1 + math.Erf(z/math.Sqrt2))
+
+	return math.Min(quantile, math.Nextafter(1, 0))
+}
+
+func (c *CorrelatedSpecificationGenerator) correlatedQuantiles() (float64, float64) {
+	runZ := c.specRand.NormFloat64()
+	memZ := c.Correlation*runZ + math.Sqrt(1-c.Correlation*c.Correlation)*c.specRand.NormFloat64()
+
+	return standardNormalCDF(runZ), standardNormalCDF(memZ)
+}
+
+// interpolateQuantile linearly interpolates the value at the quantile between the given percentiles, which is
+// equivalent to drawing uniformly between the two percentiles surrounding the quantile
+func interpolateQuantile(quantile float64, quantiles []float64, values []float64) int {
+	i := sort.SearchFloat64s(quantiles, quantile)
+	if i == 0 {
+		return int(values[0])
+	}
+	if i == len(quantiles) {
+		return int(values[len(values)-1])
+	}
+
+	fraction := (quantile - quantiles[i-1]) / (quantiles[i] - quantiles[i-1])
+
+	return int(values[i-1] + fraction*(values[i]-values[i-1]))
+}
+
+func runtimeAtQuantile(quantile float64, runStats *common.FunctionRuntimeStats) int {
+	return interpolateQuantile(
+		quantile,
+		[]float64{0, 0.01, 0.25, 0.50, 0.75, 0.99, 1},
+		[]float64{runStats.Percentile0, runStats.Percentile1, runStats.Percentile25, runStats.Percentile50,
+			runStats.Percentile75, runStats.Percentile99, runStats.Percentile100},
+	)
+}
+
+func memoryAtQuantile(quantile float64, memStats *common.FunctionMemoryStats) int {
+	// The trace has no 0th memory percentile, quantiles up to 0.01 map to the 1st percentile
+	return interpolateQuantile(
+		quantile,
+		[]float64{0.01, 0.05, 0.25, 0.50, 0.75, 0.95, 0.99, 1},
+		[]float64{memStats.Percentile1, memStats.Percentile5, memStats.Percentile25, memStats.Percentile50,
+			memStats.Percentile75, memStats.Percentile95, memStats.Percentile99, memStats.Percentile100},
+	)
+}
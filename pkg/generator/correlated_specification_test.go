@@ -0,0 +1,80 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestCorrelatedSpecificationGenerator(t *testing.T) {
+	// Percentiles growing linearly, such that the Pearson correlation of the samples follows the one of the copula
+	function := &common.Function{
+		RuntimeStats: &common.FunctionRuntimeStats{
+			Count: 1, Percentile0: 100, Percentile1: 110, Percentile25: 350, Percentile50: 600,
+			Percentile75: 850, Percentile99: 1090, Percentile100: 1100,
+		},
+		MemoryStats: &common.FunctionMemoryStats{
+			Count: 1, Percentile1: 110, Percentile5: 150, Percentile25: 350, Percentile50: 600,
+			Percentile75: 850, Percentile95: 1050, Percentile99: 1090, Percentile100: 1100,
+		},
+	}
+
+	for _, correlation := range []float64{-1, -0.6, 0, 0.3, 0.8, 1} {
+		t.Run(fmt.Sprintf("rho_%.1f", correlation), func(t *testing.T) {
+			generator := NewCorrelatedSpecificationGenerator(42, correlation)
+
+			samples := 10000
+			runtimes, memories := make([]float64, samples), make([]float64, samples)
+			for i := 0; i < samples; i++ {
+				spec := generator.generateExecutionSpecs(function)
+				runtimes[i], memories[i] = float64(spec.Runtime), float64(spec.Memory)
+			}
+
+			if empirical := stat.Correlation(runtimes, memories, nil); math.Abs(empirical-correlation) > 0.05 {
+				t.Errorf("Expected a correlation of %.2f, got %.3f.", correlation, empirical)
+			}
+		})
+	}
+}
+
+func TestCorrelatedSpecificationGeneratorKeepsMarginals(t *testing.T) {
+	generator := NewCorrelatedSpecificationGenerator(42, 0.8)
+
+	for i := 0; i < 1000; i++ {
+		spec := generator.generateExecutionSpecs(&testFunction)
+
+		if spec.Runtime < int(testFunction.RuntimeStats.Percentile0) || spec.Runtime > int(testFunction.RuntimeStats.Percentile100) {
+			t.Fatalf("Runtime %d outside of the trace percentiles.", spec.Runtime)
+		}
+		if spec.Memory < common.MinMemQuotaMib || spec.Memory > int(testFunction.MemoryStats.Percentile100) {
+			t.Fatalf("Memory %d outside of the trace percentiles.", spec.Memory)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"gonum.org/v1/gonum/stat"
+)
+
+const (
+	// spilloverEpsilon is the tolerance in microseconds when checking whether the IATs of a minute add up to the full minute
+	spilloverEpsilon = 1e-3
+	// klDivergenceBins is the number of histogram bins used to estimate the KL divergence
+	klDivergenceBins = 20
+	// klDivergenceSmoothing prevents log(0) for histogram bins to which the theoretical distribution assigns no mass
+	klDivergenceSmoothing = 1e-10
+)
+
+// DistributionStatistics summarizes the IATs generated for a function with a single IAT distribution
+type DistributionStatistics struct {
+	Distribution common.IatDistribution
+
+	SpilloverRate          float64 // fraction of minutes whose IATs do not add up to a minute
+	MeanIAT                float64 // μs
+	StdDevIAT              float64 // μs
+	CoefficientOfVariation float64
+	KLDivergence           float64 // from the theoretical distribution of the IAT
+}
+
+// ComparisonReport contains one entry per compared IAT distribution, in the order in which they were requested
+type ComparisonReport struct {
+	Entries []DistributionStatistics
+}
+
+// RunDistributionComparison generates the IATs of the given function with each of the given distributions
+// and reports how the resulting IATs compare. Each distribution is generated from the same seed.
+func RunDistributionComparison(fn common.Function, distributions []common.IatDistribution, seed int64) ComparisonReport {
+	report := ComparisonReport{}
+
+	for _, distribution := range distributions {
+		sg := NewSpecificationGenerator(seed)
+		iat, _, _ := sg.generateIAT(fn.InvocationStats.Invocations, distribution, false, common.MinuteGranularity, nil)
+
+		report.Entries = append(report.Entries, computeDistributionStatistics(iat, distribution))
+	}
+
+	return report
+}
+
+func computeDistributionStatistics(iat common.IATMatrix, distribution common.IatDistribution) DistributionStatistics {
+	result := DistributionStatistics{Distribution: distribution}
+
+	var allIAT []float64
+	// IATs divided by the expected IAT of their minute, so that minutes with different invocation counts can be pooled
+	var normalizedIAT []float64
+	spilloverMinutes := 0
+
+	for minute := 0; minute < len(iat); minute++ {
+		if minuteHasSpillover(iat[minute], common.MinuteGranularity) {
+			spilloverMinutes++
+		}
+
+		// The first element is the offset from the beginning of the minute
+		if len(iat[minute]) < 2 {
+			continue
+		}
+		gaps := iat[minute][1:]
+		expectedIAT := 60 * common.OneSecondInMicroseconds / float64(len(gaps))
+
+		for _, gap := range gaps {
+			allIAT = append(allIAT, gap)
+			normalizedIAT = append(normalizedIAT, gap/expectedIAT)
+		}
+	}
+
+	if len(iat) > 0 {
+		result.SpilloverRate = float64(spilloverMinutes) / float64(len(iat))
+	}
+
+	if len(allIAT) == 0 {
+		return result
+	}
+
+	result.MeanIAT, result.StdDevIAT = stat.PopMeanStdDev(allIAT, nil)
+	if result.MeanIAT != 0 {
+		result.CoefficientOfVariation = result.StdDevIAT / result.MeanIAT
+	}
+	result.KLDivergence = klDivergence(normalizedIAT, theoreticalCDF(distribution))
+
+	return result
+}
+
+// minuteHasSpillover checks whether the IATs of a single minute (or second) do not fit exactly in the time window.
+// Minutes without invocations cannot spill over.
+func minuteHasSpillover(iat []float64, granularity common.TraceGranularity) bool {
+	if len(iat) == 0 {
+		return false
+	}
+
+	sum := 0.0
+	for i := 0; i < len(iat); i++ {
+		sum += iat[i]
+	}
+
+	threshold := common.OneSecondInMicroseconds
+	if granularity == common.MinuteGranularity {
+		threshold *= 60
+	}
+
+	return math.Abs(sum-threshold) > spilloverEpsilon
+}
+
+// theoreticalCDF returns the CDF of the IAT distribution normalized to a mean of 1
+func theoreticalCDF(distribution common.IatDistribution) func(float64) float64 {
+	switch distribution {
+	case common.Exponential:
+		return func(x float64) float64 {
+			return 1 - math.Exp(-x)
+		}
+	case common.Uniform:
+		// IATs are drawn from [0, 1) and scaled so that their mean is 1, i.e., they become uniform on [0, 2)
+		return func(x float64) float64 {
+			return math.Min(math.Max(x/2, 0), 1)
+		}
+	default:
+		// Equidistant - all the probability mass is on the mean
+		return func(x float64) float64 {
+			if x < 1-spilloverEpsilon {
+				return 0
+			}
+			return 1
+		}
+	}
+}
+
+// klDivergence estimates KL(P || Q) where P is the empirical distribution of the sample and Q is given by its CDF
+func klDivergence(sample []float64, cdf func(float64) float64) float64 {
+	max := 0.0
+	for _, x := range sample {
+		max = math.Max(max, x)
+	}
+	if max == 0 {
+		return 0
+	}
+
+	binWidth := max / klDivergenceBins
+	histogram := make([]float64, klDivergenceBins)
+	for _, x := range sample {
+		bin := int(x / binWidth)
+		if bin >= klDivergenceBins {
+			bin = klDivergenceBins - 1
+		}
+		histogram[bin]++
+	}
+
+	divergence := 0.0
+	for i := 0; i < klDivergenceBins; i++ {
+		p := histogram[i] / float64(len(sample))
+		if p == 0 {
+			continue
+		}
+
+		lower, upper := float64(i)*binWidth, float64(i+1)*binWidth
+		if i == klDivergenceBins-1 {
+			upper = math.Inf(1)
+		}
+		q := math.Max(cdf(upper)-cdf(lower), klDivergenceSmoothing)
+
+		divergence += p * math.Log(p/q)
+	}
+
+	return divergence
+}
@@ -0,0 +1,91 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestRunDistributionComparison(t *testing.T) {
+	function := common.Function{
+		InvocationStats: &common.FunctionInvocationStats{
+			Invocations: []int{100, 250, 0, 1000, 50},
+		},
+	}
+	distributions := []common.IatDistribution{common.Equidistant, common.Uniform, common.Exponential}
+
+	report := RunDistributionComparison(function, distributions, 123456789)
+
+	if len(report.Entries) != len(distributions) {
+		t.Fatalf("Expected %d entries in the report, got %d.", len(distributions), len(report.Entries))
+	}
+
+	for i, entry := range report.Entries {
+		if entry.Distribution != distributions[i] {
+			t.Errorf("Entry %d reports distribution %d, expected %d.", i, entry.Distribution, distributions[i])
+		}
+		if entry.SpilloverRate != 0 {
+			t.Errorf("Distribution %d has a non-zero spillover rate %f.", entry.Distribution, entry.SpilloverRate)
+		}
+		if entry.MeanIAT <= 0 {
+			t.Errorf("Distribution %d has an invalid mean IAT %f.", entry.Distribution, entry.MeanIAT)
+		}
+	}
+
+	equidistant := report.Entries[0]
+	if equidistant.KLDivergence > 1e-6 {
+		t.Errorf("Equidistant distribution should not diverge from its theoretical distribution, got %f.", equidistant.KLDivergence)
+	}
+
+	uniform, exponential := report.Entries[1], report.Entries[2]
+	if uniform.CoefficientOfVariation >= exponential.CoefficientOfVariation {
+		t.Errorf("Uniform IATs should vary less than exponential IATs (CV %f vs %f).",
+			uniform.CoefficientOfVariation, exponential.CoefficientOfVariation)
+	}
+	if uniform.KLDivergence > 0.1 || exponential.KLDivergence > 0.1 {
+		t.Errorf("Generated IATs diverge too much from their theoretical distributions (uniform: %f, exponential: %f).",
+			uniform.KLDivergence, exponential.KLDivergence)
+	}
+}
+
+func TestRunDistributionComparisonEquidistantStdDev(t *testing.T) {
+	function := common.Function{
+		InvocationStats: &common.FunctionInvocationStats{
+			Invocations: []int{5, 5, 5},
+		},
+	}
+
+	report := RunDistributionComparison(function, []common.IatDistribution{common.Equidistant}, 42)
+
+	entry := report.Entries[0]
+	if entry.StdDevIAT > 1e-6 || entry.CoefficientOfVariation > 1e-6 {
+		t.Errorf("Equidistant IATs should have zero standard deviation, got %f.", entry.StdDevIAT)
+	}
+	if entry.MeanIAT != 12_000_000 {
+		t.Errorf("Unexpected mean IAT %f.", entry.MeanIAT)
+	}
+}
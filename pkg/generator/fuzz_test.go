@@ -0,0 +1,99 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+const (
+	fuzzMaxMinutes              = 10
+	fuzzMaxInvocationsPerMinute = 10000
+)
+
+// encodeInvocations is the inverse of decodeInvocations, used to seed the corpus
+func encodeInvocations(invocations []int) []byte {
+	data := make([]byte, 2*len(invocations))
+	for i, count := range invocations {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(count))
+	}
+
+	return data
+}
+
+// decodeInvocations reads the number of invocations per minute as little-endian uint16 values, bounding the
+// trace length and the invocation rate so that a single fuzzing iteration stays fast
+func decodeInvocations(data []byte) []int {
+	var invocations []int
+	for i := 0; i+1 < len(data) && len(invocations) < fuzzMaxMinutes; i += 2 {
+		invocations = append(invocations, int(binary.LittleEndian.Uint16(data[i:]))%(fuzzMaxInvocationsPerMinute+1))
+	}
+
+	return invocations
+}
+
+func FuzzGenerateIAT(f *testing.F) {
+	for _, invocations := range [][]int{{5}, {1}, {25}, {5, 4, 2}, {5, 5, 5, 5, 5}, {0, 5, 0, 25, 0}, {fuzzMaxInvocationsPerMinute}} {
+		for _, distribution := range []common.IatDistribution{common.Equidistant, common.Uniform, common.Exponential, common.CompoundPoisson} {
+			f.Add(encodeInvocations(invocations), int64(123456789), uint8(distribution), false)
+			f.Add(encodeInvocations(invocations), int64(123456789), uint8(distribution), true)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, seed int64, distribution uint8, shiftIAT bool) {
+		invocations := decodeInvocations(data)
+		iatDistribution := common.IatDistribution(distribution % 4)
+
+		function := testFunction
+		function.InvocationStats = &common.FunctionInvocationStats{Invocations: invocations}
+
+		spec, err := NewSpecificationGenerator(seed).GenerateInvocationData(&function, iatDistribution, shiftIAT, common.MinuteGranularity)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(spec.IAT) != len(invocations) {
+			t.Fatalf("Expected %d IAT rows, got %d.", len(invocations), len(spec.IAT))
+		}
+
+		for minute, row := range spec.IAT {
+			sum := 0.0
+			for _, iat := range row {
+				if iat < 0 {
+					t.Fatalf("Negative IAT %f in minute %d.", iat, minute)
+				}
+				sum += iat
+			}
+
+			// Allow for the rounding errors of summing up to fuzzMaxInvocationsPerMinute values
+			if limit := 60 * common.OneSecondInMicroseconds; sum > limit*(1+1e-9) {
+				t.Fatalf("IATs of minute %d spill over the minute: %f μs.", minute, sum)
+			}
+		}
+	})
+}
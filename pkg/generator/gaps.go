@@ -0,0 +1,68 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+// GapReport is an invocation preceded by an unusually long idle period, during which the platform may evict the warm
+// instances of the function
+type GapReport struct {
+	Minute   int
+	Position int     // index of the invocation in the minute
+	GapUs    float64 // IAT preceding the invocation
+}
+
+// FindInvocationGaps reports the invocations that fall behind the expected schedule of their minute, where the n
+// invocations of a minute are expected to be spread evenly over thresholdUs, e.g. the length of the minute. The
+// invocation at position i is behind if the time from the start of the minute to it exceeds
+// thresholdUs * (i + 1) / n. Only the invocation starting each run of invocations behind the schedule is reported, as
+// it follows the gap that delayed the rest of the run.
+//
+// The last IAT of each minute leads to the end of the minute rather than to an invocation, as generated by the
+// SpecificationGenerator.
+func FindInvocationGaps(iats [][]float64, thresholdUs float64) []GapReport {
+	var gaps []GapReport
+	for minute, row := range iats {
+		n := len(row) - 1
+		if n <= 0 {
+			continue
+		}
+
+		cumulative := 0.0
+		behind := false
+		for i := 0; i < n; i++ {
+			cumulative += row[i]
+
+			if cumulative <= thresholdUs*float64(i+1)/float64(n) {
+				behind = false
+				continue
+			}
+			if !behind {
+				gaps = append(gaps, GapReport{Minute: minute, Position: i, GapUs: row[i]})
+			}
+			behind = true
+		}
+	}
+
+	return gaps
+}
@@ -0,0 +1,84 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestFindInvocationGaps(t *testing.T) {
+	const invocations = 100
+	const minuteUs = 60 * common.OneSecondInMicroseconds
+
+	sg := NewSpecificationGenerator(42)
+	iats, _, err := sg.generateIAT([]int{invocations, invocations, 0}, common.Uniform, false, common.MinuteGranularity, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Delay the invocations from position 40 of the second minute by 20 s, shrinking the subsequent IATs to stay
+	// within the minute
+	const position, delayUs = 40, 20 * common.OneSecondInMicroseconds
+	row := iats[1]
+	remaining := 0.0
+	for _, iat := range row[position+1:] {
+		remaining += iat
+	}
+	for i := position + 1; i < len(row); i++ {
+		row[i] *= (remaining - delayUs) / remaining
+	}
+	row[position] += delayUs
+
+	gaps := FindInvocationGaps(iats, minuteUs)
+
+	var injected *GapReport
+	for i, gap := range gaps {
+		if gap.Minute == 1 && gap.Position == position {
+			injected = &gaps[i]
+		}
+	}
+	if injected == nil {
+		t.Fatalf("Expected the injected gap to be detected, got %v", gaps)
+	}
+	if injected.GapUs != row[position] {
+		t.Errorf("Expected a gap of %f μs, got %f μs", row[position], injected.GapUs)
+	}
+	for _, gap := range gaps {
+		if gap.GapUs > injected.GapUs || gap.Minute == 2 {
+			t.Errorf("Unexpected gap %+v", gap)
+		}
+	}
+}
+
+func TestFindInvocationGapsEquidistant(t *testing.T) {
+	iats := [][]float64{{0, 10, 10, 10, 10}, {0, 10, 25, 5, 0}, {}}
+
+	gaps := FindInvocationGaps(iats, 40)
+	if len(gaps) != 1 || gaps[0] != (GapReport{Minute: 1, Position: 2, GapUs: 25}) {
+		t.Errorf("Expected a single gap before the third invocation of the second minute, got %v", gaps)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// SpecificationProfile describes where the time and the memory went while generating the specification of a function
+type SpecificationProfile struct {
+	Function    string
+	Invocations int
+
+	IATGeneration     time.Duration
+	RuntimeSpecSample time.Duration
+
+	AllocatedBytes uint64 // cumulative bytes allocated on the heap, as per runtime.MemStats.TotalAlloc
+	Allocations    uint64 // number of heap objects allocated, as per runtime.MemStats.Mallocs
+}
+
+// Profiler receives the profile of each generated function specification
+type Profiler interface {
+	Record(profile SpecificationProfile)
+}
+
+// ProfiledSpecificationGenerator measures the hot path of GenerateInvocationData. Profiling is disabled if
+// Profiler is nil, in which case it behaves exactly like the wrapped SpecificationGenerator.
+type ProfiledSpecificationGenerator struct {
+	*SpecificationGenerator
+
+	Profiler Profiler
+}
+
+func NewProfiledSpecificationGenerator(seed int64, profiler Profiler) *ProfiledSpecificationGenerator {
+	return &ProfiledSpecificationGenerator{
+		SpecificationGenerator: NewSpecificationGenerator(seed),
+		Profiler:               profiler,
+	}
+}
+
+func (p *ProfiledSpecificationGenerator) GenerateInvocationData(function *common.Function, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) (*common.FunctionSpecification, error) {
+	if p.Profiler == nil {
+		return p.SpecificationGenerator.GenerateInvocationData(function, iatDistribution, shiftIAT, granularity)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	iatStart := time.Now()
+	detector := NewSpilloverDetector(granularity, len(function.InvocationStats.Invocations))
+	iat, rawDuration, err := p.generateIAT(function.InvocationStats.Invocations, iatDistribution, shiftIAT, granularity, detector)
+	if closeErr := detector.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid IATs generated for function %s: %w", function.Name, err)
+	}
+	iatElapsed := time.Since(iatStart)
+
+	specStart := time.Now()
+	runtimeMatrix := p.generateRuntimeSpecification(function)
+	specElapsed := time.Since(specStart)
+
+	runtime.ReadMemStats(&after)
+
+	invocations := 0
+	for _, count := range function.InvocationStats.Invocations {
+		invocations += count
+	}
+
+	p.Profiler.Record(SpecificationProfile{
+		Function:          function.Name,
+		Invocations:       invocations,
+		IATGeneration:     iatElapsed,
+		RuntimeSpecSample: specElapsed,
+		AllocatedBytes:    after.TotalAlloc - before.TotalAlloc,
+		Allocations:       after.Mallocs - before.Mallocs,
+	})
+
+	return &common.FunctionSpecification{
+		IAT:                  iat,
+		RawDuration:          rawDuration,
+		RuntimeSpecification: runtimeMatrix,
+	}, nil
+}
+
+// TextProfiler writes a one-line summary of each recorded profile
+type TextProfiler struct {
+	Output io.Writer
+}
+
+func (t *TextProfiler) Record(profile SpecificationProfile) {
+	_, _ = fmt.Fprintf(t.Output, "%s: %d invocations, IAT generation %v, runtime specification %v, %.2f MiB allocated in %d objects\n",
+		profile.Function, profile.Invocations, profile.IATGeneration, profile.RuntimeSpecSample,
+		float64(profile.AllocatedBytes)/(1024*1024), profile.Allocations)
+}
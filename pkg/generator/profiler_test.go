@@ -0,0 +1,113 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+type mockProfiler struct {
+	profiles []SpecificationProfile
+}
+
+func (m *mockProfiler) Record(profile SpecificationProfile) {
+	m.profiles = append(m.profiles, profile)
+}
+
+func TestProfiledSpecificationGenerator(t *testing.T) {
+	profiler := &mockProfiler{}
+	sg := NewProfiledSpecificationGenerator(42, profiler)
+
+	function := testFunction
+	function.InvocationStats = &common.FunctionInvocationStats{Invocations: []int{5000, 5000}}
+	spec, err := sg.GenerateInvocationData(&function, common.Exponential, false, common.MinuteGranularity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each minute of the IAT matrix starts with the time before its first invocation
+	if len(spec.IAT) != 2 || len(spec.IAT[0])+len(spec.IAT[1]) != 10002 {
+		t.Error("Unexpected IAT matrix.")
+	}
+	if len(spec.RuntimeSpecification) != 2 || len(spec.RuntimeSpecification[0])+len(spec.RuntimeSpecification[1]) != 10000 {
+		t.Error("Unexpected runtime specification matrix.")
+	}
+
+	if len(profiler.profiles) != 1 {
+		t.Fatalf("Expected one profile, got %d.", len(profiler.profiles))
+	}
+	profile := profiler.profiles[0]
+	if profile.Invocations != 10000 {
+		t.Errorf("Expected 10000 profiled invocations, got %d.", profile.Invocations)
+	}
+	if profile.IATGeneration <= 0 || profile.RuntimeSpecSample <= 0 {
+		t.Errorf("Expected non-zero timings, got %+v.", profile)
+	}
+	if profile.AllocatedBytes == 0 || profile.Allocations == 0 {
+		t.Errorf("Expected non-zero allocations, got %+v.", profile)
+	}
+}
+
+func TestProfiledSpecificationGeneratorMatchesUnprofiled(t *testing.T) {
+	function := testFunction
+	function.InvocationStats = &common.FunctionInvocationStats{Invocations: []int{100, 0, 50}}
+
+	expected, err := NewSpecificationGenerator(42).GenerateInvocationData(&function, common.Uniform, false, common.MinuteGranularity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiled, err := NewProfiledSpecificationGenerator(42, &mockProfiler{}).GenerateInvocationData(&function, common.Uniform, false, common.MinuteGranularity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for minute := range expected.IAT {
+		for i := range expected.IAT[minute] {
+			if expected.IAT[minute][i] != profiled.IAT[minute][i] {
+				t.Fatalf("IAT at minute %d, invocation %d differs from the unprofiled generator.", minute, i)
+			}
+		}
+		for i := range expected.RuntimeSpecification[minute] {
+			if expected.RuntimeSpecification[minute][i] != profiled.RuntimeSpecification[minute][i] {
+				t.Fatalf("Runtime specification at minute %d, invocation %d differs from the unprofiled generator.", minute, i)
+			}
+		}
+	}
+}
+
+func TestTextProfiler(t *testing.T) {
+	var output bytes.Buffer
+	profiler := &TextProfiler{Output: &output}
+
+	profiler.Record(SpecificationProfile{Function: "f", Invocations: 10, AllocatedBytes: 2 * 1024 * 1024, Allocations: 7})
+
+	if !strings.HasPrefix(output.String(), "f: 10 invocations") || !strings.Contains(output.String(), "2.00 MiB allocated in 7 objects") {
+		t.Errorf("Unexpected profiler output %q.", output.String())
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ProgressReporter is notified each time the specification of one more minute of the trace has been generated
+type ProgressReporter interface {
+	Report(minutesDone, minutesTotal int)
+}
+
+// TerminalProgressReporter prints the progress of the specification generation on a single, continuously
+// overwritten terminal line
+type TerminalProgressReporter struct {
+	Output io.Writer
+}
+
+func NewTerminalProgressReporter() *TerminalProgressReporter {
+	return &TerminalProgressReporter{Output: os.Stderr}
+}
+
+func (r *TerminalProgressReporter) Report(minutesDone, minutesTotal int) {
+	percentage := 100
+	if minutesTotal > 0 {
+		percentage = minutesDone * 100 / minutesTotal
+	}
+
+	_, _ = fmt.Fprintf(r.Output, "\rGenerating specification: %d/%d minutes (%d%%)", minutesDone, minutesTotal, percentage)
+	if minutesDone >= minutesTotal {
+		_, _ = fmt.Fprintln(r.Output)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+type mockProgressReporter struct {
+	calls [][2]int
+}
+
+func (r *mockProgressReporter) Report(minutesDone, minutesTotal int) {
+	r.calls = append(r.calls, [2]int{minutesDone, minutesTotal})
+}
+
+func TestGenerateInvocationDataReportsProgress(t *testing.T) {
+	reporter := &mockProgressReporter{}
+	sg := NewSpecificationGeneratorWithProgress(42, reporter)
+
+	function := testFunction
+	function.InvocationStats = &common.FunctionInvocationStats{Invocations: []int{5, 0, 10, 1}}
+	if _, err := sg.GenerateInvocationData(&function, common.Exponential, false, common.MinuteGranularity); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][2]int{{1, 4}, {2, 4}, {3, 4}, {4, 4}}
+	if len(reporter.calls) != len(expected) {
+		t.Fatalf("Expected %d progress reports, got %d.", len(expected), len(reporter.calls))
+	}
+	for i := range expected {
+		if reporter.calls[i] != expected[i] {
+			t.Errorf("Progress report %d is %v, expected %v.", i, reporter.calls[i], expected[i])
+		}
+	}
+}
+
+func TestTerminalProgressReporter(t *testing.T) {
+	var output bytes.Buffer
+	reporter := &TerminalProgressReporter{Output: &output}
+
+	reporter.Report(1, 2)
+	reporter.Report(2, 2)
+
+	if output.String() != "\rGenerating specification: 1/2 minutes (50%)\rGenerating specification: 2/2 minutes (100%)\n" {
+		t.Errorf("Unexpected progress output %q.", output.String())
+	}
+	if strings.Count(output.String(), "\n") != 1 {
+		t.Error("Progress line should only be terminated once the generation is completed.")
+	}
+}
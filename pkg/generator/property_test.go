@@ -0,0 +1,186 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"flag"
+	"sort"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"pgregory.net/rapid"
+)
+
+// propertySeed makes the properties check the same inputs on every run unless -rapid.seed is given. A failing input
+// is reported with the seed reproducing it.
+const propertySeed = "20231017"
+
+const (
+	propertyMaxMinutes              = 5
+	propertyMaxInvocationsPerMinute = 1000
+)
+
+var propertyDistributions = map[string]common.IatDistribution{
+	"exponential":      common.Exponential,
+	"uniform":          common.Uniform,
+	"equidistant":      common.Equidistant,
+	"compound_poisson": common.CompoundPoisson,
+}
+
+func seedRapid(t *testing.T) {
+	if seed := flag.Lookup("rapid.seed"); seed != nil && seed.Value.String() == "0" {
+		if err := flag.Set("rapid.seed", propertySeed); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// sortedDraw draws n non-decreasing values in [0, max], e.g., the percentiles of a distribution
+func sortedDraw(t *rapid.T, n int, max float64, label string) []float64 {
+	values := rapid.SliceOfN(rapid.Float64Range(0, max), n, n).Draw(t, label)
+	sort.Float64s(values)
+
+	return values
+}
+
+// validFunction draws a function with a non-empty trace and consistent runtime and memory statistics
+func validFunction(t *rapid.T) *common.Function {
+	invocations := rapid.SliceOfN(rapid.IntRange(0, propertyMaxInvocationsPerMinute), 1, propertyMaxMinutes).Draw(t, "invocations")
+
+	runtime := sortedDraw(t, 7, common.MaxExecTimeMilli, "runtime percentiles")
+	memory := sortedDraw(t, 8, common.MaxMemQuotaMib, "memory percentiles")
+
+	return &common.Function{
+		Name:            "property-function",
+		InvocationStats: &common.FunctionInvocationStats{Invocations: invocations},
+		RuntimeStats: &common.FunctionRuntimeStats{
+			Average:       runtime[3],
+			Count:         float64(rapid.IntRange(1, 1e6).Draw(t, "runtime count")),
+			Minimum:       runtime[0],
+			Maximum:       runtime[6],
+			Percentile0:   runtime[0],
+			Percentile1:   runtime[1],
+			Percentile25:  runtime[2],
+			Percentile50:  runtime[3],
+			Percentile75:  runtime[4],
+			Percentile99:  runtime[5],
+			Percentile100: runtime[6],
+		},
+		MemoryStats: &common.FunctionMemoryStats{
+			Average:       memory[3],
+			Count:         float64(rapid.IntRange(1, 1e6).Draw(t, "memory count")),
+			Percentile1:   memory[0],
+			Percentile5:   memory[1],
+			Percentile25:  memory[2],
+			Percentile50:  memory[3],
+			Percentile75:  memory[4],
+			Percentile95:  memory[5],
+			Percentile99:  memory[6],
+			Percentile100: memory[7],
+		},
+	}
+}
+
+// checkProperty checks the property against the invocation data generated for valid functions with each IAT
+// distribution, at both trace granularities and with or without shifting the IATs
+func checkProperty(t *testing.T, property func(t *rapid.T, function *common.Function, spec *common.FunctionSpecification, granularity common.TraceGranularity)) {
+	seedRapid(t)
+
+	for name, distribution := range propertyDistributions {
+		distribution := distribution
+
+		t.Run(name, func(t *testing.T) {
+			rapid.Check(t, func(t *rapid.T) {
+				function := validFunction(t)
+				seed := rapid.Int64().Draw(t, "seed")
+				shiftIAT := rapid.Bool().Draw(t, "shiftIAT")
+				granularity := rapid.SampledFrom([]common.TraceGranularity{common.MinuteGranularity, common.SecondGranularity}).Draw(t, "granularity")
+
+				spec, err := NewSpecificationGenerator(seed).GenerateInvocationData(function, distribution, shiftIAT, granularity)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				property(t, function, spec, granularity)
+			})
+		})
+	}
+}
+
+func TestPropertyIATMatchesInvocations(t *testing.T) {
+	checkProperty(t, func(t *rapid.T, function *common.Function, spec *common.FunctionSpecification, granularity common.TraceGranularity) {
+		invocations := function.InvocationStats.Invocations
+
+		if len(spec.IAT) != len(invocations) {
+			t.Fatalf("Expected %d IAT rows, got %d", len(invocations), len(spec.IAT))
+		}
+
+		for minute, row := range spec.IAT {
+			// The first IAT of a minute is the time before its first invocation
+			expected := 0
+			if invocations[minute] > 0 {
+				expected = invocations[minute] + 1
+			}
+			if len(row) != expected {
+				t.Fatalf("Expected %d IATs in minute %d with %d invocations, got %d", expected, minute, invocations[minute], len(row))
+			}
+
+			for _, iat := range row {
+				if iat < 0 {
+					t.Fatalf("Negative IAT %f in minute %d", iat, minute)
+				}
+			}
+
+			if err := checkSpillover(minute, row, granularity); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestPropertyRuntimeIsPositive(t *testing.T) {
+	checkProperty(t, func(t *rapid.T, function *common.Function, spec *common.FunctionSpecification, _ common.TraceGranularity) {
+		invocations := function.InvocationStats.Invocations
+
+		if len(spec.RuntimeSpecification) != len(invocations) {
+			t.Fatalf("Expected %d runtime specification rows, got %d", len(invocations), len(spec.RuntimeSpecification))
+		}
+
+		for minute, row := range spec.RuntimeSpecification {
+			if len(row) != invocations[minute] {
+				t.Fatalf("Expected %d runtime specifications in minute %d, got %d", invocations[minute], minute, len(row))
+			}
+
+			for i, runtime := range row {
+				if runtime.Runtime <= 0 || runtime.Runtime > int(common.MaxExecTimeMilli) {
+					t.Fatalf("Runtime %d ms of invocation %d in minute %d out of (0, %d]", runtime.Runtime, i, minute, int(common.MaxExecTimeMilli))
+				}
+				if runtime.Memory <= 0 || runtime.Memory > common.MaxMemQuotaMib {
+					t.Fatalf("Memory %d MiB of invocation %d in minute %d out of (0, %d]", runtime.Memory, i, minute, common.MaxMemQuotaMib)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,135 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+const (
+	DefaultSensitivitySamples         = 10_000
+	DefaultSensitivityPerturbationPct = 10
+)
+
+// SensitivityReport is the change of the mean sampled runtime and memory when perturbing a parameter of the trace
+type SensitivityReport struct {
+	Parameter        string
+	PerturbationPct  float64
+	RuntimeChangePct float64
+	MemoryChangePct  float64
+}
+
+// SensitivityAnalyzer measures how sensitive the sampled execution specifications of a function are to the runtime
+// and memory statistics of the trace, perturbing one at a time. As the specifications are sampled from the trace
+// percentiles, perturbing an average scales the whole distribution, i.e. the average and all the percentiles.
+type SensitivityAnalyzer struct {
+	Seed            int64
+	Samples         int     // sampled specifications per run
+	PerturbationPct float64 // applied in both directions
+}
+
+func NewSensitivityAnalyzer(seed int64) *SensitivityAnalyzer {
+	return &SensitivityAnalyzer{
+		Seed:            seed,
+		Samples:         DefaultSensitivitySamples,
+		PerturbationPct: DefaultSensitivityPerturbationPct,
+	}
+}
+
+// Analyze returns a report for each of RuntimeStats.Average and MemoryStats.Average perturbed by +PerturbationPct and
+// -PerturbationPct, in this order
+func (a *SensitivityAnalyzer) Analyze(function *common.Function) []SensitivityReport {
+	baselineRuntime, baselineMemory := a.sampleMeans(function)
+
+	var reports []SensitivityReport
+	for _, parameter := range []string{"RuntimeStats.Average", "MemoryStats.Average"} {
+		for _, perturbationPct := range []float64{a.PerturbationPct, -a.PerturbationPct} {
+			perturbed := *function
+			factor := 1 + perturbationPct/100
+			if parameter == "RuntimeStats.Average" {
+				perturbed.RuntimeStats = scaleRuntimeStats(function.RuntimeStats, factor)
+			} else {
+				perturbed.MemoryStats = scaleMemoryStats(function.MemoryStats, factor)
+			}
+
+			runtime, memory := a.sampleMeans(&perturbed)
+			reports = append(reports, SensitivityReport{
+				Parameter:        parameter,
+				PerturbationPct:  perturbationPct,
+				RuntimeChangePct: changePct(baselineRuntime, runtime),
+				MemoryChangePct:  changePct(baselineMemory, memory),
+			})
+		}
+	}
+
+	return reports
+}
+
+// sampleMeans returns the mean runtime and memory of the sampled specifications. Every run samples at the same
+// quantiles, so that the samples of the perturbed runs are paired with the baseline ones.
+func (a *SensitivityAnalyzer) sampleMeans(function *common.Function) (float64, float64) {
+	generator := NewSpecificationGenerator(a.Seed)
+	generator.specQuantiles = generator.determineExecutionSpecSeedQuantiles
+
+	var runtimeSum, memorySum float64
+	for i := 0; i < a.Samples; i++ {
+		spec := generator.generateExecutionSpecs(function)
+		runtimeSum += float64(spec.Runtime)
+		memorySum += float64(spec.Memory)
+	}
+
+	return runtimeSum / float64(a.Samples), memorySum / float64(a.Samples)
+}
+
+func scaleRuntimeStats(stats *common.FunctionRuntimeStats, factor float64) *common.FunctionRuntimeStats {
+	scaled := *stats
+	for _, value := range []*float64{&scaled.Average, &scaled.Minimum, &scaled.Maximum, &scaled.Percentile0,
+		&scaled.Percentile1, &scaled.Percentile25, &scaled.Percentile50, &scaled.Percentile75, &scaled.Percentile99,
+		&scaled.Percentile100} {
+
+		*value *= factor
+	}
+
+	return &scaled
+}
+
+func scaleMemoryStats(stats *common.FunctionMemoryStats, factor float64) *common.FunctionMemoryStats {
+	scaled := *stats
+	for _, value := range []*float64{&scaled.Average, &scaled.Percentile1, &scaled.Percentile5, &scaled.Percentile25,
+		&scaled.Percentile50, &scaled.Percentile75, &scaled.Percentile95, &scaled.Percentile99, &scaled.Percentile100} {
+
+		*value *= factor
+	}
+
+	return &scaled
+}
+
+func changePct(baseline, perturbed float64) float64 {
+	if baseline == 0 {
+		return 0
+	}
+
+	return (perturbed - baseline) / baseline * 100
+}
@@ -0,0 +1,63 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSensitivityAnalyzer(t *testing.T) {
+	analyzer := NewSensitivityAnalyzer(42)
+	analyzer.Samples = 2000
+
+	reports := analyzer.Analyze(&testFunction)
+	if len(reports) != 4 {
+		t.Fatalf("Expected a report per parameter and direction, got %v", reports)
+	}
+
+	for _, report := range reports {
+		if math.Abs(report.PerturbationPct) != DefaultSensitivityPerturbationPct {
+			t.Errorf("Unexpected perturbation %+v", report)
+		}
+
+		affected, unaffected := report.RuntimeChangePct, report.MemoryChangePct
+		if report.Parameter == "MemoryStats.Average" {
+			affected, unaffected = report.MemoryChangePct, report.RuntimeChangePct
+		}
+
+		// The samples are truncated to integers, so the change is only roughly proportional
+		if math.Abs(affected-report.PerturbationPct) > 2 {
+			t.Errorf("Expected a change of about %.0f%% when perturbing %s, got %+v", report.PerturbationPct, report.Parameter, report)
+		}
+		if unaffected != 0 {
+			t.Errorf("Expected perturbing %s to affect only its own samples, got %+v", report.Parameter, report)
+		}
+	}
+
+	if testFunction.RuntimeStats.Average != 50 || testFunction.MemoryStats.Percentile50 != 5000 {
+		t.Error("The function analyzed should not be modified.")
+	}
+}
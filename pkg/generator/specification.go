@@ -25,6 +25,10 @@
 package generator
 
 import (
+	crand "crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
 	"math/rand"
 
 	log "github.com/sirupsen/logrus"
@@ -34,13 +38,41 @@ import (
 type SpecificationGenerator struct {
 	iatRand  *rand.Rand
 	specRand *rand.Rand
+
+	progressReporter ProgressReporter
+	burstSizes       BurstSizeDistribution // used by the compound Poisson distribution
+
+	// specQuantiles draws the joint runtime and memory quantiles of an invocation, which are then mapped to the trace
+	// percentiles by linear interpolation. Runtime and memory are sampled independently if nil.
+	specQuantiles func() (float64, float64)
 }
 
 func NewSpecificationGenerator(seed int64) *SpecificationGenerator {
 	return &SpecificationGenerator{
-		iatRand:  rand.New(rand.NewSource(seed)),
-		specRand: rand.New(rand.NewSource(seed)),
+		iatRand:    rand.New(rand.NewSource(seed)),
+		specRand:   rand.New(rand.NewSource(seed)),
+		burstSizes: DefaultBurstSizeDistribution,
+	}
+}
+
+// NewCryptoSpecificationGenerator creates a generator seeded from a cryptographically secure random source, for load
+// tests that must not be reproducible across runs
+func NewCryptoSpecificationGenerator() *SpecificationGenerator {
+	seed, err := crand.Int(crand.Reader, big.NewInt(math.MaxInt64))
+	if err != nil {
+		log.Fatalf("Failed to draw a random seed - %v", err)
 	}
+
+	return NewSpecificationGenerator(seed.Int64())
+}
+
+// NewSpecificationGeneratorWithProgress creates a generator that notifies the reporter about the number of
+// minutes whose specification has been generated
+func NewSpecificationGeneratorWithProgress(seed int64, reporter ProgressReporter) *SpecificationGenerator {
+	generator := NewSpecificationGenerator(seed)
+	generator.progressReporter = reporter
+
+	return generator
 }
 
 //////////////////////////////////////////////////
@@ -53,6 +85,11 @@ func (s *SpecificationGenerator) generateIATPerGranularity(numberOfInvocations i
 		return []float64{}, 0.0
 	}
 
+	if iatDistribution == common.CompoundPoisson {
+		iatResult, totalDuration := s.generateCompoundPoissonIAT(numberOfInvocations, granularity)
+		return s.shiftIAT(iatResult, shiftIAT, granularity), totalDuration
+	}
+
 	var iatResult []float64
 	totalDuration := 0.0 // total non-scaled duration
 
@@ -100,6 +137,11 @@ func (s *SpecificationGenerator) generateIATPerGranularity(numberOfInvocations i
 		}
 	}
 
+	return s.shiftIAT(iatResult, shiftIAT, granularity), totalDuration
+}
+
+// shiftIAT prepends the time before the first invocation of the minute, which is zero unless the IATs are shifted
+func (s *SpecificationGenerator) shiftIAT(iatResult []float64, shiftIAT bool, granularity common.TraceGranularity) []float64 {
 	if shiftIAT {
 		// Cut the IAT array at random place to move the first invocation from the beginning of the minute
 		split := s.iatRand.Float64() * common.OneSecondInMicroseconds
@@ -122,48 +164,94 @@ func (s *SpecificationGenerator) generateIATPerGranularity(numberOfInvocations i
 		iatResult = append([]float64{0.0}, iatResult...)
 	}
 
-	return iatResult, totalDuration
+	return iatResult
 }
 
-// GenerateIAT generates IAT according to the given distribution. Number of minutes is the length of invocationsPerMinute array
-func (s *SpecificationGenerator) generateIAT(invocationsPerMinute []int, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) (common.IATMatrix, common.ProbabilisticDuration) {
+// GenerateIAT generates IAT according to the given distribution. Number of minutes is the length of invocationsPerMinute array.
+// If a spillover detector is given, each generated minute is submitted to it and the generation stops at the first
+// spillover it reports.
+func (s *SpecificationGenerator) generateIAT(invocationsPerMinute []int, iatDistribution common.IatDistribution, shiftIAT bool,
+	granularity common.TraceGranularity, detector *SpilloverDetector) (common.IATMatrix, common.ProbabilisticDuration, error) {
+
 	var IAT [][]float64
 	var nonScaledDuration []float64
 
 	numberOfMinutes := len(invocationsPerMinute)
 	for i := 0; i < numberOfMinutes; i++ {
+		if detector != nil {
+			select {
+			case err := <-detector.Errors():
+				return nil, nil, err
+			default:
+			}
+		}
+
+		if invocationsPerMinute[i] == 0 {
+			// Keep one (empty) row per minute so that IAT[minute] stays aligned with the trace
+			IAT = append(IAT, []float64{})
+			nonScaledDuration = append(nonScaledDuration, 0.0)
+
+			continue
+		}
+
 		minuteIAT, duration := s.generateIATPerGranularity(invocationsPerMinute[i], iatDistribution, shiftIAT, granularity)
 
 		IAT = append(IAT, minuteIAT)
 		nonScaledDuration = append(nonScaledDuration, duration)
+
+		if detector != nil {
+			detector.Submit(i, minuteIAT)
+		}
 	}
 
-	return IAT, nonScaledDuration
+	return IAT, nonScaledDuration, nil
 }
 
-func (s *SpecificationGenerator) GenerateInvocationData(function *common.Function, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) *common.FunctionSpecification {
+// GenerateInvocationData generates the IATs and the runtime specifications of the invocations of the function. The
+// IATs of each minute are checked for spillover while the subsequent minutes are generated, returning an error
+// as soon as one spills over.
+func (s *SpecificationGenerator) GenerateInvocationData(function *common.Function, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) (*common.FunctionSpecification, error) {
 	invocationsPerMinute := function.InvocationStats.Invocations
 
 	// Generating IAT
-	iat, rawDuration := s.generateIAT(invocationsPerMinute, iatDistribution, shiftIAT, granularity)
+	detector := NewSpilloverDetector(granularity, len(invocationsPerMinute))
+	iat, rawDuration, err := s.generateIAT(invocationsPerMinute, iatDistribution, shiftIAT, granularity, detector)
+	if closeErr := detector.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid IATs generated for function %s: %w", function.Name, err)
+	}
 
 	// Generating runtime specifications
+	runtimeMatrix := s.generateRuntimeSpecification(function)
+
+	return &common.FunctionSpecification{
+		IAT:                  iat,
+		RawDuration:          rawDuration,
+		RuntimeSpecification: runtimeMatrix,
+	}, nil
+}
+
+func (s *SpecificationGenerator) generateRuntimeSpecification(function *common.Function) common.RuntimeSpecificationMatrix {
+	invocationsPerMinute := function.InvocationStats.Invocations
+
 	var runtimeMatrix common.RuntimeSpecificationMatrix
 	for i := 0; i < len(invocationsPerMinute); i++ {
-		var row []common.RuntimeSpecification
+		row := []common.RuntimeSpecification{}
 
 		for j := 0; j < invocationsPerMinute[i]; j++ {
 			row = append(row, s.generateExecutionSpecs(function))
 		}
 
 		runtimeMatrix = append(runtimeMatrix, row)
-	}
 
-	return &common.FunctionSpecification{
-		IAT:                  iat,
-		RawDuration:          rawDuration,
-		RuntimeSpecification: runtimeMatrix,
+		if s.progressReporter != nil {
+			s.progressReporter.Report(i+1, len(invocationsPerMinute))
+		}
 	}
+
+	return runtimeMatrix
 }
 
 //////////////////////////////////////////////////
@@ -246,9 +334,17 @@ func (s *SpecificationGenerator) generateExecutionSpecs(function *common.Functio
 		log.Fatal("Invalid duration or memory specification of the function '" + function.Name + "'.")
 	}
 
-	runQtl, memQtl := s.determineExecutionSpecSeedQuantiles()
-	runtime := common.MinOf(common.MaxExecTimeMilli, common.MaxOf(common.MinExecTimeMilli, s.generateExecuteSpec(runQtl, runStats)))
-	memory := common.MinOf(common.MaxMemQuotaMib, common.MaxOf(common.MinMemQuotaMib, s.generateMemorySpec(memQtl, memStats)))
+	var runtime, memory int
+	if s.specQuantiles != nil {
+		runQtl, memQtl := s.specQuantiles()
+		runtime, memory = runtimeAtQuantile(runQtl, runStats), memoryAtQuantile(memQtl, memStats)
+	} else {
+		runQtl, memQtl := s.determineExecutionSpecSeedQuantiles()
+		runtime, memory = s.generateExecuteSpec(runQtl, runStats), s.generateMemorySpec(memQtl, memStats)
+	}
+
+	runtime = common.MinOf(common.MaxExecTimeMilli, common.MaxOf(common.MinExecTimeMilli, runtime))
+	memory = common.MinOf(common.MaxMemQuotaMib, common.MaxOf(common.MinMemQuotaMib, memory))
 
 	return common.RuntimeSpecification{
 		Runtime: runtime,
@@ -0,0 +1,101 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+const (
+	benchmarkFunctions            = 1000
+	benchmarkInvocationsPerMinute = 100
+	benchmarkMinutes              = 60
+)
+
+func createBenchmarkFunctions() []*common.Function {
+	invocations := make([]int, benchmarkMinutes)
+	for i := range invocations {
+		invocations[i] = benchmarkInvocationsPerMinute
+	}
+
+	functions := make([]*common.Function, benchmarkFunctions)
+	for i := range functions {
+		functions[i] = &common.Function{
+			Name:            fmt.Sprintf("benchmark-function-%d", i),
+			InvocationStats: &common.FunctionInvocationStats{Invocations: invocations},
+			RuntimeStats:    testFunction.RuntimeStats,
+			MemoryStats:     testFunction.MemoryStats,
+		}
+	}
+
+	return functions
+}
+
+func BenchmarkGenerateInvocationData(b *testing.B) {
+	functions := createBenchmarkFunctions()
+	invocationsPerOp := benchmarkFunctions * benchmarkInvocationsPerMinute * benchmarkMinutes
+
+	distributions := []struct {
+		name         string
+		distribution common.IatDistribution
+	}{
+		{name: "equidistant", distribution: common.Equidistant},
+		{name: "uniform", distribution: common.Uniform},
+		{name: "exponential", distribution: common.Exponential},
+	}
+
+	for _, d := range distributions {
+		b.Run(d.name, func(b *testing.B) {
+			sg := NewSpecificationGenerator(123)
+
+			b.ResetTimer()
+			start := time.Now()
+			for n := 0; n < b.N; n++ {
+				for _, function := range functions {
+					if _, err := sg.GenerateInvocationData(function, d.distribution, true, common.MinuteGranularity); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+			elapsed := time.Since(start)
+
+			b.ReportMetric(float64(elapsed.Nanoseconds())/float64(b.N*invocationsPerOp), "ns/invocation")
+		})
+	}
+}
+
+func BenchmarkGenerateExecutionSpecifications(b *testing.B) {
+	sg := NewSpecificationGenerator(123)
+	function := &testFunction
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		sg.generateExecutionSpecs(function)
+	}
+}
@@ -374,7 +374,10 @@ func TestSerialGenerateIAT(t *testing.T) {
 			sg := NewSpecificationGenerator(seed)
 
 			testFunction.InvocationStats = &common.FunctionInvocationStats{Invocations: test.invocations}
-			spec := sg.GenerateInvocationData(&testFunction, test.iatDistribution, test.shiftIAT, test.granularity)
+			spec, err := sg.GenerateInvocationData(&testFunction, test.iatDistribution, test.shiftIAT, test.granularity)
+			if err != nil {
+				t.Fatal(err)
+			}
 			IAT, nonScaledDuration := spec.IAT, spec.RawDuration
 
 			failed := false
@@ -415,23 +418,67 @@ func TestSerialGenerateIAT(t *testing.T) {
 	}
 }
 
-func hasSpillover(data [][]float64, granularity common.TraceGranularity) bool {
-	for min := 0; min < len(data); min++ {
-		sum := 0.0
-		epsilon := 1e-3
+func TestGenerateIATWithZeroInvocationMinutes(t *testing.T) {
+	invocations := []int{0, 5, 0, 25, 0}
+
+	for _, distribution := range []common.IatDistribution{common.Equidistant, common.Uniform, common.Exponential} {
+		for _, shiftIAT := range []bool{false, true} {
+			sg := NewSpecificationGenerator(123456789)
+
+			testFunction.InvocationStats = &common.FunctionInvocationStats{Invocations: invocations}
+			spec, err := sg.GenerateInvocationData(&testFunction, distribution, shiftIAT, common.MinuteGranularity)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(spec.IAT) != len(invocations) || len(spec.RuntimeSpecification) != len(invocations) ||
+				len(spec.RawDuration) != len(invocations) {
 
-		for i := 0; i < len(data[min]); i++ {
-			sum += data[min][i]
+				t.Fatalf("Specification does not have one row per minute (distribution: %d, shift: %t).", distribution, shiftIAT)
+			}
+
+			for minute, count := range invocations {
+				if count == 0 && (spec.IAT[minute] == nil || len(spec.IAT[minute]) != 0) {
+					t.Errorf("Minute %d without invocations should have an empty IAT row.", minute)
+				}
+				if count > 0 && len(spec.IAT[minute]) != count+1 {
+					t.Errorf("Minute %d has %d IATs, expected %d.", minute, len(spec.IAT[minute]), count+1)
+				}
+				if len(spec.RuntimeSpecification[minute]) != count {
+					t.Errorf("Minute %d has %d runtime specifications, expected %d.", minute, len(spec.RuntimeSpecification[minute]), count)
+				}
+			}
+
+			if hasSpillover(spec.IAT, common.MinuteGranularity) {
+				t.Errorf("Minutes without invocations should not be reported as spillover (distribution: %d, shift: %t).", distribution, shiftIAT)
+			}
 		}
+	}
+}
 
-		log.Debug(fmt.Sprintf("Total execution time: %f μs\n", sum))
+func TestCryptoSpecificationGenerator(t *testing.T) {
+	// Each of the 32 IATs drawn differs between the generators unless their seeds collide, which happens with a
+	// probability of 2^-63
+	invocations := []int{32}
+	testFunction.InvocationStats = &common.FunctionInvocationStats{Invocations: invocations}
 
-		spilloverThreshold := common.OneSecondInMicroseconds
-		if granularity == common.MinuteGranularity {
-			spilloverThreshold *= 60
+	var iats [2][]float64
+	for i := range iats {
+		spec, err := NewCryptoSpecificationGenerator().GenerateInvocationData(&testFunction, common.Exponential, false, common.MinuteGranularity)
+		if err != nil {
+			t.Fatal(err)
 		}
+		iats[i] = spec.IAT[0]
+	}
 
-		if math.Abs(sum-spilloverThreshold) > epsilon {
+	if fmt.Sprint(iats[0]) == fmt.Sprint(iats[1]) {
+		t.Errorf("Expected different IAT sequences, got %v twice.", iats[0])
+	}
+}
+
+func hasSpillover(data [][]float64, granularity common.TraceGranularity) bool {
+	for min := 0; min < len(data); min++ {
+		if minuteHasSpillover(data[min], granularity) {
 			return true
 		}
 	}
@@ -568,7 +615,11 @@ func TestGenerateExecutionSpecifications(t *testing.T) {
 				Invocations: []int{test.iterations},
 			}
 			// distribution is irrelevant here
-			spec := sg.GenerateInvocationData(&testFunction, common.Equidistant, false, test.granularity).RuntimeSpecification
+			invocationData, err := sg.GenerateInvocationData(&testFunction, common.Equidistant, false, test.granularity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			spec := invocationData.RuntimeSpecification
 
 			for i := 0; i < test.iterations; i++ {
 				wg.Add(1)
@@ -0,0 +1,61 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// SpikeWindowMicroseconds is the time at the beginning of each minute in which spike mode issues all of its invocations
+const SpikeWindowMicroseconds = common.OneSecondInMicroseconds
+
+// ApplySpikeMode rescales the IATs so that all invocations of a minute are issued equidistantly within the first
+// second of the minute, leaving the rest of the minute idle. The number of invocations per minute is preserved.
+func ApplySpikeMode(spec *common.FunctionSpecification, granularity common.TraceGranularity) {
+	period := common.OneSecondInMicroseconds
+	if granularity == common.MinuteGranularity {
+		period *= 60.0
+	}
+
+	for minute, row := range spec.IAT {
+		if len(row) == 0 {
+			continue
+		}
+
+		// A row holds the time before the first invocation, the IATs between the invocations, and the remainder
+		// of the minute after the last invocation
+		invocations := len(row) - 1
+		step := SpikeWindowMicroseconds / float64(invocations)
+
+		spiked := make([]float64, 0, len(row))
+		spiked = append(spiked, 0.0)
+		for i := 1; i < invocations; i++ {
+			spiked = append(spiked, step)
+		}
+		spiked = append(spiked, period-float64(invocations-1)*step)
+
+		spec.IAT[minute] = spiked
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestApplySpikeMode(t *testing.T) {
+	invocations := []int{4, 0, 1, 100}
+
+	sg := NewSpecificationGenerator(123456789)
+	testFunction.InvocationStats = &common.FunctionInvocationStats{Invocations: invocations}
+	spec, err := sg.GenerateInvocationData(&testFunction, common.Exponential, true, common.MinuteGranularity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ApplySpikeMode(spec, common.MinuteGranularity)
+
+	for minute, count := range invocations {
+		row := spec.IAT[minute]
+		if count == 0 {
+			if len(row) != 0 {
+				t.Errorf("Minute %d without invocations should stay empty, got %v.", minute, row)
+			}
+			continue
+		}
+
+		if len(row) != count+1 {
+			t.Fatalf("Minute %d has %d IATs, expected %d.", minute, len(row), count+1)
+		}
+
+		// Time of the last invocation relative to the beginning of the minute
+		lastInvocation := 0.0
+		for i := 0; i < count; i++ {
+			lastInvocation += row[i]
+		}
+		if lastInvocation >= SpikeWindowMicroseconds {
+			t.Errorf("Minute %d issues its last invocation after %f μs.", minute, lastInvocation)
+		}
+		if count > 1 && row[1] != SpikeWindowMicroseconds/float64(count) {
+			t.Errorf("Minute %d is not spaced equidistantly: %v.", minute, row[:count])
+		}
+
+		if total := lastInvocation + row[count]; math.Abs(total-60*common.OneSecondInMicroseconds) > 1e-3 {
+			t.Errorf("Minute %d lasts %f μs instead of a minute.", minute, total)
+		}
+	}
+
+	if hasSpillover(spec.IAT, common.MinuteGranularity) {
+		t.Error("Spike mode should not spill over the minute.")
+	}
+}
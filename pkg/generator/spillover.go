@@ -0,0 +1,125 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// SpilloverTolerance is the deviation in microseconds of the sum of the IATs of a minute from the length of the
+// minute up to which the IATs are not reported as spillover. The driver sleeps with microsecond precision anyway.
+const SpilloverTolerance = 1.0
+
+type minuteIAT struct {
+	minute int
+	iat    []float64
+}
+
+// SpilloverDetector verifies in a background goroutine that the IATs generated for each minute add up to the
+// length of the minute (or the second, depending on the granularity), so that the invocations of a minute do not
+// spill over into the next one. The check of a minute runs while the subsequent minutes are being generated.
+type SpilloverDetector struct {
+	granularity common.TraceGranularity
+
+	minutes chan minuteIAT
+	errors  chan error
+	done    chan struct{}
+	err     error // the first spillover, read after done is closed
+}
+
+// NewSpilloverDetector starts a detector that can be submitted the given number of minutes without blocking
+func NewSpilloverDetector(granularity common.TraceGranularity, minutes int) *SpilloverDetector {
+	d := &SpilloverDetector{
+		granularity: granularity,
+		minutes:     make(chan minuteIAT, minutes),
+		errors:      make(chan error, 1),
+		done:        make(chan struct{}),
+	}
+
+	go d.run()
+
+	return d
+}
+
+func (d *SpilloverDetector) run() {
+	defer close(d.done)
+
+	for m := range d.minutes {
+		if err := checkSpillover(m.minute, m.iat, d.granularity); err != nil {
+			d.err = err
+			d.errors <- err
+
+			break
+		}
+	}
+
+	// Minutes submitted after the first spillover are not checked
+	for range d.minutes {
+	}
+}
+
+// Submit queues the IATs of a generated minute for verification
+func (d *SpilloverDetector) Submit(minute int, iat []float64) {
+	d.minutes <- minuteIAT{minute: minute, iat: iat}
+}
+
+// Errors receives the first spillover as soon as it is detected
+func (d *SpilloverDetector) Errors() <-chan error {
+	return d.errors
+}
+
+// Close waits for the verification of all the submitted minutes and returns the first spillover, if any
+func (d *SpilloverDetector) Close() error {
+	close(d.minutes)
+	<-d.done
+
+	return d.err
+}
+
+func checkSpillover(minute int, iat []float64, granularity common.TraceGranularity) error {
+	if len(iat) == 0 {
+		// nothing is scheduled in minutes without invocations
+		return nil
+	}
+
+	sum := 0.0
+	for _, value := range iat {
+		sum += value
+	}
+
+	expected := common.OneSecondInMicroseconds
+	if granularity == common.MinuteGranularity {
+		expected *= 60
+	}
+
+	if math.Abs(sum-expected) > SpilloverTolerance {
+		return fmt.Errorf("IATs of minute %d add up to %f μs instead of %f μs", minute, sum, expected)
+	}
+
+	return nil
+}
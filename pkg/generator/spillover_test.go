@@ -0,0 +1,138 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+const minuteInMicroseconds = 60 * common.OneSecondInMicroseconds
+
+// waitForSpillover blocks until the detector has reported a spillover, without consuming it
+func waitForSpillover(t *testing.T, detector *SpilloverDetector) {
+	deadline := time.Now().Add(5 * time.Second)
+	for len(detector.errors) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Spillover not detected.")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSpilloverDetector(t *testing.T) {
+	tests := []struct {
+		name        string
+		granularity common.TraceGranularity
+		minutes     [][]float64
+		spillover   string
+	}{
+		{
+			name:        "no_spillover",
+			granularity: common.MinuteGranularity,
+			minutes:     [][]float64{{0, minuteInMicroseconds / 2, minuteInMicroseconds / 2}, {}, {0, minuteInMicroseconds}},
+		},
+		{
+			name:        "no_spillover_second_granularity",
+			granularity: common.SecondGranularity,
+			minutes:     [][]float64{{0, common.OneSecondInMicroseconds}},
+		},
+		{
+			name:        "first_spillover_reported",
+			granularity: common.MinuteGranularity,
+			minutes:     [][]float64{{0, minuteInMicroseconds}, {0, minuteInMicroseconds, 10}, {0, 2 * minuteInMicroseconds}},
+			spillover:   "minute 1 ",
+		},
+		{
+			name:        "minute_too_short",
+			granularity: common.SecondGranularity,
+			minutes:     [][]float64{{0, common.OneSecondInMicroseconds - 2}},
+			spillover:   "minute 0 ",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detector := NewSpilloverDetector(test.granularity, len(test.minutes))
+			for minute, iat := range test.minutes {
+				detector.Submit(minute, iat)
+			}
+
+			err := detector.Close()
+			if test.spillover == "" && err != nil {
+				t.Errorf("Unexpected spillover: %v", err)
+			} else if test.spillover != "" && (err == nil || !strings.Contains(err.Error(), test.spillover)) {
+				t.Errorf("Expected a spillover in %q, got %v", test.spillover, err)
+			}
+		})
+	}
+}
+
+func TestGenerateIATStopsOnSpillover(t *testing.T) {
+	invocations := make([]int, 1000)
+	for i := range invocations {
+		invocations[i] = 10
+	}
+
+	detector := NewSpilloverDetector(common.MinuteGranularity, len(invocations)+1)
+	detector.Submit(0, []float64{0, 2 * minuteInMicroseconds})
+	waitForSpillover(t, detector)
+
+	sg := NewSpecificationGenerator(42)
+	iat, duration, err := sg.generateIAT(invocations, common.Exponential, false, common.MinuteGranularity, detector)
+	if err == nil || !strings.Contains(err.Error(), "minute 0 ") {
+		t.Errorf("Expected the spillover of minute 0, got %v", err)
+	}
+	if iat != nil || duration != nil {
+		t.Errorf("Expected the generation to stop at the spillover, got %d minutes", len(iat))
+	}
+
+	// The generation stopped before generating any minute, leaving the random generator untouched
+	expected, _, _ := NewSpecificationGenerator(42).generateIAT(invocations[:1], common.Exponential, false, common.MinuteGranularity, nil)
+	actual, _, _ := sg.generateIAT(invocations[:1], common.Exponential, false, common.MinuteGranularity, nil)
+	if expected[0][1] != actual[0][1] {
+		t.Error("Expected no minute to be generated after the spillover.")
+	}
+
+	if err = detector.Close(); err == nil {
+		t.Error("Expected Close to return the spillover.")
+	}
+}
+
+func TestGenerateInvocationDataWithoutSpillover(t *testing.T) {
+	function := testFunction
+	function.InvocationStats = &common.FunctionInvocationStats{Invocations: []int{100, 0, 1, 5000}}
+
+	for _, distribution := range []common.IatDistribution{common.Exponential, common.Uniform, common.Equidistant, common.CompoundPoisson} {
+		for _, granularity := range []common.TraceGranularity{common.MinuteGranularity, common.SecondGranularity} {
+			if _, err := NewSpecificationGenerator(42).GenerateInvocationData(&function, distribution, true, granularity); err != nil {
+				t.Errorf("Unexpected error (distribution: %d, granularity: %d): %v", distribution, granularity, err)
+			}
+		}
+	}
+}
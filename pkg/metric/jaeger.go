@@ -0,0 +1,115 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package metric
+
+import (
+	"io"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
+)
+
+const (
+	jaegerServiceName   = "loader"
+	jaegerOperationName = "invoke"
+)
+
+// Values of the status tag of the exported spans
+const (
+	StatusSuccess                 = "success"
+	StatusConnectionTimeout       = "connection_timeout"
+	StatusFunctionTimeout         = "function_timeout"
+	StatusMemoryAllocationTimeout = "memory_allocation_timeout"
+	StatusShed                    = "shed"
+)
+
+// JaegerExporter exports one span per invocation to a Jaeger agent. Spans are batched and sent when Close is called
+// or the batch is full.
+type JaegerExporter struct {
+	tracer opentracing.Tracer
+	closer io.Closer
+}
+
+// NewJaegerExporter creates an exporter sending the spans over UDP to the Jaeger agent at the endpoint (host:port)
+func NewJaegerExporter(endpoint string) (*JaegerExporter, error) {
+	transport, err := jaeger.NewUDPTransport(endpoint, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return newJaegerExporter(transport), nil
+}
+
+func newJaegerExporter(transport jaeger.Transport) *JaegerExporter {
+	tracer, closer := jaeger.NewTracer(
+		jaegerServiceName,
+		jaeger.NewConstSampler(true),
+		jaeger.NewRemoteReporter(transport),
+	)
+
+	return &JaegerExporter{
+		tracer: tracer,
+		closer: closer,
+	}
+}
+
+func invocationStatus(record *ExecutionRecord) string {
+	switch {
+	case record.Shed:
+		return StatusShed
+	case record.ConnectionTimeout:
+		return StatusConnectionTimeout
+	case record.FunctionTimeout:
+		return StatusFunctionTimeout
+	case record.MemoryAllocationTimeout:
+		return StatusMemoryAllocationTimeout
+	default:
+		return StatusSuccess
+	}
+}
+
+// Export converts each record into a span lasting from the start of the invocation until its response
+func (e *JaegerExporter) Export(records []*ExecutionRecord) {
+	for _, record := range records {
+		start := time.UnixMicro(record.StartTime)
+
+		span := e.tracer.StartSpan(jaegerOperationName, opentracing.StartTime(start))
+		span.SetTag("function.name", record.Instance)
+		span.SetTag("runtime.ms", float64(record.ActualDuration)/1e3)
+		span.SetTag("memory.mib", record.ActualMemoryUsage)
+		span.SetTag("status", invocationStatus(record))
+		span.SetTag("invocation.id", record.InvocationID)
+
+		span.FinishWithOptions(opentracing.FinishOptions{
+			FinishTime: start.Add(time.Duration(record.ResponseTime) * time.Microsecond),
+		})
+	}
+}
+
+// Close flushes the pending spans
+func (e *JaegerExporter) Close() error {
+	return e.closer.Close()
+}
@@ -0,0 +1,111 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package metric
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uber/jaeger-client-go"
+)
+
+// mockTransport captures the spans instead of sending them to a Jaeger agent
+type mockTransport struct {
+	mutex sync.Mutex
+	spans []*jaeger.Span
+}
+
+func (m *mockTransport) Append(span *jaeger.Span) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.spans = append(m.spans, span.Retain())
+	return 0, nil
+}
+
+func (m *mockTransport) Flush() (int, error) {
+	return 0, nil
+}
+
+func (m *mockTransport) Close() error {
+	return nil
+}
+
+func TestJaegerExporter(t *testing.T) {
+	transport := &mockTransport{}
+	exporter := newJaegerExporter(transport)
+
+	start := time.Now()
+	records := []*ExecutionRecord{
+		{
+			ExecutionRecordBase: ExecutionRecordBase{
+				Instance:       "trace-func-0",
+				InvocationID:   "min0.inv0",
+				StartTime:      start.UnixMicro(),
+				ResponseTime:   120000,
+				ActualDuration: 100000,
+			},
+			ActualMemoryUsage: 128,
+		},
+		{
+			ExecutionRecordBase: ExecutionRecordBase{
+				Instance:        "trace-func-1",
+				InvocationID:    "min0.inv1",
+				StartTime:       start.UnixMicro(),
+				ResponseTime:    900000,
+				FunctionTimeout: true,
+			},
+		},
+	}
+
+	exporter.Export(records)
+	if err := exporter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transport.spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d.", len(transport.spans))
+	}
+
+	expectedTags := []map[string]interface{}{
+		{"function.name": "trace-func-0", "runtime.ms": 100.0, "memory.mib": uint32(128), "status": StatusSuccess},
+		{"function.name": "trace-func-1", "runtime.ms": 0.0, "memory.mib": uint32(0), "status": StatusFunctionTimeout},
+	}
+
+	for i, span := range transport.spans {
+		tags := span.Tags()
+		for key, expected := range expectedTags[i] {
+			if tags[key] != expected {
+				t.Errorf("Span %d: expected tag %s to be %v, got %v.", i, key, expected, tags[key])
+			}
+		}
+
+		expectedDuration := time.Duration(records[i].ResponseTime) * time.Microsecond
+		if span.Duration() != expectedDuration {
+			t.Errorf("Span %d: expected a duration of %v, got %v.", i, expectedDuration, span.Duration())
+		}
+	}
+}
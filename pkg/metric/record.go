@@ -39,6 +39,10 @@ type MinuteInvocationRecord struct {
 	NumFuncTargeted int   `csv:"num_func_target"`
 	NumFuncInvoked  int   `csv:"num_func_invoked"`
 	NumColdStarts   int   `csv:"num_coldstarts"`
+
+	// PredictionError is the mean absolute error in microseconds of the execution times predicted by the
+	// LatencyPredictor of the driver for the invocations of the minute
+	PredictionError float64 `csv:"prediction_error"`
 }
 
 type ExecutionRecordBase struct {
@@ -55,6 +59,10 @@ type ExecutionRecordBase struct {
 
 	ConnectionTimeout bool `csv:"connectionTimeout"`
 	FunctionTimeout   bool `csv:"functionTimeout"`
+	Shed              bool `csv:"shed"`             // never issued because the driver was overloaded
+	CacheHit          bool `csv:"cacheHit"`         // answered from the result cache of a previous experiment
+	CertPinViolation  bool `csv:"certPinViolation"` // refused because the endpoint presented a certificate other than the pinned one
+	Throttled         bool `csv:"throttled"`        // the endpoint responded with 429 Too Many Requests
 }
 
 type ExecutionRecordOpenWhisk struct {
@@ -75,6 +83,7 @@ type ExecutionRecord struct {
 	// Measurements in microseconds
 	ActualMemoryUsage       uint32 `csv:"actualMemoryUsage"`
 	MemoryAllocationTimeout bool   `csv:"memoryAllocationTimeout"`
+	IOLatency               uint32 `csv:"ioLatency"` // of the I/O workload of the AWS Lambda function, in microseconds
 
 	// TODO: EVERYTHING BELOW ARE UNTESTED FIELDS
 
@@ -0,0 +1,107 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"fmt"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// SpecOverride scales the average runtime and memory of a function
+type SpecOverride struct {
+	RuntimeMultiplier float64
+	MemoryMultiplier  float64
+}
+
+// ABTestConfig splits the functions of the trace into a control and a treatment group by name. The specification
+// of the treatment functions is overridden, while the control functions run as in the trace.
+type ABTestConfig struct {
+	ControlFunctions   []string
+	TreatmentFunctions []string
+	TreatmentSpec      SpecOverride
+}
+
+// ApplyABTest returns the functions of the control and the treatment group, in the order they appear in the trace.
+// Functions in neither group are left out of the experiment. The treatment functions are copies of the functions in
+// the trace with their RuntimeStats.Average and MemoryStats.Average scaled, so that the given functions remain
+// unmodified.
+func ApplyABTest(functions []*common.Function, cfg ABTestConfig) (control, treatment []*common.Function, err error) {
+	if cfg.TreatmentSpec.RuntimeMultiplier <= 0 || cfg.TreatmentSpec.MemoryMultiplier <= 0 {
+		return nil, nil, fmt.Errorf("multipliers of the treatment must be positive, got runtime %f and memory %f",
+			cfg.TreatmentSpec.RuntimeMultiplier, cfg.TreatmentSpec.MemoryMultiplier)
+	}
+
+	groups := make(map[string]bool) // true for the treatment group
+	for _, name := range cfg.ControlFunctions {
+		groups[name] = false
+	}
+	for _, name := range cfg.TreatmentFunctions {
+		if isTreatment, ok := groups[name]; ok && !isTreatment {
+			return nil, nil, fmt.Errorf("function %s is in both the control and the treatment group", name)
+		}
+		groups[name] = true
+	}
+
+	found := make(map[string]bool)
+	for _, function := range functions {
+		isTreatment, ok := groups[function.Name]
+		if !ok {
+			continue
+		}
+		found[function.Name] = true
+
+		if isTreatment {
+			treatment = append(treatment, overrideSpec(function, cfg.TreatmentSpec))
+		} else {
+			control = append(control, function)
+		}
+	}
+
+	for name := range groups {
+		if !found[name] {
+			return nil, nil, fmt.Errorf("function %s of the A/B test is not in the trace", name)
+		}
+	}
+
+	return control, treatment, nil
+}
+
+func overrideSpec(function *common.Function, override SpecOverride) *common.Function {
+	result := *function
+
+	if function.RuntimeStats != nil {
+		runtimeStats := *function.RuntimeStats
+		runtimeStats.Average *= override.RuntimeMultiplier
+		result.RuntimeStats = &runtimeStats
+	}
+	if function.MemoryStats != nil {
+		memoryStats := *function.MemoryStats
+		memoryStats.Average *= override.MemoryMultiplier
+		result.MemoryStats = &memoryStats
+	}
+
+	return &result
+}
@@ -0,0 +1,116 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func createABTestFixture() []*common.Function {
+	var functions []*common.Function
+	for _, name := range []string{"f0", "f1", "f2", "f3"} {
+		functions = append(functions, &common.Function{
+			Name:         name,
+			RuntimeStats: &common.FunctionRuntimeStats{Average: 100, Minimum: 10, Maximum: 1000, Percentile50: 90},
+			MemoryStats:  &common.FunctionMemoryStats{Average: 256, Percentile50: 200, Percentile100: 512},
+		})
+	}
+
+	return functions
+}
+
+func TestApplyABTest(t *testing.T) {
+	functions := createABTestFixture()
+
+	control, treatment, err := ApplyABTest(functions, ABTestConfig{
+		ControlFunctions:   []string{"f0", "f2"},
+		TreatmentFunctions: []string{"f3", "f1"},
+		TreatmentSpec:      SpecOverride{RuntimeMultiplier: 1.5, MemoryMultiplier: 0.5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !equalNames(control, []string{"f0", "f2"}) || !equalNames(treatment, []string{"f1", "f3"}) {
+		t.Fatalf("Wrong groups - control %v, treatment %v", functionNames(control), functionNames(treatment))
+	}
+
+	for _, function := range control {
+		if *function.RuntimeStats != *createABTestFixture()[0].RuntimeStats || *function.MemoryStats != *createABTestFixture()[0].MemoryStats {
+			t.Errorf("Control function %s was modified", function.Name)
+		}
+	}
+
+	for _, function := range treatment {
+		if function.RuntimeStats.Average != 150 || function.MemoryStats.Average != 128 {
+			t.Errorf("Treatment function %s not scaled - runtime %f, memory %f", function.Name,
+				function.RuntimeStats.Average, function.MemoryStats.Average)
+		}
+
+		runtimeStats, memoryStats := *function.RuntimeStats, *function.MemoryStats
+		runtimeStats.Average, memoryStats.Average = 100, 256
+		if runtimeStats != *createABTestFixture()[0].RuntimeStats || memoryStats != *createABTestFixture()[0].MemoryStats {
+			t.Errorf("Statistics other than the average of treatment function %s were modified", function.Name)
+		}
+	}
+
+	for _, function := range functions {
+		if function.RuntimeStats.Average != 100 || function.MemoryStats.Average != 256 {
+			t.Errorf("Function %s of the trace was modified", function.Name)
+		}
+	}
+}
+
+func TestApplyABTestErrors(t *testing.T) {
+	override := SpecOverride{RuntimeMultiplier: 2, MemoryMultiplier: 2}
+
+	tests := []struct {
+		name string
+		cfg  ABTestConfig
+	}{
+		{
+			name: "function_in_both_groups",
+			cfg:  ABTestConfig{ControlFunctions: []string{"f0", "f1"}, TreatmentFunctions: []string{"f1"}, TreatmentSpec: override},
+		},
+		{
+			name: "unknown_function",
+			cfg:  ABTestConfig{ControlFunctions: []string{"f0"}, TreatmentFunctions: []string{"f9"}, TreatmentSpec: override},
+		},
+		{
+			name: "non_positive_multiplier",
+			cfg:  ABTestConfig{ControlFunctions: []string{"f0"}, TreatmentFunctions: []string{"f1"}, TreatmentSpec: SpecOverride{RuntimeMultiplier: 2}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := ApplyABTest(createABTestFixture(), test.cfg); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// anonymizedLength is the number of hex characters kept from the HMAC of an identifier
+const anonymizedLength = 16
+
+// AnonymizeTrace returns copies of the functions in which the name and the hashes of the owner, the application,
+// and the function are replaced by their HMAC-SHA256 keyed with the salt, truncated to 16 hex characters. The
+// same identifier is always replaced by the same value, so that functions of the same owner or application remain
+// grouped. The invocation counts and the runtime and memory statistics are preserved.
+func AnonymizeTrace(functions []*common.Function, salt string) []*common.Function {
+	anonymize := func(value string) string {
+		if value == "" {
+			return ""
+		}
+
+		mac := hmac.New(sha256.New, []byte(salt))
+		mac.Write([]byte(value))
+
+		return hex.EncodeToString(mac.Sum(nil))[:anonymizedLength]
+	}
+
+	var result []*common.Function
+	for _, function := range functions {
+		anonymized := *function
+		anonymized.Name = anonymize(function.Name)
+		anonymized.HashOwner = anonymize(function.HashOwner)
+		anonymized.HashApp = anonymize(function.HashApp)
+
+		if function.InvocationStats != nil {
+			stats := *function.InvocationStats
+			stats.HashOwner, stats.HashApp, stats.HashFunction = anonymize(stats.HashOwner), anonymize(stats.HashApp), anonymize(stats.HashFunction)
+			stats.Invocations = append([]int(nil), function.InvocationStats.Invocations...)
+			anonymized.InvocationStats = &stats
+		}
+		if function.RuntimeStats != nil {
+			stats := *function.RuntimeStats
+			stats.HashOwner, stats.HashApp, stats.HashFunction = anonymize(stats.HashOwner), anonymize(stats.HashApp), anonymize(stats.HashFunction)
+			anonymized.RuntimeStats = &stats
+		}
+		if function.MemoryStats != nil {
+			stats := *function.MemoryStats
+			stats.HashOwner, stats.HashApp, stats.HashFunction = anonymize(stats.HashOwner), anonymize(stats.HashApp), anonymize(stats.HashFunction)
+			anonymized.MemoryStats = &stats
+		}
+		if function.DirigentMetadata != nil {
+			metadata := *function.DirigentMetadata
+			metadata.HashFunction = anonymize(metadata.HashFunction)
+			anonymized.DirigentMetadata = &metadata
+		}
+
+		result = append(result, &anonymized)
+	}
+
+	return result
+}
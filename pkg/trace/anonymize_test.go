@@ -0,0 +1,107 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func createAnonymizationFixture() []*common.Function {
+	var functions []*common.Function
+	for _, name := range []string{"f0", "f1"} {
+		functions = append(functions, &common.Function{
+			Name:      name,
+			HashOwner: "owner",
+			HashApp:   "app",
+			InvocationStats: &common.FunctionInvocationStats{
+				HashOwner: "owner", HashApp: "app", HashFunction: "hash-" + name, Invocations: []int{1, 2, 3},
+			},
+			RuntimeStats: &common.FunctionRuntimeStats{HashOwner: "owner", HashApp: "app", HashFunction: "hash-" + name, Average: 100, Count: 6},
+			MemoryStats:  &common.FunctionMemoryStats{HashOwner: "owner", HashApp: "app", HashFunction: "hash-" + name, Average: 256, Count: 6},
+		})
+	}
+
+	return functions
+}
+
+func TestAnonymizeTrace(t *testing.T) {
+	functions := createAnonymizationFixture()
+
+	anonymized := AnonymizeTrace(functions, "salt")
+	if len(anonymized) != len(functions) {
+		t.Fatalf("Expected %d functions, got %d", len(functions), len(anonymized))
+	}
+
+	hexPattern := regexp.MustCompile(`^[0-9a-f]{16}$`)
+	for i, function := range anonymized {
+		for _, value := range []string{function.Name, function.HashOwner, function.HashApp, function.InvocationStats.HashFunction,
+			function.RuntimeStats.HashFunction, function.MemoryStats.HashFunction} {
+			if !hexPattern.MatchString(value) {
+				t.Errorf("Expected 16 hex characters, got %q", value)
+			}
+		}
+
+		if function.Name == functions[i].Name || function.HashOwner == functions[i].HashOwner {
+			t.Errorf("Function %s is not anonymized", functions[i].Name)
+		}
+		if function.InvocationStats.HashFunction != function.RuntimeStats.HashFunction ||
+			function.RuntimeStats.HashFunction != function.MemoryStats.HashFunction {
+			t.Errorf("Statistics of function %s are no longer matched by the function hash", functions[i].Name)
+		}
+
+		if !reflect.DeepEqual(function.InvocationStats.Invocations, functions[i].InvocationStats.Invocations) ||
+			function.RuntimeStats.Average != functions[i].RuntimeStats.Average || function.MemoryStats.Average != functions[i].MemoryStats.Average {
+			t.Errorf("Statistics of function %s are not preserved", functions[i].Name)
+		}
+	}
+
+	if anonymized[0].Name == anonymized[1].Name || anonymized[0].InvocationStats.HashFunction == anonymized[1].InvocationStats.HashFunction {
+		t.Error("Different functions have the same anonymized name")
+	}
+	if anonymized[0].HashOwner != anonymized[1].HashOwner || anonymized[0].HashApp != anonymized[1].HashApp {
+		t.Error("Functions of the same owner and application are no longer grouped")
+	}
+
+	if !reflect.DeepEqual(functions, createAnonymizationFixture()) {
+		t.Error("The original functions were modified")
+	}
+}
+
+func TestAnonymizeTraceDeterministic(t *testing.T) {
+	first := AnonymizeTrace(createAnonymizationFixture(), "salt")
+	second := AnonymizeTrace(createAnonymizationFixture(), "salt")
+	if !reflect.DeepEqual(first, second) {
+		t.Error("Anonymization with the same salt is not deterministic")
+	}
+
+	otherSalt := AnonymizeTrace(createAnonymizationFixture(), "pepper")
+	if first[0].Name == otherSalt[0].Name {
+		t.Error("Anonymization does not depend on the salt")
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTraceChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invocations.csv")
+	content := []byte("HashOwner,HashApp,HashFunction,Trigger,1,2,3\nc455,b5a2,7b3c,http,1,0,4\n")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checksum, err := GenerateTraceChecksum(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checksum) != 64 {
+		t.Errorf("Expected a hex-encoded SHA-256 checksum, got %s.", checksum)
+	}
+
+	if err = VerifyTraceChecksum(path, checksum); err != nil {
+		t.Errorf("Unexpected checksum mismatch: %v", err)
+	}
+	if err = VerifyTraceChecksum(path, strings.ToUpper(checksum)); err != nil {
+		t.Errorf("Checksum comparison should be case-insensitive: %v", err)
+	}
+
+	// Corrupt a single byte
+	content[len(content)-2] = '5'
+	if err = os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = VerifyTraceChecksum(path, checksum); err == nil {
+		t.Error("Corrupted trace file was not detected.")
+	}
+}
+
+func TestTraceChecksumMissingFile(t *testing.T) {
+	if _, err := GenerateTraceChecksum(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("Expected an error for a missing trace file.")
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"fmt"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// DuplicateGroup lists the positions of the trace entries describing the same function
+type DuplicateGroup struct {
+	Name    string // HashFunction of the duplicated entries
+	Indices []int
+	// Identical is true if all the entries have the same statistics and can be safely deduplicated
+	Identical bool
+}
+
+func hashFunction(function *common.Function) string {
+	if function.InvocationStats == nil {
+		return function.Name
+	}
+
+	return function.InvocationStats.HashFunction
+}
+
+func functionTraceKey(function *common.Function) string {
+	return fmt.Sprintf("%s/%s/%s", function.HashOwner, function.HashApp, hashFunction(function))
+}
+
+func haveSameStatistics(a, b *common.Function) bool {
+	return reflect.DeepEqual(a.InvocationStats, b.InvocationStats) &&
+		reflect.DeepEqual(a.RuntimeStats, b.RuntimeStats) &&
+		reflect.DeepEqual(a.MemoryStats, b.MemoryStats)
+}
+
+// DetectDuplicates finds the functions that appear in the trace more than once, i.e., share the owner, application,
+// and function hash. The groups are ordered by the position of their first entry.
+func DetectDuplicates(functions []*common.Function) []DuplicateGroup {
+	indicesByKey := make(map[string][]int)
+	var keys []string
+
+	for i, function := range functions {
+		key := functionTraceKey(function)
+		if _, ok := indicesByKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		indicesByKey[key] = append(indicesByKey[key], i)
+	}
+
+	var result []DuplicateGroup
+	for _, key := range keys {
+		indices := indicesByKey[key]
+		if len(indices) < 2 {
+			continue
+		}
+
+		identical := true
+		for _, index := range indices[1:] {
+			if !haveSameStatistics(functions[indices[0]], functions[index]) {
+				identical = false
+				break
+			}
+		}
+
+		result = append(result, DuplicateGroup{
+			Name:      hashFunction(functions[indices[0]]),
+			Indices:   indices,
+			Identical: identical,
+		})
+	}
+
+	return result
+}
+
+// DeduplicateFunctions keeps only the first entry of each group of identical duplicates. Duplicates with conflicting
+// statistics cannot be merged and result in an error.
+func DeduplicateFunctions(functions []*common.Function) ([]*common.Function, error) {
+	duplicates := DetectDuplicates(functions)
+
+	toRemove := make(map[int]bool)
+	for _, group := range duplicates {
+		if !group.Identical {
+			return nil, fmt.Errorf("function %s appears %d times in the trace with conflicting statistics", group.Name, len(group.Indices))
+		}
+
+		log.Warnf("Function %s appears %d times in the trace, keeping only its first entry.", group.Name, len(group.Indices))
+		for _, index := range group.Indices[1:] {
+			toRemove[index] = true
+		}
+	}
+
+	var result []*common.Function
+	for i, function := range functions {
+		if !toRemove[i] {
+			result = append(result, function)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func createDuplicateTestFunction(name, hashFunction string, invocations []int) *common.Function {
+	return &common.Function{
+		Name:      name,
+		HashOwner: "owner",
+		HashApp:   "app",
+		InvocationStats: &common.FunctionInvocationStats{
+			HashOwner:    "owner",
+			HashApp:      "app",
+			HashFunction: hashFunction,
+			Invocations:  invocations,
+		},
+		RuntimeStats: &common.FunctionRuntimeStats{HashFunction: hashFunction, Average: 100, Count: 10},
+		MemoryStats:  &common.FunctionMemoryStats{HashFunction: hashFunction, Average: 128, Count: 10},
+	}
+}
+
+func TestDeduplicateIdenticalFunctions(t *testing.T) {
+	functions := []*common.Function{
+		createDuplicateTestFunction("f0", "a", []int{1, 2, 3}),
+		createDuplicateTestFunction("f1", "b", []int{4, 5, 6}),
+		createDuplicateTestFunction("f2", "a", []int{1, 2, 3}),
+		createDuplicateTestFunction("f3", "a", []int{1, 2, 3}),
+	}
+
+	duplicates := DetectDuplicates(functions)
+	if len(duplicates) != 1 {
+		t.Fatalf("Expected one duplicate group, got %v.", duplicates)
+	}
+	if duplicates[0].Name != "a" || !duplicates[0].Identical || len(duplicates[0].Indices) != 3 ||
+		duplicates[0].Indices[0] != 0 || duplicates[0].Indices[1] != 2 || duplicates[0].Indices[2] != 3 {
+		t.Errorf("Unexpected duplicate group: %+v", duplicates[0])
+	}
+
+	result, err := DeduplicateFunctions(functions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 || result[0].Name != "f0" || result[1].Name != "f1" {
+		t.Errorf("Unexpected deduplication result: %v", result)
+	}
+}
+
+func TestDeduplicateConflictingFunctions(t *testing.T) {
+	functions := []*common.Function{
+		createDuplicateTestFunction("f0", "a", []int{1, 2, 3}),
+		createDuplicateTestFunction("f1", "a", []int{1, 2, 4}),
+	}
+
+	duplicates := DetectDuplicates(functions)
+	if len(duplicates) != 1 || duplicates[0].Identical {
+		t.Fatalf("Expected one conflicting duplicate group, got %+v.", duplicates)
+	}
+
+	if _, err := DeduplicateFunctions(functions); err == nil {
+		t.Error("Expected an error for conflicting duplicates.")
+	}
+}
+
+func TestDetectDuplicatesWithoutDuplicates(t *testing.T) {
+	functions := []*common.Function{
+		createDuplicateTestFunction("f0", "a", []int{1}),
+		createDuplicateTestFunction("f1", "b", []int{1}),
+	}
+
+	if duplicates := DetectDuplicates(functions); len(duplicates) != 0 {
+		t.Errorf("Expected no duplicates, got %v.", duplicates)
+	}
+
+	result, err := DeduplicateFunctions(functions)
+	if err != nil || len(result) != 2 {
+		t.Errorf("Functions without duplicates should be left untouched (error: %v).", err)
+	}
+}
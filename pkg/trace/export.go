@@ -0,0 +1,148 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gocarina/gocsv"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+const defaultTrigger = "http"
+
+// ExportAzureTraceCSV writes the invocation counts of the functions in the format of the invocations file of the
+// Azure Functions dataset, i.e., HashOwner, HashApp, HashFunction, Trigger, followed by one column per minute
+func ExportAzureTraceCSV(functions []*common.Function, w io.Writer) error {
+	numberOfMinutes := 0
+	for _, function := range functions {
+		if function.InvocationStats != nil {
+			numberOfMinutes = common.MaxOf(numberOfMinutes, len(function.InvocationStats.Invocations))
+		}
+	}
+
+	writer := csv.NewWriter(w)
+
+	header := []string{"HashOwner", "HashApp", "HashFunction", "Trigger"}
+	for minute := 1; minute <= numberOfMinutes; minute++ {
+		header = append(header, strconv.Itoa(minute))
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, function := range functions {
+		trigger := defaultTrigger
+		var invocations []int
+		if function.InvocationStats != nil {
+			invocations = function.InvocationStats.Invocations
+			if function.InvocationStats.Trigger != "" {
+				trigger = function.InvocationStats.Trigger
+			}
+		}
+
+		row := []string{function.HashOwner, function.HashApp, hashFunction(function), trigger}
+		for minute := 0; minute < numberOfMinutes; minute++ {
+			count := 0
+			if minute < len(invocations) {
+				count = invocations[minute]
+			}
+			row = append(row, strconv.Itoa(count))
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportAzureDurationCSV writes the runtime statistics of the functions in the format of the durations file of the
+// Azure Functions dataset
+func ExportAzureDurationCSV(functions []*common.Function, w io.Writer) error {
+	var rows []common.FunctionRuntimeStats
+	for _, function := range functions {
+		if function.RuntimeStats == nil {
+			return fmt.Errorf("function %s has no runtime statistics", function.Name)
+		}
+
+		stats := *function.RuntimeStats
+		stats.HashOwner, stats.HashApp, stats.HashFunction = function.HashOwner, function.HashApp, hashFunction(function)
+		rows = append(rows, stats)
+	}
+
+	return gocsv.Marshal(&rows, w)
+}
+
+// ExportAzureMemoryCSV writes the memory statistics of the functions in the format of the memory file of the Azure
+// Functions dataset
+func ExportAzureMemoryCSV(functions []*common.Function, w io.Writer) error {
+	var rows []common.FunctionMemoryStats
+	for _, function := range functions {
+		if function.MemoryStats == nil {
+			return fmt.Errorf("function %s has no memory statistics", function.Name)
+		}
+
+		stats := *function.MemoryStats
+		stats.HashOwner, stats.HashApp, stats.HashFunction = function.HashOwner, function.HashApp, hashFunction(function)
+		rows = append(rows, stats)
+	}
+
+	return gocsv.Marshal(&rows, w)
+}
+
+// ExportAzureTrace writes the invocations, durations, and memory files to the directory, which can then be used as
+// the TracePath of the loader
+func ExportAzureTrace(functions []*common.Function, directoryPath string) error {
+	exporters := map[string]func([]*common.Function, io.Writer) error{
+		"invocations.csv": ExportAzureTraceCSV,
+		"durations.csv":   ExportAzureDurationCSV,
+		"memory.csv":      ExportAzureMemoryCSV,
+	}
+
+	for filename, export := range exporters {
+		file, err := os.Create(filepath.Join(directoryPath, filename))
+		if err != nil {
+			return err
+		}
+
+		err = export(functions, file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", filename, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestExportAzureTraceRoundTrip(t *testing.T) {
+	duration := 10
+	functions := NewAzureParser("test_data", duration).Parse("Knative")
+
+	directory := t.TempDir()
+	if err := ExportAzureTrace(functions, directory); err != nil {
+		t.Fatal(err)
+	}
+
+	exported := NewAzureParser(directory, duration).Parse("Knative")
+	if len(exported) != len(functions) {
+		t.Fatalf("Expected %d functions after the round trip, got %d.", len(functions), len(exported))
+	}
+
+	for i := range functions {
+		original, roundTrip := functions[i], exported[i]
+
+		if original.HashOwner != roundTrip.HashOwner || original.HashApp != roundTrip.HashApp ||
+			original.InvocationStats.HashFunction != roundTrip.InvocationStats.HashFunction {
+			t.Errorf("Function %d: hashes do not match after the round trip.", i)
+		}
+		if !reflect.DeepEqual(original.InvocationStats.Invocations, roundTrip.InvocationStats.Invocations) {
+			t.Errorf("Function %d: invocations do not match after the round trip.", i)
+		}
+		if !reflect.DeepEqual(original.RuntimeStats, roundTrip.RuntimeStats) {
+			t.Errorf("Function %d: runtime statistics do not match after the round trip.", i)
+		}
+		if !reflect.DeepEqual(original.MemoryStats, roundTrip.MemoryStats) {
+			t.Errorf("Function %d: memory statistics do not match after the round trip.", i)
+		}
+	}
+}
+
+func TestExportAzureTraceCSVPadsMissingMinutes(t *testing.T) {
+	functions := []*common.Function{
+		{HashOwner: "o", HashApp: "a", InvocationStats: &common.FunctionInvocationStats{HashFunction: "f1", Invocations: []int{1, 2, 3}}},
+		{HashOwner: "o", HashApp: "a", InvocationStats: &common.FunctionInvocationStats{HashFunction: "f2", Trigger: "timer", Invocations: []int{4}}},
+	}
+
+	var buffer bytes.Buffer
+	if err := ExportAzureTraceCSV(functions, &buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "HashOwner,HashApp,HashFunction,Trigger,1,2,3\n" +
+		"o,a,f1,http,1,2,3\n" +
+		"o,a,f2,timer,4,0,0\n"
+	if buffer.String() != expected {
+		t.Errorf("Unexpected CSV:\n%s", buffer.String())
+	}
+
+	if err := ExportAzureDurationCSV(functions, &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "runtime") {
+		t.Errorf("Expected an error for functions without runtime statistics, got %v.", err)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import "github.com/vhive-serverless/loader/pkg/common"
+
+// GroupByOwner groups the functions by the tenant owning them, preserving the order of the functions within a group
+func GroupByOwner(functions []*common.Function) map[string][]*common.Function {
+	result := make(map[string][]*common.Function)
+
+	for _, function := range functions {
+		result[function.HashOwner] = append(result[function.HashOwner], function)
+	}
+
+	return result
+}
+
+// GroupByApp groups the functions by the application they belong to, preserving the order of the functions within a group
+func GroupByApp(functions []*common.Function) map[string][]*common.Function {
+	result := make(map[string][]*common.Function)
+
+	for _, function := range functions {
+		result[function.HashApp] = append(result[function.HashApp], function)
+	}
+
+	return result
+}
+
+// FilterByOwner returns the functions owned by the given tenant
+func FilterByOwner(functions []*common.Function, owner string) []*common.Function {
+	var result []*common.Function
+
+	for _, function := range functions {
+		if function.HashOwner == owner {
+			result = append(result, function)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,126 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func createGroupingFixture() []*common.Function {
+	return []*common.Function{
+		{Name: "f0", HashOwner: "owner-a", HashApp: "app-a1"},
+		{Name: "f1", HashOwner: "owner-a", HashApp: "app-a1"},
+		{Name: "f2", HashOwner: "owner-a", HashApp: "app-a2"},
+		{Name: "f3", HashOwner: "owner-b", HashApp: "app-b1"},
+		{Name: "f4", HashOwner: "owner-b", HashApp: "app-b1"},
+		{Name: "f5", HashOwner: "owner-c", HashApp: "app-c1"},
+	}
+}
+
+func functionNames(functions []*common.Function) []string {
+	var names []string
+	for _, f := range functions {
+		names = append(names, f.Name)
+	}
+
+	return names
+}
+
+func equalNames(got []*common.Function, expected []string) bool {
+	names := functionNames(got)
+	if len(names) != len(expected) {
+		return false
+	}
+
+	for i := range names {
+		if names[i] != expected[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestGroupByOwner(t *testing.T) {
+	groups := GroupByOwner(createGroupingFixture())
+
+	expected := map[string][]string{
+		"owner-a": {"f0", "f1", "f2"},
+		"owner-b": {"f3", "f4"},
+		"owner-c": {"f5"},
+	}
+
+	if len(groups) != len(expected) {
+		t.Fatalf("Expected %d owners, got %d.", len(expected), len(groups))
+	}
+	for owner, names := range expected {
+		if !equalNames(groups[owner], names) {
+			t.Errorf("Owner %s has functions %v, expected %v.", owner, functionNames(groups[owner]), names)
+		}
+	}
+}
+
+func TestGroupByApp(t *testing.T) {
+	groups := GroupByApp(createGroupingFixture())
+
+	expected := map[string][]string{
+		"app-a1": {"f0", "f1"},
+		"app-a2": {"f2"},
+		"app-b1": {"f3", "f4"},
+		"app-c1": {"f5"},
+	}
+
+	if len(groups) != len(expected) {
+		t.Fatalf("Expected %d applications, got %d.", len(expected), len(groups))
+	}
+	for app, names := range expected {
+		if !equalNames(groups[app], names) {
+			t.Errorf("Application %s has functions %v, expected %v.", app, functionNames(groups[app]), names)
+		}
+	}
+}
+
+func TestFilterByOwner(t *testing.T) {
+	functions := createGroupingFixture()
+
+	if !equalNames(FilterByOwner(functions, "owner-b"), []string{"f3", "f4"}) {
+		t.Error("Unexpected functions for owner-b.")
+	}
+	if len(FilterByOwner(functions, "owner-unknown")) != 0 {
+		t.Error("No function should belong to an unknown owner.")
+	}
+}
+
+func TestParserSetsOwnerAndApp(t *testing.T) {
+	functions := NewAzureParser("test_data", 10).Parse("Knative")
+
+	if functions[0].HashOwner != "c455703077a17a9b8d0fc655d939fcc6d24d819fa9a1066b74f710c35a43cbc8" ||
+		functions[0].HashApp != "68baea05aa0c3619b6feb78c80a07e27e4e68f921d714b8125f916c3b3370bf2" {
+
+		t.Error("Owner and application hashes were not propagated to the function.")
+	}
+}
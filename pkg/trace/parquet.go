@@ -0,0 +1,162 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// ParquetFunctionRecord is a single row of a Parquet trace. Each row merges the invocation, duration and memory
+// statistics that the Azure trace splits across invocations.csv, durations.csv and memory.csv.
+type ParquetFunctionRecord struct {
+	HashOwner    string `parquet:"name=hash_owner, type=BYTE_ARRAY, convertedtype=UTF8"`
+	HashApp      string `parquet:"name=hash_app, type=BYTE_ARRAY, convertedtype=UTF8"`
+	HashFunction string `parquet:"name=hash_function, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Trigger      string `parquet:"name=trigger, type=BYTE_ARRAY, convertedtype=UTF8"`
+
+	Invocations []int32 `parquet:"name=invocations, type=INT32, repetitiontype=REPEATED"`
+
+	RuntimeAverage       float64 `parquet:"name=runtime_average, type=DOUBLE"`
+	RuntimeCount         float64 `parquet:"name=runtime_count, type=DOUBLE"`
+	RuntimeMinimum       float64 `parquet:"name=runtime_minimum, type=DOUBLE"`
+	RuntimeMaximum       float64 `parquet:"name=runtime_maximum, type=DOUBLE"`
+	RuntimePercentile0   float64 `parquet:"name=runtime_percentile_0, type=DOUBLE"`
+	RuntimePercentile1   float64 `parquet:"name=runtime_percentile_1, type=DOUBLE"`
+	RuntimePercentile25  float64 `parquet:"name=runtime_percentile_25, type=DOUBLE"`
+	RuntimePercentile50  float64 `parquet:"name=runtime_percentile_50, type=DOUBLE"`
+	RuntimePercentile75  float64 `parquet:"name=runtime_percentile_75, type=DOUBLE"`
+	RuntimePercentile99  float64 `parquet:"name=runtime_percentile_99, type=DOUBLE"`
+	RuntimePercentile100 float64 `parquet:"name=runtime_percentile_100, type=DOUBLE"`
+
+	MemoryCount         float64 `parquet:"name=memory_count, type=DOUBLE"`
+	MemoryAverage       float64 `parquet:"name=memory_average, type=DOUBLE"`
+	MemoryPercentile1   float64 `parquet:"name=memory_percentile_1, type=DOUBLE"`
+	MemoryPercentile5   float64 `parquet:"name=memory_percentile_5, type=DOUBLE"`
+	MemoryPercentile25  float64 `parquet:"name=memory_percentile_25, type=DOUBLE"`
+	MemoryPercentile50  float64 `parquet:"name=memory_percentile_50, type=DOUBLE"`
+	MemoryPercentile75  float64 `parquet:"name=memory_percentile_75, type=DOUBLE"`
+	MemoryPercentile95  float64 `parquet:"name=memory_percentile_95, type=DOUBLE"`
+	MemoryPercentile99  float64 `parquet:"name=memory_percentile_99, type=DOUBLE"`
+	MemoryPercentile100 float64 `parquet:"name=memory_percentile_100, type=DOUBLE"`
+}
+
+func (r *ParquetFunctionRecord) invocationStats() *common.FunctionInvocationStats {
+	invocations := make([]int, len(r.Invocations))
+	for i, count := range r.Invocations {
+		invocations[i] = int(count)
+	}
+
+	return &common.FunctionInvocationStats{
+		HashOwner:    r.HashOwner,
+		HashApp:      r.HashApp,
+		HashFunction: r.HashFunction,
+		Trigger:      r.Trigger,
+		Invocations:  invocations,
+	}
+}
+
+func (r *ParquetFunctionRecord) runtimeStats() *common.FunctionRuntimeStats {
+	return &common.FunctionRuntimeStats{
+		HashOwner:     r.HashOwner,
+		HashApp:       r.HashApp,
+		HashFunction:  r.HashFunction,
+		Average:       r.RuntimeAverage,
+		Count:         r.RuntimeCount,
+		Minimum:       r.RuntimeMinimum,
+		Maximum:       r.RuntimeMaximum,
+		Percentile0:   r.RuntimePercentile0,
+		Percentile1:   r.RuntimePercentile1,
+		Percentile25:  r.RuntimePercentile25,
+		Percentile50:  r.RuntimePercentile50,
+		Percentile75:  r.RuntimePercentile75,
+		Percentile99:  r.RuntimePercentile99,
+		Percentile100: r.RuntimePercentile100,
+	}
+}
+
+func (r *ParquetFunctionRecord) memoryStats() *common.FunctionMemoryStats {
+	return &common.FunctionMemoryStats{
+		HashOwner:     r.HashOwner,
+		HashApp:       r.HashApp,
+		HashFunction:  r.HashFunction,
+		Count:         r.MemoryCount,
+		Average:       r.MemoryAverage,
+		Percentile1:   r.MemoryPercentile1,
+		Percentile5:   r.MemoryPercentile5,
+		Percentile25:  r.MemoryPercentile25,
+		Percentile50:  r.MemoryPercentile50,
+		Percentile75:  r.MemoryPercentile75,
+		Percentile95:  r.MemoryPercentile95,
+		Percentile99:  r.MemoryPercentile99,
+		Percentile100: r.MemoryPercentile100,
+	}
+}
+
+// LoadParquetTrace reads a trace stored as a single Parquet file of ParquetFunctionRecord rows. Functions are named
+// the same way as by the Azure trace parser.
+func LoadParquetTrace(path string) ([]*common.Function, error) {
+	file, err := local.NewLocalFileReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Parquet trace %s - %w", path, err)
+	}
+	defer file.Close()
+
+	parquetReader, err := reader.NewParquetReader(file, new(ParquetFunctionRecord), 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Parquet schema of %s - %w", path, err)
+	}
+	defer parquetReader.ReadStop()
+
+	records := make([]ParquetFunctionRecord, parquetReader.GetNumRows())
+	if err = parquetReader.Read(&records); err != nil {
+		return nil, fmt.Errorf("failed to read Parquet trace %s - %w", path, err)
+	}
+
+	functionNameGenerator := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	var result []*common.Function
+	for i := range records {
+		record := &records[i]
+
+		result = append(result, &common.Function{
+			Name: fmt.Sprintf("%s-%d-%d", common.FunctionNamePrefix, i, functionNameGenerator.Uint64()),
+
+			HashOwner: record.HashOwner,
+			HashApp:   record.HashApp,
+
+			InvocationStats: record.invocationStats(),
+			RuntimeStats:    record.runtimeStats(),
+			MemoryStats:     record.memoryStats(),
+		})
+	}
+
+	return DeduplicateFunctions(result)
+}
@@ -0,0 +1,110 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+func TestLoadParquetTrace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.parquet")
+
+	file, err := local.NewLocalFileWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parquetWriter, err := writer.NewParquetWriter(file, new(ParquetFunctionRecord), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := []ParquetFunctionRecord{
+		{
+			HashOwner: "o0", HashApp: "a0", HashFunction: "f0", Trigger: "http",
+			Invocations:        []int32{1, 2, 3},
+			RuntimeAverage:     100,
+			RuntimePercentile0: 10, RuntimePercentile50: 90, RuntimePercentile100: 300,
+			MemoryAverage:     120,
+			MemoryPercentile1: 100, MemoryPercentile50: 120, MemoryPercentile100: 200,
+		},
+		{
+			HashOwner: "o1", HashApp: "a1", HashFunction: "f1", Trigger: "queue",
+			Invocations:    []int32{0, 5, 0},
+			RuntimeAverage: 2000,
+			MemoryAverage:  512,
+		},
+	}
+	for i := range records {
+		if err = parquetWriter.Write(records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = parquetWriter.WriteStop(); err != nil {
+		t.Fatal(err)
+	}
+	if err = file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	functions, err := LoadParquetTrace(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(functions) != len(records) {
+		t.Fatalf("Expected %d functions, got %d.", len(records), len(functions))
+	}
+
+	for i, function := range functions {
+		record := records[i]
+
+		if function.InvocationStats.HashFunction != record.HashFunction || function.InvocationStats.Trigger != record.Trigger {
+			t.Errorf("Function %d has unexpected invocation metadata: %+v.", i, function.InvocationStats)
+		}
+		if len(function.InvocationStats.Invocations) != len(record.Invocations) {
+			t.Fatalf("Function %d has %d minutes of invocations, expected %d.", i, len(function.InvocationStats.Invocations), len(record.Invocations))
+		}
+		for minute, count := range record.Invocations {
+			if function.InvocationStats.Invocations[minute] != int(count) {
+				t.Errorf("Function %d minute %d has %d invocations, expected %d.", i, minute, function.InvocationStats.Invocations[minute], count)
+			}
+		}
+		if !floatEqual(function.RuntimeStats.Average, record.RuntimeAverage) || !floatEqual(function.RuntimeStats.Percentile100, record.RuntimePercentile100) {
+			t.Errorf("Function %d has unexpected runtime statistics: %+v.", i, function.RuntimeStats)
+		}
+		if !floatEqual(function.MemoryStats.Average, record.MemoryAverage) || !floatEqual(function.MemoryStats.Percentile1, record.MemoryPercentile1) {
+			t.Errorf("Function %d has unexpected memory statistics: %+v.", i, function.MemoryStats)
+		}
+	}
+}
+
+func TestLoadParquetTraceMissingFile(t *testing.T) {
+	if _, err := LoadParquetTrace(filepath.Join(t.TempDir(), "missing.parquet")); err == nil {
+		t.Error("Expected an error for a missing Parquet trace.")
+	}
+}
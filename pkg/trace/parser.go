@@ -107,6 +107,9 @@ func (p *AzureTraceParser) extractFunctions(
 		function := &common.Function{
 			Name: fmt.Sprintf("%s-%d-%d", common.FunctionNamePrefix, i, p.functionNameGenerator.Uint64()),
 
+			HashOwner: invocationStats.HashOwner,
+			HashApp:   invocationStats.HashApp,
+
 			InvocationStats: &invocationStats,
 			RuntimeStats:    runtimeByHashFunction[invocationStats.HashFunction],
 			MemoryStats:     memoryByHashFunction[invocationStats.HashFunction],
@@ -133,7 +136,12 @@ func (p *AzureTraceParser) Parse(platform string) []*common.Function {
 	memoryTrace := parseMemoryTrace(memoryPath)
 	dirigentMetadata := parseDirigentMetadata(dirigentPath, platform)
 
-	return p.extractFunctions(invocationTrace, runtimeTrace, memoryTrace, dirigentMetadata)
+	functions, err := DeduplicateFunctions(p.extractFunctions(invocationTrace, runtimeTrace, memoryTrace, dirigentMetadata))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return functions
 }
 
 func parseInvocationTrace(traceFile string, traceDuration int) *[]common.FunctionInvocationStats {
@@ -142,17 +150,26 @@ func parseInvocationTrace(traceFile string, traceDuration int) *[]common.Functio
 	// Fit duration on (0, 1440] interval
 	traceDuration = common.MaxOf(common.MinOf(traceDuration, 1440), 1)
 
-	var result []common.FunctionInvocationStats
-
-	invocationIndices := make([][]int, traceDuration)
-	totalInvocations := make([]int, traceDuration)
-
 	csvfile, err := os.Open(traceFile)
 	if err != nil {
 		log.Fatal("Failed to open invocation CSV file.", err)
 	}
+	defer csvfile.Close()
 
-	reader := csv.NewReader(csvfile)
+	result, err := readInvocationTrace(csvfile, traceDuration, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return &result
+}
+
+// readInvocationTrace reads the first traceDuration minutes of the invocation trace, or all the minutes in the header
+// if traceDuration is not positive. If not nil, onRow is called with the number of functions read after each one.
+func readInvocationTrace(file io.Reader, traceDuration int, onRow func(rowsRead int)) ([]common.FunctionInvocationStats, error) {
+	var result []common.FunctionInvocationStats
+
+	reader := csv.NewReader(file)
 
 	rowID := -1
 	hashOwnerIndex, hashAppIndex, hashFunctionIndex, invocationColumnIndex := -1, -1, -1, -1
@@ -164,12 +181,12 @@ func parseInvocationTrace(traceFile string, traceDuration int) *[]common.Functio
 			if err == io.EOF {
 				break
 			}
-			log.Fatal(err)
+			return nil, err
 		}
 
 		if rowID == -1 {
 			// Parse header
-			for i := 0; i < 4; i++ {
+			for i := 0; i < 4 && i < len(record); i++ {
 				switch strings.ToLower(record[i]) {
 				case "hashowner":
 					hashOwnerIndex = i
@@ -183,27 +200,31 @@ func parseInvocationTrace(traceFile string, traceDuration int) *[]common.Functio
 			}
 
 			if hashOwnerIndex == -1 || hashAppIndex == -1 || hashFunctionIndex == -1 {
-				log.Fatal("Invocation trace does not contain at least one of the hashes.")
+				return nil, fmt.Errorf("invocation trace does not contain at least one of the hashes")
 			}
 
 			if invocationColumnIndex == -1 {
 				invocationColumnIndex = 3
 			}
+
+			if traceDuration <= 0 {
+				traceDuration = len(record) - invocationColumnIndex
+			}
 		} else {
 			// Parse invocations
+			if len(record) < invocationColumnIndex+traceDuration {
+				return nil, fmt.Errorf("function %d has fewer than %d minutes of invocations", rowID, traceDuration)
+			}
+
 			var invocations []int
 
 			for i := invocationColumnIndex; i < invocationColumnIndex+traceDuration; i++ {
-				minute := i - invocationColumnIndex
 				num, err := strconv.Atoi(record[i])
-				common.Check(err)
+				if err != nil {
+					return nil, fmt.Errorf("invalid invocation count of function %d - %w", rowID, err)
+				}
 
 				invocations = append(invocations, num)
-
-				for j := 0; j < num; j++ {
-					invocationIndices[minute] = append(invocationIndices[minute], rowID)
-				}
-				totalInvocations[minute] = totalInvocations[minute] + num
 			}
 
 			result = append(result, common.FunctionInvocationStats{
@@ -213,12 +234,16 @@ func parseInvocationTrace(traceFile string, traceDuration int) *[]common.Functio
 				Trigger:      record[invocationColumnIndex-1],
 				Invocations:  invocations,
 			})
+
+			if onRow != nil {
+				onRow(rowID + 1)
+			}
 		}
 
 		rowID++
 	}
 
-	return &result
+	return result, nil
 }
 
 func parseRuntimeTrace(traceFile string) *[]common.FunctionRuntimeStats {
@@ -0,0 +1,131 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gocarina/gocsv"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// progressInterval is the number of functions read between two progress reports
+const progressInterval = 1000
+
+// countLines returns the number of lines of the file, including a last line without a trailing newline
+func countLines(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	buffer := make([]byte, 64*1024)
+
+	lines := 0
+	lastByte := byte('\n')
+	for {
+		n, err := reader.Read(buffer)
+		if n > 0 {
+			lines += bytes.Count(buffer[:n], []byte{'\n'})
+			lastByte = buffer[n-1]
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	if lastByte != '\n' {
+		lines++
+	}
+
+	return lines, nil
+}
+
+func unmarshalTraceFile(path string, out interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return gocsv.UnmarshalFile(file, out)
+}
+
+// LoadAzureTraceWithProgress parses the Azure trace in the directory like AzureTraceParser, over all the minutes of
+// the invocation trace. The invocation trace is counted first to call onProgress every 1000 functions and after the
+// last one with the number of functions read so far and in total, which lets callers report the progress of loading
+// traces with millions of functions. Errors are returned instead of terminating the loader.
+func LoadAzureTraceWithProgress(path string, onProgress func(linesRead, totalLines int)) ([]*common.Function, error) {
+	invocationPath := filepath.Join(path, "invocations.csv")
+
+	lines, err := countLines(invocationPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count the functions of %s - %w", invocationPath, err)
+	}
+	totalLines := common.MaxOf(lines-1, 0) // without the header
+
+	invocationFile, err := os.Open(invocationPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open invocation trace %s - %w", invocationPath, err)
+	}
+	defer invocationFile.Close()
+
+	linesReported := 0
+	invocations, err := readInvocationTrace(invocationFile, 0, func(linesRead int) {
+		if onProgress != nil && linesRead%progressInterval == 0 {
+			onProgress(linesRead, totalLines)
+			linesReported = linesRead
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse invocation trace %s - %w", invocationPath, err)
+	}
+	if onProgress != nil && linesReported != len(invocations) {
+		onProgress(len(invocations), totalLines)
+	}
+
+	var runtime []common.FunctionRuntimeStats
+	if err = unmarshalTraceFile(filepath.Join(path, "durations.csv"), &runtime); err != nil {
+		return nil, fmt.Errorf("failed to parse duration trace - %w", err)
+	}
+
+	var memory []common.FunctionMemoryStats
+	if err = unmarshalTraceFile(filepath.Join(path, "memory.csv"), &memory); err != nil {
+		return nil, fmt.Errorf("failed to parse memory trace - %w", err)
+	}
+
+	parser := NewAzureParser(path, 0)
+	return DeduplicateFunctions(parser.extractFunctions(&invocations, &runtime, &memory, nil))
+}
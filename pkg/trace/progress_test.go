@@ -0,0 +1,145 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTraceFixture(t *testing.T, functions int) string {
+	directory := t.TempDir()
+
+	var invocations strings.Builder
+	invocations.WriteString("HashOwner,HashApp,HashFunction,Trigger,1,2,3\n")
+	for i := 0; i < functions; i++ {
+		fmt.Fprintf(&invocations, "o%d,a%d,f%d,http,%d,0,1\n", i, i, i, i%7)
+	}
+
+	files := map[string]string{
+		"invocations.csv": invocations.String(),
+		"durations.csv":   "HashOwner,HashApp,HashFunction,Average,Count,Minimum,Maximum,percentile_Average_0,percentile_Average_1,percentile_Average_25,percentile_Average_50,percentile_Average_75,percentile_Average_99,percentile_Average_100\no0,a0,f0,100.0,10.0,1.0,7.0,1.0,2.0,3.0,4.0,5.0,6.0,7.0\n",
+		"memory.csv":      "HashOwner,HashApp,HashFunction,SampleCount,AverageAllocatedMb,AverageAllocatedMb_pct1,AverageAllocatedMb_pct5,AverageAllocatedMb_pct25,AverageAllocatedMb_pct50,AverageAllocatedMb_pct75,AverageAllocatedMb_pct95,AverageAllocatedMb_pct99,AverageAllocatedMb_pct100\no0,a0,f0,10.0,120.0,95.0,96.0,97.0,98.0,99.0,100.0,101.0,102.0\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return directory
+}
+
+func TestLoadAzureTraceWithProgress(t *testing.T) {
+	directory := writeTraceFixture(t, 5000)
+
+	var reported []int
+	functions, err := LoadAzureTraceWithProgress(directory, func(linesRead, totalLines int) {
+		if totalLines != 5000 {
+			t.Errorf("Expected 5000 lines in total, got %d", totalLines)
+		}
+		reported = append(reported, linesRead)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reported) < 5 {
+		t.Errorf("Expected at least 5 progress reports, got %v", reported)
+	}
+	for i := 1; i < len(reported); i++ {
+		if reported[i] <= reported[i-1] {
+			t.Errorf("Progress is not monotonically increasing: %v", reported)
+		}
+	}
+	if reported[len(reported)-1] != 5000 {
+		t.Errorf("Expected the last report at 5000 lines, got %v", reported)
+	}
+
+	if len(functions) != 5000 {
+		t.Fatalf("Expected 5000 functions, got %d", len(functions))
+	}
+	if functions[3].InvocationStats.HashFunction != "f3" || len(functions[3].InvocationStats.Invocations) != 3 ||
+		functions[3].InvocationStats.Invocations[0] != 3 {
+
+		t.Errorf("Unexpected invocation statistics %+v", functions[3].InvocationStats)
+	}
+	if functions[0].RuntimeStats == nil || functions[0].RuntimeStats.Average != 100 ||
+		functions[0].MemoryStats == nil || functions[0].MemoryStats.Average != 120 {
+
+		t.Errorf("Expected the runtime and memory statistics of f0, got %+v and %+v", functions[0].RuntimeStats, functions[0].MemoryStats)
+	}
+}
+
+func TestLoadAzureTraceWithProgressPartialInterval(t *testing.T) {
+	directory := writeTraceFixture(t, 1500)
+
+	var reported []int
+	if _, err := LoadAzureTraceWithProgress(directory, func(linesRead, _ int) {
+		reported = append(reported, linesRead)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reported) != 2 || reported[0] != 1000 || reported[1] != 1500 {
+		t.Errorf("Expected progress at 1000 and 1500 lines, got %v", reported)
+	}
+
+	// Without callback
+	if _, err := LoadAzureTraceWithProgress(directory, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoadAzureTraceWithProgressErrors(t *testing.T) {
+	if _, err := LoadAzureTraceWithProgress(t.TempDir(), nil); err == nil {
+		t.Error("Expected an error for a missing trace.")
+	}
+
+	directory := writeTraceFixture(t, 2)
+	if err := os.WriteFile(filepath.Join(directory, "invocations.csv"), []byte("HashOwner,HashApp,HashFunction,Trigger,1\no,a,f,http,many\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAzureTraceWithProgress(directory, nil); err == nil {
+		t.Error("Expected an error for an invalid invocation count.")
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lines.csv")
+
+	for content, expected := range map[string]int{"": 0, "a\nb\n": 2, "a\nb": 2, "\n": 1} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if lines, err := countLines(path); err != nil || lines != expected {
+			t.Errorf("Expected %d lines in %q, got %d (%v)", expected, content, lines, err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+//
+// Regenerate the Go stubs with `make proto`, see faas.proto for the set up.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: pkg/workload/proto/server_logs.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ServerLogs_GetServerLogs_FullMethodName = "/faas.ServerLogs/GetServerLogs"
+)
+
+// ServerLogsClient is the client API for ServerLogs service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ServerLogsClient interface {
+	// Streams the execution trace log of the function server, oldest entries first, in chunks of JSON lines.
+	GetServerLogs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (ServerLogs_GetServerLogsClient, error)
+}
+
+type serverLogsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewServerLogsClient(cc grpc.ClientConnInterface) ServerLogsClient {
+	return &serverLogsClient{cc}
+}
+
+func (c *serverLogsClient) GetServerLogs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (ServerLogs_GetServerLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ServerLogs_ServiceDesc.Streams[0], ServerLogs_GetServerLogs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &serverLogsGetServerLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ServerLogs_GetServerLogsClient interface {
+	Recv() (*wrapperspb.BytesValue, error)
+	grpc.ClientStream
+}
+
+type serverLogsGetServerLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *serverLogsGetServerLogsClient) Recv() (*wrapperspb.BytesValue, error) {
+	m := new(wrapperspb.BytesValue)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ServerLogsServer is the server API for ServerLogs service.
+// All implementations must embed UnimplementedServerLogsServer
+// for forward compatibility
+type ServerLogsServer interface {
+	// Streams the execution trace log of the function server, oldest entries first, in chunks of JSON lines.
+	GetServerLogs(*emptypb.Empty, ServerLogs_GetServerLogsServer) error
+	mustEmbedUnimplementedServerLogsServer()
+}
+
+// UnimplementedServerLogsServer must be embedded to have forward compatible implementations.
+type UnimplementedServerLogsServer struct {
+}
+
+func (UnimplementedServerLogsServer) GetServerLogs(*emptypb.Empty, ServerLogs_GetServerLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetServerLogs not implemented")
+}
+func (UnimplementedServerLogsServer) mustEmbedUnimplementedServerLogsServer() {}
+
+// UnsafeServerLogsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ServerLogsServer will
+// result in compilation errors.
+type UnsafeServerLogsServer interface {
+	mustEmbedUnimplementedServerLogsServer()
+}
+
+func RegisterServerLogsServer(s grpc.ServiceRegistrar, srv ServerLogsServer) {
+	s.RegisterService(&ServerLogs_ServiceDesc, srv)
+}
+
+func _ServerLogs_GetServerLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ServerLogsServer).GetServerLogs(m, &serverLogsGetServerLogsServer{stream})
+}
+
+type ServerLogs_GetServerLogsServer interface {
+	Send(*wrapperspb.BytesValue) error
+	grpc.ServerStream
+}
+
+type serverLogsGetServerLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *serverLogsGetServerLogsServer) Send(m *wrapperspb.BytesValue) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ServerLogs_ServiceDesc is the grpc.ServiceDesc for ServerLogs service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ServerLogs_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "faas.ServerLogs",
+	HandlerType: (*ServerLogsServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetServerLogs",
+			Handler:       _ServerLogs_GetServerLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/workload/proto/server_logs.proto",
+}
@@ -0,0 +1,273 @@
+//
+// Regenerate the Go stubs with `make proto`, see faas.proto for the set up.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: pkg/workload/proto/streaming.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StreamingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestId         uint64 `protobuf:"varint,1,opt,name=requestId,proto3" json:"requestId,omitempty"`                 // Identifier of the invocation, unique on the stream.
+	RuntimeInMilliSec uint32 `protobuf:"varint,2,opt,name=runtimeInMilliSec,proto3" json:"runtimeInMilliSec,omitempty"` // Execution runtime [ms].
+	MemoryInMebiBytes uint32 `protobuf:"varint,3,opt,name=memoryInMebiBytes,proto3" json:"memoryInMebiBytes,omitempty"` // Request memory usage [MiB].
+}
+
+func (x *StreamingRequest) Reset() {
+	*x = StreamingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_workload_proto_streaming_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamingRequest) ProtoMessage() {}
+
+func (x *StreamingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_workload_proto_streaming_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamingRequest.ProtoReflect.Descriptor instead.
+func (*StreamingRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_workload_proto_streaming_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StreamingRequest) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *StreamingRequest) GetRuntimeInMilliSec() uint32 {
+	if x != nil {
+		return x.RuntimeInMilliSec
+	}
+	return 0
+}
+
+func (x *StreamingRequest) GetMemoryInMebiBytes() uint32 {
+	if x != nil {
+		return x.MemoryInMebiBytes
+	}
+	return 0
+}
+
+type StreamingReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestId          uint64 `protobuf:"varint,1,opt,name=requestId,proto3" json:"requestId,omitempty"`                   // Identifier of the invocation replied to.
+	Message            string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                        // Text message field.
+	DurationInMicroSec uint32 `protobuf:"varint,3,opt,name=durationInMicroSec,proto3" json:"durationInMicroSec,omitempty"` // Execution latency [µs].
+	MemoryUsageInKb    uint32 `protobuf:"varint,4,opt,name=memoryUsageInKb,proto3" json:"memoryUsageInKb,omitempty"`       // Memory usage [KB].
+}
+
+func (x *StreamingReply) Reset() {
+	*x = StreamingReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_workload_proto_streaming_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamingReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamingReply) ProtoMessage() {}
+
+func (x *StreamingReply) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_workload_proto_streaming_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamingReply.ProtoReflect.Descriptor instead.
+func (*StreamingReply) Descriptor() ([]byte, []int) {
+	return file_pkg_workload_proto_streaming_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StreamingReply) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *StreamingReply) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *StreamingReply) GetDurationInMicroSec() uint32 {
+	if x != nil {
+		return x.DurationInMicroSec
+	}
+	return 0
+}
+
+func (x *StreamingReply) GetMemoryUsageInKb() uint32 {
+	if x != nil {
+		return x.MemoryUsageInKb
+	}
+	return 0
+}
+
+var File_pkg_workload_proto_streaming_proto protoreflect.FileDescriptor
+
+var file_pkg_workload_proto_streaming_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x70, 0x6b, 0x67, 0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x66, 0x61, 0x61, 0x73, 0x22, 0x8c, 0x01, 0x0a, 0x10, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x2c, 0x0a,
+	0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x53,
+	0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d,
+	0x65, 0x49, 0x6e, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x53, 0x65, 0x63, 0x12, 0x2c, 0x0a, 0x11, 0x6d,
+	0x65, 0x6d, 0x6f, 0x72, 0x79, 0x49, 0x6e, 0x4d, 0x65, 0x62, 0x69, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x49, 0x6e,
+	0x4d, 0x65, 0x62, 0x69, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0xa2, 0x01, 0x0a, 0x0e, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1c, 0x0a, 0x09,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x2e, 0x0a, 0x12, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x6e, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x53, 0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x12, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x4d, 0x69, 0x63, 0x72,
+	0x6f, 0x53, 0x65, 0x63, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73,
+	0x61, 0x67, 0x65, 0x49, 0x6e, 0x4b, 0x62, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x6d,
+	0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x49, 0x6e, 0x4b, 0x62, 0x32, 0x58,
+	0x0a, 0x11, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x45, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x6f, 0x72, 0x12, 0x43, 0x0a, 0x0d, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x12, 0x16, 0x2e, 0x66, 0x61, 0x61, 0x73, 0x2e, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x66,
+	0x61, 0x61, 0x73, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x76, 0x68, 0x69, 0x76, 0x65, 0x2d, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x6c, 0x65, 0x73, 0x73, 0x2f, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x2f, 0x77,
+	0x6f, 0x72, 0x6b, 0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_workload_proto_streaming_proto_rawDescOnce sync.Once
+	file_pkg_workload_proto_streaming_proto_rawDescData = file_pkg_workload_proto_streaming_proto_rawDesc
+)
+
+func file_pkg_workload_proto_streaming_proto_rawDescGZIP() []byte {
+	file_pkg_workload_proto_streaming_proto_rawDescOnce.Do(func() {
+		file_pkg_workload_proto_streaming_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_workload_proto_streaming_proto_rawDescData)
+	})
+	return file_pkg_workload_proto_streaming_proto_rawDescData
+}
+
+var file_pkg_workload_proto_streaming_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pkg_workload_proto_streaming_proto_goTypes = []interface{}{
+	(*StreamingRequest)(nil), // 0: faas.StreamingRequest
+	(*StreamingReply)(nil),   // 1: faas.StreamingReply
+}
+var file_pkg_workload_proto_streaming_proto_depIdxs = []int32{
+	0, // 0: faas.StreamingExecutor.ExecuteStream:input_type -> faas.StreamingRequest
+	1, // 1: faas.StreamingExecutor.ExecuteStream:output_type -> faas.StreamingReply
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_pkg_workload_proto_streaming_proto_init() }
+func file_pkg_workload_proto_streaming_proto_init() {
+	if File_pkg_workload_proto_streaming_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_workload_proto_streaming_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_workload_proto_streaming_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamingReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_workload_proto_streaming_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_workload_proto_streaming_proto_goTypes,
+		DependencyIndexes: file_pkg_workload_proto_streaming_proto_depIdxs,
+		MessageInfos:      file_pkg_workload_proto_streaming_proto_msgTypes,
+	}.Build()
+	File_pkg_workload_proto_streaming_proto = out.File
+	file_pkg_workload_proto_streaming_proto_rawDesc = nil
+	file_pkg_workload_proto_streaming_proto_goTypes = nil
+	file_pkg_workload_proto_streaming_proto_depIdxs = nil
+}
@@ -0,0 +1,148 @@
+//
+// Regenerate the Go stubs with `make proto`, see faas.proto for the set up.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: pkg/workload/proto/streaming.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	StreamingExecutor_ExecuteStream_FullMethodName = "/faas.StreamingExecutor/ExecuteStream"
+)
+
+// StreamingExecutorClient is the client API for StreamingExecutor service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StreamingExecutorClient interface {
+	// Executes the invocations sent on the stream concurrently. The replies are sent as the invocations complete, not
+	// necessarily in order, and carry the requestId of their request.
+	ExecuteStream(ctx context.Context, opts ...grpc.CallOption) (StreamingExecutor_ExecuteStreamClient, error)
+}
+
+type streamingExecutorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStreamingExecutorClient(cc grpc.ClientConnInterface) StreamingExecutorClient {
+	return &streamingExecutorClient{cc}
+}
+
+func (c *streamingExecutorClient) ExecuteStream(ctx context.Context, opts ...grpc.CallOption) (StreamingExecutor_ExecuteStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StreamingExecutor_ServiceDesc.Streams[0], StreamingExecutor_ExecuteStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamingExecutorExecuteStreamClient{stream}
+	return x, nil
+}
+
+type StreamingExecutor_ExecuteStreamClient interface {
+	Send(*StreamingRequest) error
+	Recv() (*StreamingReply, error)
+	grpc.ClientStream
+}
+
+type streamingExecutorExecuteStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamingExecutorExecuteStreamClient) Send(m *StreamingRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamingExecutorExecuteStreamClient) Recv() (*StreamingReply, error) {
+	m := new(StreamingReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StreamingExecutorServer is the server API for StreamingExecutor service.
+// All implementations must embed UnimplementedStreamingExecutorServer
+// for forward compatibility
+type StreamingExecutorServer interface {
+	// Executes the invocations sent on the stream concurrently. The replies are sent as the invocations complete, not
+	// necessarily in order, and carry the requestId of their request.
+	ExecuteStream(StreamingExecutor_ExecuteStreamServer) error
+	mustEmbedUnimplementedStreamingExecutorServer()
+}
+
+// UnimplementedStreamingExecutorServer must be embedded to have forward compatible implementations.
+type UnimplementedStreamingExecutorServer struct {
+}
+
+func (UnimplementedStreamingExecutorServer) ExecuteStream(StreamingExecutor_ExecuteStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExecuteStream not implemented")
+}
+func (UnimplementedStreamingExecutorServer) mustEmbedUnimplementedStreamingExecutorServer() {}
+
+// UnsafeStreamingExecutorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StreamingExecutorServer will
+// result in compilation errors.
+type UnsafeStreamingExecutorServer interface {
+	mustEmbedUnimplementedStreamingExecutorServer()
+}
+
+func RegisterStreamingExecutorServer(s grpc.ServiceRegistrar, srv StreamingExecutorServer) {
+	s.RegisterService(&StreamingExecutor_ServiceDesc, srv)
+}
+
+func _StreamingExecutor_ExecuteStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamingExecutorServer).ExecuteStream(&streamingExecutorExecuteStreamServer{stream})
+}
+
+type StreamingExecutor_ExecuteStreamServer interface {
+	Send(*StreamingReply) error
+	Recv() (*StreamingRequest, error)
+	grpc.ServerStream
+}
+
+type streamingExecutorExecuteStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamingExecutorExecuteStreamServer) Send(m *StreamingReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamingExecutorExecuteStreamServer) Recv() (*StreamingRequest, error) {
+	m := new(StreamingRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StreamingExecutor_ServiceDesc is the grpc.ServiceDesc for StreamingExecutor service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StreamingExecutor_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "faas.StreamingExecutor",
+	HandlerType: (*StreamingExecutorServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExecuteStream",
+			Handler:       _StreamingExecutor_ExecuteStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/workload/proto/streaming.proto",
+}
@@ -0,0 +1,204 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package standard
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ComputeMode selects the CPU-bound operation the trace function spins on
+type ComputeMode string
+
+const (
+	ComputeSqrt   ComputeMode = "sqrt"   // sqrtsd instructions (default)
+	ComputeFib    ComputeMode = "fib"    // iterative Fibonacci
+	ComputeHash   ComputeMode = "hash"   // repeated SHA-256 of a fixed buffer
+	ComputeMatrix ComputeMode = "matrix" // in-place multiplication of square matrices
+)
+
+// DefaultMatrixSize is the dimension of the matrices of ComputeMatrix the multipliers are calibrated for
+const DefaultMatrixSize = 16
+
+// calibrationDuration is how long the units of a compute mode are run per measurement when calibrating
+const calibrationDuration = 20 * time.Millisecond
+
+// computeMultipliers are the number of compute units per millisecond of each mode, relative to the sqrt mode whose
+// multiplier is IterationsMultiplier, i.e., (time of a sqrt unit) / (time of a unit of the mode). The ratios depend on
+// the CPU, so they are measured by CalibrateComputeModes on the machine running the function.
+var computeMultipliers map[ComputeMode]float64
+
+var calibrateOnce sync.Once
+
+// calibrationRounds is the number of measurements per compute mode when calibrating, an odd number
+const calibrationRounds = 7
+
+// CalibrateComputeModes measures the time of a unit of each compute mode relative to a sqrt unit, so that all the
+// modes spin for the requested runtime wherever IterationsMultiplier is right for the sqrt mode. It takes about
+// 0.8 s and is done by the first invocation with another mode than sqrt unless called at startup.
+func CalibrateComputeModes() {
+	calibrateOnce.Do(func() {
+		computeMultipliers = map[ComputeMode]float64{
+			ComputeSqrt:   1,
+			ComputeFib:    relativeRate(fibUnit),
+			ComputeHash:   relativeRate(hashUnit),
+			ComputeMatrix: relativeRate(newMatrixWorkload(DefaultMatrixSize).unit),
+		}
+
+		log.Infof("Compute mode multipliers relative to sqrt: fib %.2f, hash %.3f, matrix %.3f", computeMultipliers[ComputeFib],
+			computeMultipliers[ComputeHash], computeMultipliers[ComputeMatrix])
+	})
+}
+
+// relativeRate returns the number of units run per sqrt unit. The rates of both are measured right after each other,
+// so that both are slowed down alike by the other processes of the machine, and the median ratio of the rounds is
+// returned.
+func relativeRate(unit func()) float64 {
+	ratios := make([]float64, calibrationRounds)
+	for round := range ratios {
+		sqrtRate := unitsPerMillisecond(func() { takeSqrts() })
+		ratios[round] = unitsPerMillisecond(unit) / sqrtRate
+	}
+
+	sort.Float64s(ratios)
+	return ratios[len(ratios)/2]
+}
+
+// unitsPerMillisecond returns the number of units run per millisecond during calibrationDuration
+func unitsPerMillisecond(unit func()) float64 {
+	const batch = 100
+
+	units := 0
+	start := time.Now()
+	for time.Since(start) < calibrationDuration {
+		for i := 0; i < batch; i++ {
+			unit()
+		}
+		units += batch
+	}
+
+	return float64(units) / (float64(time.Since(start)) / float64(time.Millisecond))
+}
+
+// sink prevents the compiler from eliminating the computations as dead code
+var sink uint64
+
+func fibUnit() {
+	a, b := uint64(0), uint64(1)
+	for i := 0; i < EXEC_UNIT; i++ {
+		a, b = b, a+b
+	}
+	sink += a
+}
+
+var hashBuffer = make([]byte, 1024)
+
+func hashUnit() {
+	digest := sha256.Sum256(hashBuffer)
+	copy(hashBuffer, digest[:]) // chain the digests
+	sink += uint64(digest[0])
+}
+
+type matrixWorkload struct {
+	size    int
+	a, b, c []float64
+}
+
+func newMatrixWorkload(size int) *matrixWorkload {
+	m := &matrixWorkload{
+		size: size,
+		a:    make([]float64, size*size),
+		b:    make([]float64, size*size),
+		c:    make([]float64, size*size),
+	}
+	for i := range m.a {
+		m.a[i] = float64(i%7) / 7
+		m.b[i] = float64(i%5) / 5
+	}
+
+	return m
+}
+
+// unit computes c = a * b and copies the result into a, keeping the values bounded
+func (m *matrixWorkload) unit() {
+	n := m.size
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			sum := 0.0
+			for k := 0; k < n; k++ {
+				sum += m.a[i*n+k] * m.b[k*n+j]
+			}
+			m.c[i*n+j] = sum / float64(n)
+		}
+	}
+	copy(m.a, m.c)
+}
+
+// computeUnit returns the unit of work of the mode and the number of units per millisecond
+func computeUnit(mode ComputeMode, matrixSize int) (func(), float64, error) {
+	switch mode {
+	case "", ComputeSqrt:
+		return func() { takeSqrts() }, float64(IterationsMultiplier), nil
+	case ComputeFib:
+		return fibUnit, calibratedMultiplier(ComputeFib), nil
+	case ComputeHash:
+		return hashUnit, calibratedMultiplier(ComputeHash), nil
+	case ComputeMatrix:
+		if matrixSize <= 0 {
+			matrixSize = DefaultMatrixSize
+		}
+		// The cost of a multiplication grows with the cube of the matrix size
+		scale := float64(DefaultMatrixSize*DefaultMatrixSize*DefaultMatrixSize) / float64(matrixSize*matrixSize*matrixSize)
+		return newMatrixWorkload(matrixSize).unit, calibratedMultiplier(ComputeMatrix) * scale, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported compute mode %q", mode)
+	}
+}
+
+// calibratedMultiplier returns the number of units of the mode per millisecond, calibrating the modes if not done yet
+func calibratedMultiplier(mode ComputeMode) float64 {
+	CalibrateComputeModes()
+	return float64(IterationsMultiplier) * computeMultipliers[mode]
+}
+
+// busySpinWithMode spins for runtimeMilli milliseconds on the operation of the compute mode
+func busySpinWithMode(runtimeMilli uint32, mode ComputeMode, matrixSize int) error {
+	unit, multiplier, err := computeUnit(mode, matrixSize)
+	if err != nil {
+		return err
+	}
+
+	totalIterations := int(multiplier * float64(runtimeMilli))
+	for i := 0; i < totalIterations; i++ {
+		unit()
+	}
+
+	return nil
+}
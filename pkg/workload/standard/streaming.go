@@ -0,0 +1,82 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package standard
+
+import (
+	"io"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
+)
+
+// streamingServer executes the invocations multiplexed on a bidirectional stream by the StreamingInvoker of the
+// loader, each in its own goroutine like the unary RPCs of the executor
+type streamingServer struct {
+	proto.UnimplementedStreamingExecutorServer
+	executor *funcServer
+}
+
+func (s *streamingServer) ExecuteStream(stream proto.StreamingExecutor_ExecuteStreamServer) error {
+	sendMutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	// Sending on the stream is not allowed once the handler has returned
+	defer wg.Wait()
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			reply, err := s.executor.Execute(stream.Context(), &proto.FaasRequest{
+				RuntimeInMilliSec: req.RuntimeInMilliSec,
+				MemoryInMebiBytes: req.MemoryInMebiBytes,
+			})
+			if err != nil {
+				log.Warnf("Failed to execute the streamed invocation %d - %v", req.RequestId, err)
+				return
+			}
+
+			sendMutex.Lock()
+			defer sendMutex.Unlock()
+
+			if err = stream.Send(&proto.StreamingReply{
+				RequestId:          req.RequestId,
+				Message:            reply.Message,
+				DurationInMicroSec: reply.DurationInMicroSec,
+				MemoryUsageInKb:    reply.MemoryUsageInKb,
+			}); err != nil {
+				log.Debugf("Failed to reply to the streamed invocation %d - %v", req.RequestId, err)
+			}
+		}()
+	}
+}
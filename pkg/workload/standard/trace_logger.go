@@ -0,0 +1,186 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package standard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	TraceLogMaxSizeMB  = 100
+	TraceLogMaxBackups = 3
+
+	// serverLogsChunkSize is the size of the messages GetServerLogs streams the log in
+	serverLogsChunkSize = 32 * 1024
+)
+
+// RequestIDMetadataKey is the gRPC metadata key under which clients may pass the ID of the invocation to be logged
+const RequestIDMetadataKey = "x-request-id"
+
+// TraceLogEntry describes an invocation executed by the function server
+type TraceLogEntry struct {
+	RequestID       string `json:"requestID"`
+	StartTimeUnixUs int64  `json:"startTimeUnixUs"`
+	EndTimeUnixUs   int64  `json:"endTimeUnixUs"`
+	RuntimeMs       uint32 `json:"runtimeMs"`
+	MemoryMib       uint32 `json:"memoryMib"`
+	NodeID          string `json:"nodeID"`
+}
+
+// TraceLogger writes one JSON line per executed invocation to a log file rotated once it exceeds
+// TraceLogMaxSizeMB, keeping TraceLogMaxBackups rotated files. Safe for concurrent use.
+type TraceLogger struct {
+	mutex  sync.Mutex
+	writer *lumberjack.Logger
+	nodeID string
+	nextID atomic.Int64
+}
+
+func NewTraceLogger(path string) *TraceLogger {
+	// In Kubernetes, the name of the node is set through the downward API
+	nodeID := os.Getenv("NODE_NAME")
+	if nodeID == "" {
+		nodeID = hostname
+	}
+
+	return &TraceLogger{
+		writer: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    TraceLogMaxSizeMB,
+			MaxBackups: TraceLogMaxBackups,
+		},
+		nodeID: nodeID,
+	}
+}
+
+// Log records the invocation executed between start and end. Invocations without a request ID from the loader are
+// numbered in the order they were logged.
+func (l *TraceLogger) Log(requestID string, start time.Time, end time.Time, runtimeMs uint32, memoryMib uint32) error {
+	if requestID == "" {
+		requestID = fmt.Sprintf("%s-%d", l.nodeID, l.nextID.Add(1))
+	}
+
+	line, err := json.Marshal(TraceLogEntry{
+		RequestID:       requestID,
+		StartTimeUnixUs: start.UnixMicro(),
+		EndTimeUnixUs:   end.UnixMicro(),
+		RuntimeMs:       runtimeMs,
+		MemoryMib:       memoryMib,
+		NodeID:          l.nodeID,
+	})
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	_, err = l.writer.Write(append(line, '\n'))
+	return err
+}
+
+// Files returns the rotated log files, oldest first, followed by the current one
+func (l *TraceLogger) Files() ([]string, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// lumberjack names the rotated files <name>-<timestamp><ext>, which sort chronologically
+	extension := filepath.Ext(l.writer.Filename)
+	prefix := strings.TrimSuffix(l.writer.Filename, extension) + "-"
+	backups, err := filepath.Glob(prefix + "*" + extension)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(backups)
+
+	return append(backups, l.writer.Filename), nil
+}
+
+func (l *TraceLogger) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.writer.Close()
+}
+
+type serverLogsServer struct {
+	proto.UnimplementedServerLogsServer
+	logger *TraceLogger
+}
+
+func (s *serverLogsServer) GetServerLogs(_ *emptypb.Empty, stream proto.ServerLogs_GetServerLogsServer) error {
+	files, err := s.logger.Files()
+	if err != nil {
+		return err
+	}
+
+	buffer := make([]byte, serverLogsChunkSize)
+	for _, path := range files {
+		if err = streamFile(path, buffer, stream); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func streamFile(path string, buffer []byte, stream proto.ServerLogs_GetServerLogsServer) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		// Nothing has been logged yet, or the file was rotated out in the meantime
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for {
+		n, err := file.Read(buffer)
+		if n > 0 {
+			if sendErr := stream.Send(wrapperspb.Bytes(buffer[:n])); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
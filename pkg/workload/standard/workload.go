@@ -39,6 +39,10 @@ import (
 	"github.com/vhive-serverless/loader/pkg/workload/proto"
 	tracing "github.com/vhive-serverless/vSwarm/utils/tracing/go"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -61,6 +65,40 @@ var hostname string
 var IterationsMultiplier int
 var serverSideCode FunctionType
 
+// ServerKeepalive configures the keepalive of the connections accepted by StartGRPCServer
+var ServerKeepalive ServerKeepaliveConfig
+
+// ServerKeepaliveConfig bounds the lifetime of the client connections. Zero values keep the gRPC defaults.
+type ServerKeepaliveConfig struct {
+	MaxConnectionIdle time.Duration // close connections without any RPC for this long
+	MaxConnectionAge  time.Duration // close connections older than this, forcing clients to reconnect
+
+	// MinClientPingInterval is the shortest interval between keepalive pings the server tolerates from a client,
+	// also without active RPCs. The server closes the connection of clients pinging more frequently, which by
+	// default is more than once every 5 minutes.
+	MinClientPingInterval time.Duration
+}
+
+// ServerOptions returns the gRPC server options applying the keepalive configuration
+func (c ServerKeepaliveConfig) ServerOptions() []grpc.ServerOption {
+	var opts []grpc.ServerOption
+
+	if c.MaxConnectionIdle > 0 || c.MaxConnectionAge > 0 {
+		opts = append(opts, grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle: c.MaxConnectionIdle,
+			MaxConnectionAge:  c.MaxConnectionAge,
+		}))
+	}
+	if c.MinClientPingInterval > 0 {
+		opts = append(opts, grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             c.MinClientPingInterval,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	return opts
+}
+
 type FunctionType int
 
 const (
@@ -78,6 +116,7 @@ func takeSqrts() C.double {
 
 type funcServer struct {
 	proto.UnimplementedExecutorServer
+	traceLogger *TraceLogger // nil if the invocations are not logged
 }
 
 func busySpin(runtimeMilli uint32) {
@@ -102,7 +141,27 @@ func TraceFunctionExecution(start time.Time, timeLeftMilliseconds uint32) (msg s
 	return msg
 }
 
-func (s *funcServer) Execute(_ context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
+// TraceFunctionExecutionWithMode is TraceFunctionExecution spinning on the operation of the given compute mode
+func TraceFunctionExecutionWithMode(start time.Time, timeLeftMilliseconds uint32, mode ComputeMode, matrixSize int) (string, error) {
+	if _, _, err := computeUnit(mode, matrixSize); err != nil {
+		return "", err
+	}
+
+	var msg string
+	timeConsumedMilliseconds := uint32(time.Since(start).Milliseconds())
+	if timeConsumedMilliseconds < timeLeftMilliseconds {
+		timeLeftMilliseconds -= timeConsumedMilliseconds
+		if err := busySpinWithMode(timeLeftMilliseconds, mode, matrixSize); err != nil {
+			return "", err
+		}
+
+		msg = fmt.Sprintf("OK - %s", hostname)
+	}
+
+	return msg, nil
+}
+
+func (s *funcServer) Execute(ctx context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
 	var msg string
 	start := time.Now()
 
@@ -125,13 +184,31 @@ func (s *funcServer) Execute(_ context.Context, req *proto.FaasRequest) (*proto.
 		msg = fmt.Sprintf("OK - EMPTY - %s", hostname)
 	}
 
+	end := time.Now()
+	if s.traceLogger != nil {
+		if err := s.traceLogger.Log(requestID(ctx), start, end, req.RuntimeInMilliSec, req.MemoryInMebiBytes); err != nil {
+			log.Warnf("Failed to log the invocation: %v", err)
+		}
+	}
+
 	return &proto.FaasReply{
 		Message:            msg,
-		DurationInMicroSec: uint32(time.Since(start).Microseconds()),
+		DurationInMicroSec: uint32(end.Sub(start).Microseconds()),
 		MemoryUsageInKb:    req.MemoryInMebiBytes * 1024,
 	}, nil
 }
 
+// requestID returns the invocation ID passed by the loader in the metadata of the call, if any
+func requestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDMetadataKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
+
 func readEnvironmentalVariables() {
 	if _, ok := os.LookupEnv("ITERATIONS_MULTIPLIER"); ok {
 		IterationsMultiplier, _ = strconv.Atoi(os.Getenv("ITERATIONS_MULTIPLIER"))
@@ -142,6 +219,21 @@ func readEnvironmentalVariables() {
 
 	log.Infof("ITERATIONS_MULTIPLIER = %d\n", IterationsMultiplier)
 
+	for variable, field := range map[string]*time.Duration{
+		"GRPC_MAX_CONNECTION_IDLE":      &ServerKeepalive.MaxConnectionIdle,
+		"GRPC_MAX_CONNECTION_AGE":       &ServerKeepalive.MaxConnectionAge,
+		"GRPC_MIN_CLIENT_PING_INTERVAL": &ServerKeepalive.MinClientPingInterval,
+	} {
+		if value, ok := os.LookupEnv(variable); ok {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				log.Warnf("Ignoring invalid %s %q - %v", variable, value, err)
+				continue
+			}
+			*field = duration
+		}
+	}
+
 	var err error
 	hostname, err = os.Hostname()
 	if err != nil {
@@ -150,7 +242,18 @@ func readEnvironmentalVariables() {
 	}
 }
 
-func StartGRPCServer(serverAddress string, serverPort int, functionType FunctionType, zipkinUrl string) {
+// RegisterHealthServer registers the standard grpc.health.v1 service, reporting SERVING for the server as a whole
+// and for the executor service. Calling Shutdown on the returned server switches both to NOT_SERVING.
+func RegisterHealthServer(grpcServer *grpc.Server) *health.Server {
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(proto.Executor_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	return healthServer
+}
+
+// StartGRPCServer serves the function, logging the executed invocations to traceLogPath unless empty
+func StartGRPCServer(serverAddress string, serverPort int, functionType FunctionType, zipkinUrl string, traceLogPath string) {
 	readEnvironmentalVariables()
 	serverSideCode = functionType
 
@@ -168,12 +271,25 @@ func StartGRPCServer(serverAddress string, serverPort int, functionType Function
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	var grpcServer *grpc.Server
+	serverOptions := ServerKeepalive.ServerOptions()
 	if tracing.IsTracingEnabled() {
-		grpcServer = tracing.GetGRPCServerWithUnaryInterceptor()
-	} else {
-		grpcServer = grpc.NewServer()
+		serverOptions = append(serverOptions, tracing.GetServerInterceptor())
 	}
+	grpcServer := grpc.NewServer(serverOptions...)
+
+	reflection.Register(grpcServer) // gRPC Server Reflection is used by gRPC CLI
+
+	server := &funcServer{}
+	if traceLogPath != "" {
+		log.Infof("Logging the invocations to %s", traceLogPath)
+		server.traceLogger = NewTraceLogger(traceLogPath)
+		defer server.traceLogger.Close()
+
+		proto.RegisterServerLogsServer(grpcServer, &serverLogsServer{logger: server.traceLogger})
+	}
+	proto.RegisterExecutorServer(grpcServer, server)
+	proto.RegisterStreamingExecutorServer(grpcServer, &streamingServer{executor: server})
+	healthServer := RegisterHealthServer(grpcServer)
 
 	sigc := make(chan os.Signal, 1)
 	signal.Notify(sigc, syscall.SIGTERM)
@@ -181,11 +297,11 @@ func StartGRPCServer(serverAddress string, serverPort int, functionType Function
 	go func() {
 		<-sigc
 		log.Info("Received SIGTERM, shutting down gracefully...")
+		// Fail the health checks first so that no new invocations are routed to the draining server
+		healthServer.Shutdown()
 		grpcServer.GracefulStop()
 	}()
 
-	reflection.Register(grpcServer) // gRPC Server Reflection is used by gRPC CLI
-	proto.RegisterExecutorServer(grpcServer, &funcServer{})
 	err = grpcServer.Serve(lis)
 	util.Check(err)
 }
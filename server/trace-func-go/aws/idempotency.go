@@ -0,0 +1,147 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+const (
+	idempotencyKeyAttribute      = "IdempotencyKey"
+	idempotencyResponseAttribute = "Response"
+	// idempotencyExpiryAttribute holds the epoch second at which the item expires, and should be configured as
+	// the TTL attribute of the table so that DynamoDB eventually deletes the expired items
+	idempotencyExpiryAttribute = "ExpiresAt"
+
+	// idempotencyClaimTimeout bounds the claim of an invocation without deadline, the maximum Lambda timeout
+	idempotencyClaimTimeout = 15 * time.Minute
+	// idempotencyRecordTTL is how long the responses are replayed to the retries
+	idempotencyRecordTTL = 24 * time.Hour
+)
+
+// errInvocationInProgress is returned for a duplicate of an invocation that has not completed yet
+var errInvocationInProgress = errors.New("an invocation with the same idempotency key is in progress")
+
+// dynamoDBAPI is the subset of the DynamoDB client used to deduplicate the invocations
+type dynamoDBAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+}
+
+// dynamoDBClient is created on the first idempotent invocation and reused by the subsequent (warm) invocations
+var dynamoDBClient dynamoDBAPI
+
+// newDynamoDBClient creates a DynamoDB client from the Lambda environment. The endpoint can be overridden through
+// the AWS_ENDPOINT_URL_DYNAMODB environment variable (e.g. for DynamoDB local).
+func newDynamoDBClient(ctx context.Context) (dynamoDBAPI, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamodb.NewFromConfig(cfg), nil
+}
+
+// idempotencyTable returns the table configured for the function, empty if the invocations are not idempotent. It
+// is never taken from the request, which any caller of the function URL can forge.
+func idempotencyTable() string {
+	return os.Getenv(common.AwsIdempotencyTableEnvironmentVariable)
+}
+
+// claimIdempotencyKey records the key with a conditional write that only succeeds for the first invocation with
+// that key. Duplicates get the response cached by the first invocation, or errInvocationInProgress if it is
+// still running. A claim expires with the deadline of the invocation that made it, after which the invocation
+// can no longer complete and a retry steals the claim; the cached responses expire after idempotencyRecordTTL.
+func claimIdempotencyKey(ctx context.Context, client dynamoDBAPI, table string, key string) (claimed bool, cachedResponse string, err error) {
+	now := time.Now()
+	claimExpiry, ok := ctx.Deadline()
+	if !ok {
+		claimExpiry = now.Add(idempotencyClaimTimeout)
+	}
+
+	_, err = client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(table),
+		Item: map[string]types.AttributeValue{
+			idempotencyKeyAttribute:    &types.AttributeValueMemberS{Value: key},
+			idempotencyExpiryAttribute: epochSecondAttribute(claimExpiry),
+		},
+		ConditionExpression:       aws.String("attribute_not_exists(#key) OR #expiry < :now"),
+		ExpressionAttributeNames:  map[string]string{"#key": idempotencyKeyAttribute, "#expiry": idempotencyExpiryAttribute},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":now": epochSecondAttribute(now)},
+	})
+	if err == nil {
+		return true, "", nil
+	}
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if !errors.As(err, &conditionFailed) {
+		return false, "", err
+	}
+
+	output, err := client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(table),
+		Key:            map[string]types.AttributeValue{idempotencyKeyAttribute: &types.AttributeValueMemberS{Value: key}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return false, "", err
+	}
+
+	response, ok := output.Item[idempotencyResponseAttribute].(*types.AttributeValueMemberS)
+	if !ok {
+		return false, "", errInvocationInProgress
+	}
+
+	return false, response.Value, nil
+}
+
+// storeIdempotentResponse caches the response of the invocation that claimed the key
+func storeIdempotentResponse(ctx context.Context, client dynamoDBAPI, table string, key string, response string) error {
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(table),
+		Item: map[string]types.AttributeValue{
+			idempotencyKeyAttribute:      &types.AttributeValueMemberS{Value: key},
+			idempotencyResponseAttribute: &types.AttributeValueMemberS{Value: response},
+			idempotencyExpiryAttribute:   epochSecondAttribute(time.Now().Add(idempotencyRecordTTL)),
+		},
+	})
+
+	return err
+}
+
+// epochSecondAttribute encodes the time in the format of the DynamoDB TTL attributes
+func epochSecondAttribute(t time.Time) *types.AttributeValueMemberN {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.Unix(), 10)}
+}
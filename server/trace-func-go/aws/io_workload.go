@@ -0,0 +1,102 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// s3API is the subset of the S3 client used by the I/O workloads
+type s3API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// newS3Client creates an S3 client from the Lambda environment. The endpoint can be overridden through the
+// AWS_ENDPOINT_URL_S3 environment variable (e.g. for localstack).
+func newS3Client(ctx context.Context) (s3API, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		// Path-style addressing is required by S3-compatible endpoints such as localstack
+		o.UsePathStyle = o.BaseEndpoint != nil
+	}), nil
+}
+
+// ioWorkloadObjectKey returns the key of the object read/written by the workload. Reads expect an object
+// previously created by a write of the same size.
+func ioWorkloadObjectKey(workload *common.IOWorkload) string {
+	return fmt.Sprintf("loader-io-workload-%dkb", workload.SizeKB)
+}
+
+// performIOWorkload executes the I/O operation and returns its latency
+func performIOWorkload(ctx context.Context, client s3API, workload *common.IOWorkload) (time.Duration, error) {
+	if err := workload.Validate(); err != nil {
+		return 0, err
+	}
+
+	key := ioWorkloadObjectKey(workload)
+	start := time.Now()
+
+	switch workload.Type {
+	case common.IOWorkloadS3Read:
+		output, err := client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(workload.Bucket),
+			Key:    aws.String(key),
+			Range:  aws.String(fmt.Sprintf("bytes=0-%d", workload.SizeKB*1024-1)),
+		})
+		if err != nil {
+			return 0, err
+		}
+		defer output.Body.Close()
+
+		if _, err = io.Copy(io.Discard, output.Body); err != nil {
+			return 0, err
+		}
+	case common.IOWorkloadS3Write:
+		_, err := client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(workload.Bucket),
+			Key:    aws.String(key),
+			Body:   bytes.NewReader(make([]byte, workload.SizeKB*1024)),
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return time.Since(start), nil
+}
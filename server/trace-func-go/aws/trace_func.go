@@ -28,12 +28,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-xray-sdk-go/strategy/ctxmissing"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/workload/standard"
+	"strings"
 	"time"
 )
 
+// s3Client is created on the first I/O workload and reused by the subsequent (warm) invocations
+var s3Client s3API
+
 // Response is of type APIGatewayProxyResponse since we're leveraging the
 // AWS Lambda Proxy Request functionality (default behavior)
 //
@@ -41,15 +50,19 @@ import (
 type Response events.APIGatewayProxyResponse
 
 // Handler is our lambda handler invoked by the `lambda.Start` function call
-func Handler(_ context.Context, event events.LambdaFunctionURLRequest) (Response, error) {
+func Handler(ctx context.Context, event events.LambdaFunctionURLRequest) (Response, error) {
 	var buf bytes.Buffer
 
 	start := time.Now()
 
 	// Obtain payload from the request
 	var req struct {
-		RuntimeInMilliSec uint32 `json:"RuntimeInMilliSec"`
-		MemoryInMebiBytes uint32 `json:"MemoryInMebiBytes"`
+		RuntimeInMilliSec uint32             `json:"RuntimeInMilliSec"`
+		MemoryInMebiBytes uint32             `json:"MemoryInMebiBytes"`
+		IOWorkload        *common.IOWorkload `json:"IOWorkload,omitempty"`
+		ComputeMode       string             `json:"ComputeMode,omitempty"`
+		MatrixSize        int                `json:"MatrixSize,omitempty"` // matrix compute mode only
+		IdempotencyKey    string             `json:"IdempotencyKey,omitempty"`
 	}
 
 	err := json.Unmarshal([]byte(event.Body), &req)
@@ -57,31 +70,121 @@ func Handler(_ context.Context, event events.LambdaFunctionURLRequest) (Response
 		return Response{StatusCode: 400}, err
 	}
 
-	standard.IterationsMultiplier = 102 // Cloudlab xl170 benchmark @ 1 second function execution time
-	_ = standard.TraceFunctionExecution(start, req.RuntimeInMilliSec)
+	if metadata := invitroMetadata(event.Headers); len(metadata) > 0 {
+		log.WithFields(metadata).Info("Invocation metadata")
+	}
+
+	table := idempotencyTable()
+	idempotent := req.IdempotencyKey != "" && table != ""
+	if idempotent {
+		if dynamoDBClient == nil {
+			if dynamoDBClient, err = newDynamoDBClient(ctx); err != nil {
+				return Response{StatusCode: 500}, err
+			}
+		}
 
-	body, err := json.Marshal(map[string]interface{}{
-		"DurationInMicroSec": uint32(time.Since(start).Microseconds()),
-		"MemoryUsageInKb":    req.MemoryInMebiBytes * 1024,
+		claimed, cachedBody, err := claimIdempotencyKey(ctx, dynamoDBClient, table, req.IdempotencyKey)
+		if errors.Is(err, errInvocationInProgress) {
+			return Response{StatusCode: 409}, err
+		} else if err != nil {
+			return Response{StatusCode: 500}, err
+		}
+
+		if !claimed {
+			return newResponse(cachedBody, true), nil
+		}
+	}
+
+	standard.IterationsMultiplier = 102 // Cloudlab xl170 benchmark @ 1 second function execution time
+	// Recorded as a subsegment of the invocation if X-Ray tracing is enabled for the function
+	err = xray.Capture(ctx, "TraceFunctionExecution", func(context.Context) error {
+		_, err := standard.TraceFunctionExecutionWithMode(start, req.RuntimeInMilliSec, standard.ComputeMode(req.ComputeMode), req.MatrixSize)
+		return err
 	})
 	if err != nil {
 		return Response{StatusCode: 400}, err
 	}
+
+	response := map[string]interface{}{
+		"MemoryUsageInKb": req.MemoryInMebiBytes * 1024,
+		"XRayTraceID":     xray.TraceID(ctx),
+	}
+
+	if req.IOWorkload != nil {
+		if s3Client == nil {
+			if s3Client, err = newS3Client(ctx); err != nil {
+				return Response{StatusCode: 500}, err
+			}
+		}
+
+		ioLatency, err := performIOWorkload(ctx, s3Client, req.IOWorkload)
+		if err != nil {
+			return Response{StatusCode: 500}, err
+		}
+		response["IOLatencyMicroSec"] = uint32(ioLatency.Microseconds())
+	}
+
+	// The duration includes the latency of the I/O workload
+	response["DurationInMicroSec"] = uint32(time.Since(start).Microseconds())
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		return Response{StatusCode: 400}, err
+	}
 	json.HTMLEscape(&buf, body)
 
+	if idempotent {
+		if err = storeIdempotentResponse(ctx, dynamoDBClient, table, req.IdempotencyKey, buf.String()); err != nil {
+			return Response{StatusCode: 500}, err
+		}
+	}
+
+	return newResponse(buf.String(), false), nil
+}
+
+// invitroMetadata returns the values of the X-Invitro- headers set by the loader, keyed by the lower-case header name
+// without the prefix, e.g. "minute". Function URLs pass the header names in lower case.
+func invitroMetadata(headers map[string]string) log.Fields {
+	prefix := strings.ToLower(common.InvitroHeaderPrefix)
+
+	metadata := log.Fields{}
+	for name, value := range headers {
+		if lowerName := strings.ToLower(name); strings.HasPrefix(lowerName, prefix) {
+			metadata[strings.TrimPrefix(lowerName, prefix)] = value
+		}
+	}
+
+	return metadata
+}
+
+// newResponse wraps the JSON body of the trace function. Replayed responses were cached by an earlier invocation
+// with the same idempotency key.
+func newResponse(body string, replayed bool) Response {
 	resp := Response{
 		StatusCode:      200,
 		IsBase64Encoded: false,
-		Body:            buf.String(),
+		Body:            body,
 		Headers: map[string]string{
 			"Content-Type":           "application/json",
 			"X-MyCompany-Func-Reply": "trace_func_go handler",
 		},
 	}
+	if replayed {
+		resp.Headers["X-Idempotent-Replay"] = "true"
+	}
 
-	return resp, nil
+	return resp
 }
 
 func main() {
-	lambda.Start(Handler) // Uses HTTP server under the hood
+	// Functions deployed without X-Ray tracing have no segment to attach subsegments to
+	if err := xray.Configure(xray.Config{ContextMissingStrategy: ctxmissing.NewDefaultIgnoreErrorStrategy()}); err != nil {
+		panic(err)
+	}
+	// Structured logs can be queried in CloudWatch Logs Insights
+	log.SetFormatter(&log.JSONFormatter{})
+	// In the init phase, so that the first invocation with a compute mode is not delayed
+	standard.CalibrateComputeModes()
+
+	lambda.Start(selectHandler()) // Uses HTTP server under the hood
 }
@@ -0,0 +1,163 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+const pageSize = 4096
+
+// maxMemoryStripes bounds the number of goroutines allocating memory in the striped mode
+const maxMemoryStripes = 8
+
+// simulateMemory allocates the given amount of memory and touches every page so that it is backed by physical memory
+func simulateMemory(mebiBytes uint32) []byte {
+	// Not using common.Mib2b as it overflows for allocations of 4 GiB and more
+	return touchPages(int(mebiBytes) * 1024 * 1024)
+}
+
+// touchPages allocates the given number of bytes and touches every page
+func touchPages(bytes int) []byte {
+	memory := make([]byte, bytes)
+	for i := 0; i < len(memory); i += pageSize {
+		memory[i] = 1
+	}
+
+	return memory
+}
+
+// simulateStripedMemory allocates the given amount of memory split over min(8, GOMAXPROCS) goroutines that start
+// allocating simultaneously, as a multithreaded function would. Returns the stripes and the number of goroutines
+// that allocated memory.
+func simulateStripedMemory(mebiBytes uint32) ([][]byte, int) {
+	numStripes := min(maxMemoryStripes, runtime.GOMAXPROCS(0))
+	total := int(mebiBytes) * 1024 * 1024
+	stripes := make([][]byte, numStripes)
+
+	var allocating atomic.Int32
+	start := make(chan struct{})
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < numStripes; i++ {
+		size := total / numStripes
+		if i == numStripes-1 {
+			size += total % numStripes
+		}
+
+		wg.Add(1)
+		go func(i int, size int) {
+			defer wg.Done()
+
+			<-start
+			allocating.Add(1)
+			stripes[i] = touchPages(size)
+		}(i, size)
+	}
+	close(start)
+	wg.Wait()
+
+	return stripes, int(allocating.Load())
+}
+
+// MemoryHandler only allocates memory and holds it for the requested time without spinning the CPU, which allows
+// characterizing the memory overhead of a function independently of its CPU usage
+func MemoryHandler(_ context.Context, event events.LambdaFunctionURLRequest) (Response, error) {
+	var buf bytes.Buffer
+
+	start := time.Now()
+
+	var req struct {
+		MemoryInMebiBytes uint32 `json:"MemoryInMebiBytes"`
+		HoldDurationMs    uint32 `json:"HoldDurationMs"`
+		MemoryAllocMode   string `json:"MemoryAllocMode"`
+	}
+
+	err := json.Unmarshal([]byte(event.Body), &req)
+	if err != nil {
+		return Response{StatusCode: 400}, err
+	}
+
+	var memory [][]byte
+	goroutines := 1
+	switch req.MemoryAllocMode {
+	case "", common.MemoryAllocSingleGoroutine:
+		memory = [][]byte{simulateMemory(req.MemoryInMebiBytes)}
+	case common.MemoryAllocStriped:
+		memory, goroutines = simulateStripedMemory(req.MemoryInMebiBytes)
+	default:
+		return Response{StatusCode: 400}, fmt.Errorf("unsupported memory allocation mode %q", req.MemoryAllocMode)
+	}
+	allocationTime := time.Since(start)
+
+	allocated := 0
+	for _, stripe := range memory {
+		allocated += len(stripe)
+	}
+
+	time.Sleep(time.Duration(req.HoldDurationMs) * time.Millisecond)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"DurationInMicroSec":     uint32(time.Since(start).Microseconds()),
+		"MemoryUsageInKb":        uint32(allocated / 1024),
+		"AllocationMicroSec":     uint32(allocationTime.Microseconds()),
+		"HoldDurationInMilliSec": req.HoldDurationMs,
+		"ActualGoroutinesUsed":   goroutines,
+	})
+	if err != nil {
+		return Response{StatusCode: 400}, err
+	}
+	json.HTMLEscape(&buf, body)
+
+	return Response{
+		StatusCode:      200,
+		IsBase64Encoded: false,
+		Body:            buf.String(),
+		Headers: map[string]string{
+			"Content-Type":           "application/json",
+			"X-MyCompany-Func-Reply": "trace_func_mem handler",
+		},
+	}, nil
+}
+
+// selectHandler returns the handler variant requested through the environment of the function
+func selectHandler() func(context.Context, events.LambdaFunctionURLRequest) (Response, error) {
+	if os.Getenv(common.AwsLambdaHandlerEnvironmentVariable) == common.AwsLambdaHandlerMemory {
+		return MemoryHandler
+	}
+
+	return Handler
+}
@@ -0,0 +1,428 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+type fakeS3 struct {
+	objects map[string][]byte
+	latency time.Duration
+}
+
+func (f *fakeS3) GetObject(_ context.Context, params *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	time.Sleep(f.latency)
+
+	data, ok := f.objects[*params.Bucket+"/"+*params.Key]
+	if !ok {
+		return nil, fmt.Errorf("no such key %s", *params.Key)
+	}
+
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func (f *fakeS3) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	time.Sleep(f.latency)
+
+	data, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[*params.Bucket+"/"+*params.Key] = data
+
+	return &s3.PutObjectOutput{}, nil
+}
+
+type fakeDynamoDB struct {
+	items map[string]map[string]types.AttributeValue
+	puts  int
+}
+
+func (f *fakeDynamoDB) GetItem(_ context.Context, params *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	key := params.Key[idempotencyKeyAttribute].(*types.AttributeValueMemberS).Value
+
+	return &dynamodb.GetItemOutput{Item: f.items[key]}, nil
+}
+
+func (f *fakeDynamoDB) PutItem(_ context.Context, params *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	key := params.Item[idempotencyKeyAttribute].(*types.AttributeValueMemberS).Value
+	if item, exists := f.items[key]; exists && params.ConditionExpression != nil {
+		// attribute_not_exists(#key) OR #expiry < :now
+		expiry, _ := strconv.ParseInt(item[idempotencyExpiryAttribute].(*types.AttributeValueMemberN).Value, 10, 64)
+		now, _ := strconv.ParseInt(params.ExpressionAttributeValues[":now"].(*types.AttributeValueMemberN).Value, 10, 64)
+		if expiry >= now {
+			return nil, &types.ConditionalCheckFailedException{}
+		}
+	}
+
+	f.items[key] = params.Item
+	f.puts++
+
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func TestPerformIOWorkload(t *testing.T) {
+	client := &fakeS3{objects: map[string][]byte{}, latency: 10 * time.Millisecond}
+	write := &common.IOWorkload{Type: common.IOWorkloadS3Write, SizeKB: 4, Bucket: "loader"}
+
+	latency, err := performIOWorkload(context.Background(), client, write)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latency < client.latency {
+		t.Errorf("Expected the I/O latency to be at least %v, got %v.", client.latency, latency)
+	}
+	if len(client.objects["loader/"+ioWorkloadObjectKey(write)]) != 4*1024 {
+		t.Error("Unexpected size of the written object.")
+	}
+
+	read := &common.IOWorkload{Type: common.IOWorkloadS3Read, SizeKB: 4, Bucket: "loader"}
+	if _, err = performIOWorkload(context.Background(), client, read); err != nil {
+		t.Errorf("Unexpected error reading the object: %v", err)
+	}
+
+	invalid := &common.IOWorkload{Type: "ebs-read", SizeKB: 4, Bucket: "loader"}
+	if _, err = performIOWorkload(context.Background(), client, invalid); err == nil {
+		t.Error("Expected an error for an unsupported I/O workload.")
+	}
+}
+
+func TestHandlerReportsIOLatency(t *testing.T) {
+	s3Client = &fakeS3{objects: map[string][]byte{}, latency: 20 * time.Millisecond}
+	defer func() { s3Client = nil }()
+
+	response, err := Handler(context.Background(), events.LambdaFunctionURLRequest{
+		Body: `{"RuntimeInMilliSec": 10, "MemoryInMebiBytes": 128, "IOWorkload": {"Type": "s3-write", "SizeKB": 1, "Bucket": "loader"}}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var body struct {
+		DurationInMicroSec uint32
+		IOLatencyMicroSec  uint32
+	}
+	if err = json.Unmarshal([]byte(response.Body), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	if body.IOLatencyMicroSec < 20000 {
+		t.Errorf("Expected an I/O latency of at least 20ms, got %dµs.", body.IOLatencyMicroSec)
+	}
+	if body.DurationInMicroSec < body.IOLatencyMicroSec {
+		t.Errorf("Duration (%dµs) should include the I/O latency (%dµs).", body.DurationInMicroSec, body.IOLatencyMicroSec)
+	}
+}
+
+// TestIOWorkloadLocalstack runs against a localstack S3 endpoint, e.g.
+// LOCALSTACK_ENDPOINT=http://localhost:4566 LOCALSTACK_BUCKET=loader go test ./server/trace-func-go/aws
+func TestIOWorkloadLocalstack(t *testing.T) {
+	endpoint, bucket := os.Getenv("LOCALSTACK_ENDPOINT"), os.Getenv("LOCALSTACK_BUCKET")
+	if endpoint == "" || bucket == "" {
+		t.Skip("LOCALSTACK_ENDPOINT and LOCALSTACK_BUCKET are not set.")
+	}
+
+	t.Setenv("AWS_ENDPOINT_URL_S3", endpoint)
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_REGION", common.AwsRegion)
+
+	client, err := newS3Client(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.(*s3.Client).CreateBucket(context.Background(), &s3.CreateBucketInput{Bucket: aws.String(bucket)}); err != nil {
+		t.Logf("Bucket creation: %v", err)
+	}
+
+	for _, workloadType := range []string{common.IOWorkloadS3Write, common.IOWorkloadS3Read} {
+		workload := &common.IOWorkload{Type: workloadType, SizeKB: 16, Bucket: bucket}
+		if _, err = performIOWorkload(context.Background(), client, workload); err != nil {
+			t.Errorf("%s failed: %v", workloadType, err)
+		}
+	}
+}
+
+func TestHandlerReportsXRayTraceID(t *testing.T) {
+	ctx, segment := xray.BeginSegment(context.Background(), "trace-func-go")
+	defer segment.Close(nil)
+
+	response, err := Handler(ctx, events.LambdaFunctionURLRequest{
+		Body: `{"RuntimeInMilliSec": 10, "MemoryInMebiBytes": 128}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var body struct {
+		XRayTraceID string
+	}
+	if err = json.Unmarshal([]byte(response.Body), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	if body.XRayTraceID == "" || body.XRayTraceID != segment.TraceID {
+		t.Errorf("Expected X-Ray trace ID %s, got %q.", segment.TraceID, body.XRayTraceID)
+	}
+}
+
+func TestMemoryHandler(t *testing.T) {
+	start := time.Now()
+	response, err := MemoryHandler(context.Background(), events.LambdaFunctionURLRequest{
+		Body: `{"MemoryInMebiBytes": 16, "HoldDurationMs": 50}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected the allocation to be held for at least 50ms, returned after %v.", elapsed)
+	}
+
+	var body struct {
+		DurationInMicroSec uint32
+		MemoryUsageInKb    uint32
+	}
+	if err = json.Unmarshal([]byte(response.Body), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	if body.MemoryUsageInKb != 16*1024 {
+		t.Errorf("Expected 16 MiB to be allocated, got %d KiB.", body.MemoryUsageInKb)
+	}
+	if body.DurationInMicroSec < 50000 {
+		t.Errorf("Expected a duration of at least 50ms, got %dµs.", body.DurationInMicroSec)
+	}
+}
+
+func TestMemoryHandlerStriped(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+
+	for _, procs := range []int{1, 4, 8, 16} {
+		runtime.GOMAXPROCS(procs)
+
+		response, err := MemoryHandler(context.Background(), events.LambdaFunctionURLRequest{
+			Body: `{"MemoryInMebiBytes": 4, "MemoryAllocMode": "striped"}`,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var body struct {
+			MemoryUsageInKb      uint32
+			ActualGoroutinesUsed int
+		}
+		if err = json.Unmarshal([]byte(response.Body), &body); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := min(procs, maxMemoryStripes); body.ActualGoroutinesUsed != expected {
+			t.Errorf("Expected %d goroutines to allocate memory with GOMAXPROCS=%d, got %d.", expected, procs, body.ActualGoroutinesUsed)
+		}
+		if body.MemoryUsageInKb != 4*1024 {
+			t.Errorf("Expected 4 MiB to be allocated over all stripes, got %d KiB.", body.MemoryUsageInKb)
+		}
+	}
+
+	response, err := MemoryHandler(context.Background(), events.LambdaFunctionURLRequest{
+		Body: `{"MemoryInMebiBytes": 4, "MemoryAllocMode": "single-goroutine"}`,
+	})
+	if err != nil || !strings.Contains(response.Body, `"ActualGoroutinesUsed":1`) {
+		t.Errorf("Expected a single goroutine to allocate memory, got %s (%v).", response.Body, err)
+	}
+
+	if _, err = MemoryHandler(context.Background(), events.LambdaFunctionURLRequest{
+		Body: `{"MemoryInMebiBytes": 4, "MemoryAllocMode": "scattered"}`,
+	}); err == nil {
+		t.Error("Expected an error for an unsupported allocation mode.")
+	}
+}
+
+func TestSelectHandler(t *testing.T) {
+	t.Setenv(common.AwsLambdaHandlerEnvironmentVariable, common.AwsLambdaHandlerMemory)
+	if fmt.Sprintf("%p", selectHandler()) != fmt.Sprintf("%p", MemoryHandler) {
+		t.Error("Expected the memory handler to be selected.")
+	}
+
+	t.Setenv(common.AwsLambdaHandlerEnvironmentVariable, "")
+	if fmt.Sprintf("%p", selectHandler()) != fmt.Sprintf("%p", Handler) {
+		t.Error("Expected the trace handler to be selected by default.")
+	}
+}
+
+func TestHandlerComputeModes(t *testing.T) {
+	for _, mode := range []string{"sqrt", "fib", "hash", "matrix"} {
+		response, err := Handler(context.Background(), events.LambdaFunctionURLRequest{
+			Body: fmt.Sprintf(`{"RuntimeInMilliSec": 10, "MemoryInMebiBytes": 128, "ComputeMode": %q}`, mode),
+		})
+		if err != nil || response.StatusCode != 200 {
+			t.Errorf("Compute mode %s failed with status %d: %v", mode, response.StatusCode, err)
+		}
+	}
+
+	response, err := Handler(context.Background(), events.LambdaFunctionURLRequest{
+		Body: `{"RuntimeInMilliSec": 10, "MemoryInMebiBytes": 128, "ComputeMode": "crypto-mining"}`,
+	})
+	if err == nil || response.StatusCode != 400 {
+		t.Error("Expected an unsupported compute mode to be rejected.")
+	}
+}
+
+func TestHandlerIdempotency(t *testing.T) {
+	client := &fakeDynamoDB{items: map[string]map[string]types.AttributeValue{}}
+	dynamoDBClient = client
+	defer func() { dynamoDBClient = nil }()
+
+	key := common.IdempotencyKey("experiment", "trace-func-0", 3, 7, 42)
+	body := fmt.Sprintf(`{"RuntimeInMilliSec": 10, "MemoryInMebiBytes": 128, "IdempotencyKey": %q}`, key)
+
+	// The key is ignored by a function deployed without an idempotency table, whatever the request names
+	forged := fmt.Sprintf(`{"RuntimeInMilliSec": 10, "MemoryInMebiBytes": 128, "IdempotencyKey": %q, "IdempotencyTable": "other"}`, key)
+	if response, err := Handler(context.Background(), events.LambdaFunctionURLRequest{Body: forged}); err != nil || response.StatusCode != 200 {
+		t.Fatalf("Invocation without an idempotency table failed with status %d: %v", response.StatusCode, err)
+	}
+	if client.puts != 0 {
+		t.Errorf("Expected no write without an idempotency table, got %d writes.", client.puts)
+	}
+
+	t.Setenv(common.AwsIdempotencyTableEnvironmentVariable, "loader")
+
+	first, err := Handler(context.Background(), events.LambdaFunctionURLRequest{Body: body})
+	if err != nil || first.StatusCode != 200 {
+		t.Fatalf("First invocation failed with status %d: %v", first.StatusCode, err)
+	}
+	if client.puts != 2 {
+		t.Errorf("Expected the key to be claimed and the response stored, got %d writes.", client.puts)
+	}
+
+	retry, err := Handler(context.Background(), events.LambdaFunctionURLRequest{Body: body})
+	if err != nil || retry.StatusCode != 200 {
+		t.Fatalf("Retried invocation failed with status %d: %v", retry.StatusCode, err)
+	}
+	if retry.Body != first.Body || retry.Headers["X-Idempotent-Replay"] != "true" {
+		t.Errorf("Expected the cached response to be replayed, got %s.", retry.Body)
+	}
+	if client.puts != 2 {
+		t.Error("Expected the retried invocation not to be executed.")
+	}
+
+	// A duplicate arriving while the first invocation is still running cannot be answered yet
+	inProgressKey := common.IdempotencyKey("experiment", "trace-func-0", 3, 8, 42)
+	inProgressBody := fmt.Sprintf(`{"RuntimeInMilliSec": 10, "MemoryInMebiBytes": 128, "IdempotencyKey": %q}`, inProgressKey)
+	client.items[inProgressKey] = map[string]types.AttributeValue{
+		idempotencyKeyAttribute:    &types.AttributeValueMemberS{Value: inProgressKey},
+		idempotencyExpiryAttribute: epochSecondAttribute(time.Now().Add(time.Minute)),
+	}
+	response, err := Handler(context.Background(), events.LambdaFunctionURLRequest{Body: inProgressBody})
+	if !errors.Is(err, errInvocationInProgress) || response.StatusCode != 409 {
+		t.Errorf("Expected a conflict for an invocation in progress, got status %d: %v", response.StatusCode, err)
+	}
+
+	// The claim of an invocation that did not complete before its deadline is taken over by the retry
+	client.items[inProgressKey][idempotencyExpiryAttribute] = epochSecondAttribute(time.Now().Add(-time.Minute))
+	response, err = Handler(context.Background(), events.LambdaFunctionURLRequest{Body: inProgressBody})
+	if err != nil || response.StatusCode != 200 || response.Headers["X-Idempotent-Replay"] == "true" {
+		t.Errorf("Expected the stale claim to be stolen and the invocation executed, got status %d: %v", response.StatusCode, err)
+	}
+	if _, ok := client.items[inProgressKey][idempotencyResponseAttribute]; !ok {
+		t.Error("Expected the response of the retry to be stored.")
+	}
+	if _, ok := client.items[key][idempotencyExpiryAttribute]; !ok {
+		t.Error("Expected the stored response to carry the TTL attribute.")
+	}
+}
+
+// TestIdempotencyDynamoDBLocal runs against a DynamoDB local endpoint, e.g.
+// DYNAMODB_ENDPOINT=http://localhost:8000 DYNAMODB_TABLE=loader-idempotency go test ./server/trace-func-go/aws
+func TestIdempotencyDynamoDBLocal(t *testing.T) {
+	endpoint, table := os.Getenv("DYNAMODB_ENDPOINT"), os.Getenv("DYNAMODB_TABLE")
+	if endpoint == "" || table == "" {
+		t.Skip("DYNAMODB_ENDPOINT and DYNAMODB_TABLE are not set.")
+	}
+
+	t.Setenv("AWS_ENDPOINT_URL_DYNAMODB", endpoint)
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_REGION", common.AwsRegion)
+
+	client, err := newDynamoDBClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.(*dynamodb.Client).CreateTable(context.Background(), &dynamodb.CreateTableInput{
+		TableName:            aws.String(table),
+		AttributeDefinitions: []types.AttributeDefinition{{AttributeName: aws.String(idempotencyKeyAttribute), AttributeType: types.ScalarAttributeTypeS}},
+		KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String(idempotencyKeyAttribute), KeyType: types.KeyTypeHash}},
+		BillingMode:          types.BillingModePayPerRequest,
+	}); err != nil {
+		t.Logf("Table creation: %v", err)
+	}
+
+	key := common.IdempotencyKey("experiment", "trace-func-0", 0, 0, time.Now().UnixNano())
+	if claimed, _, err := claimIdempotencyKey(context.Background(), client, table, key); err != nil || !claimed {
+		t.Fatalf("Expected the first invocation to claim the key: %v", err)
+	}
+	if _, _, err = claimIdempotencyKey(context.Background(), client, table, key); !errors.Is(err, errInvocationInProgress) {
+		t.Errorf("Expected the duplicate to find the invocation in progress, got %v", err)
+	}
+
+	if err = storeIdempotentResponse(context.Background(), client, table, key, "OK"); err != nil {
+		t.Fatal(err)
+	}
+	if claimed, response, err := claimIdempotencyKey(context.Background(), client, table, key); err != nil || claimed || response != "OK" {
+		t.Errorf("Expected the stored response to be returned, got %q (claimed %t): %v", response, claimed, err)
+	}
+}
+
+func TestInvitroMetadata(t *testing.T) {
+	metadata := invitroMetadata(map[string]string{
+		"x-invitro-minute":           "3",
+		"X-Invitro-Invocation-Index": "7",
+		"content-type":               "application/json",
+	})
+
+	if len(metadata) != 2 || metadata["minute"] != "3" || metadata["invocation-index"] != "7" {
+		t.Errorf("Expected the minute and invocation index only, got %v.", metadata)
+	}
+}
@@ -34,10 +34,13 @@ import (
 )
 
 var (
-	zipkin = flag.String("zipkin", "http://zipkin.zipkin:9411/api/v2/spans", "zipkin url")
+	zipkin         = flag.String("zipkin", "http://zipkin.zipkin:9411/api/v2/spans", "zipkin url")
+	serverTraceLog = flag.String("server-trace-log", "", "Path of the rotated log of the executed invocations, which the loader can collect at the end of the experiment (defaults to $SERVER_TRACE_LOG)")
 )
 
 func main() {
+	flag.Parse()
+
 	// For containers - port 80; for Firecracker - 50051.
 	var serverPort = 80
 	var functionType standard.FunctionType
@@ -62,5 +65,10 @@ func main() {
 		log.Infof("Function type: EMPTY\n")
 	}
 
-	standard.StartGRPCServer("", serverPort, functionType, *zipkin)
+	traceLogPath := *serverTraceLog
+	if traceLogPath == "" {
+		traceLogPath = os.Getenv("SERVER_TRACE_LOG")
+	}
+
+	standard.StartGRPCServer("", serverPort, functionType, *zipkin, traceLogPath)
 }
@@ -0,0 +1,64 @@
+//  MIT License
+//
+//  Copyright (c) 2023 EASL and the vHive community
+//
+//  Permission is hereby granted, free of charge, to any person obtaining a copy
+//  of this software and associated documentation files (the "Software"), to deal
+//  in the Software without restriction, including without limitation the rights
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+//  copies of the Software, and to permit persons to whom the Software is
+//  furnished to do so, subject to the following conditions:
+//
+//  The above copyright notice and this permission notice shall be included in all
+//  copies or substantial portions of the Software.
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+//  SOFTWARE.
+
+// Trace function of the nodejs20.x runtime of AWS Lambda, invoked through its function URL. It takes the requests of
+// the Go trace function and replies with the same JSON body.
+
+// executeFunction spins the CPU for the requested runtime and returns the elapsed time in microseconds
+function executeFunction(runtimeMilliSec) {
+    const start = process.hrtime.bigint();
+    const runtimeNanoSec = BigInt(runtimeMilliSec) * 1000000n;
+
+    let sink = 0;
+    while (process.hrtime.bigint() - start < runtimeNanoSec) {
+        for (let i = 0; i < 1024; i++) {
+            sink += Math.sqrt(i) * Math.sin(i);
+        }
+    }
+
+    return Number((process.hrtime.bigint() - start) / 1000n);
+}
+
+exports.handler = async (event) => {
+    let body = event.body || "{}";
+    if (event.isBase64Encoded) {
+        body = Buffer.from(body, "base64").toString();
+    }
+
+    let request;
+    try {
+        request = JSON.parse(body);
+    } catch (err) {
+        return {statusCode: 400, body: `Invalid request body: ${err.message}`};
+    }
+
+    const duration = executeFunction(request.RuntimeInMilliSec || 0);
+
+    return {
+        statusCode: 200,
+        headers: {"Content-Type": "application/json"},
+        body: JSON.stringify({
+            DurationInMicroSec: duration,
+            MemoryUsageInKb: (request.MemoryInMebiBytes || 0) * 1024,
+        }),
+    };
+};
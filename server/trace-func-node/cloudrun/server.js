@@ -0,0 +1,72 @@
+//  MIT License
+//
+//  Copyright (c) 2023 EASL and the vHive community
+//
+//  Permission is hereby granted, free of charge, to any person obtaining a copy
+//  of this software and associated documentation files (the "Software"), to deal
+//  in the Software without restriction, including without limitation the rights
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+//  copies of the Software, and to permit persons to whom the Software is
+//  furnished to do so, subject to the following conditions:
+//
+//  The above copyright notice and this permission notice shall be included in all
+//  copies or substantial portions of the Software.
+//
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+//  SOFTWARE.
+
+// Trace function served over HTTP on Cloud Run. It takes the requests of the AWS Lambda trace function and replies
+// with the same JSON body, so that the loader invokes both platforms alike.
+
+const http = require("http");
+
+// executeFunction spins the CPU for the requested runtime and returns the elapsed time in microseconds
+function executeFunction(runtimeMilliSec) {
+    const start = process.hrtime.bigint();
+    const runtimeNanoSec = BigInt(runtimeMilliSec) * 1000000n;
+
+    let sink = 0;
+    while (process.hrtime.bigint() - start < runtimeNanoSec) {
+        for (let i = 0; i < 1024; i++) {
+            sink += Math.sqrt(i) * Math.sin(i);
+        }
+    }
+
+    return Number((process.hrtime.bigint() - start) / 1000n);
+}
+
+const server = http.createServer((req, res) => {
+    let body = "";
+    req.on("data", (chunk) => {
+        body += chunk;
+    });
+    req.on("end", () => {
+        let request;
+        try {
+            request = JSON.parse(body);
+        } catch (err) {
+            res.writeHead(400, {"Content-Type": "text/plain"});
+            res.end(`Invalid request body: ${err.message}`);
+            return;
+        }
+
+        const runtimeMilliSec = request.RuntimeInMilliSec || 0;
+        const memoryMebiBytes = request.MemoryInMebiBytes || 0;
+
+        const duration = executeFunction(runtimeMilliSec);
+
+        res.writeHead(200, {"Content-Type": "application/json"});
+        res.end(JSON.stringify({
+            DurationInMicroSec: duration,
+            MemoryUsageInKb: memoryMebiBytes * 1024,
+        }));
+    });
+});
+
+// Cloud Run sets the port the container listens on
+server.listen(parseInt(process.env.PORT || "8080", 10));
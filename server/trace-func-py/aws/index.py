@@ -0,0 +1,63 @@
+#  MIT License
+#
+#  Copyright (c) 2023 EASL and the vHive community
+#
+#  Permission is hereby granted, free of charge, to any person obtaining a copy
+#  of this software and associated documentation files (the "Software"), to deal
+#  in the Software without restriction, including without limitation the rights
+#  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+#  copies of the Software, and to permit persons to whom the Software is
+#  furnished to do so, subject to the following conditions:
+#
+#  The above copyright notice and this permission notice shall be included in all
+#  copies or substantial portions of the Software.
+#
+#  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+#  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+#  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+#  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+#  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+#  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+#  SOFTWARE.
+
+# Trace function of the python3.11 runtime of AWS Lambda, invoked through its function URL. It takes the requests of
+# the Go trace function and replies with the same JSON body.
+
+import base64
+import json
+import math
+from time import perf_counter_ns
+
+
+def execute_function(runtime_milli_sec):
+    """Spins the CPU for the requested runtime and returns the elapsed time in microseconds"""
+    start = perf_counter_ns()
+    runtime_nano_sec = runtime_milli_sec * 10**6
+
+    while perf_counter_ns() - start < runtime_nano_sec:
+        for i in range(1024):
+            math.sqrt(i) * math.sin(i)
+
+    return (perf_counter_ns() - start) // 1000
+
+
+def handler(event, context):
+    body = event.get("body") or "{}"
+    if event.get("isBase64Encoded"):
+        body = base64.b64decode(body).decode()
+
+    try:
+        request = json.loads(body)
+    except json.JSONDecodeError as err:
+        return {"statusCode": 400, "body": f"Invalid request body: {err}"}
+
+    duration = execute_function(request.get("RuntimeInMilliSec", 0))
+
+    return {
+        "statusCode": 200,
+        "headers": {"Content-Type": "application/json"},
+        "body": json.dumps({
+            "DurationInMicroSec": duration,
+            "MemoryUsageInKb": request.get("MemoryInMebiBytes", 0) * 1024,
+        }),
+    }
@@ -128,7 +128,10 @@ func generateLoad(outputFilename string, millisecondScale bool) {
 	specGenerator := spec.NewSpecificationGenerator(*randSeed)
 
 	for i, function := range functions {
-		spec := specGenerator.GenerateInvocationData(function, iatType, false, common.MinuteGranularity)
+		spec, err := specGenerator.GenerateInvocationData(function, iatType, false, common.MinuteGranularity)
+		if err != nil {
+			log.Fatalf("Failed to generate the invocations of function %s: %v", function.Name, err)
+		}
 		functions[i].Specification = spec
 	}
 
@@ -0,0 +1,54 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+var output = flag.String("output", config.SchemaPath, "Path the JSON Schema of the loader configuration is written to")
+
+func main() {
+	flag.Parse()
+
+	schema, err := config.GenerateJSONSchema()
+	if err != nil {
+		log.Fatalf("Failed to generate the JSON Schema of the loader configuration - %v", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(*output), 0755); err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile(*output, append(schema, '\n'), 0644); err != nil {
+		log.Fatalf("Failed to write the JSON Schema to %s - %v", *output, err)
+	}
+
+	log.Infof("Wrote the JSON Schema of the loader configuration to %s.", *output)
+}